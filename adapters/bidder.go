@@ -54,6 +54,17 @@ type TimeoutBidder interface {
 	MakeTimeoutNotification(req *RequestData) (*RequestData, []error)
 }
 
+// RejectNotifier is used to identify bidders that support notifications about discarded responses.
+type RejectNotifier interface {
+	Bidder
+
+	// MakeRejectNotification functions much the same as MakeTimeoutNotification, except it is fed the bidder request
+	// which response was discarded either because the server responded with a failure status or because MakeBids
+	// returned only errors. The errors explaining why the response was discarded are passed along with the request.
+	// Only one notification "request" is expected to be generated.
+	MakeRejectNotification(req *RequestData, errs []error) (*RequestData, []error)
+}
+
 // BidderResponse wraps the server's response with the list of bids and the currency used by the bidder.
 //
 // Currency declaration is not mandatory but helps to detect an eventual currency mismatch issue.
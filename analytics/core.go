@@ -35,6 +35,7 @@ type AuctionObject struct {
 	Account              *config.Account
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
+	HookExecutionSummary []hookexecution.StageSummary
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -47,6 +48,7 @@ type AmpObject struct {
 	Origin               string
 	StartTime            time.Time
 	HookExecutionOutcome []hookexecution.StageOutcome
+	HookExecutionSummary []hookexecution.StageSummary
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
@@ -36,6 +36,21 @@ type Account struct {
 	AlternateBidderCodes    *openrtb_ext.ExtAlternateBidderCodes `mapstructure:"alternatebiddercodes" json:"alternatebiddercodes"`
 	Hooks                   AccountHooks                         `mapstructure:"hooks" json:"hooks"`
 	Validations             Validations                          `mapstructure:"validations" json:"validations"`
+	// MaxBidCPM is the highest bid price accepted after currency conversion and bid adjustment,
+	// bids above it are dropped. Zero disables the check.
+	MaxBidCPM float64 `mapstructure:"max_bid_cpm" json:"max_bid_cpm"`
+	// DefaultCurrency is assumed for the bid request and bid responses not specifying the currency,
+	// USD is used if empty.
+	DefaultCurrency string `mapstructure:"default_currency" json:"default_currency"`
+	// PreferredCurrency is used for the bid conversion if present in the bid request currencies and having a rate,
+	// otherwise the first request currency having a rate is used.
+	PreferredCurrency string `mapstructure:"preferred_currency" json:"preferred_currency"`
+	// EnforceFloors drops the bids priced below the imp bid floor after currency conversion and bid adjustment,
+	// it can be turned on for all accounts through account_defaults.
+	EnforceFloors bool `mapstructure:"enforce_floors" json:"enforce_floors"`
+	// AllowedBidders restricts the auction to the listed bidders, the requests to other bidders are skipped.
+	// All bidders are allowed if empty.
+	AllowedBidders []string `mapstructure:"allowed_bidders" json:"allowed_bidders"`
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
@@ -237,6 +252,51 @@ func (a *AccountChannel) GetByChannelType(channelType ChannelType) *bool {
 type AccountHooks struct {
 	Modules       AccountModules    `mapstructure:"modules" json:"modules"`
 	ExecutionPlan HookExecutionPlan `mapstructure:"execution_plan" json:"execution_plan"`
+	// TimeoutMultiplier scales the timeout of every group of hooks executed for the account.
+	// Zero or negative value leaves group timeouts unchanged.
+	TimeoutMultiplier float64 `mapstructure:"timeout_multiplier" json:"timeout_multiplier"`
+	// ConditionalExt lists response.ext fields added only if specific bidders participated in the auction.
+	ConditionalExt []AccountConditionalExt `mapstructure:"conditional_ext" json:"conditional_ext"`
+	Diagnostics    AccountHooksDiagnostics `mapstructure:"diagnostics" json:"diagnostics"`
+	// AlwaysIncludeWarnings adds warnings returned from executing hooks to the response.ext.prebid.modules
+	// even if the debug mode is disabled, errors are still added only in the debug mode.
+	AlwaysIncludeWarnings bool `mapstructure:"always_include_warnings" json:"always_include_warnings"`
+	// DisabledStages lists stages, named as in the execution plan (e.g. "auction_response"),
+	// for which no hooks are executed for the account, regardless of the host and account execution plans.
+	DisabledStages []string `mapstructure:"disabled_stages" json:"disabled_stages"`
+	// TraceLevel is the default trace level ("basic" or "verbose") of the response.ext.prebid.modules,
+	// used if the request doesn't specify one in the ext.prebid.trace.
+	TraceLevel string `mapstructure:"trace_level" json:"trace_level"`
+}
+
+// IsStageDisabled reports whether execution of hooks at the given stage is disabled for the account.
+func (h AccountHooks) IsStageDisabled(stage string) bool {
+	for _, s := range h.DisabledStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountHooksDiagnostics represents the diagnostic mode configuration
+// in which every mutation applied by hooks is written to the server log.
+// Intended for short-lived debugging of a specific account.
+type AccountHooksDiagnostics struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// SamplingRate is the share of account requests, in the range [0, 1], for which mutations are logged.
+	SamplingRate float64 `mapstructure:"sampling_rate" json:"sampling_rate"`
+	// MaxLogEntries caps the number of mutations logged per request, default limit applied if not positive.
+	MaxLogEntries int `mapstructure:"max_log_entries" json:"max_log_entries"`
+}
+
+// AccountConditionalExt represents response.ext fields merged into the response
+// when any of the listed bidders participated in the auction.
+type AccountConditionalExt struct {
+	Bidders []string `mapstructure:"bidders" json:"bidders"`
+	// WithBids requires bidders to return bids, otherwise it is enough for bidders to be requested.
+	WithBids bool            `mapstructure:"with_bids" json:"with_bids"`
+	Ext      json.RawMessage `mapstructure:"ext" json:"ext"`
 }
 
 // AccountModules mapping provides account-level module configuration
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"log"
@@ -48,6 +49,26 @@ type BidderInfo struct {
 	AppSecret  string `yaml:"app_secret" mapstructure:"app_secret"`
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string `yaml:"endpointCompression" mapstructure:"endpointCompression"`
+	// GzipLevel sets the compression level used if EndpointCompression is GZIP,
+	// one of the compress/gzip levels, the default compression level is used if not set
+	GzipLevel *int `yaml:"gzipLevel" mapstructure:"gzipLevel"`
+	// AllowedResponseCurrencies, if not empty, restricts the currencies the bidder is allowed to respond with
+	AllowedResponseCurrencies []string `yaml:"allowedResponseCurrencies" mapstructure:"allowedResponseCurrencies"`
+	// FallbackEndpoint, if set, is the scheme and host the bid request is retried against once
+	// if the connection to the primary endpoint fails
+	FallbackEndpoint string `yaml:"fallbackEndpoint" mapstructure:"fallbackEndpoint"`
+	// UserAgent is the User-Agent header of the bid requests not having one set by the adapter,
+	// "prebid-server/<version>" is used if empty, the value "none" disables the header
+	UserAgent string `yaml:"userAgent" mapstructure:"userAgent"`
+	// MaxResponseBytes limits the size of the bid response body read from the bidder, both compressed
+	// and decompressed, zero means the default limit of 10MB
+	MaxResponseBytes int64 `yaml:"maxResponseBytes" mapstructure:"maxResponseBytes"`
+	// MaxRequestTimeout, if set, limits the time in milliseconds the bidder is given to respond
+	// below the auction deadline, zero means the bidder is limited by the auction deadline only
+	MaxRequestTimeout int `yaml:"maxRequestTimeoutMs" mapstructure:"maxRequestTimeoutMs"`
+	// GzipProbeRate is the share of the uncompressed bid requests sent gzip compressed instead to find out
+	// whether the bid server accepts compressed requests, zero disables the probe
+	GzipProbeRate float64 `yaml:"gzipProbeRate" mapstructure:"gzipProbeRate"`
 }
 
 // BidderInfoExperiment specifies non-production ready feature config for a bidder
@@ -69,6 +90,9 @@ type MaintainerInfo struct {
 type CapabilitiesInfo struct {
 	App  *PlatformInfo `yaml:"app" mapstructure:"app"`
 	Site *PlatformInfo `yaml:"site" mapstructure:"site"`
+	// Gzip declares the bid server accepts gzip compressed bid requests,
+	// the requests are compressed unless EndpointCompression is set explicitly
+	Gzip bool `yaml:"gzip" mapstructure:"gzip"`
 }
 
 // PlatformInfo specifies the supported media types for a bidder.
@@ -317,10 +341,26 @@ func validateInfo(info BidderInfo, bidderName string) error {
 	if err := validateCapabilities(info.Capabilities, bidderName); err != nil {
 		return err
 	}
+	if err := validateGzipLevel(info.GzipLevel, bidderName); err != nil {
+		return err
+	}
+	if info.MaxRequestTimeout < 0 {
+		return fmt.Errorf("invalid maxRequestTimeoutMs %d for adapter: %s, must not be negative", info.MaxRequestTimeout, bidderName)
+	}
+	if info.GzipProbeRate < 0 || info.GzipProbeRate > 1 {
+		return fmt.Errorf("invalid gzipProbeRate %g for adapter: %s, must be in range [0, 1]", info.GzipProbeRate, bidderName)
+	}
 
 	return nil
 }
 
+func validateGzipLevel(level *int, bidderName string) error {
+	if level != nil && (*level < gzip.HuffmanOnly || *level > gzip.BestCompression) {
+		return fmt.Errorf("invalid gzipLevel %d for adapter: %s, must be in range [%d, %d]", *level, bidderName, gzip.HuffmanOnly, gzip.BestCompression)
+	}
+	return nil
+}
+
 func validateMaintainer(info *MaintainerInfo, bidderName string) error {
 	if info == nil || info.Email == "" {
 		return fmt.Errorf("missing required field: maintainer.email for adapter: %s", bidderName)
@@ -424,6 +464,27 @@ func applyBidderInfoConfigOverrides(configBidderInfos BidderInfos, fsBidderInfos
 			if bidderInfo.EndpointCompression == "" && fsBidderCfg.EndpointCompression != "" {
 				bidderInfo.EndpointCompression = fsBidderCfg.EndpointCompression
 			}
+			if bidderInfo.FallbackEndpoint == "" && fsBidderCfg.FallbackEndpoint != "" {
+				bidderInfo.FallbackEndpoint = fsBidderCfg.FallbackEndpoint
+			}
+			if bidderInfo.UserAgent == "" && fsBidderCfg.UserAgent != "" {
+				bidderInfo.UserAgent = fsBidderCfg.UserAgent
+			}
+			if bidderInfo.MaxResponseBytes == 0 && fsBidderCfg.MaxResponseBytes != 0 {
+				bidderInfo.MaxResponseBytes = fsBidderCfg.MaxResponseBytes
+			}
+			if bidderInfo.MaxRequestTimeout == 0 && fsBidderCfg.MaxRequestTimeout != 0 {
+				bidderInfo.MaxRequestTimeout = fsBidderCfg.MaxRequestTimeout
+			}
+			if bidderInfo.GzipProbeRate == 0 && fsBidderCfg.GzipProbeRate != 0 {
+				bidderInfo.GzipProbeRate = fsBidderCfg.GzipProbeRate
+			}
+			if bidderInfo.GzipLevel == nil && fsBidderCfg.GzipLevel != nil {
+				bidderInfo.GzipLevel = fsBidderCfg.GzipLevel
+			}
+			if len(bidderInfo.AllowedResponseCurrencies) == 0 && len(fsBidderCfg.AllowedResponseCurrencies) > 0 {
+				bidderInfo.AllowedResponseCurrencies = fsBidderCfg.AllowedResponseCurrencies
+			}
 
 			// validate and try to apply the legacy usersync_url configuration in attempt to provide
 			// an easier upgrade path. be warned, this will break if the bidder adds a second syncer
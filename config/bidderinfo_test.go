@@ -353,6 +353,72 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 				errors.New("missing required field: maintainer.email for adapter: bidderA"),
 			},
 		},
+		{
+			"One bidder invalid gzip level",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					GzipLevel: intPtr(10),
+				},
+			},
+			[]error{
+				errors.New("invalid gzipLevel 10 for adapter: bidderA, must be in range [-2, 9]"),
+			},
+		},
+		{
+			"One bidder negative max request timeout",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					MaxRequestTimeout: -1,
+				},
+			},
+			[]error{
+				errors.New("invalid maxRequestTimeoutMs -1 for adapter: bidderA, must not be negative"),
+			},
+		},
+		{
+			"One bidder gzip probe rate out of range",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					GzipProbeRate: 1.5,
+				},
+			},
+			[]error{
+				errors.New("invalid gzipProbeRate 1.5 for adapter: bidderA, must be in range [0, 1]"),
+			},
+		},
 		{
 			"One bidder missing maintainer email",
 			BidderInfos{
@@ -926,6 +992,72 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: BidderInfos{"a": {EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}},
 			expectedBidderInfos:    BidderInfos{"a": {EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override GzipLevel",
+			givenFsBidderInfos:     BidderInfos{"a": {GzipLevel: intPtr(1)}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GzipLevel: intPtr(1), Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override GzipLevel",
+			givenFsBidderInfos:     BidderInfos{"a": {GzipLevel: intPtr(1)}},
+			givenConfigBidderInfos: BidderInfos{"a": {GzipLevel: intPtr(9), Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GzipLevel: intPtr(9), Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override GzipLevel with no compression",
+			givenFsBidderInfos:     BidderInfos{"a": {GzipLevel: intPtr(1)}},
+			givenConfigBidderInfos: BidderInfos{"a": {GzipLevel: intPtr(0), Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GzipLevel: intPtr(0), Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Don't override MaxResponseBytes",
+			givenFsBidderInfos:     BidderInfos{"a": {MaxResponseBytes: 1024}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {MaxResponseBytes: 1024, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override MaxResponseBytes",
+			givenFsBidderInfos:     BidderInfos{"a": {MaxResponseBytes: 1024}},
+			givenConfigBidderInfos: BidderInfos{"a": {MaxResponseBytes: 2048, Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {MaxResponseBytes: 2048, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Don't override MaxRequestTimeout",
+			givenFsBidderInfos:     BidderInfos{"a": {MaxRequestTimeout: 100}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {MaxRequestTimeout: 100, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override MaxRequestTimeout",
+			givenFsBidderInfos:     BidderInfos{"a": {MaxRequestTimeout: 100}},
+			givenConfigBidderInfos: BidderInfos{"a": {MaxRequestTimeout: 200, Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {MaxRequestTimeout: 200, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Don't override GzipProbeRate",
+			givenFsBidderInfos:     BidderInfos{"a": {GzipProbeRate: 0.1}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GzipProbeRate: 0.1, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override GzipProbeRate",
+			givenFsBidderInfos:     BidderInfos{"a": {GzipProbeRate: 0.1}},
+			givenConfigBidderInfos: BidderInfos{"a": {GzipProbeRate: 0.2, Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {GzipProbeRate: 0.2, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Don't override AllowedResponseCurrencies",
+			givenFsBidderInfos:     BidderInfos{"a": {AllowedResponseCurrencies: []string{"USD"}}},
+			givenConfigBidderInfos: BidderInfos{"a": {Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {AllowedResponseCurrencies: []string{"USD"}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override AllowedResponseCurrencies",
+			givenFsBidderInfos:     BidderInfos{"a": {AllowedResponseCurrencies: []string{"USD"}}},
+			givenConfigBidderInfos: BidderInfos{"a": {AllowedResponseCurrencies: []string{"EUR"}, Syncer: &Syncer{Key: "override"}}},
+			expectedBidderInfos:    BidderInfos{"a": {AllowedResponseCurrencies: []string{"EUR"}, Syncer: &Syncer{Key: "override"}}},
+		},
 	}
 	for _, test := range testCases {
 		bidderInfos, resultErr := applyBidderInfoConfigOverrides(test.givenConfigBidderInfos, test.givenFsBidderInfos, mockNormalizeBidderName)
@@ -994,3 +1126,7 @@ func TestReadFullYamlBidderConfig(t *testing.T) {
 	}
 	assert.Equalf(t, expectedBidderInfo, actualBidderInfo, "Bidder info objects aren't matching")
 }
+
+func intPtr(v int) *int {
+	return &v
+}
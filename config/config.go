@@ -90,6 +90,18 @@ type Configuration struct {
 	AutoGenSourceTID bool `mapstructure:"auto_gen_source_tid"`
 	//When true, new bid id will be generated in seatbid[].bid[].ext.prebid.bidid and used in event urls instead
 	GenerateBidID bool `mapstructure:"generate_bid_id"`
+	// When true, imp ids of bids from stored bid responses are never restored to the request imp ids,
+	// regardless of the per-impression "replaceimpid" setting. The default is false.
+	DisableStoredRespImpIDReplacement bool `mapstructure:"disable_stored_response_imp_id_replacement"`
+	// DedupAlternateSeatBids collapses bids returned by a bidder under several alternate seats if they have the
+	// same imp id, creative id and price, keeping the bid of the highest-priority seat. The default is false.
+	DedupAlternateSeatBids bool `mapstructure:"dedup_alternate_seat_bids"`
+	// MaxConcurrentBidderRequests limits the number of HTTP requests sent in parallel by a bidder
+	// for a single auction if the bidder splits the bid request into several ones. Zero means no limit.
+	MaxConcurrentBidderRequests int `mapstructure:"max_concurrent_bidder_requests"`
+	// NoContentAsNoBid treats the 204 No Content bidder responses as no-bids without passing them to the adapter
+	// for parsing. The default is true.
+	NoContentAsNoBid bool `mapstructure:"no_content_as_no_bid"`
 	// GenerateRequestID overrides the bidrequest.id in an AMP Request or an App Stored Request with a generated UUID if set to true. The default is false.
 	GenerateRequestID bool                      `mapstructure:"generate_request_id"`
 	HostSChainNode    *openrtb2.SupplyChainNode `mapstructure:"host_schain_node"`
@@ -125,6 +137,12 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
+	if cfg.MaxConcurrentBidderRequests < 0 {
+		errs = append(errs, fmt.Errorf("max_concurrent_bidder_requests must be >= 0. Got %d", cfg.MaxConcurrentBidderRequests))
+	}
+	if cfg.Hooks.MaxBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("hooks.max_body_bytes must be >= 0. Got %d", cfg.Hooks.MaxBodyBytes))
+	}
 	errs = cfg.GDPR.validate(v, errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
@@ -497,6 +515,9 @@ type Metrics struct {
 	Influxdb   InfluxMetrics     `mapstructure:"influxdb"`
 	Prometheus PrometheusMetrics `mapstructure:"prometheus"`
 	Disabled   DisabledMetrics   `mapstructure:"disabled_metrics"`
+	// AdapterConnectionsSampleRate is the share of bidder requests, in the range (0, 1], for which
+	// the adapter connection metrics are collected. Ignored if adapter connection metrics are disabled.
+	AdapterConnectionsSampleRate float64 `mapstructure:"adapter_connections_sample_rate"`
 }
 
 type DisabledMetrics struct {
@@ -522,6 +543,9 @@ type DisabledMetrics struct {
 }
 
 func (cfg *Metrics) validate(errs []error) []error {
+	if cfg.AdapterConnectionsSampleRate <= 0 || cfg.AdapterConnectionsSampleRate > 1 {
+		errs = append(errs, fmt.Errorf("metrics.adapter_connections_sample_rate must be in the range (0, 1]. Got %g", cfg.AdapterConnectionsSampleRate))
+	}
 	return cfg.Prometheus.validate(errs)
 }
 
@@ -607,7 +631,11 @@ type DefReqFiles struct {
 
 type Debug struct {
 	TimeoutNotification TimeoutNotification `mapstructure:"timeout_notification"`
+	RejectNotification  RejectNotification  `mapstructure:"reject_notification"`
 	OverrideToken       string              `mapstructure:"override_token"`
+	// RedactedHeaders lists the headers of the bidder requests omitted from the debug output
+	// in addition to the Authorization header, which is always omitted. The outgoing requests are not affected
+	RedactedHeaders []string `mapstructure:"redacted_headers"`
 }
 
 type Server struct {
@@ -631,6 +659,14 @@ type TimeoutNotification struct {
 	SamplingRate float32 `mapstructure:"sampling_rate"`
 	// Only log failures
 	FailOnly bool `mapstructure:"fail_only"`
+	// Max time in milliseconds allowed for the timeout and reject notification requests
+	TimeoutMillis int `mapstructure:"timeout_ms"`
+}
+
+// RejectNotification controls notifications sent to bidders implementing adapters.RejectNotifier
+// when their responses are discarded. Logging and the request timeout follow the TimeoutNotification settings.
+type RejectNotification struct {
+	Enabled bool `mapstructure:"enabled"`
 }
 
 type Validations struct {
@@ -638,6 +674,8 @@ type Validations struct {
 	SecureMarkup          string `mapstructure:"secure_markup" json:"secure_markup"`
 	MaxCreativeWidth      int64  `mapstructure:"max_creative_width" json:"max_creative_width"`
 	MaxCreativeHeight     int64  `mapstructure:"max_creative_height" json:"max_creative_height"`
+	// ValidateBidImpIds, if enabled, drops bids whose imp ID doesn't match any imp of the bidder request
+	ValidateBidImpIds bool `mapstructure:"validate_bid_imp_ids" json:"validate_bid_imp_ids"`
 }
 
 const (
@@ -656,6 +694,9 @@ func (cfg *TimeoutNotification) validate(errs []error) []error {
 	if cfg.SamplingRate < 0.0 || cfg.SamplingRate > 1.0 {
 		errs = append(errs, fmt.Errorf("debug.timeout_notification.sampling_rate must be positive and not greater than 1.0. Got %f", cfg.SamplingRate))
 	}
+	if cfg.TimeoutMillis < 0 {
+		errs = append(errs, fmt.Errorf("debug.timeout_notification.timeout_ms must be >= 0. Got %d", cfg.TimeoutMillis))
+	}
 	return errs
 }
 
@@ -846,6 +887,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("validations.secure_markup", ValidationSkip)
 	v.SetDefault("validations.max_creative_size.height", 0)
 	v.SetDefault("validations.max_creative_size.width", 0)
+	v.SetDefault("validations.validate_bid_imp_ids", false)
 	v.SetDefault("http_client.max_connections_per_host", 0) // unlimited
 	v.SetDefault("http_client.max_idle_connections", 400)
 	v.SetDefault("http_client.max_idle_connections_per_host", 10)
@@ -860,6 +902,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("metrics.disabled_metrics.account_stored_responses", true)
 	v.SetDefault("metrics.disabled_metrics.adapter_connections_metrics", true)
 	v.SetDefault("metrics.disabled_metrics.adapter_gdpr_request_blocked", false)
+	v.SetDefault("metrics.adapter_connections_sample_rate", 1.0)
 	v.SetDefault("metrics.influxdb.host", "")
 	v.SetDefault("metrics.influxdb.database", "")
 	v.SetDefault("metrics.influxdb.measurement", "")
@@ -993,6 +1036,10 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("certificates_file", "")
 	v.SetDefault("auto_gen_source_tid", true)
 	v.SetDefault("generate_bid_id", false)
+	v.SetDefault("disable_stored_response_imp_id_replacement", false)
+	v.SetDefault("dedup_alternate_seat_bids", false)
+	v.SetDefault("max_concurrent_bidder_requests", 0)
+	v.SetDefault("no_content_as_no_bid", true)
 	v.SetDefault("generate_request_id", false)
 
 	v.SetDefault("request_timeout_headers.request_time_in_queue", "")
@@ -1001,7 +1048,10 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("debug.timeout_notification.log", false)
 	v.SetDefault("debug.timeout_notification.sampling_rate", 0.0)
 	v.SetDefault("debug.timeout_notification.fail_only", false)
+	v.SetDefault("debug.timeout_notification.timeout_ms", 200)
+	v.SetDefault("debug.reject_notification.enabled", false)
 	v.SetDefault("debug.override_token", "")
+	v.SetDefault("debug.redacted_headers", []string{})
 
 	/* IPv4
 	/*  Site Local: 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16
@@ -1084,6 +1134,9 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("experiment.adscert.remote.signing_timeout_ms", 5)
 
 	v.SetDefault("hooks.enabled", false)
+	v.SetDefault("hooks.max_body_bytes", 0)
+	v.SetDefault("hooks.logging.enabled", false)
+	v.SetDefault("hooks.logging.verbosity", 0)
 
 	for bidderName := range bidderInfos {
 		setBidderDefaults(v, strings.ToLower(bidderName))
@@ -160,11 +160,16 @@ func TestDefaults(t *testing.T) {
 	cmpBools(t, "account_stored_responses", cfg.Metrics.Disabled.AccountStoredResponses, true)
 	cmpBools(t, "adapter_connections_metrics", cfg.Metrics.Disabled.AdapterConnectionMetrics, true)
 	cmpBools(t, "adapter_gdpr_request_blocked", cfg.Metrics.Disabled.AdapterGDPRRequestBlocked, false)
+	assert.Equal(t, 1.0, cfg.Metrics.AdapterConnectionsSampleRate, "metrics.adapter_connections_sample_rate")
 	cmpStrings(t, "certificates_file", cfg.PemCertsFile, "")
 	cmpBools(t, "stored_requests.filesystem.enabled", false, cfg.StoredRequests.Files.Enabled)
 	cmpStrings(t, "stored_requests.filesystem.directorypath", "./stored_requests/data/by_id", cfg.StoredRequests.Files.Path)
 	cmpBools(t, "auto_gen_source_tid", cfg.AutoGenSourceTID, true)
 	cmpBools(t, "generate_bid_id", cfg.GenerateBidID, false)
+	cmpBools(t, "disable_stored_response_imp_id_replacement", cfg.DisableStoredRespImpIDReplacement, false)
+	cmpBools(t, "dedup_alternate_seat_bids", cfg.DedupAlternateSeatBids, false)
+	cmpInts(t, "max_concurrent_bidder_requests", cfg.MaxConcurrentBidderRequests, 0)
+	cmpBools(t, "no_content_as_no_bid", cfg.NoContentAsNoBid, true)
 	cmpStrings(t, "experiment.adscert.mode", cfg.Experiment.AdCerts.Mode, "off")
 	cmpStrings(t, "experiment.adscert.inprocess.origin", cfg.Experiment.AdCerts.InProcess.Origin, "")
 	cmpStrings(t, "experiment.adscert.inprocess.key", cfg.Experiment.AdCerts.InProcess.PrivateKey, "")
@@ -175,6 +180,11 @@ func TestDefaults(t *testing.T) {
 	cmpNils(t, "host_schain_node", cfg.HostSChainNode)
 	cmpStrings(t, "datacenter", cfg.DataCenter, "")
 	cmpBools(t, "hooks.enabled", cfg.Hooks.Enabled, false)
+	cmpInts(t, "debug.timeout_notification.timeout_ms", cfg.Debug.TimeoutNotification.TimeoutMillis, 200)
+	assert.Empty(t, cfg.Debug.RedactedHeaders, "debug.redacted_headers")
+	cmpInts(t, "hooks.max_body_bytes", int(cfg.Hooks.MaxBodyBytes), 0)
+	cmpBools(t, "hooks.logging.enabled", cfg.Hooks.Logging.Enabled, false)
+	cmpInts(t, "hooks.logging.verbosity", cfg.Hooks.Logging.Verbosity, 0)
 	cmpStrings(t, "validations.banner_creative_max_size", cfg.Validations.BannerCreativeMaxSize, "skip")
 	cmpStrings(t, "validations.secure_markup", cfg.Validations.SecureMarkup, "skip")
 	cmpInts(t, "validations.max_creative_width", int(cfg.Validations.MaxCreativeWidth), 0)
@@ -408,6 +418,9 @@ request_validation:
     ipv4_private_networks: ["1.1.1.0/24"]
     ipv6_private_networks: ["1111::/16", "2222::/16"]
 generate_bid_id: true
+disable_stored_response_imp_id_replacement: true
+dedup_alternate_seat_bids: true
+no_content_as_no_bid: false
 host_schain_node:
     asi: "pbshostcompany.com"
     sid: "00001"
@@ -431,6 +444,7 @@ experiment:
             signing_timeout_ms: 10
 hooks:
     enabled: true
+    max_body_bytes: 1024
 `)
 
 var oldStoredRequestsConfig = []byte(`
@@ -672,6 +686,9 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "request_validation.ipv6_private_networks", cfg.RequestValidation.IPv6PrivateNetworks[0], "1111::/16")
 	cmpStrings(t, "request_validation.ipv6_private_networks", cfg.RequestValidation.IPv6PrivateNetworks[1], "2222::/16")
 	cmpBools(t, "generate_bid_id", cfg.GenerateBidID, true)
+	cmpBools(t, "disable_stored_response_imp_id_replacement", cfg.DisableStoredRespImpIDReplacement, true)
+	cmpBools(t, "dedup_alternate_seat_bids", cfg.DedupAlternateSeatBids, true)
+	cmpBools(t, "no_content_as_no_bid", cfg.NoContentAsNoBid, false)
 	cmpStrings(t, "debug.override_token", cfg.Debug.OverrideToken, "")
 	cmpStrings(t, "experiment.adscert.mode", cfg.Experiment.AdCerts.Mode, "inprocess")
 	cmpStrings(t, "experiment.adscert.inprocess.origin", cfg.Experiment.AdCerts.InProcess.Origin, "http://test.com")
@@ -681,6 +698,7 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "experiment.adscert.remote.url", cfg.Experiment.AdCerts.Remote.Url, "")
 	cmpInts(t, "experiment.adscert.remote.signing_timeout_ms", cfg.Experiment.AdCerts.Remote.SigningTimeoutMs, 10)
 	cmpBools(t, "hooks.enabled", cfg.Hooks.Enabled, true)
+	cmpInts(t, "hooks.max_body_bytes", int(cfg.Hooks.MaxBodyBytes), 1024)
 	cmpBools(t, "account_modules_metrics", cfg.Metrics.Disabled.AccountModulesMetrics, true)
 }
 
@@ -720,6 +738,7 @@ func TestValidateConfig(t *testing.T) {
 			Files:         FileFetcherConfig{Enabled: true},
 			InMemoryCache: InMemoryCache{Type: "none"},
 		},
+		Metrics: Metrics{AdapterConnectionsSampleRate: 1},
 	}
 
 	v := viper.New()
@@ -2597,6 +2616,18 @@ func TestNegativeRequestSize(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "cfg.max_request_size must be >= 0. Got -1")
 }
 
+func TestNegativeMaxConcurrentBidderRequests(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.MaxConcurrentBidderRequests = -1
+	assertOneError(t, cfg.validate(v), "max_concurrent_bidder_requests must be >= 0. Got -1")
+}
+
+func TestNegativeHooksMaxBodyBytes(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Hooks.MaxBodyBytes = -1
+	assertOneError(t, cfg.validate(v), "hooks.max_body_bytes must be >= 0. Got -1")
+}
+
 func TestNegativePrometheusTimeout(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Metrics.Prometheus.Port = 8001
@@ -2780,6 +2811,36 @@ func TestValidateDebug(t *testing.T) {
 	assert.NotNil(t, err, "cfg.debug.timeout_notification.sampling_rate should not be allowed to be greater than 1.0, but it was allowed")
 }
 
+func TestValidateDebugTimeoutNotificationTimeout(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Debug.TimeoutNotification.TimeoutMillis = -1
+	assertOneError(t, cfg.validate(v), "debug.timeout_notification.timeout_ms must be >= 0. Got -1")
+}
+
+func TestValidateAdapterConnectionsSampleRate(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenRate     float64
+		expectedError string
+	}{
+		{description: "Zero rate", givenRate: 0, expectedError: "metrics.adapter_connections_sample_rate must be in the range (0, 1]. Got 0"},
+		{description: "Negative rate", givenRate: -0.5, expectedError: "metrics.adapter_connections_sample_rate must be in the range (0, 1]. Got -0.5"},
+		{description: "Rate above one", givenRate: 1.5, expectedError: "metrics.adapter_connections_sample_rate must be in the range (0, 1]. Got 1.5"},
+		{description: "Rate in range", givenRate: 0.1},
+	}
+
+	for _, test := range testCases {
+		cfg, v := newDefaultConfig(t)
+		cfg.Metrics.AdapterConnectionsSampleRate = test.givenRate
+		errs := cfg.validate(v)
+		if test.expectedError == "" {
+			assert.Empty(t, errs, test.description)
+		} else {
+			assertOneError(t, errs, test.expectedError)
+		}
+	}
+}
+
 func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Accounts.Files.Enabled = true
@@ -1,22 +1,58 @@
 package config
 
+import "strings"
+
 type Hooks struct {
+	// Enabled is the master switch of the hooks subsystem, if false no hooks are executed
+	// regardless of the host, default account and account execution plans
 	Enabled bool    `mapstructure:"enabled"`
 	Modules Modules `mapstructure:"modules"`
+	// MaxBodyBytes limits the size of the request body passed to the entrypoint hooks,
+	// requests with bigger bodies are rejected, 0 means unlimited
+	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
+	// Logging enables a log line per hook invocation, disabled by default to avoid the log volume
+	Logging HookLogging `mapstructure:"logging"`
 	// HostExecutionPlan defined by the host company and is executed always
 	HostExecutionPlan HookExecutionPlan `mapstructure:"host_execution_plan"`
 	// DefaultAccountExecutionPlan can be replaced by the account-specific hook execution plan
 	DefaultAccountExecutionPlan HookExecutionPlan `mapstructure:"default_account_execution_plan"`
 }
 
+// HookLogging configures the log line written for each hook invocation.
+type HookLogging struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Verbosity is the glog verbosity level the lines are written at, so they can be enabled with the -v flag
+	Verbosity int `mapstructure:"verbosity"`
+}
+
 // Modules mapping provides module specific configuration, format: map[vendor_name]map[module_name]interface{}
 // actual configuration parsing performed by modules
 type Modules map[string]map[string]interface{}
 
+// IsModuleDisabled reports whether the module is explicitly disabled with the "enabled: false" setting.
+// The id argument is expected in the form "vendor.module_name".
+func (m Modules) IsModuleDisabled(id string) bool {
+	ns := strings.SplitN(id, ".", 2)
+	if len(ns) < 2 {
+		return false
+	}
+
+	values, ok := m[ns[0]][ns[1]].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	enabled, ok := values["enabled"].(bool)
+	return ok && !enabled
+}
+
 type HookExecutionPlan struct {
 	Endpoints map[string]struct {
 		Stages map[string]struct {
-			Groups []HookExecutionGroup `mapstructure:"groups" json:"groups"`
+			// Timeout is the max duration in milliseconds of all groups of the stage,
+			// the groups not started in time are skipped. Zero value means no limit.
+			Timeout int                  `mapstructure:"timeout" json:"timeout"`
+			Groups  []HookExecutionGroup `mapstructure:"groups" json:"groups"`
 		} `mapstructure:"stages" json:"stages"`
 	} `mapstructure:"endpoints" json:"endpoints"`
 }
@@ -29,6 +65,7 @@ type HookExecutionGroup struct {
 		// ModuleCode is a composite value in the format: {vendor_name}.{module_name}
 		ModuleCode string `mapstructure:"module_code" json:"module_code"`
 		// HookImplCode is an arbitrary value, used to identify hook when sending metrics, debug information, etc.
+		// The "*" wildcard includes the hook the module registers for the stage, identified by the stage name.
 		HookImplCode string `mapstructure:"hook_impl_code" json:"hook_impl_code"`
 	} `mapstructure:"hook_sequence" json:"hook_sequence"`
 }
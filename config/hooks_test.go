@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsModuleDisabled(t *testing.T) {
+	modules := Modules{
+		"acme": {
+			"enabled":  map[string]interface{}{"enabled": true},
+			"disabled": map[string]interface{}{"enabled": false},
+			"implicit": map[string]interface{}{"foo": "bar"},
+		},
+	}
+
+	testCases := []struct {
+		description      string
+		givenId          string
+		expectedDisabled bool
+	}{
+		{description: "Enabled module", givenId: "acme.enabled", expectedDisabled: false},
+		{description: "Explicitly disabled module", givenId: "acme.disabled", expectedDisabled: true},
+		{description: "Module without enabled setting", givenId: "acme.implicit", expectedDisabled: false},
+		{description: "Module without config", givenId: "acme.unknown", expectedDisabled: false},
+		{description: "Invalid module ID", givenId: "acme", expectedDisabled: false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedDisabled, modules.IsModuleDisabled(test.givenId), test.description)
+	}
+}
@@ -0,0 +1,46 @@
+package currency
+
+// CachedConversions memoizes the conversion rates returned by the wrapped Conversions,
+// so repeated lookups of the same currency pair are resolved without parsing the
+// currency codes again. Lookup errors are cached as well, as they're deterministic
+// for the lifetime of the wrapped rates.
+// It implements the Conversions interface and is not safe for concurrent use.
+type CachedConversions struct {
+	conversions Conversions
+	rates       map[cachedRateKey]cachedRate
+}
+
+type cachedRateKey struct {
+	from, to string
+}
+
+type cachedRate struct {
+	rate float64
+	err  error
+}
+
+// NewCachedConversions expects conversions to not be nil
+func NewCachedConversions(conversions Conversions) *CachedConversions {
+	return &CachedConversions{
+		conversions: conversions,
+		rates:       make(map[cachedRateKey]cachedRate),
+	}
+}
+
+// GetRate returns the conversion rate between two currencies, looking it up
+// in the wrapped Conversions only the first time the pair is requested.
+func (c *CachedConversions) GetRate(from string, to string) (float64, error) {
+	key := cachedRateKey{from: from, to: to}
+	if cached, ok := c.rates[key]; ok {
+		return cached.rate, cached.err
+	}
+
+	rate, err := c.conversions.GetRate(from, to)
+	c.rates[key] = cachedRate{rate: rate, err: err}
+	return rate, err
+}
+
+// GetRates returns the rates of the wrapped Conversions
+func (c *CachedConversions) GetRates() *map[string]map[string]float64 {
+	return c.conversions.GetRates()
+}
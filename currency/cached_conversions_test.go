@@ -0,0 +1,81 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingConversions counts the rate lookups delegated to the wrapped Conversions.
+type countingConversions struct {
+	Conversions
+	calls int
+}
+
+func (c *countingConversions) GetRate(from string, to string) (float64, error) {
+	c.calls++
+	return c.Conversions.GetRate(from, to)
+}
+
+func TestCachedGetRate(t *testing.T) {
+	rates := &countingConversions{Conversions: NewRates(map[string]map[string]float64{
+		"USD": {"GBP": 0.8, "EUR": 0.9},
+	})}
+	cachedConversions := NewCachedConversions(rates)
+
+	testCases := []struct {
+		desc          string
+		from          string
+		to            string
+		expectedRate  float64
+		expectedError bool
+		expectedCalls int
+	}{
+		{desc: "first lookup delegated", from: "USD", to: "GBP", expectedRate: 0.8, expectedCalls: 1},
+		{desc: "repeated lookup cached", from: "USD", to: "GBP", expectedRate: 0.8, expectedCalls: 1},
+		{desc: "lookup of other pair delegated", from: "USD", to: "EUR", expectedRate: 0.9, expectedCalls: 2},
+		{desc: "lookup of missing rate delegated", from: "GBP", to: "MXN", expectedError: true, expectedCalls: 3},
+		{desc: "repeated lookup of missing rate cached", from: "GBP", to: "MXN", expectedError: true, expectedCalls: 3},
+	}
+
+	for _, test := range testCases {
+		rate, err := cachedConversions.GetRate(test.from, test.to)
+
+		if test.expectedError {
+			assert.Error(t, err, test.desc)
+		} else {
+			assert.NoError(t, err, test.desc)
+		}
+		assert.Equal(t, test.expectedRate, rate, test.desc)
+		assert.Equal(t, test.expectedCalls, rates.calls, test.desc)
+	}
+}
+
+func TestCachedGetRates(t *testing.T) {
+	rates := NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}})
+
+	assert.Equal(t, rates.GetRates(), NewCachedConversions(rates).GetRates())
+}
+
+// BenchmarkGetRate compares repeated lookups of the same currency pair with and without the cache.
+func BenchmarkGetRate(b *testing.B) {
+	rates := NewAggregateConversions(
+		NewRates(map[string]map[string]float64{"USD": {"EUR": 0.9}}),
+		NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8, "EUR": 0.95}}),
+	)
+	conversions := map[string]Conversions{
+		"uncached": rates,
+		"cached":   NewCachedConversions(rates),
+	}
+
+	for name, conversion := range conversions {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if _, err := conversion.GetRate("GBP", "USD"); err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		})
+	}
+}
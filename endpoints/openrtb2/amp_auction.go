@@ -80,6 +80,8 @@ func NewAmpEndpoint(
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(hookExecutionPlanBuilder, hookexecution.EndpointAmp, metricsEngine)
+	hookExecutor.SetMaxBodyBytes(cfg.Hooks.MaxBodyBytes)
+	hookExecutor.SetLogging(cfg.Hooks.Logging)
 
 	return httprouter.Handle((&endpointDeps{
 		uuidGenerator,
@@ -149,7 +151,14 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 
 	// There is no body for AMP requests, so we pass a nil body and ignore the return value.
-	_, rejectErr := deps.hookExecutor.ExecuteEntrypointStage(r, nilBody)
+	_, cachedResponse, rejectErr := deps.hookExecutor.ExecuteEntrypointStage(r, nilBody)
+	if cachedResponse != nil {
+		ao.HookExecutionOutcome = deps.hookExecutor.GetOutcomes()
+		ao.HookExecutionSummary = deps.hookExecutor.GetOutcomesSummary()
+		ao.Status = writeHookHTTPResponse(w, cachedResponse.HTTPResponse)
+		return
+	}
+
 	reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, errL := deps.parseAmpRequest(r)
 	ao.Errors = append(ao.Errors, errL...)
 	// Process reject after parsing amp request, so we can use reqWrapper.
@@ -218,6 +227,28 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
+	// There is no body for AMP requests, so we pass a nil body and ignore the return value.
+	deps.hookExecutor.SetAccount(account)
+	if _, rejectErr := deps.hookExecutor.ExecuteEntrypointAccountStage(r, nilBody); rejectErr != nil {
+		labels, ao = rejectAmpRequest(*rejectErr, w, deps.hookExecutor, reqWrapper, account, labels, ao, errL)
+		return
+	}
+
+	// parse the request once more in case the AMP params were changed by the entrypoint account hooks
+	if hasPayloadUpdatesAt(hooks.StageEntrypointAccount.String(), deps.hookExecutor.GetOutcomes()) {
+		reqWrapper, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, errL = deps.parseAmpRequest(r)
+		ao.Errors = append(ao.Errors, errL...)
+		if errortypes.ContainsFatalError(errL) {
+			w.WriteHeader(http.StatusBadRequest)
+			for _, err := range errortypes.FatalOnly(errL) {
+				w.Write([]byte(fmt.Sprintf("Invalid request: %s\n", err.Error())))
+			}
+			labels.RequestStatus = metrics.RequestStatusBadInput
+			return
+		}
+		ao.Request = reqWrapper.BidRequest
+	}
+
 	secGPC := r.Header.Get("Sec-GPC")
 
 	auctionRequest := exchange.AuctionRequest{
@@ -276,6 +307,14 @@ func rejectAmpRequest(
 	ao analytics.AmpObject,
 	errs []error,
 ) (metrics.Labels, analytics.AmpObject) {
+	if rejectErr.HTTPResponse != nil {
+		ao.Errors = append(ao.Errors, rejectErr)
+		ao.HookExecutionOutcome = hookExecutor.GetOutcomes()
+		ao.HookExecutionSummary = hookExecutor.GetOutcomesSummary()
+		ao.Status = writeHookHTTPResponse(w, rejectErr.HTTPResponse)
+		return labels, ao
+	}
+
 	response := &openrtb2.BidResponse{NBR: openrtb3.NoBidReason(rejectErr.NBR).Ptr()}
 	ao.AuctionResponse = response
 	ao.Errors = append(ao.Errors, rejectErr)
@@ -391,6 +430,7 @@ func getExtBidResponse(
 
 		stageOutcomes := hookExecutor.GetOutcomes()
 		ao.HookExecutionOutcome = stageOutcomes
+		ao.HookExecutionSummary = hookExecutor.GetOutcomesSummary()
 		modules, warns, err := hookexecution.GetModulesJSON(stageOutcomes, reqWrapper.BidRequest, account)
 		if err != nil {
 			err := fmt.Errorf("Failed to get modules outcome: %s", err)
@@ -1982,6 +1982,11 @@ func TestValidAmpResponseWhenRequestRejected(t *testing.T) {
 			file:        "sample-requests/hooks/amp_entrypoint_reject.json",
 			planBuilder: mockPlanBuilder{entrypointPlan: makePlan[hookstage.Entrypoint](mockRejectionHook{nbr})},
 		},
+		{
+			description: "Assert correct AmpResponse when request rejected at entrypoint_account stage",
+			file:        "sample-requests/hooks/amp_entrypoint_account_reject.json",
+			planBuilder: mockPlanBuilder{entrypointPlan: makePlan[hookstage.Entrypoint](mockAccountRejectionHook{nbr})},
+		},
 		{
 			// raw_auction stage not executed for AMP endpoint, so we expect full response
 			description: "Assert correct AmpResponse when request rejected at raw_auction stage",
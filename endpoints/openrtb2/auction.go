@@ -34,6 +34,7 @@ import (
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange"
 	"github.com/prebid/prebid-server/hooks/hookexecution"
+	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/prebid/prebid-server/prebid_cache_client"
@@ -98,6 +99,8 @@ func NewEndpoint(
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(hookExecutionPlanBuilder, hookexecution.EndpointAuction, metricsEngine)
+	hookExecutor.SetMaxBodyBytes(cfg.Hooks.MaxBodyBytes)
+	hookExecutor.SetLogging(cfg.Hooks.Logging)
 
 	return httprouter.Handle((&endpointDeps{
 		uuidGenerator,
@@ -171,7 +174,14 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 
-	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, errL := deps.parseRequest(r, &labels)
+	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, cachedResponse, errL := deps.parseRequest(r, &labels)
+	if cachedResponse != nil {
+		ao.HookExecutionOutcome = deps.hookExecutor.GetOutcomes()
+		ao.HookExecutionSummary = deps.hookExecutor.GetOutcomesSummary()
+		ao.Status = writeHookHTTPResponse(w, cachedResponse.HTTPResponse)
+		return
+	}
+
 	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &labels) {
 		return
 	}
@@ -260,6 +270,14 @@ func rejectAuctionRequest(
 	labels metrics.Labels,
 	ao analytics.AuctionObject,
 ) (metrics.Labels, analytics.AuctionObject) {
+	if rejectErr.HTTPResponse != nil {
+		ao.Errors = append(ao.Errors, rejectErr)
+		ao.HookExecutionOutcome = hookExecutor.GetOutcomes()
+		ao.HookExecutionSummary = hookExecutor.GetOutcomesSummary()
+		ao.Status = writeHookHTTPResponse(w, rejectErr.HTTPResponse)
+		return labels, ao
+	}
+
 	response := &openrtb2.BidResponse{NBR: openrtb3.NoBidReason(rejectErr.NBR).Ptr()}
 	if request != nil {
 		response.ID = request.ID
@@ -271,6 +289,30 @@ func rejectAuctionRequest(
 	return sendAuctionResponse(w, hookExecutor, response, request, account, labels, ao)
 }
 
+// writeHookHTTPResponse writes the custom response provided by the hook that rejected request
+// or served a cached response at the entrypoint stage and returns the written status code.
+// The response replaces the OpenRTB one, so it is sent as JSON unless the hook sets another Content-Type.
+func writeHookHTTPResponse(w http.ResponseWriter, resp *hookstage.HTTPResponse) int {
+	status := resp.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	for name, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	w.WriteHeader(status)
+	w.Write(resp.Body)
+
+	return status
+}
+
 func sendAuctionResponse(
 	w http.ResponseWriter,
 	hookExecutor hookexecution.HookStageExecutor,
@@ -285,6 +327,7 @@ func sendAuctionResponse(
 	if response != nil {
 		stageOutcomes := hookExecutor.GetOutcomes()
 		ao.HookExecutionOutcome = stageOutcomes
+		ao.HookExecutionSummary = hookExecutor.GetOutcomesSummary()
 
 		ext, warns, err := hookexecution.EnrichExtBidResponse(response.Ext, stageOutcomes, request, account)
 		if err != nil {
@@ -298,6 +341,15 @@ func sendAuctionResponse(
 		if len(warns) > 0 {
 			ao.Errors = append(ao.Errors, warns...)
 		}
+
+		participation := hookexecution.GetBidderParticipation(stageOutcomes, request, response)
+		if ext, err := hookexecution.EnrichExtBidResponseByParticipation(response.Ext, participation, account); err != nil {
+			err = fmt.Errorf("Failed to enrich Bid Response with conditional ext: %s", err)
+			glog.Errorf(err.Error())
+			ao.Errors = append(ao.Errors, err)
+		} else {
+			response.Ext = ext
+		}
 	}
 
 	// Fixes #231
@@ -327,7 +379,7 @@ func sendAuctionResponse(
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, cachedResponse *hookexecution.CachedResponse, errs []error) {
 	req = &openrtb_ext.RequestWrapper{}
 	req.BidRequest = &openrtb2.BidRequest{}
 	errs = nil
@@ -350,7 +402,10 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 		}
 	}
 
-	requestJson, rejectErr := deps.hookExecutor.ExecuteEntrypointStage(httpRequest, requestJson)
+	requestJson, cachedResponse, rejectErr := deps.hookExecutor.ExecuteEntrypointStage(httpRequest, requestJson)
+	if cachedResponse != nil {
+		return
+	}
 	if rejectErr != nil {
 		errs = []error{rejectErr}
 		if err = json.Unmarshal(requestJson, req.BidRequest); err != nil {
@@ -365,7 +420,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 
 	impInfo, errs := parseImpInfo(requestJson)
 	if len(errs) > 0 {
-		return nil, nil, nil, nil, nil, nil, errs
+		return nil, nil, nil, nil, nil, nil, nil, errs
 	}
 
 	storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs := deps.getStoredRequests(ctx, requestJson, impInfo)
@@ -394,7 +449,16 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	}
 
 	deps.hookExecutor.SetAccount(account)
-	requestJson, rejectErr = deps.hookExecutor.ExecuteRawAuctionStage(requestJson)
+	requestJson, rejectErr = deps.hookExecutor.ExecuteEntrypointAccountStage(httpRequest, requestJson)
+	if rejectErr != nil {
+		errs = []error{rejectErr}
+		if err = json.Unmarshal(requestJson, req.BidRequest); err != nil {
+			glog.Errorf("Failed to unmarshal BidRequest during entrypoint account stage rejection: %s", err)
+		}
+		return
+	}
+
+	requestJson, rejectErr = deps.hookExecutor.ExecuteRawAuctionStage(httpRequest.Header, requestJson)
 	if rejectErr != nil {
 		errs = []error{rejectErr}
 		if err = json.Unmarshal(requestJson, req.BidRequest); err != nil {
@@ -403,11 +467,12 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 		return
 	}
 
-	// retrieve storedRequests and storedImps once more in case stored data was changed by the raw auction hook
-	if hasPayloadUpdatesAt(hooks.StageRawAuctionRequest.String(), deps.hookExecutor.GetOutcomes()) {
+	// retrieve storedRequests and storedImps once more in case stored data was changed by the entrypoint account or raw auction hooks
+	outcomes := deps.hookExecutor.GetOutcomes()
+	if hasPayloadUpdatesAt(hooks.StageEntrypointAccount.String(), outcomes) || hasPayloadUpdatesAt(hooks.StageRawAuctionRequest.String(), outcomes) {
 		impInfo, errs = parseImpInfo(requestJson)
 		if len(errs) > 0 {
-			return nil, nil, nil, nil, nil, nil, errs
+			return nil, nil, nil, nil, nil, nil, nil, errs
 		}
 		storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs = deps.getStoredRequests(ctx, requestJson, impInfo)
 		if len(errs) > 0 {
@@ -423,7 +488,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	//Stored auction responses should be processed after stored requests due to possible impression modification
 	storedAuctionResponses, storedBidResponses, bidderImpReplaceImpId, errs = stored_responses.ProcessStoredResponses(ctx, requestJson, deps.storedRespFetcher, deps.bidderMap)
 	if len(errs) > 0 {
-		return nil, nil, nil, nil, nil, nil, errs
+		return nil, nil, nil, nil, nil, nil, nil, errs
 	}
 
 	if err := json.Unmarshal(requestJson, req.BidRequest); err != nil {
@@ -686,6 +751,10 @@ func (deps *endpointDeps) validateRequest(req *openrtb_ext.RequestWrapper, isAmp
 			return []error{err}
 		}
 
+		if err := deps.validateBidAdjustmentFactorsByCur(reqPrebid.BidAdjustmentFactorsByCur, aliases); err != nil {
+			return []error{err}
+		}
+
 		if err := validateSChains(reqPrebid.SChains); err != nil {
 			return []error{err}
 		}
@@ -850,6 +919,22 @@ func (deps *endpointDeps) validateBidAdjustmentFactors(adjustmentFactors map[str
 	return nil
 }
 
+func (deps *endpointDeps) validateBidAdjustmentFactorsByCur(adjustmentFactors map[string]map[string]float64, aliases map[string]string) error {
+	for bidderToAdjust, curAdjustmentFactors := range adjustmentFactors {
+		if _, isBidder := deps.bidderMap[bidderToAdjust]; !isBidder {
+			if _, isAlias := aliases[bidderToAdjust]; !isAlias {
+				return fmt.Errorf("request.ext.prebid.bidadjustmentfactorsbycur.%s is not a known bidder or alias", bidderToAdjust)
+			}
+		}
+		for cur, adjustmentFactor := range curAdjustmentFactors {
+			if adjustmentFactor <= 0 {
+				return fmt.Errorf("request.ext.prebid.bidadjustmentfactorsbycur.%s.%s must be a positive number. Got %f", bidderToAdjust, cur, adjustmentFactor)
+			}
+		}
+	}
+	return nil
+}
+
 func validateSChains(sChains []*openrtb_ext.ExtRequestPrebidSChain) error {
 	_, err := schain.BidderToPrebidSChains(sChains)
 	return err
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -3453,7 +3454,7 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
 
-	resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
+	resReq, impExtInfoMap, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
 
 	assert.Nil(t, resReq, "Result request should be nil due to incorrect imp")
 	assert.Nil(t, impExtInfoMap, "Impression info map should be nil due to incorrect imp")
@@ -4028,7 +4029,7 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
+			resReq, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -4131,7 +4132,7 @@ func TestParseRequestStoredResponses(t *testing.T) {
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			_, _, storedResponses, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
+			_, _, storedResponses, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
 
 			if test.expectedErrorCount == 0 {
 				assert.Equal(t, test.expectedStoredResponses, storedResponses, "stored responses should match")
@@ -4218,7 +4219,7 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 			}
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
-			_, _, _, storedBidResponses, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
+			_, _, _, storedBidResponses, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{})
 
 			if test.expectedErrorCount == 0 {
 				assert.Equal(t, test.expectedStoredBidResponses, storedBidResponses, "stored responses should match")
@@ -4916,6 +4917,179 @@ func TestValidResponseAfterExecutingStages(t *testing.T) {
 	}
 }
 
+func TestAuctionWithHookHTTPResponse(t *testing.T) {
+	file := "sample-requests/hooks/auction_entrypoint_reject.json"
+	fileData, err := os.ReadFile(file)
+	assert.NoError(t, err, "Failed to read test file.")
+
+	test, err := parseTestFile(fileData, file)
+	assert.NoError(t, err, "Failed to parse test file.")
+	test.planBuilder = mockPlanBuilder{entrypointPlan: makePlan[hookstage.Entrypoint](mockHTTPResponseRejectionHook{
+		nbr:      123,
+		response: hookstage.HTTPResponse{StatusCode: http.StatusForbidden, Body: []byte(`{"error":"blocked"}`)},
+	})}
+	test.endpointType = OPENRTB_ENDPOINT
+
+	cfg := &config.Configuration{MaxRequestSize: maxSize, AccountDefaults: config.Account{DebugAllow: true}}
+	auctionEndpointHandler, _, mockBidServers, mockCurrencyRatesServer, err := buildTestEndpoint(test, cfg)
+	assert.NoError(t, err, "Failed to build test endpoint.")
+	defer func() {
+		for _, mockBidServer := range mockBidServers {
+			mockBidServer.Close()
+		}
+		mockCurrencyRatesServer.Close()
+	}()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(test.BidRequest))
+	auctionEndpointHandler(recorder, req, nil)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code, "Hook provided status code expected.")
+	assert.Equal(t, `{"error":"blocked"}`, recorder.Body.String(), "Hook provided body expected.")
+}
+
+func TestAuctionWithHookCachedResponse(t *testing.T) {
+	file := "sample-requests/hooks/auction_entrypoint_reject.json"
+	fileData, err := os.ReadFile(file)
+	assert.NoError(t, err, "Failed to read test file.")
+
+	test, err := parseTestFile(fileData, file)
+	assert.NoError(t, err, "Failed to parse test file.")
+	test.planBuilder = mockPlanBuilder{entrypointPlan: makePlan[hookstage.Entrypoint](mockCachedResponseHook{
+		response: hookstage.HTTPResponse{Body: []byte(`{"id":"cached"}`)},
+	})}
+	test.endpointType = OPENRTB_ENDPOINT
+
+	cfg := &config.Configuration{MaxRequestSize: maxSize, AccountDefaults: config.Account{DebugAllow: true}}
+	auctionEndpointHandler, _, mockBidServers, mockCurrencyRatesServer, err := buildTestEndpoint(test, cfg)
+	assert.NoError(t, err, "Failed to build test endpoint.")
+	defer func() {
+		for _, mockBidServer := range mockBidServers {
+			mockBidServer.Close()
+		}
+		mockCurrencyRatesServer.Close()
+	}()
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(test.BidRequest))
+	auctionEndpointHandler(recorder, req, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, "Cached response should be served with OK status.")
+	assert.Equal(t, `{"id":"cached"}`, recorder.Body.String(), "Hook provided cached body expected.")
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"), "Cached response should be served as JSON.")
+}
+
+func TestWriteHookHTTPResponse(t *testing.T) {
+	testCases := []struct {
+		description         string
+		givenResponse       hookstage.HTTPResponse
+		expectedStatus      int
+		expectedContentType string
+		expectedHeader      string
+	}{
+		{
+			description:         "Default status and content type",
+			givenResponse:       hookstage.HTTPResponse{Body: []byte(`{}`)},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			description: "Hook provided status and headers",
+			givenResponse: hookstage.HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       []byte("blocked"),
+				Header:     http.Header{"Content-Type": []string{"text/plain"}, "X-Cache": []string{"hit"}},
+			},
+			expectedStatus:      http.StatusForbidden,
+			expectedContentType: "text/plain",
+			expectedHeader:      "hit",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			status := writeHookHTTPResponse(recorder, &test.givenResponse)
+
+			assert.Equal(t, test.expectedStatus, status, "Incorrect returned status.")
+			assert.Equal(t, test.expectedStatus, recorder.Code, "Incorrect written status.")
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"), "Incorrect Content-Type.")
+			assert.Equal(t, test.expectedHeader, recorder.Header().Get("X-Cache"), "Incorrect hook provided header.")
+			assert.Equal(t, test.givenResponse.Body, recorder.Body.Bytes(), "Incorrect body.")
+		})
+	}
+}
+
+func TestAuctionWithEntrypointHookWarnings(t *testing.T) {
+	const bidRequest = `{"id":"some-request-id","site":{"page":"prebid.org"},"imp":[{"id":"some-impression-id","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}],"tmax":500%s}`
+
+	testCases := []struct {
+		description      string
+		givenDebug       string
+		givenAccount     config.Account
+		expectedWarnings json.RawMessage
+	}{
+		{
+			description:      "Entrypoint warning added to response in debug mode",
+			givenDebug:       `,"test":1`,
+			givenAccount:     config.Account{DebugAllow: true},
+			expectedWarnings: json.RawMessage(`{"foobar":{"foo":["deprecated parameter used"]}}`),
+		},
+		{
+			description:      "Entrypoint warning added to response if account always includes warnings",
+			givenAccount:     config.Account{DebugAllow: true, Hooks: config.AccountHooks{AlwaysIncludeWarnings: true}},
+			expectedWarnings: json.RawMessage(`{"foobar":{"foo":["deprecated parameter used"]}}`),
+		},
+		{
+			description:  "Entrypoint warning not added to response if debug disabled",
+			givenAccount: config.Account{DebugAllow: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			file := "sample-requests/hooks/auction.json"
+			fileData, err := os.ReadFile(file)
+			assert.NoError(t, err, "Failed to read test file.")
+
+			test, err := parseTestFile(fileData, file)
+			assert.NoError(t, err, "Failed to parse test file.")
+			test.BidRequest = []byte(fmt.Sprintf(bidRequest, tc.givenDebug))
+			test.planBuilder = mockPlanBuilder{entrypointPlan: makePlan[hookstage.Entrypoint](mockUpdateHook{
+				entrypointHandler: func(_ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+					return hookstage.HookResult[hookstage.EntrypointPayload]{Warnings: []string{"deprecated parameter used"}}, nil
+				},
+			})}
+			test.endpointType = OPENRTB_ENDPOINT
+
+			cfg := &config.Configuration{MaxRequestSize: maxSize, AccountDefaults: tc.givenAccount}
+			auctionEndpointHandler, _, mockBidServers, mockCurrencyRatesServer, err := buildTestEndpoint(test, cfg)
+			assert.NoError(t, err, "Failed to build test endpoint.")
+			defer func() {
+				for _, mockBidServer := range mockBidServers {
+					mockBidServer.Close()
+				}
+				mockCurrencyRatesServer.Close()
+			}()
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(test.BidRequest))
+			auctionEndpointHandler(recorder, req, nil)
+			assert.Equal(t, http.StatusOK, recorder.Code, "Endpoint should return 200 OK.")
+
+			var actualResp openrtb2.BidResponse
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actualResp), "Unable to unmarshal actual BidResponse.")
+			warnings, _, _, err := jsonparser.Get(actualResp.Ext, "prebid", "modules", "warnings")
+			if tc.expectedWarnings == nil {
+				assert.Equal(t, jsonparser.KeyPathNotFoundError, err, "Module warnings not expected.")
+			} else if assert.NoError(t, err, "Module warnings expected.") {
+				assert.JSONEq(t, string(tc.expectedWarnings), string(warnings))
+			}
+		})
+	}
+}
+
 func TestSendAuctionResponse_LogsErrors(t *testing.T) {
 	hookExecutor := &mockStageExecutor{
 		outcomes: []hookexecution.StageOutcome{
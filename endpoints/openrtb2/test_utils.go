@@ -1205,7 +1205,7 @@ func buildTestExchange(testCfg *testConfigValues, adapterMap map[openrtb_ext.Bid
 		bidderAdapter := mockAdapter{mockServerURL: bidServer.URL}
 		bidderName := openrtb_ext.BidderName(mockBidder.BidderName)
 
-		adapterMap[bidderName] = exchange.AdaptBidder(bidderAdapter, bidServer.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, bidderName, nil, "")
+		adapterMap[bidderName] = exchange.AdaptBidder(bidderAdapter, bidServer.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, bidderName, config.BidderInfo{})
 		mockBidServersArray = append(mockBidServersArray, bidServer)
 	}
 
@@ -1443,6 +1443,7 @@ type mockPlanBuilder struct {
 	rawAuctionPlan               hooks.Plan[hookstage.RawAuctionRequest]
 	processedAuctionPlan         hooks.Plan[hookstage.ProcessedAuctionRequest]
 	bidderRequestPlan            hooks.Plan[hookstage.BidderRequest]
+	bidderHttpRequestPlan        hooks.Plan[hookstage.BidderHttpRequest]
 	rawBidderResponsePlan        hooks.Plan[hookstage.RawBidderResponse]
 	allProcessedBidResponsesPlan hooks.Plan[hookstage.AllProcessedBidResponses]
 	auctionResponsePlan          hooks.Plan[hookstage.AuctionResponse]
@@ -1464,6 +1465,10 @@ func (m mockPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account)
 	return m.bidderRequestPlan
 }
 
+func (m mockPlanBuilder) PlanForBidderHttpRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderHttpRequest] {
+	return m.bidderHttpRequestPlan
+}
+
 func (m mockPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
 	return m.rawBidderResponsePlan
 }
@@ -1491,6 +1496,51 @@ func makePlan[H any](hook H) hooks.Plan[H] {
 	}
 }
 
+type mockHTTPResponseRejectionHook struct {
+	nbr      int
+	response hookstage.HTTPResponse
+}
+
+func (m mockHTTPResponseRejectionHook) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{Reject: true, NbrCode: m.nbr, HTTPResponse: &m.response}, nil
+}
+
+type mockCachedResponseHook struct {
+	response hookstage.HTTPResponse
+}
+
+func (m mockCachedResponseHook) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{CachedResponse: &m.response}, nil
+}
+
+type mockAccountRejectionHook struct {
+	nbr int
+}
+
+func (m mockAccountRejectionHook) HandleEntrypointHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{}, nil
+}
+
+func (m mockAccountRejectionHook) HandleEntrypointAccountHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.EntrypointPayload,
+) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{Reject: true, NbrCode: m.nbr}, nil
+}
+
 type mockRejectionHook struct {
 	nbr int
 }
@@ -24,6 +24,12 @@ const (
 	BidderLevelDebugDisabledWarningCode
 	DisabledCurrencyConversionWarningCode
 	AlternateBidderCodeWarningCode
+	NormalizedCurrencyWarningCode
+	InvalidBidImpIDWarningCode
+	MaxBidCPMExceededWarningCode
+	InvalidCurrencyWarningCode
+	BidBelowFloorWarningCode
+	DisallowedBidderWarningCode
 )
 
 // Coder provides an error or warning code with severity.
@@ -3,11 +3,13 @@ package exchange
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/version"
 )
 
 func BuildAdapters(client *http.Client, cfg *config.Configuration, infos config.BidderInfos, me metrics.MetricsEngine) (map[openrtb_ext.BidderName]AdaptedBidder, []error) {
@@ -21,13 +23,36 @@ func BuildAdapters(client *http.Client, cfg *config.Configuration, infos config.
 	exchangeBidders := make(map[openrtb_ext.BidderName]AdaptedBidder, len(bidders))
 	for bidderName, bidder := range bidders {
 		info := infos[string(bidderName)]
-		exchangeBidder := AdaptBidder(bidder, client, cfg, me, bidderName, info.Debug, info.EndpointCompression)
+		exchangeBidder := AdaptBidder(bidder, client, cfg, me, bidderName, info)
 		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder)
 		exchangeBidders[bidderName] = exchangeBidder
 	}
 	return exchangeBidders, nil
 }
 
+// bidderEndpointCompression returns the compression of the requests sent to the bidder. The explicitly
+// configured compression takes precedence, otherwise gzip is used if declared in the bidder's capabilities.
+func bidderEndpointCompression(info config.BidderInfo) string {
+	if info.EndpointCompression != "" {
+		return info.EndpointCompression
+	}
+	if info.Capabilities != nil && info.Capabilities.Gzip {
+		return Gzip
+	}
+	return ""
+}
+
+// bidderUserAgent returns the User-Agent header sent to the bidder, empty if the header is disabled.
+func bidderUserAgent(userAgent string) string {
+	if strings.EqualFold(userAgent, "none") {
+		return ""
+	}
+	if userAgent == "" {
+		return version.BuildUserAgent(version.Ver)
+	}
+	return userAgent
+}
+
 func buildBidders(infos config.BidderInfos, builders map[openrtb_ext.BidderName]adapters.Builder, server config.Server) (map[openrtb_ext.BidderName]adapters.Bidder, []error) {
 	bidders := make(map[openrtb_ext.BidderName]adapters.Bidder)
 	var errs []error
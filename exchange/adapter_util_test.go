@@ -26,14 +26,19 @@ func TestBuildAdapters(t *testing.T) {
 
 	appnexusBidder, _ := appnexus.Builder(openrtb_ext.BidderAppnexus, config.Adapter{}, config.Server{})
 	appnexusBidderWithInfo := adapters.BuildInfoAwareBidder(appnexusBidder, infoEnabled)
-	appnexusBidderAdapted := AdaptBidder(appnexusBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderAppnexus, nil, "")
+	appnexusBidderAdapted := AdaptBidder(appnexusBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderAppnexus, config.BidderInfo{UserAgent: "prebid-server/unknown"})
 	appnexusValidated := addValidatedBidderMiddleware(appnexusBidderAdapted)
 
 	rubiconBidder, _ := rubicon.Builder(openrtb_ext.BidderRubicon, config.Adapter{}, config.Server{})
 	rubiconBidderWithInfo := adapters.BuildInfoAwareBidder(rubiconBidder, infoEnabled)
-	rubiconBidderAdapted := AdaptBidder(rubiconBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderRubicon, nil, "")
+	rubiconBidderAdapted := AdaptBidder(rubiconBidderWithInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderRubicon, config.BidderInfo{UserAgent: "prebid-server/unknown"})
 	rubiconBidderValidated := addValidatedBidderMiddleware(rubiconBidderAdapted)
 
+	infoGzip := config.BidderInfo{Capabilities: &config.CapabilitiesInfo{Site: &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}}, Gzip: true}}
+	appnexusBidderWithGzipInfo := adapters.BuildInfoAwareBidder(appnexusBidder, infoGzip)
+	appnexusBidderGzipAdapted := AdaptBidder(appnexusBidderWithGzipInfo, client, &config.Configuration{}, metricEngine, openrtb_ext.BidderAppnexus, config.BidderInfo{EndpointCompression: Gzip, UserAgent: "prebid-server/unknown"})
+	appnexusGzipValidated := addValidatedBidderMiddleware(appnexusBidderGzipAdapted)
+
 	testCases := []struct {
 		description     string
 		bidderInfos     map[string]config.BidderInfo
@@ -60,6 +65,13 @@ func TestBuildAdapters(t *testing.T) {
 				openrtb_ext.BidderRubicon:  rubiconBidderValidated,
 			},
 		},
+		{
+			description: "Compression Derived From Capabilities",
+			bidderInfos: map[string]config.BidderInfo{"appnexus": infoGzip},
+			expectedBidders: map[openrtb_ext.BidderName]AdaptedBidder{
+				openrtb_ext.BidderAppnexus: appnexusGzipValidated,
+			},
+		},
 		{
 			description: "Invalid - Builder Errors",
 			bidderInfos: map[string]config.BidderInfo{"unknown": {}, "appNexus": {}},
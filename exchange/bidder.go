@@ -3,14 +3,17 @@ package exchange
 import (
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/http/httptrace"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -65,30 +68,131 @@ type bidRequestOptions struct {
 	headerDebugAllowed  bool
 	addCallSignHeader   bool
 	bidAdjustments      map[string]float64
+	// bidAdjustmentsByCur holds optional adjustment factors keyed by bidder and then by bid response currency
+	bidAdjustmentsByCur map[string]map[string]float64
+	// disableStoredRespImpIdReplacement turns off restoring imp ids of stored bid responses globally,
+	// when true the per-impression BidderRequest.ImpReplaceImpId flags are ignored,
+	// otherwise imp ids are replaced only for impressions with the flag set.
+	disableStoredRespImpIdReplacement bool
+	// seatSelection defines which seats' bids are retained when several alternate seats bid on the same imp
+	seatSelection seatSelection
+	// maxBidCPM is the highest accepted bid price in the bid request currency, the check is disabled if zero
+	maxBidCPM float64
+	// enforceFloors drops the bids priced below the floor of their imp
+	enforceFloors bool
+	// endpointCompression overrides the compression of the bidder requests configured for the adapter
+	endpointCompression string
+	// defaultCurrency is assumed for the bid request and bid responses not specifying the currency
+	defaultCurrency string
+	// debugBidders limits the debug httpcalls output to the listed bidders, all bidders are included if empty
+	debugBidders []string
+	// preferredCurrency is tried first for the bid conversion if present in the bid request currencies
+	preferredCurrency string
+	// dedupAlternateSeatBids removes bids duplicated across the seats returned by the bidder
+	dedupAlternateSeatBids bool
+	// requestTimeout limits the time of the bidder HTTP requests if shorter than the adapter config timeout,
+	// the check is disabled if not positive
+	requestTimeout time.Duration
+}
+
+// isDebugBidder checks whether the debug output of the bidder is requested, the names are compared case-insensitively.
+func (o bidRequestOptions) isDebugBidder(bidderName string) bool {
+	if len(o.debugBidders) == 0 {
+		return true
+	}
+	for _, debugBidder := range o.debugBidders {
+		if strings.EqualFold(debugBidder, bidderName) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDefaultCurrency returns the configured default currency or USD if none is set.
+func (o bidRequestOptions) getDefaultCurrency() string {
+	if o.defaultCurrency != "" {
+		return o.defaultCurrency
+	}
+	return "USD"
+}
+
+// conversionCurrencies returns the bid request currencies in the order they are tried for the bid conversion.
+// The preferred currency is moved to the front if requested, otherwise the request order is kept.
+func (o bidRequestOptions) conversionCurrencies(requestCurrencies []string) []string {
+	if o.preferredCurrency == "" {
+		return requestCurrencies
+	}
+	for i, cur := range requestCurrencies {
+		if strings.EqualFold(cur, o.preferredCurrency) {
+			currencies := make([]string, 0, len(requestCurrencies))
+			currencies = append(currencies, cur)
+			currencies = append(currencies, requestCurrencies[:i]...)
+			return append(currencies, requestCurrencies[i+1:]...)
+		}
+	}
+	return requestCurrencies
+}
+
+// getBidAdjustmentFactor returns the adjustment factor for the first of the given bidder names having one.
+// For every bidder name the currency-specific factor takes precedence over the flat one.
+// If no factor found, 1.0 is returned.
+func (o bidRequestOptions) getBidAdjustmentFactor(currency string, bidderNames ...string) float64 {
+	for _, bidderName := range bidderNames {
+		if adjustmentFactor, ok := o.bidAdjustmentsByCur[bidderName][currency]; ok {
+			return adjustmentFactor
+		}
+		if adjustmentFactor, ok := o.bidAdjustments[bidderName]; ok {
+			return adjustmentFactor
+		}
+	}
+	return 1.0
 }
 
 const ImpIdReqBody = "Stored bid response for impression id: "
 
 // Possible values of compression types Prebid Server can support for bidder compression
 const (
-	Gzip string = "GZIP"
+	Gzip          string = "GZIP"
+	NoCompression string = "NONE"
 )
 
+// defaultTimeoutNotificationTimeout is used for timeout notifications if no timeout configured
+const defaultTimeoutNotificationTimeout = 200 * time.Millisecond
+
+// defaultMaxResponseBytes limits the size of the bidder response body read if no limit configured for the bidder
+const defaultMaxResponseBytes = 10 * 1024 * 1024
+
 // AdaptBidder converts an adapters.Bidder into an exchange.AdaptedBidder.
 //
 // The name refers to the "Adapter" architecture pattern, and should not be confused with a Prebid "Adapter"
 // (which is being phased out and replaced by Bidder for OpenRTB auctions)
-func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo, endpointCompression string) AdaptedBidder {
+func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, info config.BidderInfo) AdaptedBidder {
+	gzipLevel := gzip.DefaultCompression
+	if info.GzipLevel != nil {
+		gzipLevel = *info.GzipLevel
+	}
+
 	return &bidderAdapter{
 		Bidder:     bidder,
 		BidderName: name,
 		Client:     client,
 		me:         me,
 		config: bidderAdapterConfig{
-			Debug:               cfg.Debug,
-			DisableConnMetrics:  cfg.Metrics.Disabled.AdapterConnectionMetrics,
-			DebugInfo:           config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
-			EndpointCompression: endpointCompression,
+			Debug:                     cfg.Debug,
+			DisableConnMetrics:        cfg.Metrics.Disabled.AdapterConnectionMetrics,
+			ConnMetricsSampleRate:     connMetricsSampleRate(cfg.Metrics),
+			DebugInfo:                 config.DebugInfo{Allow: parseDebugInfo(info.Debug)},
+			EndpointCompression:       bidderEndpointCompression(info),
+			GzipLevel:                 gzipLevel,
+			AllowedResponseCurrencies: info.AllowedResponseCurrencies,
+			ValidateBidImpIds:         cfg.Validations.ValidateBidImpIds,
+			FallbackEndpoint:          info.FallbackEndpoint,
+			MaxConcurrentRequests:     cfg.MaxConcurrentBidderRequests,
+			UserAgent:                 bidderUserAgent(info.UserAgent),
+			MaxResponseBytes:          info.MaxResponseBytes,
+			MaxRequestTimeout:         time.Duration(info.MaxRequestTimeout) * time.Millisecond,
+			NoContentAsNoBid:          cfg.NoContentAsNoBid,
+			GzipProbeRate:             info.GzipProbeRate,
 		},
 	}
 }
@@ -109,10 +213,37 @@ type bidderAdapter struct {
 }
 
 type bidderAdapterConfig struct {
-	Debug               config.Debug
-	DisableConnMetrics  bool
-	DebugInfo           config.DebugInfo
-	EndpointCompression string
+	Debug              config.Debug
+	DisableConnMetrics bool
+	// ConnMetricsSampleRate is the share of requests, in the range (0, 1], for which the connection metrics are collected
+	ConnMetricsSampleRate float64
+	DebugInfo             config.DebugInfo
+	EndpointCompression   string
+	// GzipLevel is the compression level of the GZIP endpoint compression
+	GzipLevel int
+	// AllowedResponseCurrencies lists currencies accepted in bidder responses, any currency is accepted if empty
+	AllowedResponseCurrencies []string
+	// ValidateBidImpIds enables dropping of bids whose imp ID doesn't match any imp of the bidder request
+	ValidateBidImpIds bool
+	// FallbackEndpoint is the endpoint whose scheme and host replace those of a bidder request
+	// retried once after a connection failure, no retry is made if empty
+	FallbackEndpoint string
+	// MaxConcurrentRequests caps the number of HTTP requests of a single bidder invocation sent in parallel,
+	// the requests are not limited if zero
+	MaxConcurrentRequests int
+	// UserAgent is the User-Agent header of the requests not having one set by the adapter, not added if empty
+	UserAgent string
+	// MaxResponseBytes limits the size of the response body read from the bidder,
+	// larger responses are rejected without reading them completely, defaultMaxResponseBytes is used if not positive
+	MaxResponseBytes int64
+	// MaxRequestTimeout limits the time of the bidder HTTP requests below the auction deadline,
+	// the requests are limited by the auction deadline only if zero
+	MaxRequestTimeout time.Duration
+	// NoContentAsNoBid skips the adapter parsing of 204 No Content responses, treating them as no-bids
+	NoContentAsNoBid bool
+	// GzipProbeRate is the share of the requests sent gzip compressed to probe the bidder support of compression
+	// if the endpoint compression is not set, the probe is disabled if zero
+	GzipProbeRate float64
 }
 
 func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor) ([]*entities.PbsOrtbSeatBid, []error) {
@@ -129,6 +260,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 	dataLen := 0
 	if len(bidderRequest.BidRequest.Imp) > 0 {
 		reqData, errs = bidder.Bidder.MakeRequests(bidderRequest.BidRequest, reqInfo)
+		bidder.me.RecordAdapterRequestFanout(bidder.BidderName, len(reqData))
 
 		if len(reqData) == 0 {
 			// If the adapter failed to generate both requests and errors, this is an error.
@@ -146,9 +278,25 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 				reqData[i].Headers = http.Header{}
 			}
 			reqData[i].Headers.Add("X-Prebid", xPrebidHeader)
+			if bidder.config.UserAgent != "" && reqData[i].Headers.Get("User-Agent") == "" {
+				reqData[i].Headers.Set("User-Agent", bidder.config.UserAgent)
+			}
 			if reqInfo.GlobalPrivacyControlHeader == "1" {
 				reqData[i].Headers.Add("Sec-GPC", reqInfo.GlobalPrivacyControlHeader)
 			}
+		}
+
+		// hooks may rewrite the URI and headers of the requests, so the requests are signed afterwards
+		client, reject := hookExecutor.ExecuteBidderHttpRequestStage(reqData, string(bidderRequest.BidderName))
+		if reject != nil {
+			return nil, append(errs, reject)
+		}
+		// the client provided by hooks is bound to the context of this call, so it's never reused for other auctions
+		if client != nil {
+			ctx = withHTTPClient(ctx, client)
+		}
+
+		for i := 0; i < len(reqData); i++ {
 			if bidRequestOptions.addCallSignHeader {
 				startSignRequestTime := time.Now()
 				signatureMessage, err := adsCertSigner.Sign(reqData[i].Uri, reqData[i].Body)
@@ -169,11 +317,19 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		dataLen = len(reqData) + len(bidderRequest.BidderStoredResponses)
 		responseChannel = make(chan *httpCallInfo, dataLen)
 		if len(reqData) == 1 {
-			responseChannel <- bidder.doRequest(ctx, reqData[0])
+			responseChannel <- bidder.doRequest(ctx, reqData[0], bidRequestOptions.endpointCompression, bidRequestOptions.requestTimeout)
 		} else {
+			var semaphore chan struct{}
+			if limit := bidder.config.MaxConcurrentRequests; limit > 0 && limit < len(reqData) {
+				semaphore = make(chan struct{}, limit)
+			}
 			for _, oneReqData := range reqData {
 				go func(data *adapters.RequestData) {
-					responseChannel <- bidder.doRequest(ctx, data)
+					if semaphore != nil {
+						semaphore <- struct{}{}
+						defer func() { <-semaphore }()
+					}
+					responseChannel <- bidder.doRequest(ctx, data, bidRequestOptions.endpointCompression, bidRequestOptions.requestTimeout)
 				}(oneReqData) // Method arg avoids a race condition on oneReqData
 			}
 		}
@@ -191,7 +347,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		}
 	}
 
-	defaultCurrency := "USD"
+	defaultCurrency := bidRequestOptions.getDefaultCurrency()
 	seatBidMap := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
 		bidderRequest.BidderName: {
 			Bids:      make([]*entities.PbsOrtbBid, 0, dataLen),
@@ -201,34 +357,66 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		},
 	}
 
+	var impsByID map[string]*openrtb2.Imp
+	if bidRequestOptions.enforceFloors {
+		impsByID = make(map[string]*openrtb2.Imp, len(bidderRequest.BidRequest.Imp))
+		for i := range bidderRequest.BidRequest.Imp {
+			impsByID[bidderRequest.BidRequest.Imp[i].ID] = &bidderRequest.BidRequest.Imp[i]
+		}
+	}
+
+	// The responses are processed sequentially, so the rates looked up for the bids and floors of this bidder
+	// can be memoized without locking. The cache could be shared by all the bidders of the auction to save
+	// the identical lookups across bidders as well, but it would have to be made safe for concurrent use.
+	conversions = currency.NewCachedConversions(conversions)
+
 	// If the bidder made multiple requests, we still want them to enter as many bids as possible...
 	// even if the timeout occurs sometime halfway through.
+	// Stop waiting once the context is done, the responses still pending are reported as timeouts.
+	// The response channel is buffered for all the responses, so the abandoned senders don't block.
 	for i := 0; i < dataLen; i++ {
-		httpInfo := <-responseChannel
+		httpInfo, received := receiveHttpCallInfo(ctx, responseChannel)
+		if !received {
+			for pending := dataLen - i; pending > 0; pending-- {
+				errs = append(errs, &errortypes.Timeout{Message: fmt.Sprintf("bidder response not received before the deadline: %s", ctx.Err())})
+			}
+			break
+		}
 		// If this is a test bid, capture debugging info from the requests.
 		// Write debug data to ext in case if:
 		// - headerDebugAllowed (debug override header specified correct) - it overrides all other debug restrictions
 		// - account debug is allowed
 		// - bidder debug is allowed
-		if bidRequestOptions.headerDebugAllowed {
-			seatBidMap[bidderRequest.BidderName].HttpCalls = append(seatBidMap[bidderRequest.BidderName].HttpCalls, makeExt(httpInfo))
-		} else {
-			if bidRequestOptions.accountDebugAllowed {
-				if bidder.config.DebugInfo.Allow {
-					seatBidMap[bidderRequest.BidderName].HttpCalls = append(seatBidMap[bidderRequest.BidderName].HttpCalls, makeExt(httpInfo))
-				} else {
-					debugDisabledWarning := errortypes.Warning{
-						WarningCode: errortypes.BidderLevelDebugDisabledWarningCode,
-						Message:     "debug turned off for bidder",
-					}
-					errs = append(errs, &debugDisabledWarning)
+		// The output is limited to the bidders listed in the request, if any.
+		isDebugBidder := bidRequestOptions.isDebugBidder(bidderRequest.BidderName.String())
+		if isDebugBidder && bidRequestOptions.headerDebugAllowed {
+			seatBidMap[bidderRequest.BidderName].HttpCalls = append(seatBidMap[bidderRequest.BidderName].HttpCalls, makeExts(httpInfo, bidder.config.Debug.RedactedHeaders)...)
+		} else if isDebugBidder && bidRequestOptions.accountDebugAllowed {
+			if bidder.config.DebugInfo.Allow {
+				seatBidMap[bidderRequest.BidderName].HttpCalls = append(seatBidMap[bidderRequest.BidderName].HttpCalls, makeExts(httpInfo, bidder.config.Debug.RedactedHeaders)...)
+			} else {
+				debugDisabledWarning := errortypes.Warning{
+					WarningCode: errortypes.BidderLevelDebugDisabledWarningCode,
+					Message:     "debug turned off for bidder",
 				}
+				errs = append(errs, &debugDisabledWarning)
 			}
 		}
 
+		if httpInfo.err == nil && bidder.isNoBidResponse(httpInfo.response) {
+			bidder.me.RecordNoBidResponse(bidder.BidderName)
+			continue
+		}
+
 		if httpInfo.err == nil {
 			bidResponse, moreErrs := bidder.Bidder.MakeBids(bidderRequest.BidRequest, httpInfo.request, httpInfo.response)
 			errs = append(errs, moreErrs...)
+			for _, err := range errortypes.FatalOnly(moreErrs) {
+				bidder.me.RecordBidderResponseError(bidder.BidderName, errorToMetric(err))
+			}
+			if (bidResponse == nil || len(bidResponse.Bids) == 0) && len(moreErrs) > 0 {
+				bidder.notifyReject(httpInfo.request, moreErrs)
+			}
 
 			if bidResponse != nil {
 				reject := hookExecutor.ExecuteRawBidderResponseStage(bidResponse, string(bidder.BidderName))
@@ -236,7 +424,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 					errs = append(errs, reject)
 					continue
 				}
-				// Setup default currency as `USD` is not set in bid request nor bid response
+				// Setup default currency if not set in bid request nor bid response
 				if bidResponse.Currency == "" {
 					bidResponse.Currency = defaultCurrency
 				}
@@ -244,20 +432,36 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 					bidderRequest.BidRequest.Cur = []string{defaultCurrency}
 				}
 
+				normalizedCurrency, currencyWarning := normalizeCurrency(bidder.BidderName, bidResponse.Currency)
+				if currencyWarning != nil {
+					errs = append(errs, currencyWarning)
+					if normalizedCurrency == "" {
+						continue
+					}
+					bidResponse.Currency = normalizedCurrency
+				}
+
+				if !bidder.isAllowedResponseCurrency(bidResponse.Currency) {
+					errs = append(errs, &errortypes.BadServerResponse{
+						Message: fmt.Sprintf("Bidder response currency %s is not in the list of allowed response currencies", bidResponse.Currency),
+					})
+					continue
+				}
+
 				// Try to get a conversion rate
 				// Try to get the first currency from request.cur having a match in the rate converter,
-				// and use it as currency
+				// and use it as currency, the preferred currency is tried first if requested
 				var conversionRate float64
 				var err error
-				for _, bidReqCur := range bidderRequest.BidRequest.Cur {
+				for _, bidReqCur := range bidRequestOptions.conversionCurrencies(bidderRequest.BidRequest.Cur) {
 					if conversionRate, err = conversions.GetRate(bidResponse.Currency, bidReqCur); err == nil {
 						seatBidMap[bidderRequest.BidderName].Currency = bidReqCur
 						break
 					}
 				}
 
-				// Only do this for request from mobile app
-				if bidderRequest.BidRequest.App != nil {
+				// Only do this for requests from mobile app or site
+				if bidderRequest.BidRequest.App != nil || bidderRequest.BidRequest.Site != nil {
 					for i := 0; i < len(bidResponse.Bids); i++ {
 						if bidResponse.Bids[i].BidType == openrtb_ext.BidTypeNative {
 							nativeMarkup, moreErrs := addNativeTypes(bidResponse.Bids[i].Bid, bidderRequest.BidRequest)
@@ -275,7 +479,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 					}
 				}
 
-				if len(bidderRequest.BidderStoredResponses) > 0 {
+				if len(bidderRequest.BidderStoredResponses) > 0 && !bidRequestOptions.disableStoredRespImpIdReplacement {
 					//set imp ids back to response for bids with stored responses
 					for i := 0; i < len(bidResponse.Bids); i++ {
 						if httpInfo.request.Uri == "" {
@@ -291,6 +495,12 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 					}
 				}
 
+				if bidder.config.ValidateBidImpIds {
+					var invalidImpIdErrs []error
+					bidResponse.Bids, invalidImpIdErrs = bidder.removeBidsWithInvalidImpIds(bidResponse.Bids, bidderRequest)
+					errs = append(errs, invalidImpIdErrs...)
+				}
+
 				if err == nil {
 					// Conversion rate found, using it for conversion
 					for i := 0; i < len(bidResponse.Bids); i++ {
@@ -315,17 +525,33 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 							continue
 						}
 
-						adjustmentFactor := 1.0
-						if givenAdjustment, ok := bidRequestOptions.bidAdjustments[bidderName.String()]; ok {
-							adjustmentFactor = givenAdjustment
-						} else if givenAdjustment, ok := bidRequestOptions.bidAdjustments[bidderRequest.BidderName.String()]; ok {
-							adjustmentFactor = givenAdjustment
-						}
+						adjustmentFactor := bidRequestOptions.getBidAdjustmentFactor(bidResponse.Currency, bidderName.String(), bidderRequest.BidderName.String())
 
 						originalBidCpm := 0.0
 						if bidResponse.Bids[i].Bid != nil {
 							originalBidCpm = bidResponse.Bids[i].Bid.Price
 							bidResponse.Bids[i].Bid.Price = bidResponse.Bids[i].Bid.Price * adjustmentFactor * conversionRate
+
+							if bidRequestOptions.maxBidCPM > 0 && bidResponse.Bids[i].Bid.Price > bidRequestOptions.maxBidCPM {
+								bidder.me.RecordBidValidationMaxCPMError(bidder.BidderName)
+								errs = append(errs, &errortypes.Warning{
+									WarningCode: errortypes.MaxBidCPMExceededWarningCode,
+									Message:     fmt.Sprintf("Bid %s dropped: price %g exceeds the max bid CPM %g", bidResponse.Bids[i].Bid.ID, bidResponse.Bids[i].Bid.Price, bidRequestOptions.maxBidCPM),
+								})
+								continue
+							}
+
+							if bidRequestOptions.enforceFloors {
+								bidCurrency := seatBidMap[bidderRequest.BidderName].Currency
+								if floor, ok := impFloor(impsByID[bidResponse.Bids[i].Bid.ImpID], bidCurrency, conversions); ok && bidResponse.Bids[i].Bid.Price < floor {
+									bidder.me.RecordBidValidationFloorError(bidder.BidderName)
+									errs = append(errs, &errortypes.Warning{
+										WarningCode: errortypes.BidBelowFloorWarningCode,
+										Message:     fmt.Sprintf("Bid %s dropped: price %g %s is below the imp %s floor %g %s", bidResponse.Bids[i].Bid.ID, bidResponse.Bids[i].Bid.Price, bidCurrency, bidResponse.Bids[i].Bid.ImpID, floor, bidCurrency),
+									})
+									continue
+								}
+							}
 						}
 
 						if _, ok := seatBidMap[bidderName]; !ok {
@@ -355,10 +581,23 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 				}
 			}
 		} else {
+			// response is present only if the server responded with a failure status
+			if httpInfo.response != nil {
+				bidder.notifyReject(httpInfo.request, []error{httpInfo.err})
+			}
 			errs = append(errs, httpInfo.err)
 		}
 	}
 
+	if bidRequestOptions.dedupAlternateSeatBids {
+		allowedBidderCodes := alternateBidderCodes.Bidders[bidderRequest.BidderName.String()].AllowedBidderCodes
+		if removed := dedupSeatBids(seatBidMap, bidderRequest.BidderName, allowedBidderCodes); removed > 0 {
+			bidder.me.RecordDuplicateBids(bidder.BidderName, removed)
+		}
+	}
+
+	bidRequestOptions.seatSelection.apply(seatBidMap, bidderRequest.BidderName)
+
 	seatBids := make([]*entities.PbsOrtbSeatBid, 0, len(seatBidMap))
 	for _, seatBid := range seatBidMap {
 		seatBids = append(seatBids, seatBid)
@@ -367,6 +606,137 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 	return seatBids, errs
 }
 
+// receiveHttpCallInfo waits for the next bidder response, it returns false if the context is done first.
+// A response already available is returned even if the context is done.
+func receiveHttpCallInfo(ctx context.Context, responseChannel <-chan *httpCallInfo) (*httpCallInfo, bool) {
+	select {
+	case httpInfo := <-responseChannel:
+		return httpInfo, true
+	default:
+	}
+
+	select {
+	case httpInfo := <-responseChannel:
+		return httpInfo, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// nonStandardCurrencies maps common non-standard currency representations to ISO 4217 codes.
+var nonStandardCurrencies = map[string]string{
+	"$":    "USD",
+	"US$":  "USD",
+	"USD$": "USD",
+	"€":    "EUR",
+	"£":    "GBP",
+	"GB£":  "GBP",
+	"C$":   "CAD",
+	"CA$":  "CAD",
+	"A$":   "AUD",
+	"AU$":  "AUD",
+	"NZ$":  "NZD",
+	"R$":   "BRL",
+	"₹":    "INR",
+}
+
+// normalizeCurrency converts the currency returned by bidder to the ISO 4217 code format.
+// It returns a warning if the currency is normalized. If the result doesn't have the format
+// of the ISO 4217 code, i.e. three uppercase letters, it returns an empty currency and a warning
+// the bids must be dropped. The code isn't checked to be assigned to any currency.
+func normalizeCurrency(bidderName openrtb_ext.BidderName, cur string) (string, *errortypes.Warning) {
+	normalized := strings.ToUpper(strings.TrimSpace(cur))
+	if isoCur, ok := nonStandardCurrencies[normalized]; ok {
+		normalized = isoCur
+	}
+
+	if len(normalized) != 3 || strings.IndexFunc(normalized, func(c rune) bool { return c < 'A' || c > 'Z' }) != -1 {
+		return "", &errortypes.Warning{
+			WarningCode: errortypes.InvalidCurrencyWarningCode,
+			Message:     fmt.Sprintf("Bidder %s responded with invalid currency code %s, bids dropped", bidderName, cur),
+		}
+	}
+
+	if normalized != cur {
+		return normalized, &errortypes.Warning{
+			WarningCode: errortypes.NormalizedCurrencyWarningCode,
+			Message:     fmt.Sprintf("Bidder response currency %s normalized to %s", cur, normalized),
+		}
+	}
+
+	return normalized, nil
+}
+
+// removeBidsWithInvalidImpIds drops the bids whose imp ID doesn't match any imp of the bidder request.
+// Imp IDs of the stored bid responses are recognized as valid, as such imps are not sent to the bidder.
+func (bidder *bidderAdapter) removeBidsWithInvalidImpIds(bids []*adapters.TypedBid, bidderRequest BidderRequest) ([]*adapters.TypedBid, []error) {
+	impIds := make(map[string]struct{}, len(bidderRequest.BidRequest.Imp)+len(bidderRequest.BidderStoredResponses))
+	for _, imp := range bidderRequest.BidRequest.Imp {
+		impIds[imp.ID] = struct{}{}
+	}
+	for impId := range bidderRequest.BidderStoredResponses {
+		impIds[impId] = struct{}{}
+	}
+
+	var errs []error
+	validBids := make([]*adapters.TypedBid, 0, len(bids))
+	for _, bid := range bids {
+		if bid == nil || bid.Bid == nil {
+			validBids = append(validBids, bid)
+			continue
+		}
+		if _, ok := impIds[bid.Bid.ImpID]; !ok {
+			bidder.me.RecordBidValidationImpIDError(bidder.BidderName)
+			errs = append(errs, &errortypes.Warning{
+				WarningCode: errortypes.InvalidBidImpIDWarningCode,
+				Message:     fmt.Sprintf("Bid %s dropped: imp ID %s doesn't match any imp of the bid request", bid.Bid.ID, bid.Bid.ImpID),
+			})
+			continue
+		}
+		validBids = append(validBids, bid)
+	}
+
+	return validBids, errs
+}
+
+// impFloor returns the bid floor of the imp converted to the given currency, the floor currency defaults to USD.
+// False is returned if the imp has no floor or the floor currency cannot be converted, in which case the floor isn't enforced.
+func impFloor(imp *openrtb2.Imp, cur string, conversions currency.Conversions) (float64, bool) {
+	if imp == nil || imp.BidFloor <= 0 {
+		return 0, false
+	}
+
+	floorCur := imp.BidFloorCur
+	if floorCur == "" {
+		floorCur = "USD"
+	}
+	rate, err := conversions.GetRate(floorCur, cur)
+	if err != nil {
+		return 0, false
+	}
+	return imp.BidFloor * rate, true
+}
+
+// isNoBidResponse checks whether the response is a 204 No Content to be treated as a no-bid without parsing.
+func (bidder *bidderAdapter) isNoBidResponse(response *adapters.ResponseData) bool {
+	return bidder.config.NoContentAsNoBid && response != nil && response.StatusCode == http.StatusNoContent
+}
+
+// isAllowedResponseCurrency checks whether the bidder is allowed to respond with the given currency.
+// Any currency is allowed if the bidder does not restrict response currencies.
+func (bidder *bidderAdapter) isAllowedResponseCurrency(cur string) bool {
+	if len(bidder.config.AllowedResponseCurrencies) == 0 {
+		return true
+	}
+
+	for _, allowedCur := range bidder.config.AllowedResponseCurrencies {
+		if strings.EqualFold(allowedCur, cur) {
+			return true
+		}
+	}
+	return false
+}
+
 func addNativeTypes(bid *openrtb2.Bid, request *openrtb2.BidRequest) (*nativeResponse.Response, []error) {
 	var errs []error
 	var nativeMarkup *nativeResponse.Response
@@ -452,20 +822,34 @@ func getAssetByID(id int64, assets []nativeRequests.Asset) (nativeRequests.Asset
 
 var authorizationHeader = http.CanonicalHeaderKey("authorization")
 
-func filterHeader(h http.Header) http.Header {
+// filterHeader returns a copy of the headers without the redacted ones,
+// the Authorization header is always redacted, the configured headers are redacted in addition to it.
+func filterHeader(h http.Header, redactedHeaders []string) http.Header {
 	clone := h.Clone()
 	clone.Del(authorizationHeader)
+	for _, header := range redactedHeaders {
+		clone.Del(header)
+	}
 	return clone
 }
 
+// makeExts returns the debug info of the http call preceded by the info of its previous attempts.
+func makeExts(httpInfo *httpCallInfo, redactedHeaders []string) []*openrtb_ext.ExtHttpCall {
+	var exts []*openrtb_ext.ExtHttpCall
+	if httpInfo != nil && httpInfo.previousAttempt != nil {
+		exts = makeExts(httpInfo.previousAttempt, redactedHeaders)
+	}
+	return append(exts, makeExt(httpInfo, redactedHeaders))
+}
+
 // makeExt transforms information about the HTTP call into the contract class for the PBS response.
-func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
+func makeExt(httpInfo *httpCallInfo, redactedHeaders []string) *openrtb_ext.ExtHttpCall {
 	ext := &openrtb_ext.ExtHttpCall{}
 
 	if httpInfo != nil && httpInfo.request != nil {
 		ext.Uri = httpInfo.request.Uri
 		ext.RequestBody = string(httpInfo.request.Body)
-		ext.RequestHeaders = filterHeader(httpInfo.request.Headers)
+		ext.RequestHeaders = filterHeader(httpInfo.request.Headers, redactedHeaders)
 
 		if httpInfo.err == nil && httpInfo.response != nil {
 			ext.ResponseBody = string(httpInfo.response.Body)
@@ -478,17 +862,102 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
-func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData) *httpCallInfo {
-	return bidder.doRequestImpl(ctx, req, glog.Warningf)
+func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, endpointCompression string, requestTimeout time.Duration) *httpCallInfo {
+	return bidder.doRequestImpl(ctx, req, endpointCompression, requestTimeout, glog.Warningf)
+}
+
+func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, endpointCompression string, requestTimeout time.Duration, logger util.LogMsg) *httpCallInfo {
+	// the child context deadline is the earlier of the auction deadline and the bidder timeout,
+	// exceeding the bidder timeout is handled as the auction timeout
+	if timeout := bidder.requestTimeout(requestTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	httpInfo := bidder.sendRequestWithGzipProbe(ctx, req, endpointCompression, logger)
+	if !httpInfo.connectionFailed || bidder.config.FallbackEndpoint == "" || ctx.Err() != nil {
+		return httpInfo
+	}
+
+	fallbackReq, err := withFallbackEndpoint(req, bidder.config.FallbackEndpoint)
+	if err != nil {
+		logger("Failed to retry request of bidder %s against the fallback endpoint: %v", bidder.BidderName, err)
+		return httpInfo
+	}
+	fallbackInfo := bidder.sendRequest(ctx, fallbackReq, endpointCompression, logger)
+	fallbackInfo.previousAttempt = httpInfo
+	return fallbackInfo
 }
 
-func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg) *httpCallInfo {
+// sendRequestWithGzipProbe sends the request gzip compressed if sampled for the gzip support probe and records
+// whether the bidder accepted it. The request is resent uncompressed if the compressed one is rejected with a 4xx status.
+func (bidder *bidderAdapter) sendRequestWithGzipProbe(ctx context.Context, req *adapters.RequestData, endpointCompression string, logger util.LogMsg) *httpCallInfo {
+	if !bidder.sampleGzipProbe(endpointCompression) {
+		return bidder.sendRequest(ctx, req, endpointCompression, logger)
+	}
+
+	probeReq := *req
+	probeReq.Headers = req.Headers.Clone()
+	if probeReq.Headers == nil {
+		probeReq.Headers = http.Header{}
+	}
+	probeInfo := bidder.sendRequest(ctx, &probeReq, Gzip, logger)
+	// the gzip support can be judged only by the response status, 5xx responses are not related to the compression
+	if probeInfo.response == nil || probeInfo.response.StatusCode >= 500 {
+		return probeInfo
+	}
+
+	rejected := probeInfo.response.StatusCode >= 400
+	bidder.me.RecordGzipProbe(bidder.BidderName, !rejected)
+	if !rejected || ctx.Err() != nil {
+		return probeInfo
+	}
+
+	httpInfo := bidder.sendRequest(ctx, req, NoCompression, logger)
+	httpInfo.previousAttempt = probeInfo
+	return httpInfo
+}
+
+// sampleGzipProbe decides whether the request is sent gzip compressed to probe the bidder support of compression,
+// only requests for which no compression is configured nor requested are probed.
+func (bidder *bidderAdapter) sampleGzipProbe(endpointCompression string) bool {
+	if bidder.config.GzipProbeRate <= 0 || bidder.endpointCompression(endpointCompression) != "" {
+		return false
+	}
+	return rand.Float64() < bidder.config.GzipProbeRate
+}
+
+// withFallbackEndpoint returns a copy of the request whose URI has the scheme and host of the fallback endpoint.
+func withFallbackEndpoint(req *adapters.RequestData, fallbackEndpoint string) (*adapters.RequestData, error) {
+	fallbackURL, err := url.Parse(fallbackEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	if fallbackURL.Scheme == "" || fallbackURL.Host == "" {
+		return nil, fmt.Errorf("invalid fallback endpoint: %s", fallbackEndpoint)
+	}
+	reqURL, err := url.Parse(req.Uri)
+	if err != nil {
+		return nil, err
+	}
+	reqURL.Scheme = fallbackURL.Scheme
+	reqURL.Host = fallbackURL.Host
+
+	fallbackReq := *req
+	fallbackReq.Uri = reqURL.String()
+	fallbackReq.Headers = req.Headers.Clone()
+	return &fallbackReq, nil
+}
+
+func (bidder *bidderAdapter) sendRequest(ctx context.Context, req *adapters.RequestData, endpointCompression string, logger util.LogMsg) *httpCallInfo {
 	var requestBody []byte
 
-	switch strings.ToUpper(bidder.config.EndpointCompression) {
+	switch bidder.endpointCompression(endpointCompression) {
 	case Gzip:
-		requestBody = compressToGZIP(req.Body)
+		requestBody = compressToGZIPLevel(req.Body, bidder.config.GzipLevel)
 		req.Headers.Set("Content-Encoding", "gzip")
+		bidder.me.RecordRequestCompression(bidder.BidderName, len(req.Body), len(requestBody))
 	default:
 		requestBody = req.Body
 	}
@@ -502,11 +971,11 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	httpReq.Header = req.Headers
 
 	// If adapter connection metrics are not disabled, add the client trace
-	// to get complete connection info into our metrics
-	if !bidder.config.DisableConnMetrics {
+	// to get complete connection info into our metrics for the sampled requests
+	if !bidder.config.DisableConnMetrics && bidder.sampleConnMetrics() {
 		ctx = bidder.addClientTrace(ctx)
 	}
-	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
+	httpResp, err := ctxhttp.Do(ctx, bidder.httpClient(ctx), httpReq)
 	if err != nil {
 		if err == context.DeadlineExceeded {
 			err = &errortypes.Timeout{Message: err.Error()}
@@ -526,19 +995,41 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 
 		}
 		return &httpCallInfo{
-			request: req,
-			err:     err,
+			request:          req,
+			err:              err,
+			connectionFailed: true,
 		}
 	}
 
-	respBody, err := io.ReadAll(httpResp.Body)
+	defer httpResp.Body.Close()
+	maxResponseBytes := bidder.maxResponseBytes()
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBytes+1))
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
 			err:     err,
 		}
 	}
-	defer httpResp.Body.Close()
+
+	if int64(len(respBody)) > maxResponseBytes {
+		return &httpCallInfo{
+			request: req,
+			err: &errortypes.BadServerResponse{
+				Message: fmt.Sprintf("Response of bidder %s exceeds the limit of %d bytes", bidder.BidderName, maxResponseBytes),
+			},
+		}
+	}
+
+	if contentEncoding := httpResp.Header.Get("Content-Encoding"); contentEncoding != "" {
+		if respBody, err = decompressResponseBody(contentEncoding, respBody, maxResponseBytes); err != nil {
+			return &httpCallInfo{
+				request: req,
+				err: &errortypes.BadServerResponse{
+					Message: fmt.Sprintf("Failed to decompress %s encoded response body: %s", contentEncoding, err.Error()),
+				},
+			}
+		}
+	}
 
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 400 {
 		err = &errortypes.BadServerResponse{
@@ -558,7 +1049,7 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 }
 
 func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder adapters.TimeoutBidder, req *adapters.RequestData, logger util.LogMsg) {
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), bidder.timeoutNotificationTimeout())
 	defer cancel()
 	toReq, errL := timeoutBidder.MakeTimeoutNotification(req)
 	if toReq != nil && len(errL) == 0 {
@@ -598,10 +1089,85 @@ func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder adapters.Timeou
 
 }
 
+// notifyReject sends a reject notification if it is enabled and supported by the bidder.
+// The notification call is tossed into a go routine so it does not delay the auction.
+func (bidder *bidderAdapter) notifyReject(req *adapters.RequestData, errs []error) {
+	if !bidder.config.Debug.RejectNotification.Enabled || req == nil {
+		return
+	}
+
+	var corebidder adapters.Bidder = bidder.Bidder
+	if b, ok := corebidder.(*adapters.InfoAwareBidder); ok {
+		corebidder = b.Bidder
+	}
+	if rn, ok := corebidder.(adapters.RejectNotifier); ok {
+		go bidder.doRejectNotification(rn, req, errs, glog.Warningf)
+	}
+}
+
+func (bidder *bidderAdapter) doRejectNotification(rejectNotifier adapters.RejectNotifier, req *adapters.RequestData, errs []error, logger util.LogMsg) {
+	ctx, cancel := context.WithTimeout(context.Background(), bidder.timeoutNotificationTimeout())
+	defer cancel()
+	logCfg := bidder.config.Debug.TimeoutNotification
+	rnReq, errL := rejectNotifier.MakeRejectNotification(req, errs)
+	if rnReq == nil || len(errL) > 0 {
+		if logCfg.Log && len(errL) > 0 {
+			msg := fmt.Sprintf("RejectNotification: Failed to generate reject request: error(%s)", errL[0].Error())
+			util.LogRandomSample(msg, logger, logCfg.SamplingRate)
+		}
+		return
+	}
+
+	httpReq, err := http.NewRequest(rnReq.Method, rnReq.Uri, bytes.NewBuffer(rnReq.Body))
+	if err != nil {
+		if logCfg.Log {
+			msg := fmt.Sprintf("RejectNotification: Failed to make reject request: method(%s), uri(%s), error(%s)", rnReq.Method, rnReq.Uri, err.Error())
+			util.LogRandomSample(msg, logger, logCfg.SamplingRate)
+		}
+		return
+	}
+
+	httpReq.Header = req.Headers
+	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
+	if err == nil {
+		defer httpResp.Body.Close()
+	}
+	success := err == nil && httpResp.StatusCode >= 200 && httpResp.StatusCode < 300
+	if logCfg.Log && !(logCfg.FailOnly && success) {
+		var msg string
+		if err == nil {
+			msg = fmt.Sprintf("RejectNotification: status:(%d) body:%s", httpResp.StatusCode, string(rnReq.Body))
+		} else {
+			msg = fmt.Sprintf("RejectNotification: error:(%s) body:%s", err.Error(), string(rnReq.Body))
+		}
+		util.LogRandomSample(msg, logger, logCfg.SamplingRate)
+	}
+}
+
 type httpCallInfo struct {
 	request  *adapters.RequestData
 	response *adapters.ResponseData
 	err      error
+	// connectionFailed is true if no response was received from the bidder endpoint
+	connectionFailed bool
+	// previousAttempt holds the failed call to the primary endpoint if the request was retried against the fallback one,
+	// or the rejected gzip compressed call if the request was resent uncompressed
+	previousAttempt *httpCallInfo
+}
+
+// connMetricsSampleRate returns the configured adapter connections sample rate,
+// every request is sampled if the rate is not set.
+func connMetricsSampleRate(cfg config.Metrics) float64 {
+	if cfg.AdapterConnectionsSampleRate == 0 {
+		return 1
+	}
+	return cfg.AdapterConnectionsSampleRate
+}
+
+// sampleConnMetrics reports whether the connection metrics are collected for the request.
+func (bidder *bidderAdapter) sampleConnMetrics() bool {
+	rate := bidder.config.ConnMetricsSampleRate
+	return rate >= 1 || rand.Float64() < rate
 }
 
 // This function adds an httptrace.ClientTrace object to the context so, if connection with the bidder
@@ -645,6 +1211,58 @@ func (bidder *bidderAdapter) addClientTrace(ctx context.Context) context.Context
 	return httptrace.WithClientTrace(ctx, trace)
 }
 
+// timeoutNotificationTimeout returns the time limit of the timeout and reject notifications sent to the bidder.
+func (bidder *bidderAdapter) timeoutNotificationTimeout() time.Duration {
+	if bidder.config.Debug.TimeoutNotification.TimeoutMillis > 0 {
+		return time.Duration(bidder.config.Debug.TimeoutNotification.TimeoutMillis) * time.Millisecond
+	}
+	return defaultTimeoutNotificationTimeout
+}
+
+// maxResponseBytes returns the limit of the bidder response body size.
+func (bidder *bidderAdapter) maxResponseBytes() int64 {
+	if bidder.config.MaxResponseBytes > 0 {
+		return bidder.config.MaxResponseBytes
+	}
+	return defaultMaxResponseBytes
+}
+
+// requestTimeout returns the shorter of the positive per-request and adapter config timeouts,
+// zero is returned if none is set.
+func (bidder *bidderAdapter) requestTimeout(override time.Duration) time.Duration {
+	timeout := bidder.config.MaxRequestTimeout
+	if override > 0 && (timeout <= 0 || override < timeout) {
+		timeout = override
+	}
+	return timeout
+}
+
+// httpClientKey is the context key of the HTTP client the bidder requests are sent with instead of the adapter client.
+type httpClientKey struct{}
+
+// withHTTPClient returns a copy of the context carrying the HTTP client to send the bidder requests with.
+func withHTTPClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, httpClientKey{}, client)
+}
+
+// httpClient returns the HTTP client carried by the context if any, the adapter client otherwise.
+func (bidder *bidderAdapter) httpClient(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(httpClientKey{}).(*http.Client); ok && client != nil {
+		return client
+	}
+	return bidder.Client
+}
+
+// endpointCompression returns the compression of the bidder requests, the per-request override
+// takes precedence over the adapter config unless it's empty or unknown.
+func (bidder *bidderAdapter) endpointCompression(override string) string {
+	switch compression := strings.ToUpper(override); compression {
+	case Gzip, NoCompression:
+		return compression
+	}
+	return strings.ToUpper(bidder.config.EndpointCompression)
+}
+
 func prepareStoredResponse(impId string, bidResp json.RawMessage) *httpCallInfo {
 	//always one element in reqData because stored response is mapped to single imp
 	body := fmt.Sprintf("%s%s", ImpIdReqBody, impId)
@@ -653,21 +1271,158 @@ func prepareStoredResponse(impId string, bidResp json.RawMessage) *httpCallInfo
 		Uri:    "",
 		Body:   []byte(body), //use it to pass imp id for stored resp
 	}
+
+	respHeaders, respBody, err := unwrapStoredResponse(bidResp)
+	if err == nil {
+		respBody, err = mergeStoredResponses(respBody)
+	}
+	if err != nil {
+		return &httpCallInfo{
+			request: &reqDataForStoredResp,
+			err:     &errortypes.BadInput{Message: fmt.Sprintf("invalid stored bid response for imp %s: %s", impId, err)},
+		}
+	}
+
 	respData := &httpCallInfo{
 		request: &reqDataForStoredResp,
 		response: &adapters.ResponseData{
 			StatusCode: 200,
-			Body:       bidResp,
+			Body:       respBody,
+			Headers:    respHeaders,
 		},
 		err: nil,
 	}
 	return respData
 }
 
+// unwrapStoredResponse extracts the response headers and body from the stored bid response envelope
+// in the format {"headers": {"Name": ["value"]}, "body": <bid response>}, so that the adapters inspecting
+// response headers can be tested with stored responses. A stored bid response not wrapped in the envelope
+// is returned unchanged. The returned headers are never nil.
+func unwrapStoredResponse(bidResp json.RawMessage) (http.Header, json.RawMessage, error) {
+	headers := http.Header{}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(bidResp, &envelope); err != nil || !isStoredResponseEnvelope(envelope) {
+		return headers, bidResp, nil
+	}
+
+	if rawHeaders, ok := envelope["headers"]; ok {
+		var envelopeHeaders map[string][]string
+		if err := json.Unmarshal(rawHeaders, &envelopeHeaders); err != nil {
+			return nil, nil, fmt.Errorf("invalid headers of stored bid response envelope: %s", err)
+		}
+		for name, values := range envelopeHeaders {
+			for _, value := range values {
+				headers.Add(name, value)
+			}
+		}
+	}
+
+	return headers, envelope["body"], nil
+}
+
+// isStoredResponseEnvelope reports whether the stored bid response object has the body and
+// optionally the headers, but no other fields.
+func isStoredResponseEnvelope(response map[string]json.RawMessage) bool {
+	if _, ok := response["body"]; !ok {
+		return false
+	}
+	_, hasHeaders := response["headers"]
+	if hasHeaders {
+		return len(response) == 2
+	}
+	return len(response) == 1
+}
+
+// mergeStoredResponses combines the array of stored bid responses of a single imp into one response,
+// the first response is used as is with the seatbids of the other responses appended.
+// A stored bid response which is not an array is returned unchanged.
+func mergeStoredResponses(bidResp json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(bidResp)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return bidResp, nil
+	}
+
+	var responses []map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &responses); err != nil {
+		return nil, err
+	}
+	if len(responses) == 0 {
+		return nil, errors.New("empty array of stored bid responses")
+	}
+
+	seatBids := make([]json.RawMessage, 0, len(responses))
+	for _, response := range responses {
+		if response["seatbid"] == nil {
+			continue
+		}
+		var responseSeatBids []json.RawMessage
+		if err := json.Unmarshal(response["seatbid"], &responseSeatBids); err != nil {
+			return nil, err
+		}
+		seatBids = append(seatBids, responseSeatBids...)
+	}
+
+	merged := responses[0]
+	if merged == nil {
+		merged = make(map[string]json.RawMessage)
+	}
+	mergedSeatBids, err := json.Marshal(seatBids)
+	if err != nil {
+		return nil, err
+	}
+	merged["seatbid"] = mergedSeatBids
+
+	return json.Marshal(merged)
+}
+
 func compressToGZIP(requestBody []byte) []byte {
+	return compressToGZIPLevel(requestBody, gzip.DefaultCompression)
+}
+
+// compressToGZIPLevel compresses the request body with the given compress/gzip level,
+// the level is expected to be validated beforehand, the default compression is used if it's invalid.
+func compressToGZIPLevel(requestBody []byte, level int) []byte {
 	var b bytes.Buffer
-	w := gzip.NewWriter(&b)
+	w, err := gzip.NewWriterLevel(&b, level)
+	if err != nil {
+		w = gzip.NewWriter(&b)
+	}
 	w.Write([]byte(requestBody))
 	w.Close()
 	return b.Bytes()
 }
+
+// decompressResponseBody decompresses the gzip or deflate encoded response body.
+// Body is returned unchanged for any other content encoding.
+// An error is returned if the decompressed body exceeds maxBytes
+// to protect against decompression bombs.
+func decompressResponseBody(contentEncoding string, body []byte, maxBytes int64) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+	default:
+		return body, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(decompressed)) > maxBytes {
+		return nil, fmt.Errorf("decompressed body exceeds the limit of %d bytes", maxBytes)
+	}
+
+	return decompressed, nil
+}
@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/metrics"
+)
+
+// benchmarkBuildBidRequestBody returns a bidder request body with several banner and video impressions,
+// similar to the ones sent to bidders by the exchange.
+func benchmarkBuildBidRequestBody(b *testing.B) []byte {
+	request := openrtb2.BidRequest{
+		ID:   "some-request-id",
+		Site: &openrtb2.Site{Page: "prebid.org", Publisher: &openrtb2.Publisher{ID: "some-publisher-id"}},
+		Device: &openrtb2.Device{
+			UA: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/107.0.0.0 Safari/537.36",
+			IP: "192.168.0.1",
+		},
+		User: &openrtb2.User{ID: "some-user-id", BuyerUID: "some-buyer-uid"},
+		Cur:  []string{"USD"},
+		TMax: 500,
+	}
+
+	for i := 0; i < 10; i++ {
+		request.Imp = append(request.Imp,
+			openrtb2.Imp{
+				ID:     fmt.Sprintf("banner-imp-%d", i),
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 300, H: 600}, {W: 728, H: 90}}},
+				Ext:    json.RawMessage(`{"bidder":{"placementId":12883451}}`),
+			},
+			openrtb2.Imp{
+				ID:    fmt.Sprintf("video-imp-%d", i),
+				Video: &openrtb2.Video{MIMEs: []string{"video/mp4", "video/webm"}, W: 640, H: 480, MinDuration: 5, MaxDuration: 30},
+				Ext:   json.RawMessage(`{"bidder":{"placementId":12883452}}`),
+			},
+		)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+	return body
+}
+
+// BenchmarkCompressToGZIPLevel compares the gzip compression levels available to the bidders.
+func BenchmarkCompressToGZIPLevel(b *testing.B) {
+	body := benchmarkBuildBidRequestBody(b)
+	levels := map[string]int{
+		"default":          gzip.DefaultCompression,
+		"best_speed":       gzip.BestSpeed,
+		"best_compression": gzip.BestCompression,
+		"huffman_only":     gzip.HuffmanOnly,
+	}
+
+	for name, level := range levels {
+		b.Run(name, func(b *testing.B) {
+			var compressed []byte
+			for n := 0; n < b.N; n++ {
+				compressed = compressToGZIPLevel(body, level)
+			}
+			b.ReportMetric(float64(len(compressed))/float64(len(body)), "ratio")
+		})
+	}
+}
+
+// BenchmarkConnMetricsSampling compares the client trace allocations for the connection metrics sample rates.
+func BenchmarkConnMetricsSampling(b *testing.B) {
+	rates := map[string]float64{
+		"all":        1,
+		"one_in_10":  0.1,
+		"one_in_100": 0.01,
+	}
+
+	for name, rate := range rates {
+		b.Run(name, func(b *testing.B) {
+			bidder := &bidderAdapter{
+				me:     &metrics.MetricsEngineMock{},
+				config: bidderAdapterConfig{ConnMetricsSampleRate: rate},
+			}
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				ctx := context.Background()
+				if bidder.sampleConnMetrics() {
+					ctx = bidder.addClientTrace(ctx)
+				}
+				_ = ctx
+			}
+		})
+	}
+}
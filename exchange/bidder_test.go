@@ -2,6 +2,8 @@ package exchange
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -13,6 +15,8 @@ import (
 	"net/http/httptrace"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -26,7 +30,9 @@ import (
 	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/exchange/entities"
 	"github.com/prebid/prebid-server/experiment/adscert"
+	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/hooks/hookexecution"
+	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/metrics"
 	metricsConfig "github.com/prebid/prebid-server/metrics/config"
 	"github.com/prebid/prebid-server/openrtb_ext"
@@ -95,7 +101,7 @@ func TestSingleBidder(t *testing.T) {
 		}
 		bidderImpl.bidResponse = mockBidderResponse
 
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, test.debugInfo, "")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: test.debugInfo})
 		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 		bidderReq := BidderRequest{
@@ -217,7 +223,7 @@ func TestSingleBidderGzip(t *testing.T) {
 		}
 		bidderImpl.bidResponse = mockBidderResponse
 
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, test.debugInfo, "GZIP")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: test.debugInfo, EndpointCompression: "GZIP"})
 		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 		bidderReq := BidderRequest{
@@ -316,7 +322,7 @@ func TestRequestBidRemovesSensitiveHeaders(t *testing.T) {
 	debugInfo := &config.DebugInfo{Allow: true}
 	ctx := context.Background()
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, debugInfo, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: debugInfo, UserAgent: "none"})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -369,7 +375,7 @@ func TestSetGPCHeader(t *testing.T) {
 	debugInfo := &config.DebugInfo{Allow: true}
 	ctx := context.Background()
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, debugInfo, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: debugInfo, UserAgent: "none"})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
@@ -399,6 +405,139 @@ func TestSetGPCHeader(t *testing.T) {
 	assert.ElementsMatch(t, seatBids[0].HttpCalls, expectedHttpCall)
 }
 
+// uriRewriteHookExecutor routes the bidder HTTP requests to the proxy server.
+type uriRewriteHookExecutor struct {
+	hookexecution.EmptyHookExecutor
+	proxyURL string
+}
+
+func (e *uriRewriteHookExecutor) ExecuteBidderHttpRequestStage(requests []*adapters.RequestData, _ string) (*http.Client, *hookexecution.RejectError) {
+	for _, request := range requests {
+		request.Uri = e.proxyURL
+		request.Headers.Set("X-Region", "eu")
+	}
+	return nil, nil
+}
+
+// clientOverrideHookExecutor provides the HTTP client the bidder requests are sent with.
+type clientOverrideHookExecutor struct {
+	hookexecution.EmptyHookExecutor
+	client *http.Client
+}
+
+func (e *clientOverrideHookExecutor) ExecuteBidderHttpRequestStage(_ []*adapters.RequestData, _ string) (*http.Client, *hookexecution.RejectError) {
+	return e.client, nil
+}
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	count int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.count, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRequestBidHttpClientOverride(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description           string
+		givenOverride         bool
+		expectedAdapterCalls  int32
+		expectedOverrideCalls int32
+	}{
+		{
+			description:          "Adapter client used without override",
+			givenOverride:        false,
+			expectedAdapterCalls: 2,
+		},
+		{
+			description:           "Override client used for all requests",
+			givenOverride:         true,
+			expectedOverrideCalls: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &mixedMultiBidder{
+				httpRequests: []*adapters.RequestData{
+					{Method: "POST", Uri: server.URL, Body: []byte("requestJson"), Headers: http.Header{}},
+					{Method: "POST", Uri: server.URL, Body: []byte("requestJson"), Headers: http.Header{}},
+				},
+				bidResponse: &adapters.BidderResponse{},
+			}
+
+			adapterTransport := &countingTransport{}
+			overrideTransport := &countingTransport{}
+			executor := &clientOverrideHookExecutor{}
+			if test.givenOverride {
+				executor.client = &http.Client{Transport: overrideTransport}
+			}
+
+			bidder := AdaptBidder(bidderImpl, &http.Client{Transport: adapterTransport}, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, executor)
+
+			assert.Equal(t, test.expectedAdapterCalls, atomic.LoadInt32(&adapterTransport.count), "Unexpected adapter client calls.")
+			assert.Equal(t, test.expectedOverrideCalls, atomic.LoadInt32(&overrideTransport.count), "Unexpected override client calls.")
+		})
+	}
+}
+
+func TestRequestBidBidderHttpRequestRewrite(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+	proxy := httptest.NewServer(mockHandler(200, "getBody", "proxyResponseJson"))
+	defer proxy.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method: "POST",
+			Uri:    server.URL,
+			Body:   []byte("requestJson"),
+		},
+		bidResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{},
+		},
+	}
+
+	bidder := AdaptBidder(bidderImpl, http.DefaultClient, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{Allow: true}, UserAgent: "none"})
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+		BidderName: "test",
+	}
+	bidReqOptions := bidRequestOptions{
+		accountDebugAllowed: true,
+		bidAdjustments:      map[string]float64{"test": 1},
+	}
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &uriRewriteHookExecutor{proxyURL: proxy.URL})
+
+	expectedHttpCall := []*openrtb_ext.ExtHttpCall{
+		{
+			Uri:            proxy.URL,
+			RequestBody:    "requestJson",
+			RequestHeaders: map[string][]string{"X-Prebid": {"pbs-go/unknown"}, "X-Region": {"eu"}},
+			ResponseBody:   "proxyResponseJson",
+			Status:         200,
+		},
+	}
+
+	assert.Empty(t, errs)
+	if assert.Len(t, seatBids, 1) {
+		assert.ElementsMatch(t, expectedHttpCall, seatBids[0].HttpCalls)
+	}
+}
+
 func TestSetGPCHeaderNil(t *testing.T) {
 	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
 	defer server.Close()
@@ -418,7 +557,7 @@ func TestSetGPCHeaderNil(t *testing.T) {
 	debugInfo := &config.DebugInfo{Allow: true}
 	ctx := context.Background()
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, debugInfo, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: debugInfo, UserAgent: "none"})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -449,6 +588,207 @@ func TestSetGPCHeaderNil(t *testing.T) {
 	assert.ElementsMatch(t, seatBids[0].HttpCalls, expectedHttpCall)
 }
 
+func TestSetUserAgentHeader(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description       string
+		givenHeaders      http.Header
+		givenUserAgent    string
+		expectedUserAgent []string
+	}{
+		{
+			description:       "Configured User-Agent set",
+			givenHeaders:      nil,
+			givenUserAgent:    "prebid-server/1.0.0",
+			expectedUserAgent: []string{"prebid-server/1.0.0"},
+		},
+		{
+			description:       "User-Agent set by adapter not overwritten",
+			givenHeaders:      http.Header{"User-Agent": []string{"Mozilla/5.0"}},
+			givenUserAgent:    "prebid-server/1.0.0",
+			expectedUserAgent: []string{"Mozilla/5.0"},
+		},
+		{
+			description:       "Default User-Agent set if not configured",
+			givenHeaders:      nil,
+			givenUserAgent:    "",
+			expectedUserAgent: []string{"prebid-server/unknown"},
+		},
+		{
+			description:       "User-Agent not set if disabled",
+			givenHeaders:      nil,
+			givenUserAgent:    "none",
+			expectedUserAgent: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{
+					Method:  "POST",
+					Uri:     server.URL,
+					Body:    []byte("requestJson"),
+					Headers: test.givenHeaders,
+				},
+				bidResponse: &adapters.BidderResponse{
+					Bids: []*adapters.TypedBid{},
+				},
+			}
+
+			bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{Allow: true}, UserAgent: test.givenUserAgent})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: "test",
+			}
+			bidReqOptions := bidRequestOptions{
+				headerDebugAllowed: true,
+				bidAdjustments:     map[string]float64{"test": 1},
+			}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			assert.Empty(t, errs)
+			if assert.Len(t, seatBids, 1) && assert.Len(t, seatBids[0].HttpCalls, 1) {
+				requestHeaders := seatBids[0].HttpCalls[0].RequestHeaders
+				assert.Equal(t, test.expectedUserAgent, requestHeaders["User-Agent"], "Incorrect User-Agent header.")
+				assert.Equal(t, []string{"pbs-go/unknown"}, requestHeaders["X-Prebid"], "X-Prebid header expected along with User-Agent.")
+			}
+		})
+	}
+}
+
+func TestRequestBidDebugBidders(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description       string
+		givenDebugBidders []string
+		givenHeaderDebug  bool
+		givenBidderDebug  bool
+		expectedHttpCalls int
+		expectedErrors    int
+	}{
+		{
+			description:       "Debug output included if debug bidders empty",
+			givenDebugBidders: nil,
+			givenBidderDebug:  true,
+			expectedHttpCalls: 1,
+		},
+		{
+			description:       "Debug output included if bidder listed",
+			givenDebugBidders: []string{"rubicon", "TEST"},
+			givenBidderDebug:  true,
+			expectedHttpCalls: 1,
+		},
+		{
+			description:       "Debug output excluded if bidder not listed",
+			givenDebugBidders: []string{"rubicon"},
+			givenBidderDebug:  true,
+			expectedHttpCalls: 0,
+		},
+		{
+			description:       "Debug output excluded if bidder not listed even if debug override header provided",
+			givenDebugBidders: []string{"rubicon"},
+			givenHeaderDebug:  true,
+			givenBidderDebug:  true,
+			expectedHttpCalls: 0,
+		},
+		{
+			description:       "Debug output excluded if bidder listed but bidder debug disabled",
+			givenDebugBidders: []string{"test"},
+			givenBidderDebug:  false,
+			expectedHttpCalls: 0,
+			expectedErrors:    1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{
+					Method: "POST",
+					Uri:    server.URL,
+					Body:   []byte("requestJson"),
+				},
+				bidResponse: &adapters.BidderResponse{
+					Bids: []*adapters.TypedBid{},
+				},
+			}
+
+			bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{Allow: test.givenBidderDebug}})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: "test",
+			}
+			bidReqOptions := bidRequestOptions{
+				accountDebugAllowed: true,
+				headerDebugAllowed:  test.givenHeaderDebug,
+				bidAdjustments:      map[string]float64{"test": 1},
+				debugBidders:        test.givenDebugBidders,
+			}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			assert.Len(t, errs, test.expectedErrors)
+			if assert.Len(t, seatBids, 1) {
+				assert.Len(t, seatBids[0].HttpCalls, test.expectedHttpCalls)
+			}
+		})
+	}
+}
+
+func TestBidderUserAgent(t *testing.T) {
+	assert.Equal(t, "prebid-server/unknown", bidderUserAgent(""), "Version-derived User-Agent expected by default.")
+	assert.Equal(t, "acme/2.0", bidderUserAgent("acme/2.0"))
+	assert.Empty(t, bidderUserAgent("NONE"), "User-Agent should be disabled.")
+}
+
+func TestBidderEndpointCompression(t *testing.T) {
+	gzipCapabilities := &config.CapabilitiesInfo{Gzip: true}
+
+	testCases := []struct {
+		description string
+		givenInfo   config.BidderInfo
+		expected    string
+	}{
+		{
+			description: "Uncompressed if no compression configured nor declared",
+			givenInfo:   config.BidderInfo{Capabilities: &config.CapabilitiesInfo{}},
+			expected:    "",
+		},
+		{
+			description: "Uncompressed if no capabilities",
+			givenInfo:   config.BidderInfo{},
+			expected:    "",
+		},
+		{
+			description: "Gzip if declared in capabilities",
+			givenInfo:   config.BidderInfo{Capabilities: gzipCapabilities},
+			expected:    Gzip,
+		},
+		{
+			description: "Configured compression used if no capability declared",
+			givenInfo:   config.BidderInfo{EndpointCompression: "gzip"},
+			expected:    "gzip",
+		},
+		{
+			description: "Configured compression takes precedence over capabilities",
+			givenInfo:   config.BidderInfo{EndpointCompression: NoCompression, Capabilities: gzipCapabilities},
+			expected:    NoCompression,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, bidderEndpointCompression(test.givenInfo))
+		})
+	}
+}
+
 // TestMultiBidder makes sure all the requests get sent, and the responses processed.
 // Because this is done in parallel, it should be run under the race detector.
 func TestMultiBidder(t *testing.T) {
@@ -489,7 +829,7 @@ func TestMultiBidder(t *testing.T) {
 			}},
 		bidResponse: mockBidderResponse,
 	}
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
@@ -547,7 +887,7 @@ func TestBidderTimeout(t *testing.T) {
 	callInfo := bidder.doRequest(ctx, &adapters.RequestData{
 		Method: "POST",
 		Uri:    server.URL,
-	})
+	}, "", 0)
 	if callInfo.err == nil {
 		t.Errorf("The bidder should report an error if the context has expired already.")
 	}
@@ -566,7 +906,7 @@ func TestInvalidRequest(t *testing.T) {
 
 	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
 		Method: "\"", // force http.NewRequest() to fail
-	})
+	}, "", 0)
 	if callInfo.err == nil {
 		t.Errorf("bidderAdapter.doRequest should return an error if the request data is malformed.")
 	}
@@ -590,12 +930,150 @@ func TestConnectionClose(t *testing.T) {
 	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
 		Method: "POST",
 		Uri:    server.URL,
-	})
+	}, "", 0)
 	if callInfo.err == nil {
 		t.Errorf("bidderAdapter.doRequest should return an error if the connection closes unexpectedly.")
 	}
 }
 
+// TestCompressedResponse makes sure that bidderAdapter.doRequest decompresses encoded response bodies.
+func TestCompressedResponse(t *testing.T) {
+	respBody := `{"bid":false}`
+	testCases := []struct {
+		description     string
+		contentEncoding string
+		compress        func([]byte) []byte
+	}{
+		{description: "gzip", contentEncoding: "gzip", compress: compressToGZIP},
+		{description: "deflate", contentEncoding: "deflate", compress: compressToZlib},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", test.contentEncoding)
+				w.Write(test.compress([]byte(respBody)))
+			})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			bidder := &bidderAdapter{
+				Bidder:     &mixedMultiBidder{},
+				Client:     server.Client(),
+				BidderName: openrtb_ext.BidderAppnexus,
+				me:         &metricsConfig.NilMetricsEngine{},
+			}
+
+			// explicitly accept encoding, otherwise the transport decompresses gzip responses itself
+			callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+				Method:  "GET",
+				Uri:     server.URL,
+				Headers: http.Header{"Accept-Encoding": []string{test.contentEncoding}},
+			}, "", 0)
+
+			if assert.NoError(t, callInfo.err) && assert.NotNil(t, callInfo.response) {
+				assert.Equal(t, respBody, string(callInfo.response.Body))
+				assert.Equal(t, test.contentEncoding, callInfo.response.Headers.Get("Content-Encoding"), "Original headers should be preserved.")
+			}
+		})
+	}
+}
+
+func TestDecompressResponseBody(t *testing.T) {
+	body := []byte(`{"bid":false}`)
+	const maxBytes = 1024
+	bomb := compressToGZIP(make([]byte, maxBytes+1))
+
+	testCases := []struct {
+		description      string
+		givenEncoding    string
+		givenBody        []byte
+		expectedBody     []byte
+		expectedErrorMsg string
+	}{
+		{
+			description:   "gzip",
+			givenEncoding: "gzip",
+			givenBody:     compressToGZIP(body),
+			expectedBody:  body,
+		},
+		{
+			description:   "deflate",
+			givenEncoding: "Deflate",
+			givenBody:     compressToZlib(body),
+			expectedBody:  body,
+		},
+		{
+			description:   "Unsupported encoding leaves body unchanged",
+			givenEncoding: "br",
+			givenBody:     body,
+			expectedBody:  body,
+		},
+		{
+			description:      "Malformed gzip body",
+			givenEncoding:    "gzip",
+			givenBody:        body,
+			expectedErrorMsg: "gzip: invalid header",
+		},
+		{
+			description:      "Decompressed body exceeds limit",
+			givenEncoding:    "gzip",
+			givenBody:        bomb,
+			expectedErrorMsg: fmt.Sprintf("decompressed body exceeds the limit of %d bytes", maxBytes),
+		},
+	}
+
+	for _, test := range testCases {
+		decompressed, err := decompressResponseBody(test.givenEncoding, test.givenBody, maxBytes)
+		if test.expectedErrorMsg != "" {
+			assert.EqualError(t, err, test.expectedErrorMsg, test.description)
+			continue
+		}
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedBody, decompressed, test.description)
+	}
+}
+
+func TestCompressToGZIPLevel(t *testing.T) {
+	body := []byte(`{"id":"some-request-id","imp":[{"id":"imp-id"}]}`)
+
+	for _, level := range []int{gzip.DefaultCompression, gzip.HuffmanOnly, gzip.BestSpeed, gzip.BestCompression, 42} {
+		decompressed, err := decompressResponseBody("gzip", compressToGZIPLevel(body, level), defaultMaxResponseBytes)
+		if assert.NoError(t, err, "level %d", level) {
+			assert.Equal(t, body, decompressed, "level %d", level)
+		}
+	}
+}
+
+func TestAdaptBidderGzipLevel(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenLevel    *int
+		expectedLevel int
+	}{
+		{description: "Default level if not set", givenLevel: nil, expectedLevel: gzip.DefaultCompression},
+		{description: "Configured level", givenLevel: intPtr(gzip.BestSpeed), expectedLevel: gzip.BestSpeed},
+		{description: "Configured no compression", givenLevel: intPtr(gzip.NoCompression), expectedLevel: gzip.NoCompression},
+	}
+
+	for _, test := range testCases {
+		bidder := AdaptBidder(&mixedMultiBidder{}, nil, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{EndpointCompression: "GZIP", GzipLevel: test.givenLevel})
+		assert.Equal(t, test.expectedLevel, bidder.(*bidderAdapter).config.GzipLevel, test.description)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func compressToZlib(body []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(body)
+	w.Close()
+	return b.Bytes()
+}
+
 type bid struct {
 	currency       string
 	price          float64
@@ -855,7 +1333,7 @@ func TestMultiCurrencies(t *testing.T) {
 		)
 
 		// Execute:
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 		currencyConverter := currency.NewRateConverter(
 			&http.Client{},
 			mockedHTTPServer.URL,
@@ -989,6 +1467,25 @@ func TestMultiCurrencies_RateConverterNotSet(t *testing.T) {
 			},
 			description: "Case 10 - Bidder respond with not existing currencies",
 		},
+		{
+			bidCurrency:       []string{"usd", "Usd", "US$"},
+			expectedBidsCount: 3,
+			expectedBadCurrencyErrors: []error{
+				&errortypes.Warning{WarningCode: errortypes.NormalizedCurrencyWarningCode, Message: "Bidder response currency usd normalized to USD"},
+				&errortypes.Warning{WarningCode: errortypes.NormalizedCurrencyWarningCode, Message: "Bidder response currency Usd normalized to USD"},
+				&errortypes.Warning{WarningCode: errortypes.NormalizedCurrencyWarningCode, Message: "Bidder response currency US$ normalized to USD"},
+			},
+			description: "Case 11 - Bidder respond with non-standard representations of default currency",
+		},
+		{
+			bidCurrency:       []string{"dollars", "USD", "U S"},
+			expectedBidsCount: 1,
+			expectedBadCurrencyErrors: []error{
+				&errortypes.Warning{WarningCode: errortypes.InvalidCurrencyWarningCode, Message: "Bidder appnexus responded with invalid currency code dollars, bids dropped"},
+				&errortypes.Warning{WarningCode: errortypes.InvalidCurrencyWarningCode, Message: "Bidder appnexus responded with invalid currency code U S, bids dropped"},
+			},
+			description: "Case 12 - Bidder respond with currencies not having the format of ISO code",
+		},
 	}
 
 	server := httptest.NewServer(mockHandler(respStatus, getRespBody, postRespBody))
@@ -1021,7 +1518,7 @@ func TestMultiCurrencies_RateConverterNotSet(t *testing.T) {
 		}
 
 		// Execute:
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 		bidderReq := BidderRequest{
 			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
@@ -1053,15 +1550,227 @@ func TestMultiCurrencies_RateConverterNotSet(t *testing.T) {
 	}
 }
 
-// TestMultiCurrencies_RequestCurrencyPick tests request currencies pick.
-func TestMultiCurrencies_RequestCurrencyPick(t *testing.T) {
-	// Setup:
+func TestNormalizeCurrency(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenCurrency    string
+		expectedCurrency string
+		expectedWarning  *errortypes.Warning
+	}{
+		{description: "ISO code not changed", givenCurrency: "EUR", expectedCurrency: "EUR"},
+		{description: "Unassigned code of valid format", givenCurrency: "AAA", expectedCurrency: "AAA"},
+		{description: "Lowercase code", givenCurrency: "eur", expectedCurrency: "EUR", expectedWarning: normalizedCurrencyWarning("eur", "EUR")},
+		{description: "Mixed case code", givenCurrency: "Gbp", expectedCurrency: "GBP", expectedWarning: normalizedCurrencyWarning("Gbp", "GBP")},
+		{description: "Code with spaces", givenCurrency: " USD ", expectedCurrency: "USD", expectedWarning: normalizedCurrencyWarning(" USD ", "USD")},
+		{description: "Dollar symbol", givenCurrency: "$", expectedCurrency: "USD", expectedWarning: normalizedCurrencyWarning("$", "USD")},
+		{description: "Prefixed dollar symbol", givenCurrency: "us$", expectedCurrency: "USD", expectedWarning: normalizedCurrencyWarning("us$", "USD")},
+		{description: "Canadian dollar symbol", givenCurrency: "C$", expectedCurrency: "CAD", expectedWarning: normalizedCurrencyWarning("C$", "CAD")},
+		{description: "Euro symbol", givenCurrency: "€", expectedCurrency: "EUR", expectedWarning: normalizedCurrencyWarning("€", "EUR")},
+		{description: "Pound symbol", givenCurrency: "£", expectedCurrency: "GBP", expectedWarning: normalizedCurrencyWarning("£", "GBP")},
+		{description: "Empty", givenCurrency: "", expectedWarning: invalidCurrencyWarning("")},
+		{description: "Too short", givenCurrency: "EU", expectedWarning: invalidCurrencyWarning("EU")},
+		{description: "Too long", givenCurrency: "EURO", expectedWarning: invalidCurrencyWarning("EURO")},
+		{description: "Digits", givenCurrency: "E1R", expectedWarning: invalidCurrencyWarning("E1R")},
+		{description: "Unknown symbol", givenCurrency: "¤", expectedWarning: invalidCurrencyWarning("¤")},
+	}
+
+	for _, test := range testCases {
+		currency, warning := normalizeCurrency("appnexus", test.givenCurrency)
+		assert.Equal(t, test.expectedCurrency, currency, test.description)
+		assert.Equal(t, test.expectedWarning, warning, test.description)
+	}
+}
+
+func normalizedCurrencyWarning(cur, normalized string) *errortypes.Warning {
+	return &errortypes.Warning{
+		WarningCode: errortypes.NormalizedCurrencyWarningCode,
+		Message:     fmt.Sprintf("Bidder response currency %s normalized to %s", cur, normalized),
+	}
+}
+
+func invalidCurrencyWarning(cur string) *errortypes.Warning {
+	return &errortypes.Warning{
+		WarningCode: errortypes.InvalidCurrencyWarningCode,
+		Message:     fmt.Sprintf("Bidder appnexus responded with invalid currency code %s, bids dropped", cur),
+	}
+}
+
+func TestGetBidAdjustmentFactor(t *testing.T) {
+	options := bidRequestOptions{
+		bidAdjustments: map[string]float64{"seat": 0.9, "adapter": 0.8},
+		bidAdjustmentsByCur: map[string]map[string]float64{
+			"seat":    {"EUR": 0.7},
+			"adapter": {"GBP": 0.6},
+		},
+	}
+
+	testCases := []struct {
+		description    string
+		givenOptions   bidRequestOptions
+		givenCurrency  string
+		givenBidders   []string
+		expectedFactor float64
+	}{
+		{
+			description:    "No adjustments",
+			givenOptions:   bidRequestOptions{},
+			givenCurrency:  "EUR",
+			givenBidders:   []string{"seat", "adapter"},
+			expectedFactor: 1.0,
+		},
+		{
+			description:    "Currency-specific seat factor",
+			givenOptions:   options,
+			givenCurrency:  "EUR",
+			givenBidders:   []string{"seat", "adapter"},
+			expectedFactor: 0.7,
+		},
+		{
+			description:    "Flat seat factor takes precedence over currency-specific adapter factor",
+			givenOptions:   options,
+			givenCurrency:  "GBP",
+			givenBidders:   []string{"seat", "adapter"},
+			expectedFactor: 0.9,
+		},
+		{
+			description:    "Currency-specific adapter factor",
+			givenOptions:   options,
+			givenCurrency:  "GBP",
+			givenBidders:   []string{"other", "adapter"},
+			expectedFactor: 0.6,
+		},
+		{
+			description:    "Flat adapter factor if no currency-specific factor",
+			givenOptions:   options,
+			givenCurrency:  "USD",
+			givenBidders:   []string{"other", "adapter"},
+			expectedFactor: 0.8,
+		},
+		{
+			description:    "Flat factors only",
+			givenOptions:   bidRequestOptions{bidAdjustments: map[string]float64{"adapter": 2.0}},
+			givenCurrency:  "USD",
+			givenBidders:   []string{"adapter"},
+			expectedFactor: 2.0,
+		},
+		{
+			description:    "Unknown bidder",
+			givenOptions:   options,
+			givenCurrency:  "EUR",
+			givenBidders:   []string{"other"},
+			expectedFactor: 1.0,
+		},
+	}
+
+	for _, test := range testCases {
+		factor := test.givenOptions.getBidAdjustmentFactor(test.givenCurrency, test.givenBidders...)
+		assert.Equal(t, test.expectedFactor, factor, test.description)
+	}
+}
+
+func TestAllowedResponseCurrencies(t *testing.T) {
 	respStatus := 200
 	getRespBody := "{\"wasPost\":false}"
 	postRespBody := "{\"wasPost\":true}"
 
 	testCases := []struct {
-		bidRequestCurrencies   []string
+		description               string
+		allowedResponseCurrencies []string
+		bidCurrency               []string
+		expectedBidsCount         uint
+		expectedErrors            []error
+	}{
+		{
+			description:               "Any currency accepted if allowed currencies not set",
+			allowedResponseCurrencies: nil,
+			bidCurrency:               []string{"USD", "AAA"},
+			expectedBidsCount:         1,
+			expectedErrors:            []error{errors.New("currency: tag is not a recognized currency")},
+		},
+		{
+			description:               "Response currency found in allowed currencies",
+			allowedResponseCurrencies: []string{"usd", "EUR"},
+			bidCurrency:               []string{"USD", ""},
+			expectedBidsCount:         2,
+			expectedErrors:            []error{},
+		},
+		{
+			description:               "Response currency not found in allowed currencies",
+			allowedResponseCurrencies: []string{"USD"},
+			bidCurrency:               []string{"USD", "EUD"},
+			expectedBidsCount:         1,
+			expectedErrors: []error{
+				&errortypes.BadServerResponse{Message: "Bidder response currency EUD is not in the list of allowed response currencies"},
+			},
+		},
+	}
+
+	server := httptest.NewServer(mockHandler(respStatus, getRespBody, postRespBody))
+	defer server.Close()
+
+	for _, tc := range testCases {
+		mockBidderResponses := make([]*adapters.BidderResponse, len(tc.bidCurrency))
+		bidderImpl := &goodMultiHTTPCallsBidder{
+			bidResponses: mockBidderResponses,
+		}
+		bidderImpl.httpRequest = make([]*adapters.RequestData, len(tc.bidCurrency))
+
+		for i, cur := range tc.bidCurrency {
+			mockBidderResponses[i] = &adapters.BidderResponse{
+				Bids: []*adapters.TypedBid{
+					{
+						Bid:     &openrtb2.Bid{},
+						BidType: openrtb_ext.BidTypeBanner,
+					},
+				},
+				Currency: cur,
+			}
+
+			bidderImpl.httpRequest[i] = &adapters.RequestData{
+				Method:  "POST",
+				Uri:     server.URL,
+				Body:    []byte("{\"key\":\"val\"}"),
+				Headers: http.Header{},
+			}
+		}
+
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{AllowedResponseCurrencies: tc.allowedResponseCurrencies})
+		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+		bidderReq := BidderRequest{
+			BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+			BidderName: "test",
+		}
+		seatBids, errs := bidder.requestBid(
+			context.Background(),
+			bidderReq,
+			currencyConverter.Rates(),
+			&adapters.ExtraRequestInfo{},
+			&adscert.NilSigner{},
+			bidRequestOptions{
+				accountDebugAllowed: true,
+				headerDebugAllowed:  true,
+				addCallSignHeader:   false,
+				bidAdjustments:      map[string]float64{"test": 1},
+			},
+			openrtb_ext.ExtAlternateBidderCodes{},
+			&hookexecution.EmptyHookExecutor{},
+		)
+
+		assert.Len(t, seatBids, 1, tc.description)
+		assert.Equal(t, tc.expectedBidsCount, uint(len(seatBids[0].Bids)), tc.description)
+		assert.ElementsMatch(t, tc.expectedErrors, errs, tc.description)
+	}
+}
+
+// TestMultiCurrencies_RequestCurrencyPick tests request currencies pick.
+func TestMultiCurrencies_RequestCurrencyPick(t *testing.T) {
+	// Setup:
+	respStatus := 200
+	getRespBody := "{\"wasPost\":false}"
+	postRespBody := "{\"wasPost\":true}"
+
+	testCases := []struct {
+		bidRequestCurrencies   []string
 		bidResponsesCurrency   string
 		expectedPickedCurrency string
 		expectedError          bool
@@ -1195,7 +1904,7 @@ func TestMultiCurrencies_RequestCurrencyPick(t *testing.T) {
 		}
 
 		// Execute:
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 		currencyConverter := currency.NewRateConverter(
 			&http.Client{},
 			mockedHTTPServer.URL,
@@ -1234,6 +1943,91 @@ func TestMultiCurrencies_RequestCurrencyPick(t *testing.T) {
 	}
 }
 
+func TestRequestBidPreferredCurrency(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	rates := currency.NewRates(map[string]map[string]float64{
+		"USD": {"EUR": 0.9, "JPY": 110, "GBP": 0.8},
+	})
+
+	testCases := []struct {
+		description      string
+		givenCurrencies  []string
+		givenPreferred   string
+		expectedCurrency string
+		expectedPrice    float64
+	}{
+		{
+			description:      "First requested currency used if no preferred currency",
+			givenCurrencies:  []string{"EUR", "JPY"},
+			expectedCurrency: "EUR",
+			expectedPrice:    0.9,
+		},
+		{
+			description:      "Preferred currency used if requested",
+			givenCurrencies:  []string{"EUR", "JPY"},
+			givenPreferred:   "JPY",
+			expectedCurrency: "JPY",
+			expectedPrice:    110,
+		},
+		{
+			description:      "Preferred currency matched case-insensitively",
+			givenCurrencies:  []string{"EUR", "JPY"},
+			givenPreferred:   "jpy",
+			expectedCurrency: "JPY",
+			expectedPrice:    110,
+		},
+		{
+			description:      "First requested currency used if preferred currency not requested",
+			givenCurrencies:  []string{"EUR", "JPY"},
+			givenPreferred:   "GBP",
+			expectedCurrency: "EUR",
+			expectedPrice:    0.9,
+		},
+		{
+			description:      "First requested currency having a rate used if preferred currency has no rate",
+			givenCurrencies:  []string{"CNY", "JPY", "EUR"},
+			givenPreferred:   "CNY",
+			expectedCurrency: "JPY",
+			expectedPrice:    110,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{
+					Method: "POST",
+					Uri:    server.URL,
+					Body:   []byte("requestJson"),
+				},
+				bidResponse: &adapters.BidderResponse{
+					Bids:     []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "impId", Price: 1}, BidType: openrtb_ext.BidTypeBanner}},
+					Currency: "USD",
+				},
+			}
+
+			bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Cur: test.givenCurrencies, Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: "test",
+			}
+			bidReqOptions := bidRequestOptions{
+				bidAdjustments:    map[string]float64{"test": 1},
+				preferredCurrency: test.givenPreferred,
+			}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, rates, &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			assert.Empty(t, errs)
+			if assert.Len(t, seatBids, 1) && assert.Len(t, seatBids[0].Bids, 1) {
+				assert.Equal(t, test.expectedCurrency, seatBids[0].Currency)
+				assert.Equal(t, test.expectedPrice, seatBids[0].Bids[0].Bid.Price)
+			}
+		})
+	}
+}
+
 func TestMakeExt(t *testing.T) {
 	testCases := []struct {
 		description string
@@ -1370,7 +2164,7 @@ func TestMakeExt(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		result := makeExt(test.given)
+		result := makeExt(test.given, nil)
 		assert.Equal(t, test.expected, result, test.description)
 	}
 }
@@ -1419,11 +2213,42 @@ func TestFilterHeader(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		result := filterHeader(test.given)
+		result := filterHeader(test.given, nil)
 		assert.Equal(t, test.expected, result, test.description)
 	}
 }
 
+func TestFilterHeaderRedactedHeaders(t *testing.T) {
+	given := makeHeader(map[string][]string{
+		"Authorization":  {"secret"},
+		"Cookie":         {"uids=abc"},
+		"X-Custom-Token": {"token"},
+		"Content-Type":   {"application/json"},
+	})
+
+	result := filterHeader(given, []string{"cookie", "X-Custom-Token"})
+
+	assert.Equal(t, makeHeader(map[string][]string{
+		"Content-Type": {"application/json"},
+	}), result, "Configured headers should be redacted in addition to Authorization, case insensitive.")
+	assert.Len(t, given, 4, "Original headers shouldn't be modified.")
+}
+
+func TestMakeExtRedactedHeaders(t *testing.T) {
+	headers := makeHeader(map[string][]string{
+		"Authorization": {"secret"},
+		"Cookie":        {"uids=abc"},
+		"Content-Type":  {"application/json"},
+	})
+	httpInfo := &httpCallInfo{request: &adapters.RequestData{Uri: "https://bidder.com/bid", Headers: headers}}
+
+	ext := makeExt(httpInfo, []string{"Cookie"})
+
+	assert.Equal(t, map[string][]string{"Content-Type": {"application/json"}}, ext.RequestHeaders)
+	assert.Equal(t, "secret", httpInfo.request.Headers.Get("Authorization"), "Outgoing request headers shouldn't be redacted.")
+	assert.Equal(t, "uids=abc", httpInfo.request.Headers.Get("Cookie"), "Outgoing request headers shouldn't be redacted.")
+}
+
 func makeHeader(v map[string][]string) http.Header {
 	h := http.Header{}
 	for key, values := range v {
@@ -1434,7 +2259,7 @@ func makeHeader(v map[string][]string) http.Header {
 	return h
 }
 
-func TestMobileNativeTypes(t *testing.T) {
+func TestNativeTypes(t *testing.T) {
 	respBody := "{\"bid\":false}"
 	respStatus := 200
 	server := httptest.NewServer(mockHandler(respStatus, "getBody", respBody))
@@ -1503,6 +2328,33 @@ func TestMobileNativeTypes(t *testing.T) {
 			expectedValue: "{\"some-diff-markup\":\"creative\"}",
 			description:   "Non IAB compliant markup",
 		},
+		{
+			mockBidderRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{
+					{
+						ID: "some-imp-id",
+						Native: &openrtb2.Native{
+							Request: "{\"ver\":\"1.1\",\"context\":1,\"contextsubtype\":11,\"plcmttype\":4,\"plcmtcnt\":1,\"assets\":[{\"id\":1,\"required\":1,\"title\":{\"len\":500}},{\"id\":2,\"required\":1,\"img\":{\"type\":3,\"wmin\":1,\"hmin\":1}},{\"id\":3,\"required\":0,\"data\":{\"type\":1,\"len\":200}},{\"id\":4,\"required\":0,\"data\":{\"type\":2,\"len\":15000}},{\"id\":5,\"required\":0,\"data\":{\"type\":6,\"len\":40}}]}",
+						},
+					},
+				},
+				Site: &openrtb2.Site{},
+			},
+			mockBidderResponse: &adapters.BidderResponse{
+				Bids: []*adapters.TypedBid{
+					{
+						Bid: &openrtb2.Bid{
+							ImpID: "some-imp-id",
+							AdM:   "{\"assets\":[{\"id\":2,\"img\":{\"url\":\"http://some-image.com/creative.jpg\",\"w\":989,\"h\":742}},{\"id\":1,\"title\":{\"text\":\"This is a Prebid Native Creative\"}},{\"id\":3,\"data\":{\"value\":\"Prebid.org\"}}],\"link\":{\"url\":\"http://some-url.com\"}}",
+							Price: 10,
+						},
+						BidType: openrtb_ext.BidTypeNative,
+					},
+				},
+			},
+			expectedValue: "{\"assets\":[{\"id\":2,\"img\":{\"type\":3,\"url\":\"http://some-image.com/creative.jpg\",\"w\":989,\"h\":742}},{\"id\":1,\"title\":{\"text\":\"This is a Prebid Native Creative\"}},{\"id\":3,\"data\":{\"type\":1,\"value\":\"Prebid.org\"}}],\"link\":{\"url\":\"http://some-url.com\"}}",
+			description:   "Checks types in response for site request",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1515,7 +2367,7 @@ func TestMobileNativeTypes(t *testing.T) {
 			},
 			bidResponse: tc.mockBidderResponse,
 		}
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 		bidderReq := BidderRequest{
@@ -1562,6 +2414,7 @@ func TestRequestBidsStoredBidResponses(t *testing.T) {
 		mockBidderRequest     *openrtb2.BidRequest
 		bidderStoredResponses map[string]json.RawMessage
 		impReplaceImpId       map[string]bool
+		disableReplacement    bool
 		expectedBidIds        []string
 		expectedImpIds        []string
 	}{
@@ -1627,12 +2480,28 @@ func TestRequestBidsStoredBidResponses(t *testing.T) {
 			expectedBidIds: []string{"bid_id2_1", "bid_id2_2", "bid_id1"},
 			expectedImpIds: []string{"bid1impid1", "bid2impid2", "bidResponseId1"},
 		},
+		{
+			description: "Single imp with multiple stored bid responses, replace impid is true but replacement disabled globally",
+			mockBidderRequest: &openrtb2.BidRequest{
+				Imp: nil,
+				App: &openrtb2.App{},
+			},
+			bidderStoredResponses: map[string]json.RawMessage{
+				"bidResponseId2": bidRespId2,
+			},
+			impReplaceImpId: map[string]bool{
+				"bidResponseId2": true,
+			},
+			disableReplacement: true,
+			expectedBidIds:     []string{"bid_id2_1", "bid_id2_2"},
+			expectedImpIds:     []string{"bid1impid1", "bid2impid2"},
+		},
 	}
 
 	for _, tc := range testCases {
 
 		bidderImpl := &goodSingleBidderWithStoredBidResp{}
-		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+		bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 		currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 		bidderReq := BidderRequest{
@@ -1649,10 +2518,11 @@ func TestRequestBidsStoredBidResponses(t *testing.T) {
 			&adapters.ExtraRequestInfo{},
 			&adscert.NilSigner{},
 			bidRequestOptions{
-				accountDebugAllowed: true,
-				headerDebugAllowed:  true,
-				addCallSignHeader:   false,
-				bidAdjustments:      bidAdjustments,
+				accountDebugAllowed:               true,
+				headerDebugAllowed:                true,
+				addCallSignHeader:                 false,
+				bidAdjustments:                    bidAdjustments,
+				disableStoredRespImpIdReplacement: tc.disableReplacement,
 			},
 			openrtb_ext.ExtAlternateBidderCodes{},
 			&hookexecution.EmptyHookExecutor{},
@@ -1668,8 +2538,44 @@ func TestRequestBidsStoredBidResponses(t *testing.T) {
 
 }
 
+func TestRequestBidsMergedStoredBidResponses(t *testing.T) {
+	bannerResp := `{"id": "resp_id1", "seatbid": [{"bid": [{"id": "banner_bid", "impid": "storedImpId", "mtype": 1}], "seat": "appnexus"}], "cur": "USD"}`
+	videoResp := `{"id": "resp_id2", "seatbid": [{"bid": [{"id": "video_bid", "impid": "storedImpId", "mtype": 2}], "seat": "appnexus"}], "cur": "USD"}`
+
+	bidder := AdaptBidder(&goodSingleBidderWithStoredBidResp{}, nil, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+	bidderReq := BidderRequest{
+		BidRequest:            &openrtb2.BidRequest{App: &openrtb2.App{}},
+		BidderName:            openrtb_ext.BidderAppnexus,
+		BidderStoredResponses: map[string]json.RawMessage{"imp1": json.RawMessage("[" + bannerResp + "," + videoResp + "]")},
+		ImpReplaceImpId:       map[string]bool{"imp1": true},
+	}
+	seatBids, errs := bidder.requestBid(
+		context.Background(),
+		bidderReq,
+		currencyConverter.Rates(),
+		&adapters.ExtraRequestInfo{},
+		&adscert.NilSigner{},
+		bidRequestOptions{bidAdjustments: map[string]float64{string(openrtb_ext.BidderAppnexus): 1.0}},
+		openrtb_ext.ExtAlternateBidderCodes{},
+		&hookexecution.EmptyHookExecutor{},
+	)
+
+	assert.Empty(t, errs, "Unexpected errors.")
+	if !assert.Len(t, seatBids, 1) || !assert.Len(t, seatBids[0].Bids, 2) {
+		return
+	}
+	bidTypes := map[string]openrtb_ext.BidType{}
+	for _, bid := range seatBids[0].Bids {
+		assert.Equal(t, "imp1", bid.Bid.ImpID, "Imp ID should be restored for merged stored bid responses.")
+		bidTypes[bid.Bid.ID] = bid.BidType
+	}
+	assert.Equal(t, map[string]openrtb_ext.BidType{"banner_bid": openrtb_ext.BidTypeBanner, "video_bid": openrtb_ext.BidTypeVideo}, bidTypes)
+}
+
 func TestErrorReporting(t *testing.T) {
-	bidder := AdaptBidder(&bidRejector{}, nil, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	bidder := AdaptBidder(&bidRejector{}, nil, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 	bidderReq := BidderRequest{
 		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
@@ -1694,6 +2600,76 @@ func TestErrorReporting(t *testing.T) {
 	}
 }
 
+func TestRequestBidHookCorrectedNativeBidType(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+		bidResponse: &adapters.BidderResponse{
+			Bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 1, AdM: `{"assets":[{"id":1,"img":{"url":"http://some-url"}}]}`}, BidType: openrtb_ext.BidTypeBanner},
+				{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 1, AdM: "<div>ad</div>"}, BidType: openrtb_ext.BidTypeBanner},
+			},
+		},
+	}
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+	hookExecutor := hookexecution.NewHookExecutor(bidTypeCorrectionPlanBuilder{}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{
+			Site: &openrtb2.Site{},
+			Imp: []openrtb2.Imp{{
+				ID:     "imp1",
+				Banner: &openrtb2.Banner{},
+				Native: &openrtb2.Native{Request: `{"assets":[{"id":1,"img":{"type":3}}]}`},
+			}},
+		},
+		BidderName: openrtb_ext.BidderAppnexus,
+	}
+	seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, hookExecutor)
+
+	assert.Empty(t, errs)
+	if !assert.Len(t, seatBids, 1) || !assert.Len(t, seatBids[0].Bids, 2) {
+		return
+	}
+	nativeBid := seatBids[0].Bids[0]
+	assert.Equal(t, openrtb_ext.BidTypeNative, nativeBid.BidType, "Bid type should be corrected by hook.")
+	assert.JSONEq(t, `{"assets":[{"id":1,"img":{"type":3,"url":"http://some-url"}}],"link":{"url":""}}`, nativeBid.Bid.AdM, "Native asset types should be added to corrected bid.")
+	bannerBid := seatBids[0].Bids[1]
+	assert.Equal(t, openrtb_ext.BidTypeBanner, bannerBid.BidType)
+	assert.Equal(t, "<div>ad</div>", bannerBid.Bid.AdM)
+}
+
+type bidTypeCorrectionPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (b bidTypeCorrectionPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 100 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockBidTypeCorrectionHook{}},
+			},
+		},
+	}
+}
+
+// mockBidTypeCorrectionHook reclassifies bids with native JSON markup as native.
+type mockBidTypeCorrectionHook struct{}
+
+func (h mockBidTypeCorrectionHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	changeSet := hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+	for _, bid := range payload.Bids {
+		if bid.BidType != openrtb_ext.BidTypeNative && strings.HasPrefix(bid.Bid.AdM, `{"assets"`) {
+			changeSet.RawBidderResponse().Bids().UpdateBidType(bid.Bid.ID, openrtb_ext.BidTypeNative)
+		}
+	}
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: changeSet}, nil
+}
+
 func TestSetAssetTypes(t *testing.T) {
 	testCases := []struct {
 		respAsset   nativeResponse.Asset
@@ -1899,9 +2875,10 @@ func TestCallRecordAdapterConnections(t *testing.T) {
 	compareConnWaitTime := func(dur time.Duration) bool { return dur.Nanoseconds() > 0 }
 
 	metrics.On("RecordAdapterConnections", expectedAdapterName, false, mock.MatchedBy(compareConnWaitTime)).Once()
+	metrics.On("RecordAdapterRequestFanout", expectedAdapterName, 1).Once()
 
 	// Run requestBid using an http.Client with a mock handler
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, metrics, openrtb_ext.BidderAppnexus, nil, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, metrics, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -1967,10 +2944,11 @@ func TestCallRecordDNSTime(t *testing.T) {
 		Bidder: &mixedMultiBidder{},
 		Client: &http.Client{Transport: DNSDoneTripper{}},
 		me:     metricsMock,
+		config: bidderAdapterConfig{ConnMetricsSampleRate: 1},
 	}
 
 	// Run test
-	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"})
+	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, "", 0)
 
 	// Tried one or another, none seem to work without panicking
 	metricsMock.AssertExpectations(t)
@@ -1988,57 +2966,1057 @@ func TestCallRecordTLSHandshakeTime(t *testing.T) {
 		Bidder: &mixedMultiBidder{},
 		Client: &http.Client{Transport: TLSHandshakeTripper{}},
 		me:     metricsMock,
+		config: bidderAdapterConfig{ConnMetricsSampleRate: 1},
 	}
 
 	// Run test
-	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"})
+	bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: "http://www.example.com/"}, "", 0)
 
 	// Tried one or another, none seem to work without panicking
 	metricsMock.AssertExpectations(t)
 }
 
-func TestTimeoutNotificationOff(t *testing.T) {
-	respBody := "{\"bid\":false}"
-	respStatus := 200
-	server := httptest.NewServer(mockHandler(respStatus, "getBody", respBody))
+func TestRecordBidderResponseErrors(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "{}"))
 	defer server.Close()
 
-	bidderImpl := &notifyingBidder{
-		notifyRequest: adapters.RequestData{
-			Method:  "GET",
-			Uri:     server.URL + "/notify/me",
-			Body:    nil,
-			Headers: http.Header{},
-		},
+	givenErrs := []error{
+		&errortypes.BadServerResponse{Message: "invalid json"},
+		&errortypes.BadInput{Message: "unsupported media type"},
+		&errortypes.BadServerResponse{Message: "unexpected status code"},
+		&errortypes.Warning{Message: "some warning"},
+		errors.New("some error"),
 	}
-	bidder := &bidderAdapter{
-		Bidder: bidderImpl,
-		Client: server.Client(),
-		config: bidderAdapterConfig{Debug: config.Debug{}},
-		me:     &metricsConfig.NilMetricsEngine{},
+	bidderImpl := &responseErrorsBidder{
+		httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+		errs:        givenErrs,
 	}
-	if tb, ok := bidder.Bidder.(adapters.TimeoutBidder); !ok {
-		t.Error("Failed to cast bidder to a TimeoutBidder")
-	} else {
-		bidder.doTimeoutNotification(tb, &adapters.RequestData{}, glog.Warningf)
+
+	metricsMock := &metrics.MetricsEngineMock{}
+	metricsMock.On("RecordBidderResponseError", openrtb_ext.BidderAppnexus, metrics.AdapterErrorBadServerResponse).Twice()
+	metricsMock.On("RecordBidderResponseError", openrtb_ext.BidderAppnexus, metrics.AdapterErrorBadInput).Once()
+	metricsMock.On("RecordBidderResponseError", openrtb_ext.BidderAppnexus, metrics.AdapterErrorUnknown).Once()
+	metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, 1).Once()
+
+	cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+	bidder := AdaptBidder(bidderImpl, server.Client(), cfg, metricsMock, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+	bidderReq := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+		BidderName: openrtb_ext.BidderAppnexus,
 	}
+	_, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+	assert.Equal(t, givenErrs, errs, "Errors returned by the adapter should be unchanged.")
+	metricsMock.AssertExpectations(t)
 }
 
-func TestTimeoutNotificationOn(t *testing.T) {
-	// Expire context immediately to force timeout handler.
-	ctx, cancelFunc := context.WithDeadline(context.Background(), time.Now())
-	cancelFunc()
+type responseErrorsBidder struct {
+	httpRequest *adapters.RequestData
+	errs        []error
+}
 
-	// Notification logic is hardcoded for 200ms. We need to wait for a little longer than that.
-	server := httptest.NewServer(mockSlowHandler(205*time.Millisecond, 200, `{"bid":false}`))
-	defer server.Close()
+func (bidder *responseErrorsBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	return []*adapters.RequestData{bidder.httpRequest}, nil
+}
 
-	bidder := &notifyingBidder{
-		notifyRequest: adapters.RequestData{
-			Method:  "GET",
-			Uri:     server.URL + "/notify/me",
-			Body:    nil,
-			Headers: http.Header{},
+func (bidder *responseErrorsBidder) MakeBids(internalRequest *openrtb2.BidRequest, externalRequest *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	return nil, bidder.errs
+}
+
+func TestRemoveBidsWithInvalidImpIds(t *testing.T) {
+	testCases := []struct {
+		description          string
+		givenBidderRequest   BidderRequest
+		givenBids            []*adapters.TypedBid
+		expectedBids         []*adapters.TypedBid
+		expectedErrs         []error
+		expectedMetricsCount int
+	}{
+		{
+			description: "All bids match request imps",
+			givenBidderRequest: BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}},
+			},
+			givenBids:    []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}, {Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2"}}},
+			expectedBids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}, {Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp2"}}},
+		},
+		{
+			description: "Bid with unknown imp ID dropped",
+			givenBidderRequest: BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}},
+			},
+			givenBids:    []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}, {Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp3"}}},
+			expectedBids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}},
+			expectedErrs: []error{&errortypes.Warning{
+				WarningCode: errortypes.InvalidBidImpIDWarningCode,
+				Message:     "Bid bid2 dropped: imp ID imp3 doesn't match any imp of the bid request",
+			}},
+			expectedMetricsCount: 1,
+		},
+		{
+			description: "Bid for stored response imp kept",
+			givenBidderRequest: BidderRequest{
+				BidRequest:            &openrtb2.BidRequest{},
+				BidderStoredResponses: map[string]json.RawMessage{"imp1": json.RawMessage(`{}`)},
+			},
+			givenBids:    []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}},
+			expectedBids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordBidValidationImpIDError", openrtb_ext.BidderAppnexus).Return()
+			metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, 1).Return()
+			bidder := &bidderAdapter{BidderName: openrtb_ext.BidderAppnexus, me: metricsMock}
+
+			bids, errs := bidder.removeBidsWithInvalidImpIds(test.givenBids, test.givenBidderRequest)
+
+			assert.Equal(t, test.expectedBids, bids, "Incorrect bids.")
+			assert.Equal(t, test.expectedErrs, errs, "Incorrect errors.")
+			metricsMock.AssertNumberOfCalls(t, "RecordBidValidationImpIDError", test.expectedMetricsCount)
+		})
+	}
+}
+
+func TestRequestBidMaxBidCPM(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description          string
+		givenMaxBidCPM       float64
+		expectedBidIDs       []string
+		expectedErrs         []error
+		expectedMetricsCount int
+	}{
+		{
+			description:    "Check disabled",
+			givenMaxBidCPM: 0,
+			expectedBidIDs: []string{"bid1", "bid2"},
+		},
+		{
+			description:    "Adjusted price above max CPM dropped",
+			givenMaxBidCPM: 10,
+			expectedBidIDs: []string{"bid1"},
+			expectedErrs: []error{&errortypes.Warning{
+				WarningCode: errortypes.MaxBidCPMExceededWarningCode,
+				Message:     "Bid bid2 dropped: price 12 exceeds the max bid CPM 10",
+			}},
+			expectedMetricsCount: 1,
+		},
+		{
+			description:    "Adjusted price equal to max CPM kept",
+			givenMaxBidCPM: 12,
+			expectedBidIDs: []string{"bid1", "bid2"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+				bidResponse: &adapters.BidderResponse{
+					Bids: []*adapters.TypedBid{
+						{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "impId", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+						{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "impId", Price: 6}, BidType: openrtb_ext.BidTypeBanner},
+					},
+				},
+			}
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordBidValidationMaxCPMError", openrtb_ext.BidderAppnexus).Return()
+			metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, 1).Return()
+
+			cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, metricsMock, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			bidReqOptions := bidRequestOptions{
+				bidAdjustments: map[string]float64{string(openrtb_ext.BidderAppnexus): 2},
+				maxBidCPM:      test.givenMaxBidCPM,
+			}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			if !assert.Len(t, seatBids, 1) {
+				return
+			}
+			var bidIDs []string
+			for _, bid := range seatBids[0].Bids {
+				bidIDs = append(bidIDs, bid.Bid.ID)
+			}
+			assert.Equal(t, test.expectedBidIDs, bidIDs, "Incorrect bids.")
+			assert.Equal(t, test.expectedErrs, errs, "Incorrect errors.")
+			metricsMock.AssertNumberOfCalls(t, "RecordBidValidationMaxCPMError", test.expectedMetricsCount)
+		})
+	}
+}
+
+func TestRequestBidEnforceFloors(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description          string
+		givenEnforceFloors   bool
+		givenFloorImp        openrtb2.Imp
+		expectedBidIDs       []string
+		expectedErrs         []error
+		expectedMetricsCount int
+	}{
+		{
+			description:        "Enforcement disabled",
+			givenEnforceFloors: false,
+			givenFloorImp:      openrtb2.Imp{ID: "imp1", BidFloor: 1, BidFloorCur: "GBP"},
+			expectedBidIDs:     []string{"bid1", "bid2", "bid3"},
+		},
+		{
+			description:        "Bid below floor converted from another currency dropped",
+			givenEnforceFloors: true,
+			givenFloorImp:      openrtb2.Imp{ID: "imp1", BidFloor: 1, BidFloorCur: "GBP"},
+			expectedBidIDs:     []string{"bid2", "bid3"},
+			expectedErrs: []error{&errortypes.Warning{
+				WarningCode: errortypes.BidBelowFloorWarningCode,
+				Message:     "Bid bid1 dropped: price 2 USD is below the imp imp1 floor 4 USD",
+			}},
+			expectedMetricsCount: 1,
+		},
+		{
+			description:        "Floor currency defaults to USD, bid equal to floor kept",
+			givenEnforceFloors: true,
+			givenFloorImp:      openrtb2.Imp{ID: "imp1", BidFloor: 2},
+			expectedBidIDs:     []string{"bid1", "bid2", "bid3"},
+		},
+		{
+			description:        "Floor not enforced if floor currency cannot be converted",
+			givenEnforceFloors: true,
+			givenFloorImp:      openrtb2.Imp{ID: "imp1", BidFloor: 10, BidFloorCur: "JPY"},
+			expectedBidIDs:     []string{"bid1", "bid2", "bid3"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+				bidResponse: &adapters.BidderResponse{
+					Currency: "EUR",
+					Bids: []*adapters.TypedBid{
+						{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+						{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", Price: 3}, BidType: openrtb_ext.BidTypeBanner},
+						{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp2", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+					},
+				},
+			}
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordBidValidationFloorError", openrtb_ext.BidderAppnexus).Return()
+			metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, 1).Return()
+
+			cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, metricsMock, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			rates := currency.NewRates(map[string]map[string]float64{
+				"EUR": {"USD": 2},
+				"GBP": {"USD": 4},
+			})
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{
+					Cur: []string{"USD"},
+					Imp: []openrtb2.Imp{test.givenFloorImp, {ID: "imp2"}},
+				},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			bidReqOptions := bidRequestOptions{enforceFloors: test.givenEnforceFloors}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, rates, &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			if !assert.Len(t, seatBids, 1) {
+				return
+			}
+			var bidIDs []string
+			for _, bid := range seatBids[0].Bids {
+				bidIDs = append(bidIDs, bid.Bid.ID)
+			}
+			assert.Equal(t, test.expectedBidIDs, bidIDs, "Incorrect bids.")
+			assert.Equal(t, test.expectedErrs, errs, "Incorrect errors.")
+			metricsMock.AssertNumberOfCalls(t, "RecordBidValidationFloorError", test.expectedMetricsCount)
+		})
+	}
+}
+
+func TestRequestBidNoContentResponse(t *testing.T) {
+	server := httptest.NewServer(mockHandler(http.StatusNoContent, "", ""))
+	defer server.Close()
+
+	testCases := []struct {
+		description           string
+		givenNoContentAsNoBid bool
+		expectMakeBidsCalled  bool
+		expectedMetricsCount  int
+	}{
+		{
+			description:           "No content response treated as no-bid",
+			givenNoContentAsNoBid: true,
+			expectMakeBidsCalled:  false,
+			expectedMetricsCount:  1,
+		},
+		{
+			description:           "No content response passed to adapter if disabled",
+			givenNoContentAsNoBid: false,
+			expectMakeBidsCalled:  true,
+			expectedMetricsCount:  0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+				bidResponse: &adapters.BidderResponse{},
+			}
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordNoBidResponse", openrtb_ext.BidderAppnexus).Return()
+			metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, 1).Return()
+
+			cfg := &config.Configuration{
+				Metrics:          config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}},
+				NoContentAsNoBid: test.givenNoContentAsNoBid,
+			}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, metricsMock, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			assert.Empty(t, errs, "No errors expected.")
+			if assert.Len(t, seatBids, 1) {
+				assert.Empty(t, seatBids[0].Bids, "No bids expected.")
+			}
+			if test.expectMakeBidsCalled {
+				if assert.NotNil(t, bidderImpl.httpResponse, "MakeBids should be called.") {
+					assert.Equal(t, http.StatusNoContent, bidderImpl.httpResponse.StatusCode)
+				}
+			} else {
+				assert.Nil(t, bidderImpl.httpResponse, "MakeBids shouldn't be called.")
+			}
+			metricsMock.AssertNumberOfCalls(t, "RecordNoBidResponse", test.expectedMetricsCount)
+		})
+	}
+}
+
+func TestRequestBidRecordsRequestFanout(t *testing.T) {
+	server := httptest.NewServer(mockHandler(http.StatusNoContent, "", ""))
+	defer server.Close()
+
+	testCases := []struct {
+		description       string
+		givenRequestCount int
+	}{
+		{
+			description:       "Single request",
+			givenRequestCount: 1,
+		},
+		{
+			description:       "Multiple requests",
+			givenRequestCount: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &mixedMultiBidder{bidResponse: &adapters.BidderResponse{}}
+			for i := 0; i < test.givenRequestCount; i++ {
+				bidderImpl.httpRequests = append(bidderImpl.httpRequests, &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}})
+			}
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, test.givenRequestCount).Once()
+			metricsMock.On("RecordNoBidResponse", openrtb_ext.BidderAppnexus).Return()
+
+			cfg := &config.Configuration{
+				Metrics:          config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}},
+				NoContentAsNoBid: true,
+			}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, metricsMock, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			metricsMock.AssertCalled(t, "RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, test.givenRequestCount)
+			metricsMock.AssertNumberOfCalls(t, "RecordAdapterRequestFanout", 1)
+		})
+	}
+}
+
+func TestRequestBidDedupAlternateSeatBids(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description          string
+		givenDedup           bool
+		expectedBids         map[string][]string
+		expectedRemovedCount int
+	}{
+		{
+			description: "Duplicates kept if dedup disabled",
+			givenDedup:  false,
+			expectedBids: map[string][]string{
+				"appnexus": {"bid1"},
+				"groupm":   {"bid2", "bid3", "bid4", "bid6"},
+				"pubmatic": {"bid5"},
+			},
+		},
+		{
+			description: "Duplicates removed from lower priority seats",
+			givenDedup:  true,
+			expectedBids: map[string][]string{
+				"appnexus": {"bid1"},
+				"groupm":   {"bid3", "bid4", "bid6"},
+			},
+			expectedRemovedCount: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+				bidResponse: &adapters.BidderResponse{
+					Bids: []*adapters.TypedBid{
+						{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1", CrID: "cr1", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+						{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1", CrID: "cr1", Price: 1}, BidType: openrtb_ext.BidTypeBanner, Seat: "groupm"},
+						{Bid: &openrtb2.Bid{ID: "bid3", ImpID: "imp1", CrID: "cr2", Price: 1}, BidType: openrtb_ext.BidTypeBanner, Seat: "groupm"},
+						{Bid: &openrtb2.Bid{ID: "bid4", ImpID: "imp1", CrID: "cr1", Price: 2}, BidType: openrtb_ext.BidTypeBanner, Seat: "groupm"},
+						{Bid: &openrtb2.Bid{ID: "bid5", ImpID: "imp2", CrID: "cr3", Price: 1}, BidType: openrtb_ext.BidTypeBanner, Seat: "pubmatic"},
+						{Bid: &openrtb2.Bid{ID: "bid6", ImpID: "imp2", CrID: "cr3", Price: 1}, BidType: openrtb_ext.BidTypeBanner, Seat: "groupm"},
+					},
+				},
+			}
+
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordDuplicateBids", openrtb_ext.BidderAppnexus, test.expectedRemovedCount).Return()
+			metricsMock.On("RecordAdapterRequestFanout", openrtb_ext.BidderAppnexus, 1).Return()
+
+			cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, metricsMock, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			alternateBidderCodes := openrtb_ext.ExtAlternateBidderCodes{
+				Enabled: true,
+				Bidders: map[string]openrtb_ext.ExtAdapterAlternateBidderCodes{
+					string(openrtb_ext.BidderAppnexus): {Enabled: true, AllowedBidderCodes: []string{"groupm", "pubmatic"}},
+				},
+			}
+			bidReqOptions := bidRequestOptions{dedupAlternateSeatBids: test.givenDedup}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, alternateBidderCodes, &hookexecution.EmptyHookExecutor{})
+
+			assert.Empty(t, errs)
+			bids := make(map[string][]string, len(seatBids))
+			for _, seatBid := range seatBids {
+				for _, bid := range seatBid.Bids {
+					bids[seatBid.Seat] = append(bids[seatBid.Seat], bid.Bid.ID)
+				}
+			}
+			assert.Equal(t, test.expectedBids, bids, "Incorrect bids.")
+			if test.expectedRemovedCount > 0 {
+				metricsMock.AssertCalled(t, "RecordDuplicateBids", openrtb_ext.BidderAppnexus, test.expectedRemovedCount)
+			} else {
+				metricsMock.AssertNotCalled(t, "RecordDuplicateBids", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRequestBidDefaultCurrency(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "responseJson"))
+	defer server.Close()
+
+	testCases := []struct {
+		description         string
+		givenCurrency       string
+		expectedCurrency    string
+		expectedRequestCurs []string
+	}{
+		{
+			description:         "USD if not configured",
+			givenCurrency:       "",
+			expectedCurrency:    "USD",
+			expectedRequestCurs: []string{"USD"},
+		},
+		{
+			description:         "Configured currency used",
+			givenCurrency:       "EUR",
+			expectedCurrency:    "EUR",
+			expectedRequestCurs: []string{"EUR"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &goodSingleBidder{
+				httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}},
+				bidResponse: &adapters.BidderResponse{
+					Bids: []*adapters.TypedBid{
+						{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "impId", Price: 1}, BidType: openrtb_ext.BidTypeBanner},
+					},
+				},
+			}
+
+			cfg := &config.Configuration{Metrics: config.Metrics{Disabled: config.DisabledMetrics{AdapterConnectionMetrics: true}}}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+			bidReqOptions := bidRequestOptions{defaultCurrency: test.givenCurrency}
+			seatBids, errs := bidder.requestBid(context.Background(), bidderReq, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidReqOptions, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			assert.Empty(t, errs, "Unexpected errors.")
+			if assert.Len(t, seatBids, 1) {
+				assert.Equal(t, test.expectedCurrency, seatBids[0].Currency, "Incorrect seat bid currency.")
+				assert.Len(t, seatBids[0].Bids, 1, "Bid without currency should be kept.")
+			}
+			assert.Equal(t, test.expectedRequestCurs, bidderReq.BidRequest.Cur, "Incorrect bid request currencies.")
+		})
+	}
+}
+
+func TestEndpointCompression(t *testing.T) {
+	testCases := []struct {
+		description         string
+		givenConfig         string
+		givenOverride       string
+		expectedCompression string
+	}{
+		{description: "Adapter config used without override", givenConfig: "gzip", givenOverride: "", expectedCompression: Gzip},
+		{description: "Override enables compression", givenConfig: "", givenOverride: "gzip", expectedCompression: Gzip},
+		{description: "Override disables compression", givenConfig: "GZIP", givenOverride: "none", expectedCompression: NoCompression},
+		{description: "Unknown override falls back to adapter config", givenConfig: "GZIP", givenOverride: "brotli", expectedCompression: Gzip},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidder := &bidderAdapter{config: bidderAdapterConfig{EndpointCompression: test.givenConfig}}
+			assert.Equal(t, test.expectedCompression, bidder.endpointCompression(test.givenOverride))
+		})
+	}
+}
+
+func TestDoRequestEndpointCompressionOverride(t *testing.T) {
+	var contentEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentEncoding = r.Header.Get("Content-Encoding")
+	}))
+	defer server.Close()
+
+	bidder := &bidderAdapter{
+		Bidder:     &mixedMultiBidder{},
+		Client:     server.Client(),
+		BidderName: openrtb_ext.BidderAppnexus,
+		me:         &metricsConfig.NilMetricsEngine{},
+		config:     bidderAdapterConfig{DisableConnMetrics: true},
+	}
+
+	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}}, "gzip", 0)
+
+	assert.NoError(t, callInfo.err)
+	assert.Equal(t, "gzip", contentEncoding, "Request should be compressed as requested by the override.")
+}
+
+func TestDoRequestRecordsCompression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	body := []byte(`{"id":"request-id","imp":[{"id":"imp-1"},{"id":"imp-2"},{"id":"imp-3"}]}`)
+
+	testCases := []struct {
+		description         string
+		endpointCompression string
+		expectRecorded      bool
+	}{
+		{description: "Recorded if compressed", endpointCompression: "gzip", expectRecorded: true},
+		{description: "Not recorded without compression", endpointCompression: "", expectRecorded: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsMock := &metrics.MetricsEngineMock{}
+			if test.expectRecorded {
+				metricsMock.On("RecordRequestCompression", openrtb_ext.BidderAppnexus, len(body), len(compressToGZIPLevel(body, gzip.DefaultCompression))).Once()
+			}
+			bidder := &bidderAdapter{
+				Bidder:     &mixedMultiBidder{},
+				Client:     server.Client(),
+				BidderName: openrtb_ext.BidderAppnexus,
+				me:         metricsMock,
+				config:     bidderAdapterConfig{DisableConnMetrics: true, EndpointCompression: test.endpointCompression, GzipLevel: gzip.DefaultCompression},
+			}
+
+			callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: body, Headers: http.Header{}}, "", 0)
+
+			assert.NoError(t, callInfo.err)
+			metricsMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDoRequestMaxResponseBytes(t *testing.T) {
+	body := []byte(`{"id":"response-id","seatbid":[]}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		description           string
+		givenMaxResponseBytes int64
+		expectedError         error
+	}{
+		{
+			description:           "Response within limit read",
+			givenMaxResponseBytes: int64(len(body)),
+		},
+		{
+			description:           "Default limit used if not configured",
+			givenMaxResponseBytes: 0,
+		},
+		{
+			description:           "Oversized response rejected",
+			givenMaxResponseBytes: int64(len(body)) - 1,
+			expectedError:         &errortypes.BadServerResponse{Message: fmt.Sprintf("Response of bidder appnexus exceeds the limit of %d bytes", len(body)-1)},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidder := &bidderAdapter{
+				Bidder:     &mixedMultiBidder{},
+				Client:     server.Client(),
+				BidderName: openrtb_ext.BidderAppnexus,
+				me:         &metricsConfig.NilMetricsEngine{},
+				config:     bidderAdapterConfig{DisableConnMetrics: true, MaxResponseBytes: test.givenMaxResponseBytes},
+			}
+
+			callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}}, "", 0)
+
+			assert.Equal(t, test.expectedError, callInfo.err)
+			if test.expectedError == nil {
+				assert.Equal(t, body, callInfo.response.Body)
+			} else {
+				assert.Nil(t, callInfo.response, "Oversized response shouldn't be returned.")
+			}
+		})
+	}
+}
+
+func TestReceiveHttpCallInfo(t *testing.T) {
+	t.Run("Available response returned even if context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		responseChannel := make(chan *httpCallInfo, 1)
+		responseChannel <- &httpCallInfo{}
+
+		httpInfo, received := receiveHttpCallInfo(ctx, responseChannel)
+
+		assert.True(t, received)
+		assert.NotNil(t, httpInfo)
+	})
+
+	t.Run("Waiting stopped when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		responseChannel := make(chan *httpCallInfo, 1)
+
+		httpInfo, received := receiveHttpCallInfo(ctx, responseChannel)
+
+		assert.False(t, received)
+		assert.Nil(t, httpInfo)
+	})
+
+	t.Run("Response sent later received", func(t *testing.T) {
+		responseChannel := make(chan *httpCallInfo, 1)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			responseChannel <- &httpCallInfo{}
+		}()
+
+		httpInfo, received := receiveHttpCallInfo(context.Background(), responseChannel)
+
+		assert.True(t, received)
+		assert.NotNil(t, httpInfo)
+	})
+}
+
+func TestDoRequestFallbackEndpoint(t *testing.T) {
+	var fallbackCalls int
+	var fallbackPath string
+	fallbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackCalls++
+		fallbackPath = r.URL.RequestURI()
+	}))
+	defer fallbackServer.Close()
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+
+	closedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedServer.Close()
+
+	testCases := []struct {
+		description           string
+		givenUri              string
+		givenFallbackEndpoint string
+		expectFallback        bool
+		expectError           bool
+	}{
+		{
+			description:           "Fallback used on connection failure",
+			givenUri:              closedServer.URL + "/bid?id=1",
+			givenFallbackEndpoint: fallbackServer.URL,
+			expectFallback:        true,
+		},
+		{
+			description:           "Fallback not used on failure status",
+			givenUri:              failingServer.URL + "/bid?id=1",
+			givenFallbackEndpoint: fallbackServer.URL,
+			expectError:           true,
+		},
+		{
+			description: "Connection failure returned if fallback not configured",
+			givenUri:    closedServer.URL + "/bid?id=1",
+			expectError: true,
+		},
+		{
+			description:           "Connection failure returned if fallback invalid",
+			givenUri:              closedServer.URL + "/bid?id=1",
+			givenFallbackEndpoint: "invalid",
+			expectError:           true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			fallbackCalls = 0
+			fallbackPath = ""
+			bidder := &bidderAdapter{
+				Bidder:     &mixedMultiBidder{},
+				Client:     http.DefaultClient,
+				BidderName: openrtb_ext.BidderAppnexus,
+				me:         &metricsConfig.NilMetricsEngine{},
+				config:     bidderAdapterConfig{DisableConnMetrics: true, FallbackEndpoint: test.givenFallbackEndpoint},
+			}
+			req := &adapters.RequestData{Method: "POST", Uri: test.givenUri, Body: []byte("{}"), Headers: http.Header{}}
+
+			callInfo := bidder.doRequestImpl(context.Background(), req, "", 0, func(msg string, args ...interface{}) {})
+
+			if test.expectError {
+				assert.Error(t, callInfo.err)
+			} else {
+				assert.NoError(t, callInfo.err)
+			}
+			if test.expectFallback {
+				assert.Equal(t, 1, fallbackCalls, "Fallback endpoint should be called once.")
+				assert.Equal(t, "/bid?id=1", fallbackPath, "Only the host should be replaced.")
+				assert.Equal(t, fallbackServer.URL+"/bid?id=1", callInfo.request.Uri)
+				if assert.NotNil(t, callInfo.previousAttempt, "Failed attempt should be kept.") {
+					assert.Equal(t, test.givenUri, callInfo.previousAttempt.request.Uri)
+					assert.Error(t, callInfo.previousAttempt.err)
+				}
+			} else {
+				assert.Zero(t, fallbackCalls, "Fallback endpoint shouldn't be called.")
+				assert.Nil(t, callInfo.previousAttempt)
+			}
+			assert.Equal(t, test.givenUri, req.Uri, "Original request shouldn't be modified.")
+		})
+	}
+}
+
+func TestDoRequestGzipProbe(t *testing.T) {
+	var requestEncodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestEncodings = append(requestEncodings, r.Header.Get("Content-Encoding"))
+		switch {
+		case r.URL.Query().Get("fail") == "true":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("Content-Encoding") == "gzip" && r.URL.Query().Get("gzip") == "false":
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		description              string
+		givenQuery               string
+		givenProbeRate           float64
+		givenEndpointCompression string
+		expectedRequestEncodings []string
+		expectedProbeSuccess     []bool
+		expectedPreviousAttempt  bool
+		expectedResponseStatus   int
+	}{
+		{
+			description:              "Probe accepted by bidder",
+			givenQuery:               "gzip=true",
+			givenProbeRate:           1,
+			expectedRequestEncodings: []string{"gzip"},
+			expectedProbeSuccess:     []bool{true},
+			expectedResponseStatus:   http.StatusOK,
+		},
+		{
+			description:              "Request resent uncompressed if probe rejected by bidder",
+			givenQuery:               "gzip=false",
+			givenProbeRate:           1,
+			expectedRequestEncodings: []string{"gzip", ""},
+			expectedProbeSuccess:     []bool{false},
+			expectedPreviousAttempt:  true,
+			expectedResponseStatus:   http.StatusOK,
+		},
+		{
+			description:              "Probe not recorded on server error",
+			givenQuery:               "fail=true",
+			givenProbeRate:           1,
+			expectedRequestEncodings: []string{"gzip"},
+			expectedResponseStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			description:              "Request not probed if disabled",
+			givenQuery:               "gzip=false",
+			givenProbeRate:           0,
+			expectedRequestEncodings: []string{""},
+			expectedResponseStatus:   http.StatusOK,
+		},
+		{
+			description:              "Request not probed if compression configured",
+			givenQuery:               "gzip=true",
+			givenProbeRate:           1,
+			givenEndpointCompression: Gzip,
+			expectedRequestEncodings: []string{"gzip"},
+			expectedResponseStatus:   http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			requestEncodings = nil
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordRequestCompression", openrtb_ext.BidderAppnexus, mock.Anything, mock.Anything).Return()
+			metricsMock.On("RecordGzipProbe", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+
+			bidder := &bidderAdapter{
+				Bidder:     &mixedMultiBidder{},
+				Client:     http.DefaultClient,
+				BidderName: openrtb_ext.BidderAppnexus,
+				me:         metricsMock,
+				config: bidderAdapterConfig{
+					DisableConnMetrics:  true,
+					EndpointCompression: test.givenEndpointCompression,
+					GzipProbeRate:       test.givenProbeRate,
+				},
+			}
+			req := &adapters.RequestData{Method: "POST", Uri: server.URL + "/bid?" + test.givenQuery, Body: []byte("{}"), Headers: http.Header{}}
+
+			callInfo := bidder.doRequestImpl(context.Background(), req, "", 0, func(msg string, args ...interface{}) {})
+
+			assert.Equal(t, test.expectedRequestEncodings, requestEncodings)
+			if assert.NotNil(t, callInfo.response) {
+				assert.Equal(t, test.expectedResponseStatus, callInfo.response.StatusCode)
+			}
+			metricsMock.AssertNumberOfCalls(t, "RecordGzipProbe", len(test.expectedProbeSuccess))
+			for _, success := range test.expectedProbeSuccess {
+				metricsMock.AssertCalled(t, "RecordGzipProbe", openrtb_ext.BidderAppnexus, success)
+			}
+			if test.expectedPreviousAttempt {
+				if assert.NotNil(t, callInfo.previousAttempt, "Rejected probe should be kept.") {
+					assert.Equal(t, http.StatusUnsupportedMediaType, callInfo.previousAttempt.response.StatusCode)
+				}
+			} else {
+				assert.Nil(t, callInfo.previousAttempt)
+			}
+			if test.givenEndpointCompression == "" {
+				assert.Empty(t, req.Headers.Get("Content-Encoding"), "Original request shouldn't be modified.")
+			}
+		})
+	}
+}
+
+func TestMakeExtsWithPreviousAttempt(t *testing.T) {
+	httpInfo := &httpCallInfo{
+		request:  &adapters.RequestData{Uri: "https://fallback.com/bid"},
+		response: &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte("{}")},
+		previousAttempt: &httpCallInfo{
+			request: &adapters.RequestData{Uri: "https://primary.com/bid"},
+			err:     errors.New("connection refused"),
+		},
+	}
+
+	exts := makeExts(httpInfo, nil)
+
+	if assert.Len(t, exts, 2) {
+		assert.Equal(t, "https://primary.com/bid", exts[0].Uri)
+		assert.Zero(t, exts[0].Status)
+		assert.Equal(t, "https://fallback.com/bid", exts[1].Uri)
+		assert.Equal(t, http.StatusOK, exts[1].Status)
+	}
+}
+
+func TestRequestBidMaxConcurrentRequests(t *testing.T) {
+	testCases := []struct {
+		description           string
+		maxConcurrentRequests int
+		expectedMaxInFlight   int32
+	}{
+		{description: "Requests not limited by default", maxConcurrentRequests: 0, expectedMaxInFlight: 4},
+		{description: "Requests limited", maxConcurrentRequests: 2, expectedMaxInFlight: 2},
+		{description: "Limit above number of requests", maxConcurrentRequests: 10, expectedMaxInFlight: 4},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var inFlight, maxInFlight, arrived int32
+			var arrivals sync.WaitGroup
+			arrivals.Add(int(test.expectedMaxInFlight))
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					observed := atomic.LoadInt32(&maxInFlight)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+						break
+					}
+				}
+				if atomic.AddInt32(&arrived, 1) <= test.expectedMaxInFlight {
+					// hold the first requests until as many as expected are in flight
+					arrivals.Done()
+					arrivals.Wait()
+				}
+				time.Sleep(5 * time.Millisecond)
+				w.Write([]byte("{}"))
+			}))
+			defer server.Close()
+
+			requests := make([]*adapters.RequestData, 4)
+			for i := range requests {
+				requests[i] = &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}")}
+			}
+			bidderImpl := &mixedMultiBidder{httpRequests: requests, bidResponse: &adapters.BidderResponse{}}
+			cfg := &config.Configuration{MaxConcurrentBidderRequests: test.maxConcurrentRequests}
+			bidder := AdaptBidder(bidderImpl, server.Client(), cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+			bidderReq := BidderRequest{
+				BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}},
+				BidderName: openrtb_ext.BidderAppnexus,
+			}
+
+			_, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{})
+
+			assert.Len(t, bidderImpl.httpResponses, 4, "All requests should be sent.")
+			assert.Len(t, errs, 5, "Each request and response error should be reported.")
+			assert.Equal(t, test.expectedMaxInFlight, atomic.LoadInt32(&maxInFlight), "Incorrect number of parallel requests.")
+		})
+	}
+}
+
+func TestSampleConnMetrics(t *testing.T) {
+	testCases := []struct {
+		description    string
+		givenRate      float64
+		expectedSample bool
+	}{
+		{description: "Every request sampled with rate one", givenRate: 1, expectedSample: true},
+		{description: "Request not sampled with negligible rate", givenRate: 1e-18, expectedSample: false},
+	}
+
+	for _, test := range testCases {
+		bidder := &bidderAdapter{config: bidderAdapterConfig{ConnMetricsSampleRate: test.givenRate}}
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, test.expectedSample, bidder.sampleConnMetrics(), test.description)
+		}
+	}
+}
+
+func TestAdaptBidderConnMetricsSampleRate(t *testing.T) {
+	testCases := []struct {
+		description  string
+		givenRate    float64
+		expectedRate float64
+	}{
+		{description: "Every request sampled if rate not set", givenRate: 0, expectedRate: 1},
+		{description: "Configured rate", givenRate: 0.1, expectedRate: 0.1},
+	}
+
+	for _, test := range testCases {
+		cfg := &config.Configuration{Metrics: config.Metrics{AdapterConnectionsSampleRate: test.givenRate}}
+		bidder := AdaptBidder(&mixedMultiBidder{}, nil, cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
+		assert.Equal(t, test.expectedRate, bidder.(*bidderAdapter).config.ConnMetricsSampleRate, test.description)
+	}
+}
+
+func TestTimeoutNotificationOff(t *testing.T) {
+	respBody := "{\"bid\":false}"
+	respStatus := 200
+	server := httptest.NewServer(mockHandler(respStatus, "getBody", respBody))
+	defer server.Close()
+
+	bidderImpl := &notifyingBidder{
+		notifyRequest: adapters.RequestData{
+			Method:  "GET",
+			Uri:     server.URL + "/notify/me",
+			Body:    nil,
+			Headers: http.Header{},
+		},
+	}
+	bidder := &bidderAdapter{
+		Bidder: bidderImpl,
+		Client: server.Client(),
+		config: bidderAdapterConfig{Debug: config.Debug{}},
+		me:     &metricsConfig.NilMetricsEngine{},
+	}
+	if tb, ok := bidder.Bidder.(adapters.TimeoutBidder); !ok {
+		t.Error("Failed to cast bidder to a TimeoutBidder")
+	} else {
+		bidder.doTimeoutNotification(tb, &adapters.RequestData{}, glog.Warningf)
+	}
+}
+
+func TestTimeoutNotificationOn(t *testing.T) {
+	// Expire context immediately to force timeout handler.
+	ctx, cancelFunc := context.WithDeadline(context.Background(), time.Now())
+	cancelFunc()
+
+	// Notification timeout defaults to 200ms if not configured. We need to wait for a little longer than that.
+	server := httptest.NewServer(mockSlowHandler(205*time.Millisecond, 200, `{"bid":false}`))
+	defer server.Close()
+
+	bidder := &notifyingBidder{
+		notifyRequest: adapters.RequestData{
+			Method:  "GET",
+			Uri:     server.URL + "/notify/me",
+			Body:    nil,
+			Headers: http.Header{},
 		},
 	}
 
@@ -2079,7 +4057,7 @@ func TestTimeoutNotificationOn(t *testing.T) {
 		loggerBuffer.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
 	}
 
-	bidderAdapter.doRequestImpl(ctx, &bidRequest, logger)
+	bidderAdapter.doRequestImpl(ctx, &bidRequest, "", 0, logger)
 
 	// Wait a little longer than the 205ms mock server sleep.
 	time.Sleep(210 * time.Millisecond)
@@ -2089,6 +4067,334 @@ func TestTimeoutNotificationOn(t *testing.T) {
 	assert.EqualValues(t, logExpected, logActual)
 }
 
+func TestDoRequestMaxRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(mockSlowHandler(100*time.Millisecond, 200, `{"bid":false}`))
+	defer server.Close()
+
+	testCases := []struct {
+		description            string
+		givenMaxRequestTimeout time.Duration
+		givenRequestTimeout    time.Duration
+		expectTimeout          bool
+	}{
+		{
+			description:            "Timeout if bidder exceeds its own budget",
+			givenMaxRequestTimeout: 10 * time.Millisecond,
+			expectTimeout:          true,
+		},
+		{
+			description:            "Auction deadline used if bidder timeout not set",
+			givenMaxRequestTimeout: 0,
+			expectTimeout:          false,
+		},
+		{
+			description:            "Auction deadline used if bidder timeout longer",
+			givenMaxRequestTimeout: 5 * time.Second,
+			expectTimeout:          false,
+		},
+		{
+			description:         "Timeout if bidder exceeds request timeout",
+			givenRequestTimeout: 10 * time.Millisecond,
+			expectTimeout:       true,
+		},
+		{
+			description:            "Request timeout used if shorter than bidder timeout",
+			givenMaxRequestTimeout: 5 * time.Second,
+			givenRequestTimeout:    10 * time.Millisecond,
+			expectTimeout:          true,
+		},
+		{
+			description:            "Bidder timeout used if shorter than request timeout",
+			givenMaxRequestTimeout: 10 * time.Millisecond,
+			givenRequestTimeout:    5 * time.Second,
+			expectTimeout:          true,
+		},
+		{
+			description:            "Auction deadline used if request and bidder timeouts longer",
+			givenMaxRequestTimeout: 5 * time.Second,
+			givenRequestTimeout:    5 * time.Second,
+			expectTimeout:          false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			notified := make(chan struct{}, 1)
+			notifyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				notified <- struct{}{}
+			}))
+			defer notifyServer.Close()
+
+			bidder := &bidderAdapter{
+				Bidder: wrapWithBidderInfo(&notifyingBidder{
+					notifyRequest: adapters.RequestData{Method: "GET", Uri: notifyServer.URL, Headers: http.Header{}},
+				}),
+				Client: server.Client(),
+				me:     &metricsConfig.NilMetricsEngine{},
+				config: bidderAdapterConfig{DisableConnMetrics: true, MaxRequestTimeout: test.givenMaxRequestTimeout},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			callInfo := bidder.doRequestImpl(ctx, &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte("{}"), Headers: http.Header{}}, "", test.givenRequestTimeout, func(string, ...interface{}) {})
+
+			if test.expectTimeout {
+				assert.IsType(t, &errortypes.Timeout{}, callInfo.err)
+				select {
+				case <-notified:
+				case <-time.After(time.Second):
+					t.Error("Timeout notification expected.")
+				}
+			} else {
+				assert.NoError(t, callInfo.err)
+				assert.Equal(t, http.StatusOK, callInfo.response.StatusCode)
+			}
+			assert.NoError(t, ctx.Err(), "Auction context shouldn't be affected by the bidder timeout.")
+		})
+	}
+}
+
+func TestBidderRequestTimeout(t *testing.T) {
+	testCases := []struct {
+		description            string
+		givenMaxRequestTimeout time.Duration
+		givenRequestTimeout    time.Duration
+		expectedTimeout        time.Duration
+	}{
+		{
+			description:     "No timeout if none set",
+			expectedTimeout: 0,
+		},
+		{
+			description:            "Bidder timeout used if request timeout not set",
+			givenMaxRequestTimeout: 200 * time.Millisecond,
+			expectedTimeout:        200 * time.Millisecond,
+		},
+		{
+			description:         "Request timeout used if bidder timeout not set",
+			givenRequestTimeout: 100 * time.Millisecond,
+			expectedTimeout:     100 * time.Millisecond,
+		},
+		{
+			description:            "Tightest timeout used",
+			givenMaxRequestTimeout: 200 * time.Millisecond,
+			givenRequestTimeout:    100 * time.Millisecond,
+			expectedTimeout:        100 * time.Millisecond,
+		},
+		{
+			description:            "Request timeout can't extend bidder timeout",
+			givenMaxRequestTimeout: 200 * time.Millisecond,
+			givenRequestTimeout:    300 * time.Millisecond,
+			expectedTimeout:        200 * time.Millisecond,
+		},
+		{
+			description:            "Negative request timeout ignored",
+			givenMaxRequestTimeout: 200 * time.Millisecond,
+			givenRequestTimeout:    -1,
+			expectedTimeout:        200 * time.Millisecond,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidder := &bidderAdapter{config: bidderAdapterConfig{MaxRequestTimeout: test.givenMaxRequestTimeout}}
+			assert.Equal(t, test.expectedTimeout, bidder.requestTimeout(test.givenRequestTimeout))
+		})
+	}
+}
+
+func TestTimeoutNotificationConfiguredTimeout(t *testing.T) {
+	server := httptest.NewServer(mockSlowHandler(50*time.Millisecond, 200, `{"bid":false}`))
+	defer server.Close()
+
+	testCases := []struct {
+		description   string
+		timeoutMillis int
+		expectedLog   string
+	}{
+		{
+			description:   "Notification fails if endpoint slower than configured timeout",
+			timeoutMillis: 10,
+			expectedLog:   "TimeoutNotification: error:(context deadline exceeded) body:\n",
+		},
+		{
+			description:   "Notification succeeds within configured timeout",
+			timeoutMillis: 1000,
+			expectedLog:   "TimeoutNotification: status:(200) body:\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidder := &notifyingBidder{
+				notifyRequest: adapters.RequestData{
+					Method:  "GET",
+					Uri:     server.URL + "/notify/me",
+					Headers: http.Header{},
+				},
+			}
+			bidderAdapter := &bidderAdapter{
+				Bidder: bidder,
+				Client: server.Client(),
+				config: bidderAdapterConfig{
+					Debug: config.Debug{
+						TimeoutNotification: config.TimeoutNotification{
+							Log:           true,
+							SamplingRate:  1.0,
+							TimeoutMillis: test.timeoutMillis,
+						},
+					},
+				},
+				me: &metricsConfig.NilMetricsEngine{},
+			}
+
+			var loggerBuffer bytes.Buffer
+			logger := func(msg string, args ...interface{}) {
+				loggerBuffer.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
+			}
+
+			bidderAdapter.doTimeoutNotification(bidder, &adapters.RequestData{}, logger)
+
+			assert.Equal(t, test.expectedLog, loggerBuffer.String())
+		})
+	}
+}
+
+func TestRejectNotification(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", `{"bid":false}`))
+	defer server.Close()
+
+	testCases := []struct {
+		description          string
+		enabled              bool
+		expectedNotification bool
+	}{
+		{
+			description:          "Notification not sent if disabled",
+			enabled:              false,
+			expectedNotification: false,
+		},
+		{
+			description:          "Notification sent if enabled",
+			enabled:              true,
+			expectedNotification: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &rejectNotifyingBidder{
+				notifyingBidder: notifyingBidder{
+					notifyRequest: adapters.RequestData{Method: "GET", Uri: server.URL + "/notify/me", Headers: http.Header{}},
+				},
+				notified: make(chan []error, 1),
+			}
+			bidder := &bidderAdapter{
+				Bidder: wrapWithBidderInfo(bidderImpl),
+				Client: server.Client(),
+				config: bidderAdapterConfig{Debug: config.Debug{RejectNotification: config.RejectNotification{Enabled: test.enabled}}},
+				me:     &metricsConfig.NilMetricsEngine{},
+			}
+			rejectErrs := []error{errors.New("Can't make a response.")}
+
+			bidder.notifyReject(&adapters.RequestData{Method: "POST", Uri: server.URL}, rejectErrs)
+
+			select {
+			case errs := <-bidderImpl.notified:
+				assert.True(t, test.expectedNotification, "Unexpected reject notification.")
+				assert.Equal(t, rejectErrs, errs)
+			case <-time.After(50 * time.Millisecond):
+				assert.False(t, test.expectedNotification, "Expected reject notification.")
+			}
+		})
+	}
+}
+
+func TestDoRejectNotification(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", `{"bid":false}`))
+	defer server.Close()
+
+	bidderImpl := &rejectNotifyingBidder{
+		notifyingBidder: notifyingBidder{
+			notifyRequest: adapters.RequestData{Method: "GET", Uri: server.URL + "/notify/me", Headers: http.Header{}},
+		},
+		notified: make(chan []error, 1),
+	}
+	bidder := &bidderAdapter{
+		Bidder: bidderImpl,
+		Client: server.Client(),
+		config: bidderAdapterConfig{
+			Debug: config.Debug{
+				TimeoutNotification: config.TimeoutNotification{Log: true, SamplingRate: 1.0},
+				RejectNotification:  config.RejectNotification{Enabled: true},
+			},
+		},
+		me: &metricsConfig.NilMetricsEngine{},
+	}
+
+	var loggerBuffer bytes.Buffer
+	logger := func(msg string, args ...interface{}) {
+		loggerBuffer.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
+	}
+
+	bidder.doRejectNotification(bidderImpl, &adapters.RequestData{}, nil, logger)
+
+	assert.Equal(t, "RejectNotification: status:(200) body:\n", loggerBuffer.String())
+}
+
+func TestRejectNotificationConfiguredTimeout(t *testing.T) {
+	server := httptest.NewServer(mockSlowHandler(50*time.Millisecond, 200, `{"bid":false}`))
+	defer server.Close()
+
+	testCases := []struct {
+		description   string
+		timeoutMillis int
+		expectedLog   string
+	}{
+		{
+			description:   "Notification fails if endpoint slower than configured timeout",
+			timeoutMillis: 10,
+			expectedLog:   "RejectNotification: error:(context deadline exceeded) body:\n",
+		},
+		{
+			description:   "Notification succeeds within configured timeout",
+			timeoutMillis: 1000,
+			expectedLog:   "RejectNotification: status:(200) body:\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderImpl := &rejectNotifyingBidder{
+				notifyingBidder: notifyingBidder{
+					notifyRequest: adapters.RequestData{Method: "GET", Uri: server.URL + "/notify/me", Headers: http.Header{}},
+				},
+				notified: make(chan []error, 1),
+			}
+			bidder := &bidderAdapter{
+				Bidder: bidderImpl,
+				Client: server.Client(),
+				config: bidderAdapterConfig{
+					Debug: config.Debug{
+						TimeoutNotification: config.TimeoutNotification{Log: true, SamplingRate: 1.0, TimeoutMillis: test.timeoutMillis},
+						RejectNotification:  config.RejectNotification{Enabled: true},
+					},
+				},
+				me: &metricsConfig.NilMetricsEngine{},
+			}
+
+			var loggerBuffer bytes.Buffer
+			logger := func(msg string, args ...interface{}) {
+				loggerBuffer.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
+			}
+
+			bidder.doRejectNotification(bidderImpl, &adapters.RequestData{}, nil, logger)
+
+			assert.Equal(t, test.expectedLog, loggerBuffer.String())
+		})
+	}
+}
+
 func TestParseDebugInfoTrue(t *testing.T) {
 	debugInfo := &config.DebugInfo{Allow: true}
 	resDebugInfo := parseDebugInfo(debugInfo)
@@ -2112,6 +4418,112 @@ func TestPrepareStoredResponse(t *testing.T) {
 	assert.Equal(t, []byte(`{"id": "resp_id1"}`), result.response.Body, "incorrect response body")
 }
 
+func TestPrepareStoredResponseHeaders(t *testing.T) {
+	testCases := []struct {
+		description     string
+		givenResponse   json.RawMessage
+		expectedBody    string
+		expectedHeaders http.Header
+		expectedError   string
+	}{
+		{
+			description:     "Empty headers without envelope",
+			givenResponse:   json.RawMessage(`{"id": "resp_id1", "body": "foo"}`),
+			expectedBody:    `{"id": "resp_id1", "body": "foo"}`,
+			expectedHeaders: http.Header{},
+		},
+		{
+			description:     "Envelope headers set",
+			givenResponse:   json.RawMessage(`{"headers": {"x-currency": ["EUR"], "X-Deal": ["a", "b"]}, "body": {"id": "resp_id1"}}`),
+			expectedBody:    `{"id": "resp_id1"}`,
+			expectedHeaders: http.Header{"X-Currency": []string{"EUR"}, "X-Deal": []string{"a", "b"}},
+		},
+		{
+			description:     "Envelope without headers",
+			givenResponse:   json.RawMessage(`{"body": [{"id": "resp_id1"}, {"id": "resp_id2", "seatbid": [{"bid": [{"id": "bid2"}]}]}]}`),
+			expectedBody:    `{"id": "resp_id1", "seatbid": [{"bid": [{"id": "bid2"}]}]}`,
+			expectedHeaders: http.Header{},
+		},
+		{
+			description:   "Error on invalid envelope headers",
+			givenResponse: json.RawMessage(`{"headers": {"x-currency": "EUR"}, "body": {"id": "resp_id1"}}`),
+			expectedError: "invalid stored bid response for imp imp_id1: invalid headers of stored bid response envelope",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result := prepareStoredResponse("imp_id1", test.givenResponse)
+
+			if test.expectedError != "" {
+				if assert.IsType(t, &errortypes.BadInput{}, result.err) {
+					assert.True(t, strings.HasPrefix(result.err.Error(), test.expectedError), "incorrect error: %s", result.err)
+				}
+				assert.Nil(t, result.response, "response not expected")
+				return
+			}
+			assert.NoError(t, result.err)
+			assert.JSONEq(t, test.expectedBody, string(result.response.Body), "incorrect response body")
+			assert.Equal(t, test.expectedHeaders, result.response.Headers, "incorrect response headers")
+		})
+	}
+}
+
+func TestPrepareStoredResponseInvalid(t *testing.T) {
+	result := prepareStoredResponse("imp_id1", json.RawMessage(`[]`))
+	assert.Equal(t, []byte(ImpIdReqBody+"imp_id1"), result.request.Body, "incorrect request body")
+	assert.Nil(t, result.response, "response not expected")
+	assert.Equal(t, &errortypes.BadInput{Message: "invalid stored bid response for imp imp_id1: empty array of stored bid responses"}, result.err)
+}
+
+func TestMergeStoredResponses(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenResponse    json.RawMessage
+		expectedResponse string
+		expectedError    bool
+	}{
+		{
+			description:      "Single response unchanged",
+			givenResponse:    json.RawMessage(`{"id": "resp_id1", "seatbid": [{"bid": [{"id": "bid1"}]}]}`),
+			expectedResponse: `{"id": "resp_id1", "seatbid": [{"bid": [{"id": "bid1"}]}]}`,
+		},
+		{
+			description:      "Seatbids of array of responses merged into first response",
+			givenResponse:    json.RawMessage(` [{"id": "resp_id1", "cur": "USD", "seatbid": [{"bid": [{"id": "bid1"}]}]}, {"id": "resp_id2", "seatbid": [{"bid": [{"id": "bid2"}]}]}]`),
+			expectedResponse: `{"id": "resp_id1", "cur": "USD", "seatbid": [{"bid": [{"id": "bid1"}]}, {"bid": [{"id": "bid2"}]}]}`,
+		},
+		{
+			description:      "Responses without seatbids merged",
+			givenResponse:    json.RawMessage(`[{"id": "resp_id1"}, {"id": "resp_id2", "seatbid": [{"bid": [{"id": "bid2"}]}]}]`),
+			expectedResponse: `{"id": "resp_id1", "seatbid": [{"bid": [{"id": "bid2"}]}]}`,
+		},
+		{
+			description:   "Error on empty array",
+			givenResponse: json.RawMessage(`[]`),
+			expectedError: true,
+		},
+		{
+			description:   "Error on invalid seatbid",
+			givenResponse: json.RawMessage(`[{"id": "resp_id1", "seatbid": {}}]`),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			response, err := mergeStoredResponses(test.givenResponse)
+
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedResponse, string(response))
+		})
+	}
+}
+
 func TestRequestBidsWithAdsCertsSigner(t *testing.T) {
 	respStatus := 200
 	respBody := `{"bid":false}`
@@ -2142,7 +4554,7 @@ func TestRequestBidsWithAdsCertsSigner(t *testing.T) {
 		},
 	}
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{Allow: false}, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{Allow: false}})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -2208,9 +4620,13 @@ func (bidder *goodSingleBidderWithStoredBidResp) MakeBids(internalRequest *openr
 
 	for _, sb := range bidResp.SeatBid {
 		for i := range sb.Bid {
+			bidType := openrtb_ext.BidTypeVideo
+			if sb.Bid[i].MType == openrtb2.MarkupBanner {
+				bidType = openrtb_ext.BidTypeBanner
+			}
 			bidResponse.Bids = append(bidResponse.Bids, &adapters.TypedBid{
 				Bid:     &sb.Bid[i],
-				BidType: openrtb_ext.BidTypeVideo,
+				BidType: bidType,
 			})
 		}
 	}
@@ -2290,6 +4706,16 @@ func (bidder *notifyingBidder) MakeTimeoutNotification(req *adapters.RequestData
 	return &bidder.notifyRequest, nil
 }
 
+type rejectNotifyingBidder struct {
+	notifyingBidder
+	notified chan []error
+}
+
+func (bidder *rejectNotifyingBidder) MakeRejectNotification(req *adapters.RequestData, errs []error) (*adapters.RequestData, []error) {
+	bidder.notified <- errs
+	return &bidder.notifyRequest, nil
+}
+
 func TestExtraBid(t *testing.T) {
 	respStatus := 200
 	respBody := "{\"bid\":false}"
@@ -2355,7 +4781,7 @@ func TestExtraBid(t *testing.T) {
 		},
 	}
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{}, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{}})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -2469,7 +4895,7 @@ func TestExtraBidWithAlternateBidderCodeDisabled(t *testing.T) {
 		},
 	}
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{}, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{}})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -2575,7 +5001,7 @@ func TestExtraBidWithBidAdjustments(t *testing.T) {
 		},
 	}
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{}, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{}})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -2688,7 +5114,7 @@ func TestExtraBidWithBidAdjustmentsUsingAdapterCode(t *testing.T) {
 		},
 	}
 
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, &config.DebugInfo{}, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{Debug: &config.DebugInfo{}})
 	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
 
 	bidderReq := BidderRequest{
@@ -2808,7 +5234,7 @@ func TestExtraBidWithMultiCurrencies(t *testing.T) {
 	)
 
 	// Execute:
-	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, config.BidderInfo{})
 	currencyConverter := currency.NewRateConverter(
 		&http.Client{},
 		mockedHTTPServer.URL,
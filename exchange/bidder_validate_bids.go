@@ -34,7 +34,7 @@ type validatedBidder struct {
 func (v *validatedBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor) ([]*entities.PbsOrtbSeatBid, []error) {
 	seatBids, errs := v.bidder.requestBid(ctx, bidderRequest, conversions, reqInfo, adsCertSigner, bidRequestOptions, alternateBidderCodes, hookExecutor)
 	for _, seatBid := range seatBids {
-		if validationErrors := removeInvalidBids(bidderRequest.BidRequest, seatBid); len(validationErrors) > 0 {
+		if validationErrors := removeInvalidBids(bidderRequest.BidRequest, seatBid, bidRequestOptions.getDefaultCurrency()); len(validationErrors) > 0 {
 			errs = append(errs, validationErrors...)
 		}
 	}
@@ -42,14 +42,13 @@ func (v *validatedBidder) requestBid(ctx context.Context, bidderRequest BidderRe
 }
 
 // validateBids will run some validation checks on the returned bids and excise any invalid bids
-func removeInvalidBids(request *openrtb2.BidRequest, seatBid *entities.PbsOrtbSeatBid) []error {
+func removeInvalidBids(request *openrtb2.BidRequest, seatBid *entities.PbsOrtbSeatBid, defaultCurrency string) []error {
 	// Exit early if there is nothing to do.
 	if seatBid == nil || len(seatBid.Bids) == 0 {
 		return nil
 	}
 
-	// By design, default currency is USD.
-	if cerr := validateCurrency(request.Cur, seatBid.Currency); cerr != nil {
+	if cerr := validateCurrency(request.Cur, seatBid.Currency, defaultCurrency); cerr != nil {
 		seatBid.Bids = nil
 		return []error{cerr}
 	}
@@ -68,9 +67,7 @@ func removeInvalidBids(request *openrtb2.BidRequest, seatBid *entities.PbsOrtbSe
 }
 
 // validateCurrency will run currency validation checks and return true if it passes, false otherwise.
-func validateCurrency(requestAllowedCurrencies []string, bidCurrency string) error {
-	// Default currency is `USD` by design.
-	defaultCurrency := "USD"
+func validateCurrency(requestAllowedCurrencies []string, bidCurrency string, defaultCurrency string) error {
 	// Make sure bid currency is a valid ISO currency code
 	if bidCurrency == "" {
 		// If bid currency is not set, then consider it's default currency.
@@ -44,4 +44,6 @@ type PbsOrtbBid struct {
 	GeneratedBidID    string
 	OriginalBidCPM    float64
 	OriginalBidCur    string
+	// DealTierOverride is the deal tier decision of a hook replacing the evaluation of the deal tier configuration if set
+	DealTierOverride *bool
 }
@@ -55,24 +55,26 @@ type IdFetcher interface {
 }
 
 type exchange struct {
-	adapterMap               map[openrtb_ext.BidderName]AdaptedBidder
-	bidderInfo               config.BidderInfos
-	bidderToSyncerKey        map[string]string
-	me                       metrics.MetricsEngine
-	cache                    prebid_cache_client.Client
-	cacheTime                time.Duration
-	gdprPermsBuilder         gdpr.PermissionsBuilder
-	tcf2ConfigBuilder        gdpr.TCF2ConfigBuilder
-	currencyConverter        *currency.RateConverter
-	externalURL              string
-	gdprDefaultValue         gdpr.Signal
-	privacyConfig            config.Privacy
-	categoriesFetcher        stored_requests.CategoryFetcher
-	bidIDGenerator           BidIDGenerator
-	hostSChainNode           *openrtb2.SupplyChainNode
-	adsCertSigner            adscert.Signer
-	server                   config.Server
-	bidValidationEnforcement config.Validations
+	adapterMap                        map[openrtb_ext.BidderName]AdaptedBidder
+	bidderInfo                        config.BidderInfos
+	bidderToSyncerKey                 map[string]string
+	me                                metrics.MetricsEngine
+	cache                             prebid_cache_client.Client
+	cacheTime                         time.Duration
+	gdprPermsBuilder                  gdpr.PermissionsBuilder
+	tcf2ConfigBuilder                 gdpr.TCF2ConfigBuilder
+	currencyConverter                 *currency.RateConverter
+	externalURL                       string
+	gdprDefaultValue                  gdpr.Signal
+	privacyConfig                     config.Privacy
+	categoriesFetcher                 stored_requests.CategoryFetcher
+	bidIDGenerator                    BidIDGenerator
+	hostSChainNode                    *openrtb2.SupplyChainNode
+	adsCertSigner                     adscert.Signer
+	server                            config.Server
+	bidValidationEnforcement          config.Validations
+	disableStoredRespImpIdReplacement bool
+	dedupAlternateSeatBids            bool
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -83,6 +85,9 @@ type seatResponseExtra struct {
 	// httpCalls is the list of debugging info. It should only be populated if the request.test == 1.
 	// This will become response.ext.debug.httpcalls.{bidder} on the final Response.
 	HttpCalls []*openrtb_ext.ExtHttpCall
+	// NonBids holds the impressions left without bid as a module rejected the bidder at one of the bidder-level stages.
+	// This will become response.ext.seatnonbid on the final Response.
+	NonBids []openrtb_ext.NonBid
 }
 
 type bidResponseWrapper struct {
@@ -148,11 +153,13 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 			GDPR: cfg.GDPR,
 			LMT:  cfg.LMT,
 		},
-		bidIDGenerator:           &bidIDGenerator{cfg.GenerateBidID},
-		hostSChainNode:           cfg.HostSChainNode,
-		adsCertSigner:            adsCertSigner,
-		server:                   config.Server{ExternalUrl: cfg.ExternalURL, GvlID: cfg.GDPR.HostVendorID, DataCenter: cfg.DataCenter},
-		bidValidationEnforcement: cfg.Validations,
+		bidIDGenerator:                    &bidIDGenerator{cfg.GenerateBidID},
+		hostSChainNode:                    cfg.HostSChainNode,
+		adsCertSigner:                     adsCertSigner,
+		server:                            config.Server{ExternalUrl: cfg.ExternalURL, GvlID: cfg.GDPR.HostVendorID, DataCenter: cfg.DataCenter},
+		bidValidationEnforcement:          cfg.Validations,
+		disableStoredRespImpIdReplacement: cfg.DisableStoredRespImpIDReplacement,
+		dedupAlternateSeatBids:            cfg.DedupAlternateSeatBids,
 	}
 }
 
@@ -260,8 +267,6 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 		}
 	}
 
-	bidAdjustmentFactors := getExtBidAdjustmentFactors(requestExt)
-
 	recordImpMetrics(r.BidRequestWrapper.BidRequest, e.me)
 
 	// Make our best guess if GDPR applies
@@ -270,6 +275,10 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 	// Slice of BidRequests, each a copy of the original cleaned to only contain bidder data for the named bidder
 	bidderRequests, privacyLabels, errs := cleanOpenRTBRequests(ctx, r, requestExt, e.bidderToSyncerKey, e.me, gdprDefaultValue, e.privacyConfig, e.gdprPermsBuilder, e.tcf2ConfigBuilder, e.hostSChainNode)
 
+	var disallowedBidderWarnings []error
+	bidderRequests, disallowedBidderWarnings = removeDisallowedBidders(bidderRequests, r.Account.AllowedBidders, e.me)
+	r.Warnings = append(r.Warnings, disallowedBidderWarnings...)
+
 	e.me.RecordRequestPrivacy(privacyLabels)
 
 	if len(r.StoredAuctionResponses) > 0 || len(r.StoredBidResponses) > 0 {
@@ -310,7 +319,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 			alternateBidderCodes = *r.Account.AlternateBidderCodes
 		}
 
-		adapterBids, adapterExtra, anyBidsReturned = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExt.Prebid.Experiment, r.HookExecutor)
+		adapterBids, adapterExtra, anyBidsReturned = e.getAllBids(auctionCtx, bidderRequests, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, r.Account, requestExt, r.HookExecutor)
 	}
 
 	var auc *auction
@@ -345,7 +354,14 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 		evTracking := getEventTracking(&requestExt.Prebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL)
 		adapterBids = evTracking.modifyBidsForEvents(adapterBids)
 
-		r.HookExecutor.ExecuteAllProcessedBidResponsesStage(adapterBids)
+		var impDealTiers map[string]openrtb_ext.DealTierBidderMap
+		if requestExt.Prebid.SupportDeals {
+			impDealTiers = getDealTiers(r.BidRequestWrapper.BidRequest)
+		}
+
+		if reject := r.HookExecutor.ExecuteAllProcessedBidResponsesStage(adapterBids, impDealTiers); reject != nil {
+			return nil, reject
+		}
 
 		if targData != nil {
 			// A non-nil auction is only needed if targeting is active. (It is used below this block to extract cache keys)
@@ -353,7 +369,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 			auc.setRoundedPrices(targData.priceGranularity)
 
 			if requestExt.Prebid.SupportDeals {
-				dealErrs := applyDealSupport(r.BidRequestWrapper.BidRequest, auc, bidCategory)
+				dealErrs := applyDealSupport(impDealTiers, auc, bidCategory)
 				errs = append(errs, dealErrs...)
 			}
 
@@ -406,6 +422,14 @@ func (e *exchange) HoldAuction(ctx context.Context, r AuctionRequest, debugLog *
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], generalWarning)
 	}
 
+	// modules' http calls follow the same debug restrictions as the bidders' calls
+	if bidResponseExt.Debug != nil && (accountDebugAllow || debugLog.DebugOverride) {
+		for module, httpCalls := range r.HookExecutor.GetHttpCalls() {
+			bidderName := openrtb_ext.BidderName(module)
+			bidResponseExt.Debug.HttpCalls[bidderName] = append(bidResponseExt.Debug.HttpCalls[bidderName], httpCalls...)
+		}
+	}
+
 	e.bidValidationEnforcement.SetBannerCreativeMaxSize(r.Account.Validations)
 
 	// Build the response
@@ -448,14 +472,21 @@ func recordImpMetrics(bidRequest *openrtb2.BidRequest, metricsEngine metrics.Met
 	}
 }
 
-// applyDealSupport updates targeting keys with deal prefixes if minimum deal tier exceeded
-func applyDealSupport(bidRequest *openrtb2.BidRequest, auc *auction, bidCategory map[string]string) []error {
+// applyDealSupport updates targeting keys with deal prefixes if minimum deal tier exceeded,
+// the deal tier decision of hooks takes precedence over the deal tier configuration
+func applyDealSupport(impDealMap map[string]openrtb_ext.DealTierBidderMap, auc *auction, bidCategory map[string]string) []error {
 	errs := []error{}
-	impDealMap := getDealTiers(bidRequest)
 
 	for impID, topBidsPerImp := range auc.winningBidsByBidder {
 		impDeal := impDealMap[impID]
 		for bidder, topBidPerBidder := range topBidsPerImp {
+			if topBidPerBidder.DealTierOverride != nil {
+				topBidPerBidder.DealTierSatisfied = *topBidPerBidder.DealTierOverride
+				if topBidPerBidder.DealTierSatisfied && validateDealTier(impDeal[bidder]) {
+					prefixHbPbCatDur(topBidPerBidder, impDeal[bidder], bidCategory)
+				}
+				continue
+			}
 			if topBidPerBidder.DealPriority > 0 {
 				if validateDealTier(impDeal[bidder]) {
 					updateHbPbCatDur(topBidPerBidder, impDeal[bidder], bidCategory)
@@ -490,16 +521,20 @@ func validateDealTier(dealTier openrtb_ext.DealTier) bool {
 
 func updateHbPbCatDur(bid *entities.PbsOrtbBid, dealTier openrtb_ext.DealTier, bidCategory map[string]string) {
 	if bid.DealPriority >= dealTier.MinDealTier {
-		prefixTier := fmt.Sprintf("%s%d_", dealTier.Prefix, bid.DealPriority)
 		bid.DealTierSatisfied = true
+		prefixHbPbCatDur(bid, dealTier, bidCategory)
+	}
+}
 
-		if oldCatDur, ok := bidCategory[bid.Bid.ID]; ok {
-			oldCatDurSplit := strings.SplitAfterN(oldCatDur, "_", 2)
-			oldCatDurSplit[0] = prefixTier
+// prefixHbPbCatDur replaces the price part of the bid's category with the deal tier prefix and the bid's deal priority
+func prefixHbPbCatDur(bid *entities.PbsOrtbBid, dealTier openrtb_ext.DealTier, bidCategory map[string]string) {
+	if oldCatDur, ok := bidCategory[bid.Bid.ID]; ok {
+		prefixTier := fmt.Sprintf("%s%d_", dealTier.Prefix, bid.DealPriority)
+		oldCatDurSplit := strings.SplitAfterN(oldCatDur, "_", 2)
+		oldCatDurSplit[0] = prefixTier
 
-			newCatDur := strings.Join(oldCatDurSplit, "")
-			bidCategory[bid.Bid.ID] = newCatDur
-		}
+		newCatDur := strings.Join(oldCatDurSplit, "")
+		bidCategory[bid.Bid.ID] = newCatDur
 	}
 }
 
@@ -518,13 +553,13 @@ func (e *exchange) makeAuctionContext(ctx context.Context, needsCache bool) (auc
 func (e *exchange) getAllBids(
 	ctx context.Context,
 	bidderRequests []BidderRequest,
-	bidAdjustments map[string]float64,
 	conversions currency.Conversions,
 	accountDebugAllowed bool,
 	globalPrivacyControlHeader string,
 	headerDebugAllowed bool,
 	alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes,
-	experiment *openrtb_ext.Experiment,
+	account config.Account,
+	requestExt *openrtb_ext.ExtRequest,
 	hookExecutor hookexecution.StageExecutor) (
 	map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid,
 	map[openrtb_ext.BidderName]*seatResponseExtra, bool) {
@@ -534,6 +569,26 @@ func (e *exchange) getAllBids(
 	chBids := make(chan *bidResponseWrapper, len(bidderRequests))
 	bidsFound := false
 
+	var experiment *openrtb_ext.Experiment
+	if requestExt != nil {
+		experiment = requestExt.Prebid.Experiment
+	}
+	endpointCompression := getExtEndpointCompression(requestExt)
+	bidderTimeouts := getExtBidderTimeouts(requestExt)
+	auctionBidReqOptions := bidRequestOptions{
+		accountDebugAllowed:               accountDebugAllowed,
+		headerDebugAllowed:                headerDebugAllowed,
+		bidAdjustments:                    getExtBidAdjustmentFactors(requestExt),
+		bidAdjustmentsByCur:               getExtBidAdjustmentFactorsByCur(requestExt),
+		disableStoredRespImpIdReplacement: e.disableStoredRespImpIdReplacement,
+		maxBidCPM:                         account.MaxBidCPM,
+		enforceFloors:                     account.EnforceFloors,
+		debugBidders:                      getExtDebugBidders(requestExt),
+		defaultCurrency:                   account.DefaultCurrency,
+		preferredCurrency:                 account.PreferredCurrency,
+		dedupAlternateSeatBids:            e.dedupAlternateSeatBids,
+	}
+
 	for _, bidder := range bidderRequests {
 		// Here we actually call the adapters and collect the bids.
 		bidderRunner := e.recoverSafely(bidderRequests, func(bidderRequest BidderRequest, conversions currency.Conversions) {
@@ -554,12 +609,10 @@ func (e *exchange) getAllBids(
 			reqInfo.PbsEntryPoint = bidderRequest.BidderLabels.RType
 			reqInfo.GlobalPrivacyControlHeader = globalPrivacyControlHeader
 
-			bidReqOptions := bidRequestOptions{
-				accountDebugAllowed: accountDebugAllowed,
-				headerDebugAllowed:  headerDebugAllowed,
-				addCallSignHeader:   isAdsCertEnabled(experiment, e.bidderInfo[string(bidderRequest.BidderName)]),
-				bidAdjustments:      bidAdjustments,
-			}
+			bidReqOptions := auctionBidReqOptions
+			bidReqOptions.addCallSignHeader = isAdsCertEnabled(experiment, e.bidderInfo[string(bidderRequest.BidderName)])
+			bidReqOptions.endpointCompression = endpointCompression[string(bidderRequest.BidderName)]
+			bidReqOptions.requestTimeout = time.Duration(bidderTimeouts[string(bidderRequest.BidderName)]) * time.Millisecond
 			seatBids, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor)
 
 			// Add in time reporting
@@ -579,6 +632,7 @@ func (e *exchange) getAllBids(
 			// Append any bid validation errors to the error list
 			ae.Errors = errsToBidderErrors(err)
 			ae.Warnings = errsToBidderWarnings(err)
+			ae.NonBids = rejectedImpsNonBids(bidderRequest.BidRequest, seatBids, err)
 			brw.adapterExtra = ae
 			for _, seatBid := range seatBids {
 				if seatBid != nil {
@@ -665,24 +719,29 @@ func errorsToMetric(errs []error) map[metrics.AdapterError]struct{} {
 	ret := make(map[metrics.AdapterError]struct{}, len(errs))
 	var s struct{}
 	for _, err := range errs {
-		switch errortypes.ReadCode(err) {
-		case errortypes.TimeoutErrorCode:
-			ret[metrics.AdapterErrorTimeout] = s
-		case errortypes.BadInputErrorCode:
-			ret[metrics.AdapterErrorBadInput] = s
-		case errortypes.BadServerResponseErrorCode:
-			ret[metrics.AdapterErrorBadServerResponse] = s
-		case errortypes.FailedToRequestBidsErrorCode:
-			ret[metrics.AdapterErrorFailedToRequestBids] = s
-		case errortypes.AlternateBidderCodeWarningCode:
-			ret[metrics.AdapterErrorValidation] = s
-		default:
-			ret[metrics.AdapterErrorUnknown] = s
-		}
+		ret[errorToMetric(err)] = s
 	}
 	return ret
 }
 
+// errorToMetric classifies the error by its errortypes code.
+func errorToMetric(err error) metrics.AdapterError {
+	switch errortypes.ReadCode(err) {
+	case errortypes.TimeoutErrorCode:
+		return metrics.AdapterErrorTimeout
+	case errortypes.BadInputErrorCode:
+		return metrics.AdapterErrorBadInput
+	case errortypes.BadServerResponseErrorCode:
+		return metrics.AdapterErrorBadServerResponse
+	case errortypes.FailedToRequestBidsErrorCode:
+		return metrics.AdapterErrorFailedToRequestBids
+	case errortypes.AlternateBidderCodeWarningCode:
+		return metrics.AdapterErrorValidation
+	default:
+		return metrics.AdapterErrorUnknown
+	}
+}
+
 func errsToBidderErrors(errs []error) []openrtb_ext.ExtBidderMessage {
 	sErr := make([]openrtb_ext.ExtBidderMessage, 0)
 	for _, err := range errortypes.FatalOnly(errs) {
@@ -1020,9 +1079,80 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 		// Defering the filling of bidResponseExt.Usersync[bidderName] until later
 
 	}
+
+	var hookNonBids map[string][]openrtb_ext.NonBid
+	if r.HookExecutor != nil {
+		hookNonBids = r.HookExecutor.GetNonBids()
+	}
+	bidResponseExt.SeatNonBid = makeSeatNonBid(adapterExtra, hookNonBids)
+
 	return bidResponseExt
 }
 
+// rejectedImpsNonBids returns the non-bids for the impressions of the bidder request left without bid
+// if the bidder was rejected by a module, the module's reject code is used as the status code.
+func rejectedImpsNonBids(bidRequest *openrtb2.BidRequest, seatBids []*entities.PbsOrtbSeatBid, errs []error) []openrtb_ext.NonBid {
+	reject := hookexecution.FindFirstRejectOrNil(errs)
+	if reject == nil || bidRequest == nil {
+		return nil
+	}
+
+	impsWithBids := make(map[string]struct{})
+	for _, seatBid := range seatBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid != nil && bid.Bid != nil {
+				impsWithBids[bid.Bid.ImpID] = struct{}{}
+			}
+		}
+	}
+
+	var nonBids []openrtb_ext.NonBid
+	for _, imp := range bidRequest.Imp {
+		if _, ok := impsWithBids[imp.ID]; !ok {
+			nonBids = append(nonBids, openrtb_ext.NonBid{ImpId: imp.ID, StatusCode: reject.NBR})
+		}
+	}
+	return nonBids
+}
+
+// makeSeatNonBid merges the non-bids reported by hooks for individual bids with the non-bids of the bidders
+// rejected by modules. Only the first reason is kept per seat and impression, seats are sorted by name.
+func makeSeatNonBid(adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, hookNonBids map[string][]openrtb_ext.NonBid) []openrtb_ext.SeatNonBid {
+	nonBidsBySeat := make(map[string][]openrtb_ext.NonBid, len(hookNonBids))
+	for seat, nonBids := range hookNonBids {
+		nonBidsBySeat[seat] = append(nonBidsBySeat[seat], nonBids...)
+	}
+	for bidderName, responseExtra := range adapterExtra {
+		if responseExtra != nil && len(responseExtra.NonBids) > 0 {
+			nonBidsBySeat[bidderName.String()] = append(nonBidsBySeat[bidderName.String()], responseExtra.NonBids...)
+		}
+	}
+
+	seats := make([]string, 0, len(nonBidsBySeat))
+	for seat := range nonBidsBySeat {
+		seats = append(seats, seat)
+	}
+	sort.Strings(seats)
+
+	var seatNonBids []openrtb_ext.SeatNonBid
+	for _, seat := range seats {
+		seen := make(map[string]struct{})
+		seatNonBid := openrtb_ext.SeatNonBid{Seat: seat}
+		for _, nonBid := range nonBidsBySeat[seat] {
+			if _, ok := seen[nonBid.ImpId]; ok {
+				continue
+			}
+			seen[nonBid.ImpId] = struct{}{}
+			seatNonBid.NonBid = append(seatNonBid.NonBid, nonBid)
+		}
+		seatNonBids = append(seatNonBids, seatNonBid)
+	}
+	return seatNonBids
+}
+
 // Return an openrtb seatBid for a bidder
 // BuildBidResponse is responsible for ensuring nil bid seatbids are not included
 func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter openrtb_ext.BidderName, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, returnCreative bool, impExtInfoMap map[string]ImpExtInfo, bidResponseExt *openrtb_ext.ExtBidResponse, pubID string) *openrtb2.SeatBid {
@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// seatBidKey identifies the bids considered duplicates when returned under several seats of the same adapter.
+type seatBidKey struct {
+	impID    string
+	crID     string
+	price    float64
+	currency string
+}
+
+// dedupSeatBids removes the bids returned under several seats of a single adapter with the same imp id, creative id
+// and original price, keeping the bid of the seat with the highest priority. The adapter seat has the highest priority,
+// followed by the alternate seats in the order of the allowed bidder codes and then by the other seats in alphabetical order.
+// Bids of the same seat and bids without creative id are never removed. Alternate seats left without bids are removed.
+// It returns the number of removed bids.
+func dedupSeatBids(seatBidMap map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, adapterSeat openrtb_ext.BidderName, allowedBidderCodes []string) int {
+	if len(seatBidMap) < 2 {
+		return 0
+	}
+
+	removed := 0
+	kept := make(map[seatBidKey]openrtb_ext.BidderName)
+	for _, seat := range seatPriority(seatBidMap, adapterSeat, allowedBidderCodes) {
+		seatBid := seatBidMap[seat]
+		bids := seatBid.Bids[:0]
+		for _, bid := range seatBid.Bids {
+			if bid.Bid != nil && bid.Bid.CrID != "" {
+				key := seatBidKey{impID: bid.Bid.ImpID, crID: bid.Bid.CrID, price: bid.OriginalBidCPM, currency: bid.OriginalBidCur}
+				if keptSeat, ok := kept[key]; ok && keptSeat != seat {
+					removed++
+					continue
+				}
+				kept[key] = seat
+			}
+			bids = append(bids, bid)
+		}
+		seatBid.Bids = bids
+
+		if len(seatBid.Bids) == 0 && seat != adapterSeat {
+			delete(seatBidMap, seat)
+		}
+	}
+	return removed
+}
+
+// seatPriority returns the seats of the map ordered from the highest to the lowest priority.
+func seatPriority(seatBidMap map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, adapterSeat openrtb_ext.BidderName, allowedBidderCodes []string) []openrtb_ext.BidderName {
+	seats := make([]openrtb_ext.BidderName, 0, len(seatBidMap))
+	added := make(map[openrtb_ext.BidderName]bool, len(seatBidMap))
+	addSeat := func(seat openrtb_ext.BidderName) {
+		if _, ok := seatBidMap[seat]; ok && !added[seat] {
+			seats = append(seats, seat)
+			added[seat] = true
+		}
+	}
+
+	addSeat(adapterSeat)
+	for _, code := range allowedBidderCodes {
+		addSeat(openrtb_ext.BidderName(code))
+	}
+
+	others := make([]openrtb_ext.BidderName, 0, len(seatBidMap))
+	for seat := range seatBidMap {
+		if !added[seat] {
+			others = append(others, seat)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+
+	return append(seats, others...)
+}
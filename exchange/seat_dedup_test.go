@@ -0,0 +1,113 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupSeatBids(t *testing.T) {
+	newBid := func(id, impID, crID string, price float64) *entities.PbsOrtbBid {
+		return &entities.PbsOrtbBid{
+			Bid:            &openrtb2.Bid{ID: id, ImpID: impID, CrID: crID, Price: price},
+			OriginalBidCPM: price,
+			OriginalBidCur: "USD",
+		}
+	}
+
+	testCases := []struct {
+		description          string
+		givenSeatBids        map[openrtb_ext.BidderName][]*entities.PbsOrtbBid
+		givenAllowedCodes    []string
+		expectedBids         map[openrtb_ext.BidderName][]string
+		expectedRemovedCount int
+	}{
+		{
+			description: "Adapter seat bid kept over alternate seat duplicate",
+			givenSeatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"pubmatic": {newBid("1", "imp1", "cr1", 1)},
+				"groupm":   {newBid("2", "imp1", "cr1", 1), newBid("3", "imp2", "cr1", 1)},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"1"},
+				"groupm":   {"3"},
+			},
+			expectedRemovedCount: 1,
+		},
+		{
+			description: "Alternate seats prioritized by allowed bidder codes order",
+			givenSeatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"pubmatic": {},
+				"groupm":   {newBid("1", "imp1", "cr1", 1)},
+				"alpha":    {newBid("2", "imp1", "cr1", 1)},
+				"zeta":     {newBid("3", "imp1", "cr1", 1)},
+			},
+			givenAllowedCodes: []string{"zeta", "groupm"},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": nil,
+				"zeta":     {"3"},
+			},
+			expectedRemovedCount: 2,
+		},
+		{
+			description: "Other seats prioritized alphabetically",
+			givenSeatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"pubmatic": {},
+				"groupm":   {newBid("1", "imp1", "cr1", 1)},
+				"alpha":    {newBid("2", "imp1", "cr1", 1)},
+			},
+			givenAllowedCodes: []string{"*"},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": nil,
+				"alpha":    {"2"},
+			},
+			expectedRemovedCount: 1,
+		},
+		{
+			description: "Bids with different creative id, price or imp kept",
+			givenSeatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"pubmatic": {newBid("1", "imp1", "cr1", 1)},
+				"groupm":   {newBid("2", "imp1", "cr2", 1), newBid("3", "imp1", "cr1", 1.5), newBid("4", "imp2", "cr1", 1)},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"1"},
+				"groupm":   {"2", "3", "4"},
+			},
+		},
+		{
+			description: "Duplicates within the same seat and bids without creative id kept",
+			givenSeatBids: map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+				"pubmatic": {newBid("1", "imp1", "cr1", 1), newBid("2", "imp1", "cr1", 1), newBid("3", "imp1", "", 1)},
+				"groupm":   {newBid("4", "imp1", "", 1)},
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"1", "2", "3"},
+				"groupm":   {"4"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			seatBidMap := make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, len(test.givenSeatBids))
+			for seat, bids := range test.givenSeatBids {
+				seatBidMap[seat] = &entities.PbsOrtbSeatBid{Seat: seat.String(), Bids: bids}
+			}
+
+			removed := dedupSeatBids(seatBidMap, "pubmatic", test.givenAllowedCodes)
+
+			bidIDs := make(map[openrtb_ext.BidderName][]string, len(seatBidMap))
+			for seat, seatBid := range seatBidMap {
+				bidIDs[seat] = nil
+				for _, bid := range seatBid.Bids {
+					bidIDs[seat] = append(bidIDs[seat], bid.Bid.ID)
+				}
+			}
+			assert.Equal(t, test.expectedBids, bidIDs)
+			assert.Equal(t, test.expectedRemovedCount, removed)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package exchange
+
+import (
+	"sort"
+
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+// seatSelectionStrategy defines how bids of the alternate bidder seats targeting the same imp are handled.
+type seatSelectionStrategy int
+
+const (
+	// seatSelectionAll keeps the bids of all seats.
+	seatSelectionAll seatSelectionStrategy = iota
+	// seatSelectionWeighted keeps the bids of a single seat per imp, chosen randomly according to the seat weights.
+	seatSelectionWeighted
+)
+
+// seatSelection holds the strategy used to choose among the seats returned by a single bidder.
+type seatSelection struct {
+	strategy seatSelectionStrategy
+	// weights holds relative weights keyed by seat, seats without a positive weight are not selected
+	// unless none of the competing seats has one, in which case all of them are equally likely.
+	weights map[string]float64
+	// random returns a pseudo-random number in [0.0,1.0), a seeded generator makes the selection deterministic.
+	random func() float64
+}
+
+// apply removes the bids of all but one seat for every imp targeted by several seats.
+// Alternate seats left without bids are removed, the seat of the adapter itself is always kept.
+func (s seatSelection) apply(seatBidMap map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, adapterSeat openrtb_ext.BidderName) {
+	if s.strategy != seatSelectionWeighted || s.random == nil || len(seatBidMap) < 2 {
+		return
+	}
+
+	// seats and imps are sorted so that the selection depends on the random numbers only
+	seats := make([]openrtb_ext.BidderName, 0, len(seatBidMap))
+	for seat := range seatBidMap {
+		seats = append(seats, seat)
+	}
+	sort.Slice(seats, func(i, j int) bool { return seats[i] < seats[j] })
+
+	impSeats := make(map[string][]openrtb_ext.BidderName)
+	for _, seat := range seats {
+		for _, bid := range seatBidMap[seat].Bids {
+			if bid.Bid == nil {
+				continue
+			}
+			candidates := impSeats[bid.Bid.ImpID]
+			if len(candidates) == 0 || candidates[len(candidates)-1] != seat {
+				impSeats[bid.Bid.ImpID] = append(candidates, seat)
+			}
+		}
+	}
+
+	impIDs := make([]string, 0, len(impSeats))
+	for impID, candidates := range impSeats {
+		if len(candidates) > 1 {
+			impIDs = append(impIDs, impID)
+		}
+	}
+	sort.Strings(impIDs)
+
+	selectedSeats := make(map[string]openrtb_ext.BidderName, len(impIDs))
+	for _, impID := range impIDs {
+		selectedSeats[impID] = s.selectSeat(impSeats[impID])
+	}
+
+	for _, seat := range seats {
+		seatBid := seatBidMap[seat]
+		bids := seatBid.Bids[:0]
+		for _, bid := range seatBid.Bids {
+			if bid.Bid != nil {
+				if selectedSeat, ok := selectedSeats[bid.Bid.ImpID]; ok && selectedSeat != seat {
+					continue
+				}
+			}
+			bids = append(bids, bid)
+		}
+		seatBid.Bids = bids
+
+		if len(seatBid.Bids) == 0 && seat != adapterSeat {
+			delete(seatBidMap, seat)
+		}
+	}
+}
+
+func (s seatSelection) selectSeat(candidates []openrtb_ext.BidderName) openrtb_ext.BidderName {
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, seat := range candidates {
+		if weight := s.weights[seat.String()]; weight > 0 {
+			weights[i] = weight
+			total += weight
+		}
+	}
+
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = float64(len(weights))
+	}
+
+	r := s.random() * total
+	for i, weight := range weights {
+		if r < weight {
+			return candidates[i]
+		}
+		r -= weight
+	}
+	return candidates[len(candidates)-1]
+}
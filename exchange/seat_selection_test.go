@@ -0,0 +1,119 @@
+package exchange
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeatSelectionApply(t *testing.T) {
+	newSeatBidMap := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"pubmatic": {Seat: "pubmatic", Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "imp1"}},
+				{Bid: &openrtb2.Bid{ID: "2", ImpID: "imp2"}},
+			}},
+			"groupm": {Seat: "groupm", Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "3", ImpID: "imp1"}},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		description   string
+		givenStrategy seatSelection
+		expectedBids  map[openrtb_ext.BidderName][]string
+	}{
+		{
+			description:   "All seats kept by default",
+			givenStrategy: seatSelection{},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"1", "2"},
+				"groupm":   {"3"},
+			},
+		},
+		{
+			description: "Alternate seat selected for shared imp and adapter seat kept for other imp",
+			givenStrategy: seatSelection{
+				strategy: seatSelectionWeighted,
+				weights:  map[string]float64{"groupm": 1},
+				random:   func() float64 { return 0.5 },
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"2"},
+				"groupm":   {"3"},
+			},
+		},
+		{
+			description: "Alternate seat removed if it has no bids left",
+			givenStrategy: seatSelection{
+				strategy: seatSelectionWeighted,
+				weights:  map[string]float64{"pubmatic": 1},
+				random:   func() float64 { return 0.5 },
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"1", "2"},
+			},
+		},
+		{
+			description: "Seats equally likely if none has weight",
+			givenStrategy: seatSelection{
+				strategy: seatSelectionWeighted,
+				// seats are sorted, so the first half of the range selects groupm
+				random: func() float64 { return 0.4 },
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"2"},
+				"groupm":   {"3"},
+			},
+		},
+		{
+			description: "Seat chosen according to weights",
+			givenStrategy: seatSelection{
+				strategy: seatSelectionWeighted,
+				weights:  map[string]float64{"groupm": 1, "pubmatic": 3},
+				random:   func() float64 { return 0.3 },
+			},
+			expectedBids: map[openrtb_ext.BidderName][]string{
+				"pubmatic": {"1", "2"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			seatBidMap := newSeatBidMap()
+			test.givenStrategy.apply(seatBidMap, "pubmatic")
+
+			actualBids := make(map[openrtb_ext.BidderName][]string, len(seatBidMap))
+			for seat, seatBid := range seatBidMap {
+				for _, bid := range seatBid.Bids {
+					actualBids[seat] = append(actualBids[seat], bid.Bid.ID)
+				}
+			}
+			assert.Equal(t, test.expectedBids, actualBids)
+		})
+	}
+}
+
+func TestSeatSelectionDeterministicWithSeededRandom(t *testing.T) {
+	selectSeats := func() []openrtb_ext.BidderName {
+		selection := seatSelection{
+			strategy: seatSelectionWeighted,
+			weights:  map[string]float64{"groupm": 1, "pubmatic": 1, "appnexus": 2},
+			random:   rand.New(rand.NewSource(42)).Float64,
+		}
+
+		selected := make([]openrtb_ext.BidderName, 0, 20)
+		for i := 0; i < 20; i++ {
+			selected = append(selected, selection.selectSeat([]openrtb_ext.BidderName{"appnexus", "groupm", "pubmatic"}))
+		}
+		return selected
+	}
+
+	assert.Equal(t, selectSeats(), selectSeats())
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 
 	"github.com/buger/jsonparser"
 	"github.com/prebid/go-gdpr/vendorconsent"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/prebid/prebid-server/firstpartydata"
 	"github.com/prebid/prebid-server/gdpr"
 	"github.com/prebid/prebid-server/metrics"
@@ -180,6 +182,37 @@ func cleanOpenRTBRequests(ctx context.Context,
 	return
 }
 
+// removeDisallowedBidders removes the requests to the bidders not allowed for the account and returns a warning for each of them.
+// A bidder is allowed if either its name or its core bidder name is listed, all bidders are allowed if the list is empty.
+func removeDisallowedBidders(bidderRequests []BidderRequest, allowedBidders []string, me metrics.MetricsEngine) ([]BidderRequest, []error) {
+	if len(allowedBidders) == 0 {
+		return bidderRequests, nil
+	}
+
+	allowed := make(map[string]struct{}, len(allowedBidders))
+	for _, bidder := range allowedBidders {
+		allowed[strings.ToLower(bidder)] = struct{}{}
+	}
+
+	var warnings []error
+	allowedBidderRequests := bidderRequests[:0]
+	for _, bidderRequest := range bidderRequests {
+		_, nameAllowed := allowed[strings.ToLower(bidderRequest.BidderName.String())]
+		_, coreNameAllowed := allowed[strings.ToLower(bidderRequest.BidderCoreName.String())]
+		if nameAllowed || coreNameAllowed {
+			allowedBidderRequests = append(allowedBidderRequests, bidderRequest)
+			continue
+		}
+
+		me.RecordAdapterAccountRequestBlocked(bidderRequest.BidderCoreName)
+		warnings = append(warnings, &errortypes.Warning{
+			WarningCode: errortypes.DisallowedBidderWarningCode,
+			Message:     fmt.Sprintf("Bidder %s skipped: not allowed for the account", bidderRequest.BidderName),
+		})
+	}
+	return allowedBidderRequests, warnings
+}
+
 func ccpaEnabled(account *config.Account, privacyConfig config.Privacy, requestType config.ChannelType) bool {
 	if accountEnabled := account.CCPA.EnabledForChannelType(requestType); accountEnabled != nil {
 		return *accountEnabled
@@ -785,6 +818,38 @@ func getExtBidAdjustmentFactors(requestExt *openrtb_ext.ExtRequest) map[string]f
 	return bidAdjustmentFactors
 }
 
+func getExtBidAdjustmentFactorsByCur(requestExt *openrtb_ext.ExtRequest) map[string]map[string]float64 {
+	var bidAdjustmentFactorsByCur map[string]map[string]float64
+	if requestExt != nil {
+		bidAdjustmentFactorsByCur = requestExt.Prebid.BidAdjustmentFactorsByCur
+	}
+	return bidAdjustmentFactorsByCur
+}
+
+func getExtEndpointCompression(requestExt *openrtb_ext.ExtRequest) map[string]string {
+	var endpointCompression map[string]string
+	if requestExt != nil {
+		endpointCompression = requestExt.Prebid.EndpointCompression
+	}
+	return endpointCompression
+}
+
+func getExtBidderTimeouts(requestExt *openrtb_ext.ExtRequest) map[string]int {
+	var bidderTimeouts map[string]int
+	if requestExt != nil {
+		bidderTimeouts = requestExt.Prebid.BidderTimeouts
+	}
+	return bidderTimeouts
+}
+
+func getExtDebugBidders(requestExt *openrtb_ext.ExtRequest) []string {
+	var debugBidders []string
+	if requestExt != nil {
+		debugBidders = requestExt.Prebid.DebugBidders
+	}
+	return debugBidders
+}
+
 func applyFPD(fpd *firstpartydata.ResolvedFirstPartyData, bidReq *openrtb2.BidRequest) {
 	if fpd.Site != nil {
 		bidReq.Site = fpd.Site
@@ -2343,6 +2343,78 @@ func TestCleanOpenRTBRequestsGDPRBlockBidRequest(t *testing.T) {
 	}
 }
 
+func TestRemoveDisallowedBidders(t *testing.T) {
+	bidderRequests := func() []BidderRequest {
+		return []BidderRequest{
+			{BidderName: "appnexus", BidderCoreName: openrtb_ext.BidderAppnexus},
+			{BidderName: "pubmatic", BidderCoreName: openrtb_ext.BidderPubmatic},
+			{BidderName: "myalias", BidderCoreName: openrtb_ext.BidderRubicon},
+		}
+	}
+
+	testCases := []struct {
+		description            string
+		givenAllowedBidders    []string
+		expectedBidders        []openrtb_ext.BidderName
+		expectedBlockedBidders []openrtb_ext.BidderName
+		expectedWarnings       []error
+	}{
+		{
+			description:     "All bidders allowed if list empty",
+			expectedBidders: []openrtb_ext.BidderName{"appnexus", "pubmatic", "myalias"},
+		},
+		{
+			description:            "Bidders not listed removed",
+			givenAllowedBidders:    []string{"appnexus"},
+			expectedBidders:        []openrtb_ext.BidderName{"appnexus"},
+			expectedBlockedBidders: []openrtb_ext.BidderName{openrtb_ext.BidderPubmatic, openrtb_ext.BidderRubicon},
+			expectedWarnings: []error{
+				&errortypes.Warning{WarningCode: errortypes.DisallowedBidderWarningCode, Message: "Bidder pubmatic skipped: not allowed for the account"},
+				&errortypes.Warning{WarningCode: errortypes.DisallowedBidderWarningCode, Message: "Bidder myalias skipped: not allowed for the account"},
+			},
+		},
+		{
+			description:            "Alias allowed by core bidder name",
+			givenAllowedBidders:    []string{"rubicon", "PubMatic"},
+			expectedBidders:        []openrtb_ext.BidderName{"pubmatic", "myalias"},
+			expectedBlockedBidders: []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus},
+			expectedWarnings: []error{
+				&errortypes.Warning{WarningCode: errortypes.DisallowedBidderWarningCode, Message: "Bidder appnexus skipped: not allowed for the account"},
+			},
+		},
+		{
+			description:            "Alias allowed by alias name",
+			givenAllowedBidders:    []string{"myalias"},
+			expectedBidders:        []openrtb_ext.BidderName{"myalias"},
+			expectedBlockedBidders: []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus, openrtb_ext.BidderPubmatic},
+			expectedWarnings: []error{
+				&errortypes.Warning{WarningCode: errortypes.DisallowedBidderWarningCode, Message: "Bidder appnexus skipped: not allowed for the account"},
+				&errortypes.Warning{WarningCode: errortypes.DisallowedBidderWarningCode, Message: "Bidder pubmatic skipped: not allowed for the account"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsMock := &metrics.MetricsEngineMock{}
+			metricsMock.On("RecordAdapterAccountRequestBlocked", mock.Anything).Return()
+
+			results, warnings := removeDisallowedBidders(bidderRequests(), test.givenAllowedBidders, metricsMock)
+
+			var bidders []openrtb_ext.BidderName
+			for _, req := range results {
+				bidders = append(bidders, req.BidderName)
+			}
+			assert.Equal(t, test.expectedBidders, bidders)
+			assert.Equal(t, test.expectedWarnings, warnings)
+			metricsMock.AssertNumberOfCalls(t, "RecordAdapterAccountRequestBlocked", len(test.expectedBlockedBidders))
+			for _, blockedBidder := range test.expectedBlockedBidders {
+				metricsMock.AssertCalled(t, "RecordAdapterAccountRequestBlocked", blockedBidder)
+			}
+		})
+	}
+}
+
 func TestBuildRequestExtForBidder(t *testing.T) {
 	bidder := "foo"
 	bidderParams := json.RawMessage(`"bar"`)
@@ -25,6 +25,10 @@ func (e EmptyPlanBuilder) PlanForBidderRequestStage(endpoint string, account *co
 	return nil
 }
 
+func (e EmptyPlanBuilder) PlanForBidderHttpRequestStage(endpoint string, account *config.Account) Plan[hookstage.BidderHttpRequest] {
+	return nil
+}
+
 func (e EmptyPlanBuilder) PlanForRawBidderResponseStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponse] {
 	return nil
 }
@@ -1,20 +1,73 @@
 package hookexecution
 
 import (
+	"context"
 	"sync"
 
+	"github.com/benbjohnson/clock"
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 )
 
+// ContextKey is the type of the keys under which the executor stores
+// read-only request metadata in the context.Context passed to hooks.
+type ContextKey string
+
+const (
+	// AccountIDContextKey holds the ID of the account the request is processed for.
+	// The value is absent at stages executed before the account is resolved (e.g. entrypoint).
+	AccountIDContextKey ContextKey = "accountId"
+	// EndpointContextKey holds the endpoint the request is processed at, e.g. "/openrtb2/auction".
+	EndpointContextKey ContextKey = "endpoint"
+	// CorrelationIDContextKey holds the ID correlating the hook invocations of the request,
+	// it is taken from the X-Request-Id header or generated if the header is absent.
+	CorrelationIDContextKey ContextKey = "correlationId"
+)
+
+// AccountIDFromContext returns the account ID stored in the context passed to a hook.
+func AccountIDFromContext(ctx context.Context) (string, bool) {
+	accountID, ok := ctx.Value(AccountIDContextKey).(string)
+	return accountID, ok
+}
+
+// EndpointFromContext returns the endpoint stored in the context passed to a hook.
+func EndpointFromContext(ctx context.Context) (string, bool) {
+	endpoint, ok := ctx.Value(EndpointContextKey).(string)
+	return endpoint, ok
+}
+
+// CorrelationIDFromContext returns the correlation ID of the request stored in the context passed to a hook.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	correlationID, ok := ctx.Value(CorrelationIDContextKey).(string)
+	return correlationID, ok
+}
+
 // executionContext holds information passed to module's hook during hook execution.
 type executionContext struct {
 	endpoint       string
 	stage          string
+	entity         entity
 	accountId      string
 	account        *config.Account
 	moduleContexts *moduleContexts
+	mutationLog    *mutationLog
+	auditor        *mutationAuditor
+	logger         *hookLogger
+	correlationID  string
+	// clock measures the hook execution time and timeouts, it is replaced by a mock in tests
+	clock clock.Clock
+	// hookClocks replace the clock for particular hooks in tests,
+	// so that a mock clock advanced by one hook doesn't time out the other hooks of the group
+	hookClocks map[HookID]clock.Clock
+}
+
+// hookClock returns the clock measuring the execution time and timeout of the hook.
+func (ctx executionContext) hookClock(hookID HookID) clock.Clock {
+	if clk, ok := ctx.hookClocks[hookID]; ok {
+		return clk
+	}
+	return ctx.clock
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
@@ -37,24 +90,33 @@ func (ctx executionContext) getModuleContext(moduleName string) hookstage.Module
 	return moduleInvocationCtx
 }
 
+// hookContext returns a copy of the parent context carrying the request metadata exposed to hooks.
+func (ctx executionContext) hookContext(parent context.Context) context.Context {
+	if ctx.endpoint != "" {
+		parent = context.WithValue(parent, EndpointContextKey, ctx.endpoint)
+	}
+	if ctx.accountId != "" {
+		parent = context.WithValue(parent, AccountIDContextKey, ctx.accountId)
+	}
+	if ctx.correlationID != "" {
+		parent = context.WithValue(parent, CorrelationIDContextKey, ctx.correlationID)
+	}
+	return parent
+}
+
 // moduleContexts preserves data the module wants to pass to itself from earlier stages to later stages.
 type moduleContexts struct {
 	sync.RWMutex
 	ctxs map[string]hookstage.ModuleContext // format: {"module_name": hookstage.ModuleContext}
 }
 
+// put merges the given context with the context saved for the module, see hookstage.ModuleContext.
+// The saved context is replaced by the merged copy, as it may be read by the hooks running concurrently.
 func (mc *moduleContexts) put(moduleName string, mCtx hookstage.ModuleContext) {
 	mc.Lock()
 	defer mc.Unlock()
 
-	newCtx := mCtx
-	if existingCtx, ok := mc.ctxs[moduleName]; ok && existingCtx != nil {
-		for k, v := range mCtx {
-			existingCtx[k] = v
-		}
-		newCtx = existingCtx
-	}
-	mc.ctxs[moduleName] = newCtx
+	mc.ctxs[moduleName] = mergeModuleContexts(mc.ctxs[moduleName], mCtx)
 }
 
 func (mc *moduleContexts) get(moduleName string) (hookstage.ModuleContext, bool) {
@@ -65,8 +127,89 @@ func (mc *moduleContexts) get(moduleName string) (hookstage.ModuleContext, bool)
 	return mCtx, ok
 }
 
+// mergeModuleContexts returns a new context holding the values of both contexts,
+// the values of src overwrite the values of dst under the same key unless both are hookstage.MergedContext.
+func mergeModuleContexts(dst, src hookstage.ModuleContext) hookstage.ModuleContext {
+	if dst == nil {
+		return src
+	}
+
+	return hookstage.ModuleContext(mergeContextValues(dst, src))
+}
+
+func mergeContextValues(dst, src map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		merged[k] = v
+	}
+
+	for k, v := range src {
+		srcValue, srcMergeable := v.(hookstage.MergedContext)
+		dstValue, dstMergeable := merged[k].(hookstage.MergedContext)
+		if srcMergeable && dstMergeable {
+			merged[k] = hookstage.MergedContext(mergeContextValues(dstValue, srcValue))
+			continue
+		}
+		merged[k] = v
+	}
+
+	return merged
+}
+
 type stageModuleContext struct {
 	groupCtx []groupModuleContext
 }
 
 type groupModuleContext map[string]hookstage.ModuleContext
+
+// mutationLog keeps track of mutations applied during request processing,
+// so that a rejecting hook can report which modules modified the request before it.
+type mutationLog struct {
+	sync.Mutex
+	entries []mutationLogEntry
+}
+
+// mutationLogEntry is the mutation record along with the entity processed by the stage applying the mutation.
+type mutationLogEntry struct {
+	entity entity
+	record MutationRecord
+}
+
+func (ml *mutationLog) add(entity entity, record MutationRecord) {
+	if ml == nil {
+		return
+	}
+
+	ml.Lock()
+	defer ml.Unlock()
+	ml.entries = append(ml.entries, mutationLogEntry{entity: entity, record: record})
+}
+
+// snapshot returns the mutations relevant to the given entity. Mutations of the bidder-level stages
+// are reported only for the same bidder, as the stages of other bidders are executed concurrently
+// and don't affect the entity, mutations of the request-level stages are always reported.
+func (ml *mutationLog) snapshot(entity entity) []MutationRecord {
+	if ml == nil {
+		return nil
+	}
+
+	ml.Lock()
+	defer ml.Unlock()
+
+	var records []MutationRecord
+	for _, entry := range ml.entries {
+		if !isBidderEntity(entity) || !isBidderEntity(entry.entity) || entry.entity == entity {
+			records = append(records, entry.record)
+		}
+	}
+	return records
+}
+
+// isBidderEntity tells whether the entity is a bidder processed by the bidder-level stages.
+func isBidderEntity(e entity) bool {
+	switch e {
+	case entityHttpRequest, entityAuctionRequest, entityAuctionResponse, entityAllProcessedBidResponses:
+		return false
+	}
+	return true
+}
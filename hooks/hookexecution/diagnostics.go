@@ -0,0 +1,290 @@
+package hookexecution
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/buger/jsonparser"
+	"github.com/golang/glog"
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/hooks/hookstage"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+const (
+	// defaultMaxAuditLogEntries used if account does not limit the number of logged mutations
+	defaultMaxAuditLogEntries = 100
+	// maxAuditLogValueLength limits the length of the before/after values written to the log
+	maxAuditLogValueLength = 2048
+)
+
+// mutationAuditor writes every mutation applied by hooks to the server log.
+// It is created only for requests of accounts with enabled diagnostic mode
+// and sampled according to the account configuration.
+//
+// All methods are safe to call on a nil mutationAuditor, in which case nothing is logged.
+type mutationAuditor struct {
+	sync.Mutex
+	accountID  string
+	maxEntries int
+	entries    int
+	logf       func(format string, args ...interface{})
+}
+
+// newMutationAuditor returns a mutationAuditor if the diagnostic mode
+// enabled by account and the request is sampled, otherwise returns nil.
+func newMutationAuditor(account *config.Account, randFloat func() float64) *mutationAuditor {
+	if account == nil || !account.Hooks.Diagnostics.Enabled {
+		return nil
+	}
+
+	cfg := account.Hooks.Diagnostics
+	if cfg.SamplingRate <= 0 || randFloat() >= cfg.SamplingRate {
+		return nil
+	}
+
+	maxEntries := cfg.MaxLogEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxAuditLogEntries
+	}
+
+	return &mutationAuditor{
+		accountID:  account.ID,
+		maxEntries: maxEntries,
+		logf:       glog.Infof,
+	}
+}
+
+func newSampledMutationAuditor(account *config.Account) *mutationAuditor {
+	return newMutationAuditor(account, rand.Float64)
+}
+
+// snapshot returns a loggable representation of the payload value at the mutation key,
+// it must be taken before applying mutation as payload can be modified in place.
+// The whole payload document is used if the key doesn't start with the name of the document.
+func (a *mutationAuditor) snapshot(payload interface{}, key []string) string {
+	if a == nil {
+		return ""
+	}
+
+	root, data, err := auditDocument(payload)
+	if err != nil {
+		return fmt.Sprintf("<failed to marshal payload: %s>", err)
+	}
+
+	var path []string
+	if len(key) > 0 && key[0] == root {
+		path = auditPath(key[1:])
+	}
+
+	value, ok := lookupAuditValue(data, path)
+	if !ok {
+		return "<not set>"
+	}
+
+	if len(value) > maxAuditLogValueLength {
+		return string(value[:maxAuditLogValueLength]) + "...<truncated>"
+	}
+
+	return string(value)
+}
+
+type auditHttpRequest struct {
+	URI    string      `json:"uri"`
+	Header http.Header `json:"header"`
+}
+
+type auditBid struct {
+	*openrtb2.Bid
+	Type openrtb_ext.BidType `json:"type,omitempty"`
+}
+
+type auditProcessedBid struct {
+	*openrtb2.Bid
+	DealPriority      int                           `json:"dealpriority"`
+	DealTierSatisfied *bool                         `json:"dealtiersatisfied,omitempty"`
+	Meta              *openrtb_ext.ExtBidPrebidMeta `json:"meta,omitempty"`
+}
+
+// auditDocument returns the JSON document the mutation keys of the payload are relative to,
+// along with the name of the document used as the first segment of the keys.
+func auditDocument(payload interface{}) (string, []byte, error) {
+	var data []byte
+	var err error
+
+	switch p := payload.(type) {
+	case hookstage.EntrypointPayload:
+		return "body", p.Body, nil
+	case hookstage.RawAuctionRequestPayload:
+		return "body", p.Body, nil
+	case hookstage.ProcessedAuctionRequestPayload:
+		data, err = marshalAuditDocument(p.BidRequest)
+		return "bidrequest", data, err
+	case hookstage.BidderRequestPayload:
+		data, err = marshalAuditDocument(p.BidRequest)
+		return "bidrequest", data, err
+	case hookstage.BidderHttpRequestPayload:
+		requests := make([]auditHttpRequest, 0, len(p.Requests))
+		for _, r := range p.Requests {
+			requests = append(requests, auditHttpRequest{URI: r.Uri, Header: r.Headers})
+		}
+		data, err = marshalAuditDocument(requests)
+		return "httprequest", data, err
+	case hookstage.RawBidderResponsePayload:
+		bids := make([]auditBid, 0, len(p.Bids))
+		for _, b := range p.Bids {
+			bids = append(bids, auditBid{Bid: b.Bid, Type: b.BidType})
+		}
+		// bids are injected under the "bids" key, existing bids are updated under the "bid" key
+		data, err = marshalAuditDocument(map[string][]auditBid{"bids": bids, "bid": bids})
+		return "bidderresponse", data, err
+	case hookstage.AllProcessedBidResponsesPayload:
+		seatBids := make(map[string][]auditProcessedBid, len(p.Responses))
+		for bidder, seatBid := range p.Responses {
+			if seatBid == nil {
+				continue
+			}
+			bids := make([]auditProcessedBid, 0, len(seatBid.Bids))
+			for _, b := range seatBid.Bids {
+				bids = append(bids, auditProcessedBid{Bid: b.Bid, DealPriority: b.DealPriority, DealTierSatisfied: b.DealTierOverride, Meta: b.BidMeta})
+			}
+			seatBids[bidder.String()] = bids
+		}
+		data, err = marshalAuditDocument(seatBids)
+		return "processedbidresponses", data, err
+	case hookstage.AuctionResponsePayload:
+		data, err = marshalAuditDocument(p.BidResponse)
+		return "bidresponse", data, err
+	}
+
+	data, err = marshalAuditDocument(payload)
+	return "", data, err
+}
+
+// marshalAuditDocument marshals the value keeping HTML characters unescaped, so the bid markups are readable.
+func marshalAuditDocument(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// pointerTokenReplacer unescapes the reference tokens of the JSON pointer
+var pointerTokenReplacer = strings.NewReplacer("~1", "/", "~0", "~")
+
+// auditPath splits the JSON pointers (RFC 6901) of the key into separate segments.
+func auditPath(key []string) []string {
+	path := make([]string, 0, len(key))
+	for _, segment := range key {
+		if !strings.HasPrefix(segment, "/") {
+			path = append(path, segment)
+			continue
+		}
+		for _, token := range strings.Split(segment[1:], "/") {
+			path = append(path, pointerTokenReplacer.Replace(token))
+		}
+	}
+	return path
+}
+
+// lookupAuditValue returns the JSON value at the path. Array elements are addressed by index or by id,
+// other segments applied to an array select the value of each element having it.
+func lookupAuditValue(data []byte, path []string) ([]byte, bool) {
+	if len(path) == 0 {
+		return data, len(data) > 0
+	}
+
+	_, dataType, _, err := jsonparser.Get(data)
+	if err != nil {
+		return nil, false
+	}
+
+	switch dataType {
+	case jsonparser.Object:
+		value, valueType, _, err := jsonparser.Get(data, path[0])
+		if err != nil {
+			return nil, false
+		}
+		return lookupAuditValue(rawJSONValue(value, valueType), path[1:])
+	case jsonparser.Array:
+		var elements [][]byte
+		_, _ = jsonparser.ArrayEach(data, func(value []byte, valueType jsonparser.ValueType, _ int, _ error) {
+			elements = append(elements, rawJSONValue(value, valueType))
+		})
+
+		if i, err := strconv.Atoi(path[0]); err == nil {
+			if i < 0 || i >= len(elements) {
+				return nil, false
+			}
+			return lookupAuditValue(elements[i], path[1:])
+		}
+
+		for _, element := range elements {
+			if id, err := jsonparser.GetString(element, "id"); err == nil && id == path[0] {
+				return lookupAuditValue(element, path[1:])
+			}
+		}
+
+		values := make([][]byte, 0, len(elements))
+		for _, element := range elements {
+			if value, ok := lookupAuditValue(element, path); ok {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return nil, false
+		}
+		return append(append([]byte("["), bytes.Join(values, []byte(","))...), ']'), true
+	}
+
+	return nil, false
+}
+
+// rawJSONValue restores the quotes jsonparser strips from the string values.
+func rawJSONValue(value []byte, valueType jsonparser.ValueType) []byte {
+	if valueType != jsonparser.String {
+		return value
+	}
+	return append(append([]byte(`"`), value...), '"')
+}
+
+func (a *mutationAuditor) log(hookID HookID, stage, key string, mutType hookstage.MutationType, before, after string) {
+	if a == nil {
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if a.entries > a.maxEntries {
+		return
+	}
+
+	a.entries++
+	if a.entries > a.maxEntries {
+		a.logf("Hook mutation audit log limit (%d) reached for account %s, further mutations are not logged", a.maxEntries, a.accountID)
+		return
+	}
+
+	a.logf(
+		"Hook mutation audit (account: %s, module: %s, hook code: %s, stage: %s, affected key: %s, mutation type: %s), before: %s, after: %s",
+		a.accountID,
+		hookID.ModuleCode,
+		hookID.HookImplCode,
+		stage,
+		key,
+		mutType,
+		before,
+		after,
+	)
+}
@@ -0,0 +1,196 @@
+package hookexecution
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/hooks/hookstage"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMutationAuditor(t *testing.T) {
+	testCases := []struct {
+		description        string
+		givenAccount       *config.Account
+		givenRandom        float64
+		expectAuditor      bool
+		expectedMaxEntries int
+	}{
+		{
+			description:   "Auditor not created without account",
+			givenAccount:  nil,
+			expectAuditor: false,
+		},
+		{
+			description:   "Auditor not created if diagnostics disabled",
+			givenAccount:  &config.Account{Hooks: config.AccountHooks{Diagnostics: config.AccountHooksDiagnostics{Enabled: false, SamplingRate: 1}}},
+			expectAuditor: false,
+		},
+		{
+			description:   "Auditor not created if sampling rate not set",
+			givenAccount:  &config.Account{Hooks: config.AccountHooks{Diagnostics: config.AccountHooksDiagnostics{Enabled: true}}},
+			expectAuditor: false,
+		},
+		{
+			description:   "Auditor not created if request not sampled",
+			givenAccount:  &config.Account{Hooks: config.AccountHooks{Diagnostics: config.AccountHooksDiagnostics{Enabled: true, SamplingRate: 0.5}}},
+			givenRandom:   0.5,
+			expectAuditor: false,
+		},
+		{
+			description:        "Auditor created with default limit if request sampled",
+			givenAccount:       &config.Account{Hooks: config.AccountHooks{Diagnostics: config.AccountHooksDiagnostics{Enabled: true, SamplingRate: 0.5}}},
+			givenRandom:        0.1,
+			expectAuditor:      true,
+			expectedMaxEntries: defaultMaxAuditLogEntries,
+		},
+		{
+			description:        "Auditor created with account limit",
+			givenAccount:       &config.Account{Hooks: config.AccountHooks{Diagnostics: config.AccountHooksDiagnostics{Enabled: true, SamplingRate: 1, MaxLogEntries: 5}}},
+			givenRandom:        0.99,
+			expectAuditor:      true,
+			expectedMaxEntries: 5,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			auditor := newMutationAuditor(test.givenAccount, func() float64 { return test.givenRandom })
+			if !test.expectAuditor {
+				assert.Nil(t, auditor, "Nil auditor expected.")
+				return
+			}
+
+			if assert.NotNil(t, auditor, "Auditor expected.") {
+				assert.Equal(t, test.expectedMaxEntries, auditor.maxEntries, "Invalid max log entries.")
+			}
+		})
+	}
+}
+
+func TestMutationAuditorLog(t *testing.T) {
+	var logs []string
+	auditor := &mutationAuditor{
+		accountID:  "acc",
+		maxEntries: 1,
+		logf: func(format string, args ...interface{}) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		},
+	}
+	hookID := HookID{ModuleCode: "foobar", HookImplCode: "foo"}
+
+	before := auditor.snapshot(map[string]string{"foo": "bar"}, []string{"header", "foo"})
+	after := auditor.snapshot(map[string]string{"foo": "baz"}, []string{"header", "foo"})
+	auditor.log(hookID, "entrypoint", "header.foo", hookstage.MutationUpdate, before, after)
+	auditor.log(hookID, "entrypoint", "header.bar", hookstage.MutationDelete, before, after)
+	auditor.log(hookID, "entrypoint", "header.baz", hookstage.MutationDelete, before, after)
+
+	assert.Equal(t, []string{
+		`Hook mutation audit (account: acc, module: foobar, hook code: foo, stage: entrypoint, affected key: header.foo, mutation type: update), before: {"foo":"bar"}, after: {"foo":"baz"}`,
+		`Hook mutation audit log limit (1) reached for account acc, further mutations are not logged`,
+	}, logs)
+}
+
+func TestMutationAuditorSnapshot(t *testing.T) {
+	longBody := `{"site":{"name":"` + strings.Repeat("a", maxAuditLogValueLength) + `"},"imp":[{"id":"imp1","ext":{"foo":"bar"}}]}`
+	dealTierSatisfied := true
+
+	testCases := []struct {
+		description   string
+		givenPayload  interface{}
+		givenKey      []string
+		expectedValue string
+	}{
+		{
+			description:   "Entrypoint body value at the pointer location past the length limit",
+			givenPayload:  hookstage.EntrypointPayload{Request: httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), Body: []byte(longBody)},
+			givenKey:      []string{"body", "/imp/0/ext"},
+			expectedValue: `{"foo":"bar"}`,
+		},
+		{
+			description:   "Raw auction body value not set before add mutation",
+			givenPayload:  hookstage.RawAuctionRequestPayload{Body: []byte(`{"imp":[{"id":"imp1"}]}`)},
+			givenKey:      []string{"body", "/imp/0/ext"},
+			expectedValue: "<not set>",
+		},
+		{
+			description:   "Whole body for the key not addressing the body",
+			givenPayload:  hookstage.RawAuctionRequestPayload{Body: []byte(`{"id":"req1"}`)},
+			givenKey:      []string{"foo"},
+			expectedValue: `{"id":"req1"}`,
+		},
+		{
+			description:   "Bidder request imp value addressed by id",
+			givenPayload:  hookstage.BidderRequestPayload{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2", BidFloor: 1.5}}}},
+			givenKey:      []string{"bidrequest", "imp", "imp2", "bidfloor"},
+			expectedValue: `1.5`,
+		},
+		{
+			description:   "Bidder request values of all imps",
+			givenPayload:  hookstage.BidderRequestPayload{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", Banner: &openrtb2.Banner{BType: []openrtb2.BannerAdType{1}}}, {ID: "imp2"}}}},
+			givenKey:      []string{"bidrequest", "imp", "banner", "btype"},
+			expectedValue: `[[1]]`,
+		},
+		{
+			description:   "Bidder http request header",
+			givenPayload:  hookstage.BidderHttpRequestPayload{Requests: []*adapters.RequestData{{Uri: "https://bidder.com", Headers: http.Header{"Foo": {"bar"}}}}},
+			givenKey:      []string{"httprequest", "0", "header", "Foo"},
+			expectedValue: `["bar"]`,
+		},
+		{
+			description:   "Raw bidder response bid markup",
+			givenPayload:  hookstage.RawBidderResponsePayload{Bids: []*adapters.TypedBid{{Bid: &openrtb2.Bid{ID: "bid1", AdM: "<div></div>"}, BidType: openrtb_ext.BidTypeBanner}}},
+			givenKey:      []string{"bidderresponse", "bid", "bid1", "adm"},
+			expectedValue: `"<div></div>"`,
+		},
+		{
+			description: "Processed bid deal tier decision",
+			givenPayload: hookstage.AllProcessedBidResponsesPayload{Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "bid1"}, DealTierOverride: &dealTierSatisfied}}},
+			}},
+			givenKey:      []string{"processedbidresponses", "appnexus", "bid1", "dealtiersatisfied"},
+			expectedValue: `true`,
+		},
+		{
+			description: "Auction response ext of all bids",
+			givenPayload: hookstage.AuctionResponsePayload{BidResponse: &openrtb2.BidResponse{SeatBid: []openrtb2.SeatBid{
+				{Bid: []openrtb2.Bid{{ID: "bid1", Ext: json.RawMessage(`{"foo":1}`)}, {ID: "bid2", Ext: json.RawMessage(`{"foo":2}`)}}},
+			}}},
+			givenKey:      []string{"bidresponse", "seatbid", "bid", "ext", "/foo"},
+			expectedValue: `[[1,2]]`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			auditor := &mutationAuditor{}
+			assert.Equal(t, test.expectedValue, auditor.snapshot(test.givenPayload, test.givenKey))
+		})
+	}
+}
+
+func TestMutationAuditorSnapshotTruncated(t *testing.T) {
+	auditor := &mutationAuditor{}
+	value := auditor.snapshot(strings.Repeat("a", maxAuditLogValueLength), nil)
+
+	assert.Len(t, value, maxAuditLogValueLength+len("...<truncated>"))
+	assert.True(t, strings.HasSuffix(value, "...<truncated>"))
+}
+
+func TestNilMutationAuditor(t *testing.T) {
+	var auditor *mutationAuditor
+
+	assert.Empty(t, auditor.snapshot("foo", nil))
+	assert.NotPanics(t, func() {
+		auditor.log(HookID{}, "entrypoint", "header.foo", hookstage.MutationUpdate, "", "")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/buger/jsonparser"
 	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/hooks/hookanalytics"
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
 )
@@ -28,6 +29,12 @@ func (t trace) isVerbose() bool {
 	return t == traceLevelVerbose
 }
 
+// messagesVisibility controls which messages returned from executing hooks are added to the response.
+type messagesVisibility struct {
+	errors   bool
+	warnings bool
+}
+
 type extPrebid struct {
 	Prebid extModules `json:"prebid"`
 }
@@ -39,7 +46,8 @@ type extModules struct {
 // EnrichExtBidResponse adds debug and trace information returned from executing hooks to the ext argument.
 // In response the outcome is visible under the key response.ext.prebid.modules.
 //
-// Debug information is added only if the debug mode is enabled by request and allowed by account (if provided).
+// Debug information is added only if the debug mode is enabled by request and allowed by account (if provided),
+// hook warnings are also added regardless of the debug mode if the account enables account.Hooks.AlwaysIncludeWarnings.
 // The details of the trace output depends on the value in the bidRequest.ext.prebid.trace field.
 // Warnings returned if bidRequest contains unexpected types for debug fields controlling debug output.
 func EnrichExtBidResponse(
@@ -65,8 +73,104 @@ func EnrichExtBidResponse(
 	return response, warnings, err
 }
 
+// BidderParticipation holds the names of bidders requested during the auction
+// and the names of bidders that returned bids.
+type BidderParticipation struct {
+	Requested map[string]struct{}
+	WithBids  map[string]struct{}
+}
+
+// GetBidderParticipation collects bidders participated in the auction.
+// Requested bidders are taken from the bidRequest.imp[].ext.prebid.bidder objects
+// and from the entities of the bidder-request stage outcomes,
+// bidders with bids are taken from the non-empty seat bids of the bidResponse.
+func GetBidderParticipation(
+	stageOutcomes []StageOutcome,
+	bidRequest *openrtb2.BidRequest,
+	bidResponse *openrtb2.BidResponse,
+) BidderParticipation {
+	participation := BidderParticipation{
+		Requested: make(map[string]struct{}),
+		WithBids:  make(map[string]struct{}),
+	}
+
+	if bidRequest != nil {
+		for _, imp := range bidRequest.Imp {
+			_ = jsonparser.ObjectEach(imp.Ext, func(key []byte, _ []byte, _ jsonparser.ValueType, _ int) error {
+				participation.Requested[string(key)] = struct{}{}
+				return nil
+			}, "prebid", "bidder")
+		}
+	}
+
+	for _, stageOutcome := range stageOutcomes {
+		if stageOutcome.Stage == hooks.StageBidderRequest.String() {
+			participation.Requested[string(stageOutcome.Entity)] = struct{}{}
+		}
+	}
+
+	if bidResponse != nil {
+		for _, seatBid := range bidResponse.SeatBid {
+			if len(seatBid.Bid) > 0 {
+				participation.WithBids[seatBid.Seat] = struct{}{}
+				participation.Requested[seatBid.Seat] = struct{}{}
+			}
+		}
+	}
+
+	return participation
+}
+
+// EnrichExtBidResponseByParticipation merges into the ext argument the fields
+// configured by account for bidders that participated in the auction.
+//
+// Each account.Hooks.ConditionalExt entry is applied if any of its bidders was requested,
+// or returned bids if the entry requires bids. Entries are applied in the configured order.
+func EnrichExtBidResponseByParticipation(
+	ext json.RawMessage,
+	participation BidderParticipation,
+	account *config.Account,
+) (json.RawMessage, error) {
+	if account == nil {
+		return ext, nil
+	}
+
+	var err error
+	for _, conditionalExt := range account.Hooks.ConditionalExt {
+		if len(conditionalExt.Ext) == 0 || !participation.hasAnyBidder(conditionalExt.Bidders, conditionalExt.WithBids) {
+			continue
+		}
+
+		if ext == nil {
+			ext = conditionalExt.Ext
+			continue
+		}
+
+		if ext, err = jsonpatch.MergePatch(ext, conditionalExt.Ext); err != nil {
+			return nil, err
+		}
+	}
+
+	return ext, nil
+}
+
+func (p BidderParticipation) hasAnyBidder(bidders []string, withBids bool) bool {
+	participants := p.Requested
+	if withBids {
+		participants = p.WithBids
+	}
+
+	for _, bidder := range bidders {
+		if _, ok := participants[bidder]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // GetModulesJSON returns debug and trace information produced from executing hooks.
-// Debug information is returned only if the debug mode is enabled by request and allowed by account (if provided).
+// Debug information is returned only if the debug mode is enabled by request and allowed by account (if provided),
+// hook warnings are also returned regardless of the debug mode if the account enables account.Hooks.AlwaysIncludeWarnings.
 // The details of the trace output depends on the value in the bidRequest.ext.prebid.trace field.
 // Warnings returned if bidRequest contains unexpected types for debug fields controlling debug output.
 func GetModulesJSON(
@@ -78,8 +182,8 @@ func GetModulesJSON(
 		return nil, nil, nil
 	}
 
-	trace, isDebugEnabled, warnings := getDebugContext(bidRequest, account)
-	modulesOutcome := getModulesOutcome(stageOutcomes, trace, isDebugEnabled)
+	trace, visibility, warnings := getDebugContext(bidRequest, account)
+	modulesOutcome := getModulesOutcome(stageOutcomes, trace, visibility)
 	if modulesOutcome == nil {
 		return nil, warnings, nil
 	}
@@ -89,7 +193,7 @@ func GetModulesJSON(
 	return data, warnings, err
 }
 
-func getDebugContext(bidRequest *openrtb2.BidRequest, account *config.Account) (trace, bool, []error) {
+func getDebugContext(bidRequest *openrtb2.BidRequest, account *config.Account) (trace, messagesVisibility, []error) {
 	var traceLevel string
 	var isDebugEnabled bool
 	var warnings []error
@@ -112,10 +216,19 @@ func getDebugContext(bidRequest *openrtb2.BidRequest, account *config.Account) (
 		}
 	}
 
-	return trace(traceLevel), isDebugEnabled, warnings
+	if traceLevel == "" && account != nil {
+		traceLevel = account.Hooks.TraceLevel
+	}
+
+	visibility := messagesVisibility{
+		errors:   isDebugEnabled,
+		warnings: isDebugEnabled || (account != nil && account.Hooks.AlwaysIncludeWarnings),
+	}
+
+	return trace(traceLevel), visibility, warnings
 }
 
-func getModulesOutcome(stageOutcomes []StageOutcome, trace trace, isDebugEnabled bool) *ModulesOutcome {
+func getModulesOutcome(stageOutcomes []StageOutcome, trace trace, visibility messagesVisibility) *ModulesOutcome {
 	var modulesOutcome ModulesOutcome
 	stages := make(map[string]Stage)
 	stageNames := make([]string, 0)
@@ -125,7 +238,7 @@ func getModulesOutcome(stageOutcomes []StageOutcome, trace trace, isDebugEnabled
 			continue
 		}
 
-		prepareModulesOutcome(&modulesOutcome, stageOutcome.Groups, trace, isDebugEnabled)
+		prepareModulesOutcome(&modulesOutcome, stageOutcome.Groups, trace, visibility)
 		if !trace.isBasicOrHigher() {
 			continue
 		}
@@ -164,16 +277,20 @@ func getModulesOutcome(stageOutcomes []StageOutcome, trace trace, isDebugEnabled
 	return &modulesOutcome
 }
 
-func prepareModulesOutcome(modulesOutcome *ModulesOutcome, groups []GroupOutcome, trace trace, isDebugEnabled bool) {
+func prepareModulesOutcome(modulesOutcome *ModulesOutcome, groups []GroupOutcome, trace trace, visibility messagesVisibility) {
 	for _, group := range groups {
 		for i, hookOutcome := range group.InvocationResults {
 			if !trace.isVerbose() {
 				group.InvocationResults[i].DebugMessages = nil
 				group.InvocationResults[i].AnalyticsTags = hookanalytics.Analytics{}
+				group.InvocationResults[i].RejectChain = nil
 			}
 
-			if isDebugEnabled {
+			if visibility.errors {
 				modulesOutcome.Errors = fillMessages(modulesOutcome.Errors, hookOutcome.Errors, hookOutcome.HookID)
+			}
+
+			if visibility.warnings {
 				modulesOutcome.Warnings = fillMessages(modulesOutcome.Warnings, hookOutcome.Warnings, hookOutcome.HookID)
 			}
 		}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/prebid/openrtb/v17/openrtb2"
 	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/openrtb_ext"
 	"github.com/stretchr/testify/assert"
@@ -30,14 +31,18 @@ type GroupOutcomeTest struct {
 
 type HookOutcomeTest struct {
 	ExecutionTime
-	AnalyticsTags hookanalytics.Analytics `json:"analytics_tags"`
-	HookID        HookID                  `json:"hook_id"`
-	Status        Status                  `json:"status"`
-	Action        Action                  `json:"action"`
-	Message       string                  `json:"message"`
-	DebugMessages []string                `json:"debug_messages"`
-	Errors        []string                `json:"errors"`
-	Warnings      []string                `json:"warnings"`
+	AnalyticsTags hookanalytics.Analytics    `json:"analytics_tags"`
+	HookID        HookID                     `json:"hook_id"`
+	Status        Status                     `json:"status"`
+	Action        Action                     `json:"action"`
+	Message       string                     `json:"message"`
+	DebugMessages []string                   `json:"debug_messages"`
+	RejectChain   []MutationRecord           `json:"reject_chain"`
+	HTTPStatus    int                        `json:"http_status"`
+	Errors        []string                   `json:"errors"`
+	Warnings      []string                   `json:"warnings"`
+	HttpCalls     []*openrtb_ext.ExtHttpCall `json:"http_calls"`
+	NonBids       []openrtb_ext.NonBid       `json:"non_bids"`
 }
 
 func TestEnrichBidResponse(t *testing.T) {
@@ -208,6 +213,46 @@ func TestGetModulesJSON(t *testing.T) {
 			bidRequest:              &openrtb2.BidRequest{Test: 1, Ext: []byte(`{"prebid": {"debug": true, "trace": "verbose"}}`)},
 			account:                 &config.Account{DebugAllow: false},
 		},
+		{
+			description:             "Modules Outcome contains only warnings when debug disabled and account.Hooks.AlwaysIncludeWarnings=true",
+			expectedWarnings:        nil,
+			expectedBidResponseFile: "test/complete-stage-outcomes/expected-warnings-response.json",
+			stageOutcomesFile:       "test/complete-stage-outcomes/stage-outcomes.json",
+			bidRequest:              &openrtb2.BidRequest{},
+			account:                 &config.Account{DebugAllow: true, Hooks: config.AccountHooks{AlwaysIncludeWarnings: true}},
+		},
+		{
+			description:             "Modules Outcome contains only warnings when account.DebugAllow=false and account.Hooks.AlwaysIncludeWarnings=true",
+			expectedWarnings:        nil,
+			expectedBidResponseFile: "test/complete-stage-outcomes/expected-warnings-response.json",
+			stageOutcomesFile:       "test/complete-stage-outcomes/stage-outcomes.json",
+			bidRequest:              &openrtb2.BidRequest{Test: 1},
+			account:                 &config.Account{DebugAllow: false, Hooks: config.AccountHooks{AlwaysIncludeWarnings: true}},
+		},
+		{
+			description:             "Modules Outcome contains only verbose trace when account.Hooks.TraceLevel=verbose and trace not set in request",
+			expectedWarnings:        nil,
+			expectedBidResponseFile: "test/complete-stage-outcomes/expected-verbose-response.json",
+			stageOutcomesFile:       "test/complete-stage-outcomes/stage-outcomes.json",
+			bidRequest:              &openrtb2.BidRequest{},
+			account:                 &config.Account{DebugAllow: true, Hooks: config.AccountHooks{TraceLevel: "verbose"}},
+		},
+		{
+			description:             "Modules Outcome contains verbose trace and debug info when account.Hooks.TraceLevel=verbose and bidRequest.test=1",
+			expectedWarnings:        nil,
+			expectedBidResponseFile: "test/complete-stage-outcomes/expected-verbose-debug-response.json",
+			stageOutcomesFile:       "test/complete-stage-outcomes/stage-outcomes.json",
+			bidRequest:              &openrtb2.BidRequest{Test: 1},
+			account:                 &config.Account{DebugAllow: true, Hooks: config.AccountHooks{TraceLevel: "verbose"}},
+		},
+		{
+			description:             "Modules Outcome contains basic trace when bidRequest.ext.prebid.trace=basic overrides account.Hooks.TraceLevel=verbose",
+			expectedWarnings:        nil,
+			expectedBidResponseFile: "test/complete-stage-outcomes/expected-basic-debug-response.json",
+			stageOutcomesFile:       "test/complete-stage-outcomes/stage-outcomes.json",
+			bidRequest:              &openrtb2.BidRequest{Ext: []byte(`{"prebid": {"debug": true, "trace": "basic"}}`)},
+			account:                 &config.Account{DebugAllow: true, Hooks: config.AccountHooks{TraceLevel: "verbose"}},
+		},
 		{
 			description:             "Modules Outcome contains debug info if bidResponse.Ext is nil",
 			expectedWarnings:        nil,
@@ -266,6 +311,94 @@ func TestGetModulesJSON(t *testing.T) {
 	}
 }
 
+func TestGetBidderParticipation(t *testing.T) {
+	bidRequest := &openrtb2.BidRequest{
+		Imp: []openrtb2.Imp{
+			{ID: "imp1", Ext: json.RawMessage(`{"prebid": {"bidder": {"appnexus": {}, "rubicon": {}}}}`)},
+			{ID: "imp2", Ext: json.RawMessage(`{"prebid": {"bidder": {"pubmatic": {}}}}`)},
+		},
+	}
+	bidResponse := &openrtb2.BidResponse{
+		SeatBid: []openrtb2.SeatBid{
+			{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid1"}}},
+			{Seat: "rubicon"},
+		},
+	}
+	stageOutcomes := []StageOutcome{
+		{Entity: entityAuctionRequest, Stage: hooks.StageProcessedAuctionRequest.String()},
+		{Entity: entity("openx"), Stage: hooks.StageBidderRequest.String()},
+	}
+
+	participation := GetBidderParticipation(stageOutcomes, bidRequest, bidResponse)
+	assert.Equal(t, map[string]struct{}{"appnexus": {}, "rubicon": {}, "pubmatic": {}, "openx": {}}, participation.Requested)
+	assert.Equal(t, map[string]struct{}{"appnexus": {}}, participation.WithBids)
+}
+
+func TestEnrichExtBidResponseByParticipation(t *testing.T) {
+	participation := BidderParticipation{
+		Requested: map[string]struct{}{"appnexus": {}, "rubicon": {}},
+		WithBids:  map[string]struct{}{"appnexus": {}},
+	}
+
+	testCases := []struct {
+		description string
+		givenExt    json.RawMessage
+		givenRules  []config.AccountConditionalExt
+		expectedExt json.RawMessage
+		expectedErr error
+	}{
+		{
+			description: "Ext not changed if no rules configured",
+			givenExt:    json.RawMessage(`{"foo":"bar"}`),
+			givenRules:  nil,
+			expectedExt: json.RawMessage(`{"foo":"bar"}`),
+		},
+		{
+			description: "Ext enriched if requested bidder matches rule",
+			givenExt:    json.RawMessage(`{"foo":"bar"}`),
+			givenRules:  []config.AccountConditionalExt{{Bidders: []string{"openx", "rubicon"}, Ext: json.RawMessage(`{"sdk":{"rubicon":true}}`)}},
+			expectedExt: json.RawMessage(`{"foo":"bar","sdk":{"rubicon":true}}`),
+		},
+		{
+			description: "Ext not enriched if bidder without bids matches rule requiring bids",
+			givenExt:    json.RawMessage(`{"foo":"bar"}`),
+			givenRules:  []config.AccountConditionalExt{{Bidders: []string{"rubicon"}, WithBids: true, Ext: json.RawMessage(`{"sdk":{"rubicon":true}}`)}},
+			expectedExt: json.RawMessage(`{"foo":"bar"}`),
+		},
+		{
+			description: "Ext enriched with all matching rules in order",
+			givenExt:    nil,
+			givenRules: []config.AccountConditionalExt{
+				{Bidders: []string{"appnexus"}, WithBids: true, Ext: json.RawMessage(`{"sdk":{"appnexus":true,"v":1}}`)},
+				{Bidders: []string{"openx"}, Ext: json.RawMessage(`{"sdk":{"openx":true}}`)},
+				{Bidders: []string{"rubicon"}, Ext: json.RawMessage(`{"sdk":{"v":2}}`)},
+			},
+			expectedExt: json.RawMessage(`{"sdk":{"appnexus":true,"v":2}}`),
+		},
+		{
+			description: "Error returned if rule ext is invalid",
+			givenExt:    json.RawMessage(`{"foo":"bar"}`),
+			givenRules:  []config.AccountConditionalExt{{Bidders: []string{"appnexus"}, Ext: json.RawMessage(`invalid`)}},
+			expectedExt: nil,
+			expectedErr: errors.New("Invalid JSON Patch"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			account := &config.Account{Hooks: config.AccountHooks{ConditionalExt: test.givenRules}}
+
+			ext, err := EnrichExtBidResponseByParticipation(test.givenExt, participation, account)
+			assert.Equal(t, test.expectedErr, err, "Invalid error returned.")
+			if test.expectedExt == nil {
+				assert.Nil(t, ext, "Nil ext expected.")
+			} else {
+				assert.JSONEq(t, string(test.expectedExt), string(ext), "Invalid ext returned.")
+			}
+		})
+	}
+}
+
 func getStageOutcomes(t *testing.T, file string) []StageOutcome {
 	var stageOutcomes []StageOutcome
 	var stageOutcomesTest []StageOutcomeTest
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/prebid/prebid-server/errortypes"
+	"github.com/prebid/prebid-server/hooks/hookstage"
 )
 
 // TimeoutError indicates exceeding of the max execution time allotted for hook.
@@ -31,9 +32,18 @@ func (e FailureError) Error() string {
 // Implements errortypes.Coder interface for compatibility only,
 // so as not to be recognized as a fatal error
 type RejectError struct {
-	NBR   int
-	Hook  HookID
+	// NBR is the reject reason code set by the hook via the hookstage.HookResult.NbrCode.
+	NBR int
+	// Hook identifies the module and the hook that rejected the stage,
+	// it is empty if the stage was rejected by the executor itself, e.g. if the request body is too large.
+	Hook HookID
+	// Stage is the name of the rejected stage.
 	Stage string
+	// HTTPResponse is the custom response provided by the entrypoint hook that rejected request,
+	// nil means the default response should be rendered.
+	HTTPResponse *hookstage.HTTPResponse
+	// Reason explains the rejection, it is the hookstage.HookResult.Message of the rejecting hook.
+	Reason string
 }
 
 func (e RejectError) Code() int {
@@ -45,13 +55,22 @@ func (e RejectError) Severity() errortypes.Severity {
 }
 
 func (e RejectError) Error() string {
-	return fmt.Sprintf(
+	if e.Hook.ModuleCode == "" {
+		return fmt.Sprintf(`Request rejected with code %d at %s stage`, e.NBR, e.Stage)
+	}
+
+	msg := fmt.Sprintf(
 		`Module %s (hook: %s) rejected request with code %d at %s stage`,
 		e.Hook.ModuleCode,
 		e.Hook.HookImplCode,
 		e.NBR,
 		e.Stage,
 	)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+
+	return msg
 }
 
 func FindFirstRejectOrNil(errors []error) *RejectError {
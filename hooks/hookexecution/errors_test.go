@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/prebid/prebid-server/errortypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -82,3 +83,32 @@ func TestCastRejectErr(t *testing.T) {
 		})
 	}
 }
+
+func TestRejectError(t *testing.T) {
+	err := RejectError{
+		NBR:   123,
+		Hook:  HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+		Stage: "entrypoint",
+	}
+
+	assert.Equal(t, "Module foobar (hook: foo) rejected request with code 123 at entrypoint stage", err.Error())
+	assert.Equal(t, errortypes.ModuleRejectionErrorCode, err.Code())
+	assert.Equal(t, errortypes.SeverityWarning, err.Severity())
+}
+
+func TestRejectErrorWithoutHook(t *testing.T) {
+	err := RejectError{NBR: 2, Stage: "entrypoint"}
+
+	assert.Equal(t, "Request rejected with code 2 at entrypoint stage", err.Error())
+}
+
+func TestRejectErrorWithReason(t *testing.T) {
+	err := RejectError{
+		NBR:    2,
+		Hook:   HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+		Stage:  "processed_auction_request",
+		Reason: "too many imps",
+	}
+
+	assert.Equal(t, "Module foobar (hook: foo) rejected request with code 2 at processed_auction_request stage: too many imps", err.Error())
+}
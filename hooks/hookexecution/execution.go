@@ -3,11 +3,14 @@ package hookexecution
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/prebid/prebid-server/hooks"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/metrics"
 )
@@ -32,25 +35,64 @@ func executeStage[H any, P any](
 	payload P,
 	hookHandler hookHandler[H, P],
 	metricEngine metrics.MetricsEngine,
-) (StageOutcome, P, stageModuleContext, *RejectError) {
+) (StageOutcome, P, stageModuleContext, *RejectError, *CachedResponse) {
 	stageOutcome := StageOutcome{}
 	stageOutcome.Groups = make([]GroupOutcome, 0, len(plan))
 	stageModuleCtx := stageModuleContext{}
 	stageModuleCtx.groupCtx = make([]groupModuleContext, 0, len(plan))
 
-	for _, group := range plan {
-		groupOutcome, newPayload, moduleContexts, rejectErr := executeGroup(executionCtx, group, payload, hookHandler, metricEngine)
+	start := executionCtx.clock.Now()
+	stageTimeout := plan.StageTimeout()
+	for i, group := range plan {
+		if stageTimeout > 0 {
+			remaining := stageTimeout - executionCtx.clock.Since(start)
+			if remaining <= 0 {
+				for _, skippedGroup := range plan[i:] {
+					stageOutcome.Groups = append(stageOutcome.Groups, skippedGroupOutcome(skippedGroup))
+				}
+				break
+			}
+			// group timeout is limited by the time left from the stage budget
+			if group.Timeout > remaining {
+				group.Timeout = remaining
+			}
+		}
+
+		groupOutcome, newPayload, moduleContexts, rejectErr, cachedResponse := executeGroup(executionCtx, group, payload, hookHandler, metricEngine)
 		stageOutcome.ExecutionTimeMillis += groupOutcome.ExecutionTimeMillis
 		stageOutcome.Groups = append(stageOutcome.Groups, groupOutcome)
 		stageModuleCtx.groupCtx = append(stageModuleCtx.groupCtx, moduleContexts)
 		if rejectErr != nil {
-			return stageOutcome, payload, stageModuleCtx, rejectErr
+			metricEngine.RecordRejectedRequest(rejectErr.Stage, rejectErr.Hook.ModuleCode, rejectErr.NBR)
+			executionCtx.logger.logStage(executionCtx, stageOutcome)
+			return stageOutcome, payload, stageModuleCtx, rejectErr, nil
+		}
+		if cachedResponse != nil {
+			executionCtx.logger.logStage(executionCtx, stageOutcome)
+			return stageOutcome, payload, stageModuleCtx, nil, cachedResponse
 		}
 
 		payload = newPayload
 	}
+	executionCtx.logger.logStage(executionCtx, stageOutcome)
 
-	return stageOutcome, payload, stageModuleCtx, nil
+	return stageOutcome, payload, stageModuleCtx, nil, nil
+}
+
+// skippedGroupOutcome marks all hooks of the group as timed out,
+// it is used for the groups not started because the stage ran out of time.
+func skippedGroupOutcome[H any](group hooks.Group[H]) GroupOutcome {
+	groupOutcome := GroupOutcome{InvocationResults: make([]HookOutcome, 0, len(group.Hooks))}
+	for _, hook := range group.Hooks {
+		groupOutcome.InvocationResults = append(groupOutcome.InvocationResults, HookOutcome{
+			HookID: HookID{ModuleCode: hook.Module, HookImplCode: hook.Code},
+			Status: StatusTimeout,
+			Errors: []string{"Hook skipped, stage execution timeout exceeded"},
+		})
+	}
+	groupOutcome.InvocationResults = append(groupOutcome.InvocationResults, unresolvedHookOutcomes(group.Unresolved)...)
+
+	return groupOutcome
 }
 
 func executeGroup[H any, P any](
@@ -59,18 +101,20 @@ func executeGroup[H any, P any](
 	payload P,
 	hookHandler hookHandler[H, P],
 	metricEngine metrics.MetricsEngine,
-) (GroupOutcome, P, groupModuleContext, *RejectError) {
+) (GroupOutcome, P, groupModuleContext, *RejectError, *CachedResponse) {
 	var wg sync.WaitGroup
-	rejected := make(chan struct{})
+	stopped := make(chan struct{})
 	resp := make(chan hookResponse[P])
+	parentCtx := executionCtx.hookContext(context.Background())
 
 	for _, hook := range group.Hooks {
 		mCtx := executionCtx.getModuleContext(hook.Module)
+		clk := executionCtx.hookClock(HookID{ModuleCode: hook.Module, HookImplCode: hook.Code})
 		wg.Add(1)
-		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext) {
+		go func(hw hooks.HookWrapper[H], moduleCtx hookstage.ModuleInvocationContext, hookPayload P) {
 			defer wg.Done()
-			executeHook(moduleCtx, hw, payload, hookHandler, group.Timeout, resp, rejected)
-		}(hook, mCtx)
+			executeHook(parentCtx, moduleCtx, hw, hookPayload, hookHandler, group.Timeout, clk, resp, stopped)
+		}(hook, mCtx, payload)
 	}
 
 	go func() {
@@ -78,26 +122,53 @@ func executeGroup[H any, P any](
 		close(resp)
 	}()
 
-	hookResponses := collectHookResponses(resp, rejected)
+	hookResponses := collectHookResponses(resp, stopped)
+
+	groupOutcome, payload, groupModuleCtx, rejectErr, cachedResponse := handleHookResponses(executionCtx, hookResponses, payload, metricEngine)
+	groupOutcome.InvocationResults = append(groupOutcome.InvocationResults, unresolvedHookOutcomes(group.Unresolved)...)
 
-	return handleHookResponses(executionCtx, hookResponses, payload, metricEngine)
+	return groupOutcome, payload, groupModuleCtx, rejectErr, cachedResponse
+}
+
+// unresolvedHookOutcomes reports hooks referenced by the execution plan but missing in the hook repository,
+// so that misconfigured plans are visible in the debug output.
+func unresolvedHookOutcomes(unresolved []hooks.UnresolvedHook) []HookOutcome {
+	outcomes := make([]HookOutcome, 0, len(unresolved))
+	for _, h := range unresolved {
+		outcomes = append(outcomes, HookOutcome{
+			HookID: HookID{ModuleCode: h.Module, HookImplCode: h.Code},
+			Status: StatusUnresolved,
+			Action: ActionNone,
+			Warnings: []string{
+				fmt.Sprintf("Hook %s.%s referenced by the execution plan but not registered", h.Module, h.Code),
+			},
+		})
+	}
+
+	return outcomes
 }
 
 func executeHook[H any, P any](
+	parentCtx context.Context,
 	moduleCtx hookstage.ModuleInvocationContext,
 	hw hooks.HookWrapper[H],
 	payload P,
 	hookHandler hookHandler[H, P],
 	timeout time.Duration,
+	clk clock.Clock,
 	resp chan<- hookResponse[P],
-	rejected <-chan struct{},
+	stopped <-chan struct{},
 ) {
 	hookRespCh := make(chan hookResponse[P], 1)
-	startTime := time.Now()
+	startTime := clk.Now()
 	hookId := HookID{ModuleCode: hw.Module, HookImplCode: hw.Code}
 
+	// the timer and the hook context deadline are set before the hook is started,
+	// so the hook is guaranteed to observe them even if it advances a mock clock
+	timeoutCh := clk.After(timeout)
+	ctx, cancel := clk.WithTimeout(parentCtx, timeout)
+
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 		result, err := hookHandler(ctx, moduleCtx, hw.Hook, payload)
 		hookRespCh <- hookResponse[P]{
@@ -106,29 +177,38 @@ func executeHook[H any, P any](
 		}
 	}()
 
+	var res hookResponse[P]
 	select {
-	case res := <-hookRespCh:
+	case res = <-hookRespCh:
 		res.HookID = hookId
-		res.ExecutionTime = time.Since(startTime)
-		resp <- res
-	case <-time.After(timeout):
-		resp <- hookResponse[P]{
+		res.ExecutionTime = clk.Since(startTime)
+	case <-timeoutCh:
+		res = hookResponse[P]{
 			Err:           TimeoutError{},
-			ExecutionTime: time.Since(startTime),
+			ExecutionTime: clk.Since(startTime),
 			HookID:        hookId,
 			Result:        hookstage.HookResult[P]{},
 		}
-	case <-rejected:
+	case <-stopped:
 		return
 	}
+
+	// the collector stops reading responses once the group is stopped,
+	// so the send must not block the hook goroutine forever
+	select {
+	case resp <- res:
+	case <-stopped:
+	}
 }
 
-func collectHookResponses[P any](resp <-chan hookResponse[P], rejected chan<- struct{}) []hookResponse[P] {
+// collectHookResponses gathers responses of the group hooks in the order of their completion.
+// Remaining hooks are abandoned once any hook rejects the stage or stops the group.
+func collectHookResponses[P any](resp <-chan hookResponse[P], stopped chan<- struct{}) []hookResponse[P] {
 	hookResponses := make([]hookResponse[P], 0)
 	for r := range resp {
 		hookResponses = append(hookResponses, r)
-		if r.Result.Reject {
-			close(rejected)
+		if r.Result.Reject || (r.Err == nil && r.Result.StopGroup) {
+			close(stopped)
 			break
 		}
 	}
@@ -141,27 +221,51 @@ func handleHookResponses[P any](
 	hookResponses []hookResponse[P],
 	payload P,
 	metricEngine metrics.MetricsEngine,
-) (GroupOutcome, P, groupModuleContext, *RejectError) {
+) (GroupOutcome, P, groupModuleContext, *RejectError, *CachedResponse) {
 	groupOutcome := GroupOutcome{}
 	groupOutcome.InvocationResults = make([]HookOutcome, 0, len(hookResponses))
 	groupModuleCtx := make(groupModuleContext, len(hookResponses))
 
 	for _, r := range hookResponses {
-		groupModuleCtx[r.HookID.ModuleCode] = r.Result.ModuleContext
+		// hooks of the same module within the group contribute to a single context
+		if moduleCtx, ok := groupModuleCtx[r.HookID.ModuleCode]; ok {
+			groupModuleCtx[r.HookID.ModuleCode] = mergeModuleContexts(moduleCtx, r.Result.ModuleContext)
+		} else {
+			groupModuleCtx[r.HookID.ModuleCode] = r.Result.ModuleContext
+		}
 		if r.ExecutionTime > groupOutcome.ExecutionTimeMillis {
 			groupOutcome.ExecutionTimeMillis = r.ExecutionTime
 		}
 
-		updatedPayload, hookOutcome, rejectErr := handleHookResponse(executionCtx, payload, r, metricEngine)
+		updatedPayload, hookOutcome, rejectErr, cachedResponse := handleHookResponse(executionCtx, payload, r, metricEngine)
 		groupOutcome.InvocationResults = append(groupOutcome.InvocationResults, hookOutcome)
 		payload = updatedPayload
 
-		if rejectErr != nil {
-			return groupOutcome, payload, groupModuleCtx, rejectErr
+		if rejectErr != nil || cachedResponse != nil {
+			return groupOutcome, payload, groupModuleCtx, rejectErr, cachedResponse
+		}
+	}
+
+	return groupOutcome, payload, groupModuleCtx, nil, nil
+}
+
+// namespaceAnalyticsTags prefixes the names of the activities reported by the module with the module code,
+// so that the same-named activities of different modules don't collide in the analytics.
+func namespaceAnalyticsTags(moduleCode string, tags hookanalytics.Analytics) hookanalytics.Analytics {
+	if len(tags.Activities) == 0 {
+		return tags
+	}
+
+	prefix := moduleCode + "."
+	activities := make([]hookanalytics.Activity, len(tags.Activities))
+	for i, activity := range tags.Activities {
+		if !strings.HasPrefix(activity.Name, prefix) {
+			activity.Name = prefix + activity.Name
 		}
+		activities[i] = activity
 	}
 
-	return groupOutcome, payload, groupModuleCtx, nil
+	return hookanalytics.Analytics{Activities: activities}
 }
 
 // handleHookResponse is a strategy function that selects and applies
@@ -171,8 +275,9 @@ func handleHookResponse[P any](
 	payload P,
 	hr hookResponse[P],
 	metricEngine metrics.MetricsEngine,
-) (P, HookOutcome, *RejectError) {
+) (P, HookOutcome, *RejectError, *CachedResponse) {
 	var rejectErr *RejectError
+	var cachedResponse *CachedResponse
 	labels := metrics.ModuleLabels{Module: hr.HookID.ModuleCode, Stage: ctx.stage, AccountID: ctx.accountId}
 	metricEngine.RecordModuleCalled(labels, hr.ExecutionTime)
 
@@ -183,7 +288,9 @@ func handleHookResponse[P any](
 		Errors:        hr.Result.Errors,
 		Warnings:      hr.Result.Warnings,
 		DebugMessages: hr.Result.DebugMessages,
-		AnalyticsTags: hr.Result.AnalyticsTags,
+		AnalyticsTags: namespaceAnalyticsTags(hr.HookID.ModuleCode, hr.Result.AnalyticsTags),
+		HttpCalls:     hr.Result.HttpCalls,
+		NonBids:       hr.Result.NonBids,
 		ExecutionTime: ExecutionTime{ExecutionTimeMillis: hr.ExecutionTime},
 	}
 
@@ -192,11 +299,16 @@ func handleHookResponse[P any](
 		handleHookError(hr, &hookOutcome, metricEngine, labels)
 	case hr.Result.Reject:
 		rejectErr = handleHookReject(ctx, hr, &hookOutcome, metricEngine, labels)
+	case hr.Result.CachedResponse != nil && hooks.Stage(ctx.stage) == hooks.StageEntrypoint:
+		cachedResponse = handleHookCachedResponse(ctx, hr, &hookOutcome, metricEngine, labels)
 	default:
-		payload = handleHookMutations(payload, hr, &hookOutcome, metricEngine, labels)
+		payload = handleHookMutations(ctx, payload, hr, &hookOutcome, metricEngine, labels)
+		if hr.Result.StopGroup {
+			hookOutcome.Action = ActionStopGroup
+		}
 	}
 
-	return payload, hookOutcome, rejectErr
+	return payload, hookOutcome, rejectErr, cachedResponse
 }
 
 // handleHookError sets an appropriate status to HookOutcome depending on the type of hook execution error.
@@ -253,16 +365,42 @@ func handleHookReject[P any](
 		return nil
 	}
 
-	rejectErr := &RejectError{NBR: hr.Result.NbrCode, Hook: hr.HookID, Stage: ctx.stage}
+	rejectErr := &RejectError{NBR: hr.Result.NbrCode, Hook: hr.HookID, Stage: ctx.stage, Reason: hr.Result.Message}
+	if stage == hooks.StageEntrypoint && hr.Result.HTTPResponse != nil {
+		rejectErr.HTTPResponse = hr.Result.HTTPResponse
+		hookOutcome.HTTPStatus = hr.Result.HTTPResponse.StatusCode
+	}
 	hookOutcome.Action = ActionReject
+	hookOutcome.RejectChain = ctx.mutationLog.snapshot(ctx.entity)
 	hookOutcome.Errors = append(hookOutcome.Errors, rejectErr.Error())
 	metricEngine.RecordModuleSuccessRejected(labels)
 
 	return rejectErr
 }
 
+// handleHookCachedResponse short-circuits the request with the cached response provided by the entrypoint hook.
+func handleHookCachedResponse[P any](
+	ctx executionContext,
+	hr hookResponse[P],
+	hookOutcome *HookOutcome,
+	metricEngine metrics.MetricsEngine,
+	labels metrics.ModuleLabels,
+) *CachedResponse {
+	response := *hr.Result.CachedResponse
+	if response.StatusCode == 0 {
+		response.StatusCode = http.StatusOK
+	}
+
+	hookOutcome.Action = ActionCacheHit
+	hookOutcome.HTTPStatus = response.StatusCode
+	metricEngine.RecordModuleSuccessCacheHit(labels)
+
+	return &CachedResponse{Hook: hr.HookID, Stage: ctx.stage, HTTPResponse: &response}
+}
+
 // handleHookMutations applies mutations returned by hook to provided payload.
 func handleHookMutations[P any](
+	ctx executionContext,
 	payload P,
 	hr hookResponse[P],
 	hookOutcome *HookOutcome,
@@ -270,16 +408,19 @@ func handleHookMutations[P any](
 	labels metrics.ModuleLabels,
 ) P {
 	if len(hr.Result.ChangeSet.Mutations()) == 0 {
-		metricEngine.RecordModuleSuccessNooped(labels)
+		metricEngine.RecordModuleSuccessNooped(labels, hr.HookID.HookImplCode)
 		hookOutcome.Action = ActionNone
 		return payload
 	}
 
 	hookOutcome.Action = ActionUpdate
 	successfulMutations := 0
+	injectedBids := 0
 	for _, mut := range hr.Result.ChangeSet.Mutations() {
+		before := ctx.auditor.snapshot(payload, mut.Key())
 		p, err := mut.Apply(payload)
 		if err != nil {
+			metricEngine.RecordModuleMutationError(labels, hr.HookID.HookImplCode)
 			hookOutcome.Warnings = append(
 				hookOutcome.Warnings,
 				fmt.Sprintf("failed to apply hook mutation: %s", err),
@@ -288,15 +429,25 @@ func handleHookMutations[P any](
 		}
 
 		payload = p
+		key := strings.Join(mut.Key(), ".")
 		hookOutcome.DebugMessages = append(
 			hookOutcome.DebugMessages,
 			fmt.Sprintf(
 				"Hook mutation successfully applied, affected key: %s, mutation type: %s",
-				strings.Join(mut.Key(), "."),
+				key,
 				mut.Type(),
 			),
 		)
+		ctx.mutationLog.add(ctx.entity, MutationRecord{HookID: hr.HookID, Stage: ctx.stage, Key: key, Type: mut.Type().String()})
+		ctx.auditor.log(hr.HookID, ctx.stage, key, mut.Type(), before, ctx.auditor.snapshot(payload, mut.Key()))
 		successfulMutations++
+		if mut.Type() == hookstage.MutationInjectBid {
+			injectedBids++
+		}
+	}
+
+	if injectedBids > 0 {
+		hookOutcome.Action = ActionInjectBids
 	}
 
 	// if at least one mutation from a given module was successfully applied
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/benbjohnson/clock"
 	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/openrtb/v17/openrtb3"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/exchange/entities"
@@ -13,6 +15,7 @@ import (
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/metrics"
 	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/prebid/prebid-server/util/uuidutil"
 )
 
 const (
@@ -30,22 +33,50 @@ const (
 	entityAllProcessedBidResponses entity = "all_processed_bid_responses"
 )
 
+// CachedResponse is the complete response served by the entrypoint hook
+// via the hookstage.HookResult.CachedResponse instead of running the auction.
+// Unlike the RejectError, it is a successful result short-circuiting the request processing.
+type CachedResponse struct {
+	// Hook identifies the module and the hook that served the response.
+	Hook HookID
+	// Stage is the name of the stage the response was served at.
+	Stage string
+	// HTTPResponse is the response to be written to the client, its status code defaults to http.StatusOK.
+	HTTPResponse *hookstage.HTTPResponse
+}
+
 type StageExecutor interface {
-	ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError)
-	ExecuteRawAuctionStage(body []byte) ([]byte, *RejectError)
+	ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *CachedResponse, *RejectError)
+	ExecuteEntrypointAccountStage(req *http.Request, body []byte) ([]byte, *RejectError)
+	ExecuteRawAuctionStage(header http.Header, body []byte) ([]byte, *RejectError)
 	ExecuteProcessedAuctionStage(req *openrtb2.BidRequest) *RejectError
 	ExecuteBidderRequestStage(req *openrtb2.BidRequest, bidder string) *RejectError
+	ExecuteBidderHttpRequestStage(requests []*adapters.RequestData, bidder string) (*http.Client, *RejectError)
 	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
-	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, dealTiers map[string]openrtb_ext.DealTierBidderMap) *RejectError
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
+	GetHttpCalls() map[string][]*openrtb_ext.ExtHttpCall
+	GetNonBids() map[string][]openrtb_ext.NonBid
 }
 
 type HookStageExecutor interface {
 	StageExecutor
 	SetAccount(account *config.Account)
 	GetOutcomes() []StageOutcome
+	GetOutcomesSummary() []StageSummary
 }
 
+// StageResultObserver is notified about the outcome of each stage right after all its hooks complete.
+// Implementations must be safe for concurrent use, as bidder-specific stages are executed in parallel.
+type StageResultObserver interface {
+	OnStageResult(outcome StageOutcome)
+}
+
+// NoopStageResultObserver is the default StageResultObserver which ignores stage outcomes.
+type NoopStageResultObserver struct{}
+
+func (o NoopStageResultObserver) OnStageResult(_ StageOutcome) {}
+
 type hookExecutor struct {
 	account        *config.Account
 	accountID      string
@@ -53,7 +84,19 @@ type hookExecutor struct {
 	planBuilder    hooks.ExecutionPlanBuilder
 	stageOutcomes  []StageOutcome
 	moduleContexts *moduleContexts
+	mutationLog    *mutationLog
+	auditor        *mutationAuditor
+	observer       StageResultObserver
 	metricEngine   metrics.MetricsEngine
+	// maxBodyBytes limits the size of the request body passed to the entrypoint hooks, 0 means unlimited
+	maxBodyBytes int64
+	logger       *hookLogger
+	// correlationID identifies the request in the hook execution log and is passed to hooks via context
+	correlationID string
+	uuidGenerator uuidutil.UUIDGenerator
+	clock         clock.Clock
+	// hookClocks replace the clock for particular hooks in tests
+	hookClocks map[HookID]clock.Clock
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
@@ -64,8 +107,31 @@ func NewHookExecutor(builder hooks.ExecutionPlanBuilder, endpoint string, me met
 		planBuilder:    builder,
 		stageOutcomes:  []StageOutcome{},
 		moduleContexts: &moduleContexts{ctxs: make(map[string]hookstage.ModuleContext)},
+		mutationLog:    &mutationLog{},
+		observer:       NoopStageResultObserver{},
 		metricEngine:   me,
+		uuidGenerator:  uuidutil.UUIDRandomGenerator{},
+		clock:          clock.New(),
+	}
+}
+
+// SetStageResultObserver registers the observer notified about each executed stage outcome.
+func (e *hookExecutor) SetStageResultObserver(observer StageResultObserver) {
+	if observer == nil {
+		observer = NoopStageResultObserver{}
 	}
+	e.observer = observer
+}
+
+// SetMaxBodyBytes sets the max size of the request body accepted by the entrypoint stage.
+// Requests with bigger bodies are rejected before any hook is executed, 0 means unlimited.
+func (e *hookExecutor) SetMaxBodyBytes(maxBodyBytes int64) {
+	e.maxBodyBytes = maxBodyBytes
+}
+
+// SetLogging enables the log line per hook invocation according to the host config, logging is disabled by default.
+func (e *hookExecutor) SetLogging(cfg config.HookLogging) {
+	e.logger = newHookLogger(cfg)
 }
 
 func (e *hookExecutor) SetAccount(account *config.Account) {
@@ -75,16 +141,94 @@ func (e *hookExecutor) SetAccount(account *config.Account) {
 
 	e.account = account
 	e.accountID = account.ID
+	e.auditor = newSampledMutationAuditor(account)
+}
+
+// Reset clears the state collected while processing a request, the configuration of the executor is kept.
+// It allows reusing pooled executors, callers must Reset the executor before reuse
+// as the outcomes, account and module contexts of the previous request leak otherwise.
+func (e *hookExecutor) Reset() {
+	e.Lock()
+	defer e.Unlock()
+
+	e.account = nil
+	e.accountID = ""
+	e.auditor = nil
+	e.correlationID = ""
+	e.stageOutcomes = []StageOutcome{}
+	e.moduleContexts = &moduleContexts{ctxs: make(map[string]hookstage.ModuleContext)}
+	e.mutationLog = &mutationLog{}
 }
 
 func (e *hookExecutor) GetOutcomes() []StageOutcome {
 	return e.stageOutcomes
 }
 
-func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *RejectError) {
+// GetOutcomesSummary returns the summary of all stages executed so far,
+// regardless of the request debug and trace settings.
+func (e *hookExecutor) GetOutcomesSummary() []StageSummary {
+	e.Lock()
+	defer e.Unlock()
+	return SummarizeOutcomes(e.stageOutcomes)
+}
+
+// GetHttpCalls returns the outbound HTTP calls reported by hooks of all stages executed so far,
+// grouped by the module code.
+func (e *hookExecutor) GetHttpCalls() map[string][]*openrtb_ext.ExtHttpCall {
+	e.Lock()
+	defer e.Unlock()
+
+	httpCalls := make(map[string][]*openrtb_ext.ExtHttpCall)
+	for _, stageOutcome := range e.stageOutcomes {
+		for _, groupOutcome := range stageOutcome.Groups {
+			for _, hookOutcome := range groupOutcome.InvocationResults {
+				if len(hookOutcome.HttpCalls) > 0 {
+					module := hookOutcome.HookID.ModuleCode
+					httpCalls[module] = append(httpCalls[module], hookOutcome.HttpCalls...)
+				}
+			}
+		}
+	}
+	return httpCalls
+}
+
+// GetNonBids returns the reasons of the bids removed by hooks of the bidder-level stages executed so far,
+// grouped by the bidder. Hooks of other stages can't report non-bids as they are not bound to a bidder.
+func (e *hookExecutor) GetNonBids() map[string][]openrtb_ext.NonBid {
+	e.Lock()
+	defer e.Unlock()
+
+	nonBids := make(map[string][]openrtb_ext.NonBid)
+	for _, stageOutcome := range e.stageOutcomes {
+		switch hooks.Stage(stageOutcome.Stage) {
+		case hooks.StageBidderRequest, hooks.StageBidderHttpRequest, hooks.StageRawBidderResponse:
+		default:
+			continue
+		}
+
+		for _, groupOutcome := range stageOutcome.Groups {
+			for _, hookOutcome := range groupOutcome.InvocationResults {
+				if len(hookOutcome.NonBids) > 0 {
+					bidder := string(stageOutcome.Entity)
+					nonBids[bidder] = append(nonBids[bidder], hookOutcome.NonBids...)
+				}
+			}
+		}
+	}
+	return nonBids
+}
+
+func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([]byte, *CachedResponse, *RejectError) {
+	e.correlationID = correlationID(req, e.uuidGenerator)
+
+	if e.maxBodyBytes > 0 && int64(len(body)) > e.maxBodyBytes {
+		e.metricEngine.RecordRequestBodySizeExceeded()
+		return body, nil, &RejectError{NBR: int(openrtb3.NoBidInvalidRequest), Stage: hooks.StageEntrypoint.String()}
+	}
+
 	plan := e.planBuilder.PlanForEntrypointStage(e.endpoint)
 	if len(plan) == 0 {
-		return body, nil
+		return body, nil, nil
 	}
 
 	handler := func(
@@ -97,20 +241,142 @@ func (e *hookExecutor) ExecuteEntrypointStage(req *http.Request, body []byte) ([
 	}
 
 	stageName := hooks.StageEntrypoint.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityHttpRequest)
+	payload := hookstage.EntrypointPayload{Request: req, Body: body}
+	originalAmpParams := e.setAmpParams(&payload)
+
+	outcome, payload, contexts, rejectErr, cachedResponse := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityHttpRequest
+	outcome.Stage = stageName
+
+	if originalAmpParams != nil && rejectErr == nil && cachedResponse == nil {
+		updateAmpParams(payload.Request, originalAmpParams, payload.AmpParams)
+	}
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	return payload.Body, cachedResponse, rejectErr
+}
+
+// ExecuteEntrypointAccountStage invokes once more the entrypoint hooks implementing hookstage.EntrypointAccount,
+// it is called after the account is set, so the hooks are provided with the account-level module config.
+// The stage uses the entrypoint execution plan, other entrypoint hooks are not invoked again.
+func (e *hookExecutor) ExecuteEntrypointAccountStage(req *http.Request, body []byte) ([]byte, *RejectError) {
+	plan := entrypointAccountPlan(e.planBuilder.PlanForEntrypointStage(e.endpoint))
+	if len(plan) == 0 {
+		return body, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.EntrypointAccount,
+		payload hookstage.EntrypointPayload,
+	) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+		return hook.HandleEntrypointAccountHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageEntrypointAccount.String()
+	executionCtx := e.newContext(stageName, entityHttpRequest)
 	payload := hookstage.EntrypointPayload{Request: req, Body: body}
+	originalAmpParams := e.setAmpParams(&payload)
 
-	outcome, payload, contexts, rejectErr := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, payload, contexts, rejectErr, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entityHttpRequest
 	outcome.Stage = stageName
 
+	if originalAmpParams != nil && rejectErr == nil {
+		updateAmpParams(payload.Request, originalAmpParams, payload.AmpParams)
+	}
+
 	e.saveModuleContexts(contexts)
 	e.pushStageOutcome(outcome)
 
 	return payload.Body, rejectErr
 }
 
-func (e *hookExecutor) ExecuteRawAuctionStage(requestBody []byte) ([]byte, *RejectError) {
+// entrypointAccountPlan keeps the hooks of the entrypoint plan implementing hookstage.EntrypointAccount,
+// groups left without hooks are dropped.
+func entrypointAccountPlan(entrypointPlan hooks.Plan[hookstage.Entrypoint]) hooks.Plan[hookstage.EntrypointAccount] {
+	var plan hooks.Plan[hookstage.EntrypointAccount]
+	for _, group := range entrypointPlan {
+		accountGroup := hooks.Group[hookstage.EntrypointAccount]{Timeout: group.Timeout, StageTimeout: group.StageTimeout}
+		for _, hw := range group.Hooks {
+			if hook, ok := hw.Hook.(hookstage.EntrypointAccount); ok {
+				accountGroup.Hooks = append(accountGroup.Hooks, hooks.HookWrapper[hookstage.EntrypointAccount]{Module: hw.Module, Code: hw.Code, Hook: hook})
+			}
+		}
+		if len(accountGroup.Hooks) > 0 {
+			plan = append(plan, accountGroup)
+		}
+	}
+	return plan
+}
+
+// setAmpParams provides the payload of the "/openrtb2/amp" endpoint with the AMP params.
+// Hooks may change the params in place, so a copy of the original values is returned to detect the changes,
+// it is nil for other endpoints.
+func (e *hookExecutor) setAmpParams(payload *hookstage.EntrypointPayload) map[string]string {
+	if e.endpoint != EndpointAmp {
+		return nil
+	}
+
+	payload.AmpParams = getAmpParams(payload.Request)
+	return copyAmpParams(payload.AmpParams)
+}
+
+// getAmpParams returns the first values of the AMP request query parameters keyed by name.
+func getAmpParams(req *http.Request) map[string]string {
+	params := make(map[string]string)
+	if req == nil || req.URL == nil {
+		return params
+	}
+
+	for name, values := range req.URL.Query() {
+		if len(values) > 0 {
+			params[name] = values[0]
+		}
+	}
+	return params
+}
+
+func copyAmpParams(params map[string]string) map[string]string {
+	c := make(map[string]string, len(params))
+	for name, value := range params {
+		c[name] = value
+	}
+	return c
+}
+
+// updateAmpParams writes the AMP parameters changed by hooks back to the request query,
+// parameters left unchanged are not touched, so direct mutations of the request URL are preserved.
+func updateAmpParams(req *http.Request, before, after map[string]string) {
+	if req == nil || req.URL == nil {
+		return
+	}
+
+	query := req.URL.Query()
+	changed := false
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			query.Del(name)
+			changed = true
+		}
+	}
+	for name, value := range after {
+		if oldValue, ok := before[name]; !ok || oldValue != value {
+			query.Set(name, value)
+			changed = true
+		}
+	}
+
+	if changed {
+		req.URL.RawQuery = query.Encode()
+	}
+}
+
+func (e *hookExecutor) ExecuteRawAuctionStage(header http.Header, requestBody []byte) ([]byte, *RejectError) {
 	plan := e.planBuilder.PlanForRawAuctionStage(e.endpoint, e.account)
 	if len(plan) == 0 {
 		return requestBody, nil
@@ -126,17 +392,17 @@ func (e *hookExecutor) ExecuteRawAuctionStage(requestBody []byte) ([]byte, *Reje
 	}
 
 	stageName := hooks.StageRawAuctionRequest.String()
-	executionCtx := e.newContext(stageName)
-	payload := hookstage.RawAuctionRequestPayload(requestBody)
+	executionCtx := e.newContext(stageName, entityAuctionRequest)
+	payload := hookstage.RawAuctionRequestPayload{Body: requestBody, Header: header.Clone()}
 
-	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, payload, contexts, reject, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entityAuctionRequest
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
 	e.pushStageOutcome(outcome)
 
-	return payload, reject
+	return payload.Body, reject
 }
 
 func (e *hookExecutor) ExecuteProcessedAuctionStage(request *openrtb2.BidRequest) *RejectError {
@@ -155,10 +421,10 @@ func (e *hookExecutor) ExecuteProcessedAuctionStage(request *openrtb2.BidRequest
 	}
 
 	stageName := hooks.StageProcessedAuctionRequest.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityAuctionRequest)
 	payload := hookstage.ProcessedAuctionRequestPayload{BidRequest: request}
 
-	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, _, contexts, reject, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entityAuctionRequest
 	outcome.Stage = stageName
 
@@ -168,6 +434,9 @@ func (e *hookExecutor) ExecuteProcessedAuctionStage(request *openrtb2.BidRequest
 	return reject
 }
 
+// ExecuteBidderRequestStage runs the bidder-request hooks and applies their mutations to the given request in place.
+// The stage is executed concurrently for the bidders of the auction, so the request of each bidder,
+// including its impressions, must not be shared with other bidders.
 func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb2.BidRequest, bidder string) *RejectError {
 	plan := e.planBuilder.PlanForBidderRequestStage(e.endpoint, e.account)
 	if len(plan) == 0 {
@@ -184,9 +453,9 @@ func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb2.BidRequest, bidde
 	}
 
 	stageName := hooks.StageBidderRequest.String()
-	executionCtx := e.newContext(stageName)
-	payload := hookstage.BidderRequestPayload{BidRequest: req, Bidder: bidder}
-	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	executionCtx := e.newContext(stageName, entity(bidder))
+	payload := hookstage.NewBidderRequestPayload(req, bidder)
+	outcome, payload, contexts, reject, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entity(bidder)
 	outcome.Stage = stageName
 
@@ -196,6 +465,41 @@ func (e *hookExecutor) ExecuteBidderRequestStage(req *openrtb2.BidRequest, bidde
 	return reject
 }
 
+// ExecuteBidderHttpRequestStage runs the hooks allowed to rewrite the URI and headers of the bidder HTTP requests,
+// the requests are mutated in place. It returns the HTTP client provided by hooks to send the requests with,
+// nil if the adapter client should be used.
+func (e *hookExecutor) ExecuteBidderHttpRequestStage(requests []*adapters.RequestData, bidder string) (*http.Client, *RejectError) {
+	plan := e.planBuilder.PlanForBidderHttpRequestStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return nil, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.BidderHttpRequest,
+		payload hookstage.BidderHttpRequestPayload,
+	) (hookstage.HookResult[hookstage.BidderHttpRequestPayload], error) {
+		return hook.HandleBidderHttpRequestHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageBidderHttpRequest.String()
+	executionCtx := e.newContext(stageName, entity(bidder))
+	payload := hookstage.BidderHttpRequestPayload{Requests: requests, Bidder: bidder}
+
+	outcome, payload, contexts, reject, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entity(bidder)
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	if reject != nil {
+		return nil, reject
+	}
+	return payload.Client, nil
+}
+
 func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError {
 	plan := e.planBuilder.PlanForRawBidderResponseStage(e.endpoint, e.account)
 	if len(plan) == 0 {
@@ -212,23 +516,28 @@ func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderRe
 	}
 
 	stageName := hooks.StageRawBidderResponse.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entity(bidder))
 	payload := hookstage.RawBidderResponsePayload{Bids: response.Bids, Bidder: bidder}
 
-	outcome, _, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, payload, contexts, reject, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entity(bidder)
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
 	e.pushStageOutcome(outcome)
 
+	if reject == nil {
+		// synthetic bids may be appended by hooks
+		response.Bids = payload.Bids
+	}
+
 	return reject
 }
 
-func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, dealTiers map[string]openrtb_ext.DealTierBidderMap) *RejectError {
 	plan := e.planBuilder.PlanForAllProcessedBidResponsesStage(e.endpoint, e.account)
 	if len(plan) == 0 {
-		return
+		return nil
 	}
 
 	handler := func(
@@ -241,14 +550,16 @@ func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[open
 	}
 
 	stageName := hooks.StageAllProcessedBidResponses.String()
-	executionCtx := e.newContext(stageName)
-	payload := hookstage.AllProcessedBidResponsesPayload{Responses: adapterBids}
-	outcome, _, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	executionCtx := e.newContext(stageName, entityAllProcessedBidResponses)
+	payload := hookstage.AllProcessedBidResponsesPayload{Responses: adapterBids, DealTiers: dealTiers}
+	outcome, _, contexts, reject, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entityAllProcessedBidResponses
 	outcome.Stage = stageName
 
 	e.saveModuleContexts(contexts)
 	e.pushStageOutcome(outcome)
+
+	return reject
 }
 
 func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidResponse) {
@@ -267,10 +578,10 @@ func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidRespons
 	}
 
 	stageName := hooks.StageAuctionResponse.String()
-	executionCtx := e.newContext(stageName)
+	executionCtx := e.newContext(stageName, entityAuctionResponse)
 	payload := hookstage.AuctionResponsePayload{BidResponse: response}
 
-	outcome, _, contexts, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome, _, contexts, _, _ := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
 	outcome.Entity = entityAuctionResponse
 	outcome.Stage = stageName
 
@@ -278,13 +589,20 @@ func (e *hookExecutor) ExecuteAuctionResponseStage(response *openrtb2.BidRespons
 	e.pushStageOutcome(outcome)
 }
 
-func (e *hookExecutor) newContext(stage string) executionContext {
+func (e *hookExecutor) newContext(stage string, entity entity) executionContext {
 	return executionContext{
+		entity:         entity,
 		account:        e.account,
 		accountId:      e.accountID,
 		endpoint:       e.endpoint,
 		moduleContexts: e.moduleContexts,
+		mutationLog:    e.mutationLog,
+		auditor:        e.auditor,
+		logger:         e.logger,
+		correlationID:  e.correlationID,
 		stage:          stage,
+		clock:          e.clock,
+		hookClocks:     e.hookClocks,
 	}
 }
 
@@ -298,8 +616,10 @@ func (e *hookExecutor) saveModuleContexts(ctxs stageModuleContext) {
 
 func (e *hookExecutor) pushStageOutcome(outcome StageOutcome) {
 	e.Lock()
-	defer e.Unlock()
 	e.stageOutcomes = append(e.stageOutcomes, outcome)
+	e.Unlock()
+
+	e.observer.OnStageResult(outcome)
 }
 
 type EmptyHookExecutor struct{}
@@ -310,11 +630,19 @@ func (executor *EmptyHookExecutor) GetOutcomes() []StageOutcome {
 	return []StageOutcome{}
 }
 
-func (executor *EmptyHookExecutor) ExecuteEntrypointStage(_ *http.Request, body []byte) ([]byte, *RejectError) {
+func (executor *EmptyHookExecutor) GetOutcomesSummary() []StageSummary {
+	return []StageSummary{}
+}
+
+func (executor *EmptyHookExecutor) ExecuteEntrypointStage(_ *http.Request, body []byte) ([]byte, *CachedResponse, *RejectError) {
+	return body, nil, nil
+}
+
+func (executor *EmptyHookExecutor) ExecuteEntrypointAccountStage(_ *http.Request, body []byte) ([]byte, *RejectError) {
 	return body, nil
 }
 
-func (executor *EmptyHookExecutor) ExecuteRawAuctionStage(body []byte) ([]byte, *RejectError) {
+func (executor *EmptyHookExecutor) ExecuteRawAuctionStage(_ http.Header, body []byte) ([]byte, *RejectError) {
 	return body, nil
 }
 
@@ -326,11 +654,24 @@ func (executor *EmptyHookExecutor) ExecuteBidderRequestStage(_ *openrtb2.BidRequ
 	return nil
 }
 
+func (executor *EmptyHookExecutor) ExecuteBidderHttpRequestStage(_ []*adapters.RequestData, _ string) (*http.Client, *RejectError) {
+	return nil, nil
+}
+
 func (executor *EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.BidderResponse, _ string) *RejectError {
 	return nil
 }
 
-func (executor *EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+func (executor *EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, _ map[string]openrtb_ext.DealTierBidderMap) *RejectError {
+	return nil
 }
 
 func (executor *EmptyHookExecutor) ExecuteAuctionResponseStage(_ *openrtb2.BidResponse) {}
+
+func (executor *EmptyHookExecutor) GetHttpCalls() map[string][]*openrtb_ext.ExtHttpCall {
+	return nil
+}
+
+func (executor *EmptyHookExecutor) GetNonBids() map[string][]openrtb_ext.NonBid {
+	return nil
+}
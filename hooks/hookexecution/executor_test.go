@@ -2,13 +2,20 @@ package hookexecution
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/openrtb/v17/openrtb3"
 	"github.com/prebid/prebid-server/adapters"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/exchange/entities"
@@ -34,8 +41,9 @@ func TestEmptyHookExecutor(t *testing.T) {
 	bidderRequest := &openrtb2.BidRequest{ID: "some-id"}
 	expectedBidderRequest := &openrtb2.BidRequest{ID: "some-id"}
 
-	entrypointBody, entrypointRejectErr := executor.ExecuteEntrypointStage(req, body)
-	rawAuctionBody, rawAuctionRejectErr := executor.ExecuteRawAuctionStage(body)
+	entrypointBody, entrypointCachedResponse, entrypointRejectErr := executor.ExecuteEntrypointStage(req, body)
+	entrypointAccountBody, entrypointAccountRejectErr := executor.ExecuteEntrypointAccountStage(req, body)
+	rawAuctionBody, rawAuctionRejectErr := executor.ExecuteRawAuctionStage(req.Header, body)
 	processedAuctionRejectErr := executor.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{})
 	bidderRequestRejectErr := executor.ExecuteBidderRequestStage(bidderRequest, "bidder-name")
 	executor.ExecuteAuctionResponseStage(&openrtb2.BidResponse{})
@@ -44,9 +52,13 @@ func TestEmptyHookExecutor(t *testing.T) {
 	assert.Equal(t, EmptyHookExecutor{}, executor, "EmptyHookExecutor shouldn't be changed.")
 	assert.Empty(t, outcomes, "EmptyHookExecutor shouldn't return stage outcomes.")
 
+	assert.Nil(t, entrypointCachedResponse, "EmptyHookExecutor shouldn't return cached response at entrypoint stage.")
 	assert.Nil(t, entrypointRejectErr, "EmptyHookExecutor shouldn't return reject error at entrypoint stage.")
 	assert.Equal(t, body, entrypointBody, "EmptyHookExecutor shouldn't change body at entrypoint stage.")
 
+	assert.Nil(t, entrypointAccountRejectErr, "EmptyHookExecutor shouldn't return reject error at entrypoint account stage.")
+	assert.Equal(t, body, entrypointAccountBody, "EmptyHookExecutor shouldn't change body at entrypoint account stage.")
+
 	assert.Nil(t, rawAuctionRejectErr, "EmptyHookExecutor shouldn't return reject error at raw-auction stage.")
 	assert.Equal(t, body, rawAuctionBody, "EmptyHookExecutor shouldn't change body at raw-auction stage.")
 
@@ -173,7 +185,7 @@ func TestExecuteEntrypointStage(t *testing.T) {
 			expectedBody:           body,
 			expectedHeader:         http.Header{"Foo": []string{"bar"}},
 			expectedQuery:          url.Values{},
-			expectedReject:         &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "bar"}, hooks.StageEntrypoint.String()},
+			expectedReject:         &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "bar"}, Stage: hooks.StageEntrypoint.String()},
 			expectedModuleContexts: foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -215,6 +227,9 @@ func TestExecuteEntrypointStage(t *testing.T) {
 									Action:        ActionReject,
 									Message:       "",
 									DebugMessages: nil,
+									RejectChain: []MutationRecord{
+										{HookID: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: "entrypoint", Key: "header.foo", Type: "update"},
+									},
 									Errors: []string{
 										`Module foobar (hook: bar) rejected request with code 0 at entrypoint stage`,
 									},
@@ -357,10 +372,12 @@ func TestExecuteEntrypointStage(t *testing.T) {
 			req, err := http.NewRequest(http.MethodPost, test.givenUrl, reader)
 			assert.NoError(t, err)
 
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
-			newBody, reject := exec.ExecuteEntrypointStage(req, body)
+			metricEngine := &rejectMetricsEngine{}
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, metricEngine)
+			newBody, _, reject := exec.ExecuteEntrypointStage(req, body)
 
 			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assertRejectedRequestMetrics(t, test.expectedReject, metricEngine)
 			assert.JSONEq(t, test.expectedBody, string(newBody), "Incorrect request body.")
 			assert.Equal(t, test.expectedHeader, req.Header, "Incorrect request header.")
 			assert.Equal(t, test.expectedQuery, req.URL.Query(), "Incorrect request query.")
@@ -376,6 +393,301 @@ func TestExecuteEntrypointStage(t *testing.T) {
 	}
 }
 
+func TestExecuteEntrypointStageTimeout(t *testing.T) {
+	body := []byte(`{"name": "John", "last_name": "Doe"}`)
+	req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", bytes.NewReader(body))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	fakeClock := clock.NewMock()
+	release := make(chan struct{})
+	defer close(release)
+	timeoutHook := mockFakeClockTimeoutHook{clock: fakeClock, delay: 20 * time.Millisecond, release: release}
+
+	exec := NewHookExecutor(TestWithTimeoutPlanBuilder{entrypointHook: timeoutHook}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	// the timing out hook has its own clock, so the hooks of the same group aren't timed out when it's advanced
+	exec.hookClocks = map[HookID]clock.Clock{{ModuleCode: "foobar", HookImplCode: "bar"}: fakeClock}
+	newBody, _, reject := exec.ExecuteEntrypointStage(req, body)
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.JSONEq(t, `{"foo":"bar", "last_name":"Doe"}`, string(newBody), "Request should be changed by hooks not timed out.")
+	assert.Equal(t, http.Header{"Foo": []string{"bar"}}, req.Header, "Incorrect request header.")
+	assert.Equal(t, url.Values{}, req.URL.Query(), "Changes of timed out hook shouldn't be applied.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if !assert.Len(t, stageOutcomes, 1) {
+		return
+	}
+	assertEqualStageOutcomes(t, StageOutcome{
+		Entity: entityHttpRequest,
+		Stage:  hooks.StageEntrypoint.String(),
+		Groups: []GroupOutcome{
+			{
+				InvocationResults: []HookOutcome{
+					{
+						AnalyticsTags: hookanalytics.Analytics{},
+						HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+						Status:        StatusSuccess,
+						Action:        ActionUpdate,
+						DebugMessages: []string{
+							fmt.Sprintf("Hook mutation successfully applied, affected key: header.foo, mutation type: %s", hookstage.MutationUpdate),
+						},
+					},
+					{
+						AnalyticsTags: hookanalytics.Analytics{},
+						HookID:        HookID{ModuleCode: "foobar", HookImplCode: "bar"},
+						Status:        StatusTimeout,
+						Errors:        []string{"Hook execution timeout"},
+					},
+				},
+			},
+			{
+				InvocationResults: []HookOutcome{
+					{
+						AnalyticsTags: hookanalytics.Analytics{},
+						HookID:        HookID{ModuleCode: "foobar", HookImplCode: "baz"},
+						Status:        StatusSuccess,
+						Action:        ActionUpdate,
+						DebugMessages: []string{
+							fmt.Sprintf("Hook mutation successfully applied, affected key: body.foo, mutation type: %s", hookstage.MutationUpdate),
+							fmt.Sprintf("Hook mutation successfully applied, affected key: body.name, mutation type: %s", hookstage.MutationDelete),
+						},
+					},
+				},
+			},
+		},
+	}, stageOutcomes[0])
+}
+
+// rejectMetricsEngine keeps the rejected request metrics, other metrics are ignored.
+type rejectMetricsEngine struct {
+	metricsConfig.NilMetricsEngine
+	rejects []RejectError
+}
+
+func (me *rejectMetricsEngine) RecordRejectedRequest(stage, module string, code int) {
+	me.rejects = append(me.rejects, RejectError{NBR: code, Hook: HookID{ModuleCode: module}, Stage: stage})
+}
+
+func assertRejectedRequestMetrics(t *testing.T, expectedReject *RejectError, metricEngine *rejectMetricsEngine) {
+	if expectedReject == nil {
+		assert.Empty(t, metricEngine.rejects, "Rejected request metric shouldn't be recorded.")
+		return
+	}
+
+	expectedMetric := RejectError{NBR: expectedReject.NBR, Hook: HookID{ModuleCode: expectedReject.Hook.ModuleCode}, Stage: expectedReject.Stage}
+	assert.Equal(t, []RejectError{expectedMetric}, metricEngine.rejects, "Incorrect rejected request metrics.")
+}
+
+func TestExecuteEntrypointAccountStage(t *testing.T) {
+	const body string = `{"name": "John"}`
+
+	req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating http request: %s", err)
+	}
+
+	exec := NewHookExecutor(TestEntrypointAccountPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	entrypointBody, _, reject := exec.ExecuteEntrypointStage(req, []byte(body))
+	assert.Nil(t, reject, "Unexpected entrypoint stage reject.")
+	assert.JSONEq(t, body, string(entrypointBody), "Entrypoint stage shouldn't depend on account.")
+
+	exec.SetAccount(&config.Account{
+		ID:    "account-id",
+		Hooks: config.AccountHooks{Modules: config.AccountModules{"acme": {"foobar": json.RawMessage(`{"enabled": true}`)}}},
+	})
+	newBody, reject := exec.ExecuteEntrypointAccountStage(req, entrypointBody)
+
+	assert.Nil(t, reject, "Unexpected entrypoint account stage reject.")
+	assert.JSONEq(t, `{"name": "John", "module_config": {"enabled": true}}`, string(newBody), "Account-level module config should be passed to hook.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if !assert.Len(t, stageOutcomes, 2) {
+		return
+	}
+	assert.Equal(t, hooks.StageEntrypoint.String(), stageOutcomes[0].Stage)
+	assert.Len(t, stageOutcomes[0].Groups[0].InvocationResults, 2, "All entrypoint hooks should be invoked at entrypoint stage.")
+
+	accountOutcome := stageOutcomes[1]
+	assert.Equal(t, hooks.StageEntrypointAccount.String(), accountOutcome.Stage)
+	assert.Equal(t, entityHttpRequest, accountOutcome.Entity)
+	if !assert.Len(t, accountOutcome.Groups, 1, "Groups without account hooks should be dropped.") {
+		return
+	}
+	invocationResults := accountOutcome.Groups[0].InvocationResults
+	if !assert.Len(t, invocationResults, 1, "Only hooks depending on account should be invoked again.") {
+		return
+	}
+	assert.Equal(t, HookID{ModuleCode: "acme.foobar", HookImplCode: "account"}, invocationResults[0].HookID)
+	assert.Equal(t, ActionUpdate, invocationResults[0].Action)
+}
+
+func TestExecuteEntrypointAccountStageWithoutAccountHooks(t *testing.T) {
+	body := []byte(`{"name": "John"}`)
+	exec := NewHookExecutor(TestApplyHookMutationsBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	exec.SetAccount(&config.Account{ID: "account-id"})
+
+	newBody, reject := exec.ExecuteEntrypointAccountStage(nil, body)
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Equal(t, body, newBody, "Payload shouldn't be changed.")
+	assert.Empty(t, exec.GetOutcomes(), "Stage shouldn't be executed without hooks depending on account.")
+}
+
+func TestExecuteEntrypointStageCachedResponse(t *testing.T) {
+	const body string = `{"name": "John"}`
+
+	testCases := []struct {
+		description      string
+		givenResponse    hookstage.HTTPResponse
+		expectedResponse hookstage.HTTPResponse
+	}{
+		{
+			description:      "Cached response served with provided status",
+			givenResponse:    hookstage.HTTPResponse{StatusCode: http.StatusAccepted, Body: []byte(`{"id":"cached"}`)},
+			expectedResponse: hookstage.HTTPResponse{StatusCode: http.StatusAccepted, Body: []byte(`{"id":"cached"}`)},
+		},
+		{
+			description:      "Status defaults to OK if not provided",
+			givenResponse:    hookstage.HTTPResponse{Body: []byte(`{"id":"cached"}`)},
+			expectedResponse: hookstage.HTTPResponse{StatusCode: http.StatusOK, Body: []byte(`{"id":"cached"}`)},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricEngine := &cacheHitMetricsEngine{}
+			exec := NewHookExecutor(TestCachedResponsePlanBuilder{hook: mockCachedResponseHook{response: test.givenResponse}}, EndpointAuction, metricEngine)
+
+			newBody, cachedResponse, reject := exec.ExecuteEntrypointStage(nil, []byte(body))
+
+			assert.Nil(t, reject, "Cached response shouldn't be reported as a rejection.")
+			expectedCachedResponse := &CachedResponse{
+				Hook:         HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+				Stage:        hooks.StageEntrypoint.String(),
+				HTTPResponse: &test.expectedResponse,
+			}
+			assert.Equal(t, expectedCachedResponse, cachedResponse, "Incorrect cached response.")
+			assert.JSONEq(t, body, string(newBody), "Payload shouldn't be changed.")
+
+			assertRejectedRequestMetrics(t, nil, &metricEngine.rejectMetricsEngine)
+			assert.Equal(t, []metrics.ModuleLabels{{Module: "foobar", Stage: hooks.StageEntrypoint.String()}}, metricEngine.cacheHits)
+
+			stageOutcomes := exec.GetOutcomes()
+			if !assert.Len(t, stageOutcomes, 1) || !assert.Len(t, stageOutcomes[0].Groups, 1, "Groups after the cache hit shouldn't be executed.") {
+				return
+			}
+			hookOutcome := stageOutcomes[0].Groups[0].InvocationResults[0]
+			assert.Equal(t, StatusSuccess, hookOutcome.Status)
+			assert.Equal(t, ActionCacheHit, hookOutcome.Action)
+			assert.Equal(t, test.expectedResponse.StatusCode, hookOutcome.HTTPStatus)
+		})
+	}
+}
+
+func TestExecuteRawAuctionStageCachedResponseIgnored(t *testing.T) {
+	const body string = `{"name": "John"}`
+
+	metricEngine := &cacheHitMetricsEngine{}
+	exec := NewHookExecutor(TestCachedResponsePlanBuilder{hook: mockCachedResponseHook{}}, EndpointAuction, metricEngine)
+	exec.SetAccount(&config.Account{})
+
+	newBody, reject := exec.ExecuteRawAuctionStage(nil, []byte(body))
+
+	assert.Nil(t, reject, "Cached response should be served at entrypoint stage only.")
+	assert.JSONEq(t, body, string(newBody), "Payload shouldn't be changed.")
+	assert.Empty(t, metricEngine.cacheHits, "Cache hit metric shouldn't be recorded.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if assert.Len(t, stageOutcomes, 1) && assert.Len(t, stageOutcomes[0].Groups, 1) {
+		assert.Equal(t, ActionNone, stageOutcomes[0].Groups[0].InvocationResults[0].Action)
+	}
+}
+
+type cacheHitMetricsEngine struct {
+	rejectMetricsEngine
+	cacheHits []metrics.ModuleLabels
+}
+
+func (me *cacheHitMetricsEngine) RecordModuleSuccessCacheHit(labels metrics.ModuleLabels) {
+	me.cacheHits = append(me.cacheHits, labels)
+}
+
+type TestCachedResponsePlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockCachedResponseHook
+}
+
+func (e TestCachedResponsePlanBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
+	return hooks.Plan[hookstage.Entrypoint]{
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "foobar", Code: "bar", Hook: mockUpdateBodyHook{}},
+			},
+		},
+	}
+}
+
+func (e TestCachedResponsePlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func TestResetExecutor(t *testing.T) {
+	const body string = `{"name": "John"}`
+
+	exec := NewHookExecutor(TestApplyHookMutationsBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	execute := func(account *config.Account) {
+		req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error creating http request: %s", err)
+		}
+		req.Header.Set(correlationIDHeader, account.ID)
+
+		_, _, reject := exec.ExecuteEntrypointStage(req, []byte(body))
+		assert.Nil(t, reject, "Unexpected entrypoint stage reject.")
+		exec.SetAccount(account)
+		_, reject = exec.ExecuteRawAuctionStage(nil, []byte(body))
+		assert.Nil(t, reject, "Unexpected raw auction stage reject.")
+	}
+
+	execute(&config.Account{ID: "first-account"})
+	if !assert.Len(t, exec.GetOutcomes(), 2) {
+		return
+	}
+	assert.NotEmpty(t, exec.moduleContexts.ctxs, "Module contexts should be collected.")
+
+	exec.Reset()
+
+	assert.Nil(t, exec.account, "Account should be cleared.")
+	assert.Empty(t, exec.accountID, "Account ID should be cleared.")
+	assert.Empty(t, exec.correlationID, "Correlation ID should be cleared.")
+	assert.Empty(t, exec.GetOutcomes(), "Stage outcomes should be cleared.")
+	assert.Empty(t, exec.moduleContexts.ctxs, "Module contexts should be cleared.")
+	assert.Equal(t, EndpointAuction, exec.endpoint, "Executor config should be kept.")
+
+	execute(&config.Account{ID: "second-account"})
+
+	assert.Len(t, exec.GetOutcomes(), 2, "Only outcomes of the second request expected.")
+	assert.Equal(t, "second-account", exec.accountID)
+	assert.Equal(t, "second-account", exec.correlationID)
+}
+
 func TestMetricsAreGatheredDuringHookExecution(t *testing.T) {
 	reader := bytes.NewReader(nil)
 	req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", reader)
@@ -399,17 +711,68 @@ func TestMetricsAreGatheredDuringHookExecution(t *testing.T) {
 	metricEngine.On("RecordModuleCalled", moduleLabels, mock.MatchedBy(rTime)).Times(hooksCalledDuringStage)
 	metricEngine.On("RecordModuleSuccessUpdated", moduleLabels).Once()
 	metricEngine.On("RecordModuleSuccessRejected", moduleLabels).Once()
+	metricEngine.On("RecordRejectedRequest", "entrypoint", "module-1", 0).Once()
 	metricEngine.On("RecordModuleTimeout", moduleLabels).Once()
 	metricEngine.On("RecordModuleExecutionError", moduleLabels).Twice()
+	metricEngine.On("RecordModuleMutationError", moduleLabels, "code-6").Once()
 	metricEngine.On("RecordModuleFailed", moduleLabels).Once()
-	metricEngine.On("RecordModuleSuccessNooped", moduleLabels).Once()
+	metricEngine.On("RecordModuleSuccessNooped", moduleLabels, "code-7").Once()
 
-	_, _ = exec.ExecuteEntrypointStage(req, nil)
+	_, _, _ = exec.ExecuteEntrypointStage(req, nil)
 
 	// Assert that all module metrics funcs were called with the parameters we expected
 	metricEngine.AssertExpectations(t)
 }
 
+func TestExecuteEntrypointStageMaxBodyBytes(t *testing.T) {
+	body := []byte(`{"name": "John", "last_name": "Doe"}`)
+	testCases := []struct {
+		description      string
+		givenMaxBodySize int64
+		expectedReject   *RejectError
+	}{
+		{
+			description:      "Request rejected before hook execution if body exceeds limit",
+			givenMaxBodySize: int64(len(body)) - 1,
+			expectedReject:   &RejectError{NBR: int(openrtb3.NoBidInvalidRequest), Stage: hooks.StageEntrypoint.String()},
+		},
+		{
+			description:      "Request not rejected if body size equals limit",
+			givenMaxBodySize: int64(len(body)),
+			expectedReject:   nil,
+		},
+		{
+			description:      "Request not rejected if limit not set",
+			givenMaxBodySize: 0,
+			expectedReject:   nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", bytes.NewReader(body))
+			assert.NoError(t, err)
+
+			// metrics engine mock fails on any unexpected call, e.g. if hooks were executed for rejected request
+			metricEngine := &metrics.MetricsEngineMock{}
+			var planBuilder hooks.ExecutionPlanBuilder = hooks.EmptyPlanBuilder{}
+			if test.expectedReject != nil {
+				metricEngine.On("RecordRequestBodySizeExceeded").Once()
+				planBuilder = TestAllHookResultsBuilder{}
+			}
+
+			exec := NewHookExecutor(planBuilder, EndpointAuction, metricEngine)
+			exec.SetMaxBodyBytes(test.givenMaxBodySize)
+			newBody, _, reject := exec.ExecuteEntrypointStage(req, body)
+
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assert.Equal(t, body, newBody, "Incorrect request body.")
+			assert.Empty(t, exec.GetOutcomes(), "Hooks should not be executed.")
+			metricEngine.AssertExpectations(t)
+		})
+	}
+}
+
 func TestExecuteRawAuctionStage(t *testing.T) {
 	const body string = `{"name": "John", "last_name": "Doe"}`
 	const bodyUpdated string = `{"last_name": "Doe", "foo": "bar"}`
@@ -506,7 +869,7 @@ func TestExecuteRawAuctionStage(t *testing.T) {
 			givenPlanBuilder:       TestRejectPlanBuilder{},
 			givenAccount:           nil,
 			expectedBody:           bodyUpdated,
-			expectedReject:         &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "bar"}, hooks.StageRawAuctionRequest.String()},
+			expectedReject:         &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "bar"}, Stage: hooks.StageRawAuctionRequest.String()},
 			expectedModuleContexts: foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -549,6 +912,10 @@ func TestExecuteRawAuctionStage(t *testing.T) {
 									Action:        ActionReject,
 									Message:       "",
 									DebugMessages: nil,
+									RejectChain: []MutationRecord{
+										{HookID: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: "raw_auction_request", Key: "body.foo", Type: "update"},
+										{HookID: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: "raw_auction_request", Key: "body.name", Type: "delete"},
+									},
 									Errors: []string{
 										`Module foobar (hook: bar) rejected request with code 0 at raw_auction_request stage`,
 									},
@@ -672,12 +1039,14 @@ func TestExecuteRawAuctionStage(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.description, func(t *testing.T) {
-			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			metricEngine := &rejectMetricsEngine{}
+			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, metricEngine)
 			exec.SetAccount(test.givenAccount)
 
-			newBody, reject := exec.ExecuteRawAuctionStage([]byte(test.givenBody))
+			newBody, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(test.givenBody))
 
 			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
+			assertRejectedRequestMetrics(t, test.expectedReject, metricEngine)
 			assert.JSONEq(t, test.expectedBody, string(newBody), "Incorrect request body.")
 			assert.Equal(t, test.expectedModuleContexts, exec.moduleContexts, "Incorrect module contexts")
 
@@ -757,7 +1126,7 @@ func TestExecuteProcessedAuctionStage(t *testing.T) {
 			givenAccount:           nil,
 			givenRequest:           req,
 			expectedRequest:        req,
-			expectedReject:         &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageProcessedAuctionRequest.String()},
+			expectedReject:         &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: hooks.StageProcessedAuctionRequest.String()},
 			expectedModuleContexts: foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -1012,7 +1381,7 @@ func TestExecuteBidderRequestStage(t *testing.T) {
 			givenPlanBuilder:       TestRejectPlanBuilder{},
 			givenAccount:           nil,
 			expectedBidderRequest:  expectedBidderRequest,
-			expectedReject:         &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageBidderRequest.String()},
+			expectedReject:         &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: hooks.StageBidderRequest.String()},
 			expectedModuleContexts: foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -1252,7 +1621,7 @@ func TestExecuteRawBidderResponseStage(t *testing.T) {
 			givenAccount:           nil,
 			givenBidderResponse:    resp,
 			expectedBidderResponse: resp,
-			expectedReject:         &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageRawBidderResponse.String()},
+			expectedReject:         &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: hooks.StageRawBidderResponse.String()},
 			expectedModuleContexts: foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -1407,6 +1776,118 @@ func TestExecuteRawBidderResponseStage(t *testing.T) {
 	}
 }
 
+func TestExecuteRawBidderResponseStageInjectBids(t *testing.T) {
+	bidderBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bidder-bid", ImpID: "imp", Price: 1}}
+	houseBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "house-bid", ImpID: "imp", Price: 0.5}, BidType: openrtb_ext.BidTypeBanner}
+	noPriceBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "no-price-bid", ImpID: "imp"}}
+	response := &adapters.BidderResponse{Bids: []*adapters.TypedBid{bidderBid}}
+
+	builder := TestInjectBidsPlanBuilder{hook: mockInjectBidsHook{bids: []*adapters.TypedBid{houseBid, noPriceBid}}}
+	exec := NewHookExecutor(builder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	reject := exec.ExecuteRawBidderResponseStage(response, "the-bidder")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Equal(t, []*adapters.TypedBid{bidderBid, houseBid}, response.Bids, "Synthetic bid not injected.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if assert.Len(t, stageOutcomes, 1, "Stage outcome expected.") {
+		assertEqualStageOutcomes(t, StageOutcome{
+			Entity: entity("the-bidder"),
+			Stage:  hooks.StageRawBidderResponse.String(),
+			Groups: []GroupOutcome{
+				{
+					InvocationResults: []HookOutcome{
+						{
+							AnalyticsTags: hookanalytics.Analytics{},
+							HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+							Status:        StatusSuccess,
+							Action:        ActionInjectBids,
+							DebugMessages: []string{
+								fmt.Sprintf("Hook mutation successfully applied, affected key: bidderresponse.bids, mutation type: %s", hookstage.MutationInjectBid),
+							},
+							Warnings: []string{"failed to apply hook mutation: synthetic bid must have a positive price"},
+						},
+					},
+				},
+			},
+		}, stageOutcomes[0])
+	}
+}
+
+type TestInjectBidsPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockInjectBidsHook
+}
+
+func (e TestInjectBidsPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func TestExecuteRawBidderResponseStageUpdateAdM(t *testing.T) {
+	bid1 := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp", Price: 1, AdM: "<div>ad</div>"}}
+	bid2 := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp", Price: 1}}
+	response := &adapters.BidderResponse{Bids: []*adapters.TypedBid{bid1, bid2}}
+
+	builder := TestUpdateAdMPlanBuilder{hook: mockUpdateAdMHook{missingBidIDs: []string{"bid-3"}}}
+	exec := NewHookExecutor(builder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	reject := exec.ExecuteRawBidderResponseStage(response, "the-bidder")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	if assert.Len(t, response.Bids, 2) {
+		assert.Same(t, bid1, response.Bids[0], "Bid should be updated in place.")
+		assert.Equal(t, `<div>ad</div><script src="viewability.js"></script>`, response.Bids[0].Bid.AdM)
+		assert.Equal(t, `<script src="viewability.js"></script>`, response.Bids[1].Bid.AdM)
+	}
+
+	stageOutcomes := exec.GetOutcomes()
+	if assert.Len(t, stageOutcomes, 1, "Stage outcome expected.") {
+		assertEqualStageOutcomes(t, StageOutcome{
+			Entity: entity("the-bidder"),
+			Stage:  hooks.StageRawBidderResponse.String(),
+			Groups: []GroupOutcome{
+				{
+					InvocationResults: []HookOutcome{
+						{
+							AnalyticsTags: hookanalytics.Analytics{},
+							HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+							Status:        StatusSuccess,
+							Action:        ActionUpdate,
+							DebugMessages: []string{
+								fmt.Sprintf("Hook mutation successfully applied, affected key: bidderresponse.bid.bid-1.adm, mutation type: %s", hookstage.MutationUpdateAdM),
+								fmt.Sprintf("Hook mutation successfully applied, affected key: bidderresponse.bid.bid-2.adm, mutation type: %s", hookstage.MutationUpdateAdM),
+							},
+							Warnings: []string{"failed to apply hook mutation: bid bid-3 not found"},
+						},
+					},
+				},
+			},
+		}, stageOutcomes[0])
+	}
+}
+
+type TestUpdateAdMPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockUpdateAdMHook
+}
+
+func (e TestUpdateAdMPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
 func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 	foobarModuleCtx := &moduleContexts{ctxs: map[string]hookstage.ModuleContext{"foobar": nil}}
 	account := &config.Account{}
@@ -1500,14 +1981,14 @@ func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 			},
 		},
 		{
-			description: "Stage execution can't be rejected - stage doesn't support rejection",
+			description: "Stage execution can be rejected",
 			givenBiddersResponse: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
 				"some-bidder": {Bids: []*entities.PbsOrtbBid{{DealPriority: 1}}},
 			},
 			givenPlanBuilder:        TestRejectPlanBuilder{},
 			givenAccount:            nil,
-			expectedBiddersResponse: expectedUpdatedAllProcBidResponses,
-			expectedReject:          &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageAllProcessedBidResponses.String()},
+			expectedBiddersResponse: expectedAllProcBidResponses,
+			expectedReject:          &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: hooks.StageAllProcessedBidResponses.String()},
 			expectedModuleContexts:  foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -1533,29 +2014,13 @@ func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 								{
 									AnalyticsTags: hookanalytics.Analytics{},
 									HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
-									Status:        StatusExecutionFailure,
-									Action:        "",
+									Status:        StatusSuccess,
+									Action:        ActionReject,
 									Message:       "",
 									DebugMessages: nil,
 									Errors: []string{
-										fmt.Sprintf("Module (name: foobar, hook code: foo) tried to reject request on the %s stage that does not support rejection", hooks.StageAllProcessedBidResponses),
-									},
-									Warnings: nil,
-								},
-							},
-						},
-						{
-							InvocationResults: []HookOutcome{
-								{
-									AnalyticsTags: hookanalytics.Analytics{},
-									HookID:        HookID{ModuleCode: "foobar", HookImplCode: "bar"},
-									Status:        StatusSuccess,
-									Action:        ActionUpdate,
-									Message:       "",
-									DebugMessages: []string{
-										fmt.Sprintf("Hook mutation successfully applied, affected key: processedBidderResponse.bid.deal-priority, mutation type: %s", hookstage.MutationUpdate),
+										`Module foobar (hook: foo) rejected request with code 0 at all_processed_bid_responses stage`,
 									},
-									Errors:   nil,
 									Warnings: nil,
 								},
 							},
@@ -1670,8 +2135,9 @@ func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 			exec := NewHookExecutor(test.givenPlanBuilder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
 			exec.SetAccount(test.givenAccount)
 
-			exec.ExecuteAllProcessedBidResponsesStage(test.givenBiddersResponse)
+			reject := exec.ExecuteAllProcessedBidResponsesStage(test.givenBiddersResponse, nil)
 
+			assert.Equal(t, test.expectedReject, reject, "Unexpected stage reject.")
 			assert.Equal(t, test.expectedBiddersResponse, test.givenBiddersResponse, "Incorrect bidders response.")
 			assert.Equal(t, test.expectedModuleContexts, exec.moduleContexts, "Incorrect module contexts")
 
@@ -1750,7 +2216,7 @@ func TestExecuteAuctionResponseStage(t *testing.T) {
 			givenAccount:           nil,
 			givenResponse:          resp,
 			expectedResponse:       expResp,
-			expectedReject:         &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageAuctionResponse.String()},
+			expectedReject:         &RejectError{NBR: 0, Hook: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: hooks.StageAuctionResponse.String()},
 			expectedModuleContexts: foobarModuleCtx,
 			expectedStageOutcomes: []StageOutcome{
 				{
@@ -1942,7 +2408,7 @@ func TestInterStageContextCommunication(t *testing.T) {
 	assert.NoError(t, err)
 
 	// test that context added at the entrypoint stage
-	_, reject := exec.ExecuteEntrypointStage(req, body)
+	_, _, reject := exec.ExecuteEntrypointStage(req, body)
 	assert.Nil(t, reject, "Unexpected reject from entrypoint stage.")
 	assert.Equal(
 		t,
@@ -1958,7 +2424,7 @@ func TestInterStageContextCommunication(t *testing.T) {
 	)
 
 	// test that context added at the raw-auction stage merged with existing module contexts
-	_, reject = exec.ExecuteRawAuctionStage(body)
+	_, reject = exec.ExecuteRawAuctionStage(req.Header, body)
 	assert.Nil(t, reject, "Unexpected reject from raw-auction stage.")
 	assert.Equal(t, &moduleContexts{ctxs: map[string]hookstage.ModuleContext{
 		"module-1": {
@@ -2040,36 +2506,1059 @@ func TestInterStageContextCommunication(t *testing.T) {
 	}}, exec.moduleContexts, "Wrong module contexts after executing auction-response hook.")
 }
 
-type TestApplyHookMutationsBuilder struct {
-	hooks.EmptyPlanBuilder
+func TestStageResultObserver(t *testing.T) {
+	observer := &mockStageResultObserver{}
+	exec := NewHookExecutor(TestApplyHookMutationsBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	exec.SetStageResultObserver(observer)
+
+	exec.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{User: &openrtb2.User{}})
+	exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{User: &openrtb2.User{}}, "appnexus")
+
+	assert.Equal(t, exec.GetOutcomes(), observer.outcomes, "Observer should receive outcome of each executed stage.")
+
+	exec.SetStageResultObserver(nil)
+	assert.Equal(t, NoopStageResultObserver{}, exec.observer, "Nil observer should be replaced with no-op observer.")
 }
 
-func (e TestApplyHookMutationsBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
-	return hooks.Plan[hookstage.Entrypoint]{
-		hooks.Group[hookstage.Entrypoint]{
-			Timeout: 10 * time.Millisecond,
-			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
-				{Module: "foobar", Code: "foo", Hook: mockUpdateHeaderEntrypointHook{}},
-				{Module: "foobar", Code: "foobaz", Hook: mockFailedMutationHook{}},
-				{Module: "foobar", Code: "bar", Hook: mockUpdateQueryEntrypointHook{}},
-			},
-		},
-		hooks.Group[hookstage.Entrypoint]{
-			Timeout: 10 * time.Millisecond,
-			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
-				{Module: "foobar", Code: "baz", Hook: mockUpdateBodyHook{}},
-				{Module: "foobar", Code: "foo", Hook: mockFailureHook{}},
-			},
-		},
+func TestExecuteBidderRequestStageImpCount(t *testing.T) {
+	hook := &mockImpCountBidderRequestHook{}
+	exec := NewHookExecutor(TestImpCountPlanBuilder{hook: hook}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}}, "appnexus")
+
+	assert.Equal(t, 2, hook.impCount, "Payload should hold the number of bidder request impressions.")
+}
+
+func TestExecuteBidderRequestStageConcurrentImpFloors(t *testing.T) {
+	floors := map[string]float64{"appnexus": 1, "rubicon": 2, "openx": 3, "pubmatic": 4}
+	exec := NewHookExecutor(TestImpFloorPlanBuilder{hook: mockImpFloorHook{floors: floors}}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	baseRequest := openrtb2.BidRequest{ID: "some-id", Imp: []openrtb2.Imp{{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"}}}
+
+	// each bidder gets its own copy of the impressions, as done by the exchange when splitting the request
+	requests := make(map[string]*openrtb2.BidRequest, len(floors))
+	for bidder := range floors {
+		request := baseRequest
+		request.Imp = append([]openrtb2.Imp(nil), baseRequest.Imp...)
+		requests[bidder] = &request
+	}
+
+	var wg sync.WaitGroup
+	for bidder, request := range requests {
+		wg.Add(1)
+		go func(bidder string, request *openrtb2.BidRequest) {
+			defer wg.Done()
+			reject := exec.ExecuteBidderRequestStage(request, bidder)
+			assert.Nil(t, reject, "Unexpected stage reject.")
+		}(bidder, request)
+	}
+	wg.Wait()
+
+	for bidder, request := range requests {
+		assert.Equal(t, floors[bidder], request.Imp[0].BidFloor, "Incorrect floor of %s request.", bidder)
+		assert.Equal(t, "EUR", request.Imp[0].BidFloorCur, "Incorrect floor currency of %s request.", bidder)
+	}
+	assert.Equal(t, openrtb2.Imp{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"}, baseRequest.Imp[0], "Base request shouldn't be modified.")
+
+	stageOutcomes := exec.GetOutcomes()
+	assert.Len(t, stageOutcomes, len(floors), "Outcome expected for each bidder.")
+	for _, outcome := range stageOutcomes {
+		assert.Equal(t, []string{
+			"Hook mutation successfully applied, affected key: bidrequest.imp.imp1.bidfloor, mutation type: update",
+		}, outcome.Groups[0].InvocationResults[0].DebugMessages, "Floor update should be recorded in debug.")
 	}
 }
 
-func (e TestApplyHookMutationsBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
-	return hooks.Plan[hookstage.RawAuctionRequest]{
-		hooks.Group[hookstage.RawAuctionRequest]{
-			Timeout: 10 * time.Millisecond,
-			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
-				{Module: "foobar", Code: "foo", Hook: mockUpdateBodyHook{}},
+func TestModuleContextMergedAcrossStages(t *testing.T) {
+	hook := &mockMergedContextHook{}
+	exec := NewHookExecutor(TestMergedContextPlanBuilder{hook: hook}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating http request: %s", err)
+	}
+
+	exec.ExecuteEntrypointStage(req, []byte(`{}`))
+
+	var wg sync.WaitGroup
+	for _, bidder := range []string{"appnexus", "rubicon", "openx"} {
+		wg.Add(1)
+		go func(bidder string) {
+			defer wg.Done()
+			exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{}, bidder)
+		}(bidder)
+	}
+	wg.Wait()
+
+	exec.ExecuteAuctionResponseStage(&openrtb2.BidResponse{})
+
+	assert.Equal(t, hookstage.ModuleContext{
+		"stage": "bidder_request",
+		"visits": hookstage.MergedContext{
+			"entrypoint": true,
+			"bidders":    hookstage.MergedContext{"appnexus": true, "rubicon": true, "openx": true},
+		},
+	}, hook.receivedCtx, "Context of earlier stages should be passed to the hook.")
+
+	moduleCtx, _ := exec.moduleContexts.get("foobar")
+	assert.Equal(t, hookstage.ModuleContext{
+		"stage": "auction_response",
+		"visits": hookstage.MergedContext{
+			"entrypoint":       true,
+			"bidders":          hookstage.MergedContext{"appnexus": true, "rubicon": true, "openx": true},
+			"auction_response": true,
+		},
+	}, moduleCtx, "Plain values should be overwritten, merged values should be accumulated.")
+}
+
+func TestMergeModuleContexts(t *testing.T) {
+	testCases := []struct {
+		description string
+		givenDst    hookstage.ModuleContext
+		givenSrc    hookstage.ModuleContext
+		expectedCtx hookstage.ModuleContext
+	}{
+		{
+			description: "Source returned if nothing saved",
+			givenDst:    nil,
+			givenSrc:    hookstage.ModuleContext{"a": 1},
+			expectedCtx: hookstage.ModuleContext{"a": 1},
+		},
+		{
+			description: "Saved keys preserved if nothing returned",
+			givenDst:    hookstage.ModuleContext{"a": 1},
+			givenSrc:    nil,
+			expectedCtx: hookstage.ModuleContext{"a": 1},
+		},
+		{
+			description: "Plain values overwritten",
+			givenDst:    hookstage.ModuleContext{"a": 1, "b": hookstage.MergedContext{"c": 1}},
+			givenSrc:    hookstage.ModuleContext{"a": 2, "b": map[string]interface{}{"d": 1}},
+			expectedCtx: hookstage.ModuleContext{"a": 2, "b": map[string]interface{}{"d": 1}},
+		},
+		{
+			description: "Merged values merged recursively",
+			givenDst:    hookstage.ModuleContext{"a": hookstage.MergedContext{"b": 1, "c": hookstage.MergedContext{"d": 1}}},
+			givenSrc:    hookstage.ModuleContext{"a": hookstage.MergedContext{"b": 2, "c": hookstage.MergedContext{"e": 1}}},
+			expectedCtx: hookstage.ModuleContext{"a": hookstage.MergedContext{"b": 2, "c": hookstage.MergedContext{"d": 1, "e": 1}}},
+		},
+		{
+			description: "Merged value overwrites plain value",
+			givenDst:    hookstage.ModuleContext{"a": 1},
+			givenSrc:    hookstage.ModuleContext{"a": hookstage.MergedContext{"b": 1}},
+			expectedCtx: hookstage.ModuleContext{"a": hookstage.MergedContext{"b": 1}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var dstCopy hookstage.ModuleContext
+			if test.givenDst != nil {
+				dstCopy = mergeModuleContexts(hookstage.ModuleContext{}, test.givenDst)
+			}
+
+			merged := mergeModuleContexts(test.givenDst, test.givenSrc)
+
+			assert.Equal(t, test.expectedCtx, merged)
+			assert.Equal(t, dstCopy, test.givenDst, "Saved context shouldn't be modified.")
+		})
+	}
+}
+
+func TestExecuteBidderHttpRequestStage(t *testing.T) {
+	requests := []*adapters.RequestData{
+		{Uri: "https://bidder.com/bid?id=1", Headers: http.Header{}},
+		{Uri: "https://bidder.com/video"},
+	}
+	exec := NewHookExecutor(TestUriRewritePlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	client, reject := exec.ExecuteBidderHttpRequestStage(requests, "appnexus")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Nil(t, client, "Adapter client should be used if not provided by hooks.")
+	assert.Equal(t, "https://eu.proxy.com/bid?id=1", requests[0].Uri, "URI should be rewritten.")
+	assert.Equal(t, "eu", requests[0].Headers.Get("X-Region"), "Header should be set.")
+	assert.Equal(t, "https://bidder.com/video", requests[1].Uri, "Other requests should be left unchanged.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if assert.Len(t, stageOutcomes, 1) {
+		assert.Equal(t, hooks.StageBidderHttpRequest.String(), stageOutcomes[0].Stage)
+		assert.Equal(t, entity("appnexus"), stageOutcomes[0].Entity)
+		assert.Equal(t, []string{
+			"Hook mutation successfully applied, affected key: httprequest.0.uri, mutation type: update",
+			"Hook mutation successfully applied, affected key: httprequest.0.header.X-Region, mutation type: update",
+		}, stageOutcomes[0].Groups[0].InvocationResults[0].DebugMessages, "Rewrite should be recorded in debug.")
+	}
+}
+
+func TestExecutionPlanSelectedByEndpoint(t *testing.T) {
+	stages := []hooks.Stage{
+		hooks.StageEntrypoint,
+		hooks.StageRawAuctionRequest,
+		hooks.StageProcessedAuctionRequest,
+		hooks.StageBidderRequest,
+		hooks.StageBidderHttpRequest,
+		hooks.StageRawBidderResponse,
+		hooks.StageAllProcessedBidResponses,
+		hooks.StageAuctionResponse,
+	}
+	endpointStages := func(module string) string {
+		stagesJSON := make([]string, 0, len(stages))
+		for _, stage := range stages {
+			stagesJSON = append(stagesJSON, fmt.Sprintf(`"%s": {"groups": [{"timeout": 100, "hook_sequence": [{"module_code": "%s", "hook_impl_code": "code"}]}]}`, stage, module))
+		}
+		return `{"stages": {` + strings.Join(stagesJSON, ",") + `}}`
+	}
+
+	cfg := config.Hooks{Enabled: true}
+	planJSON := fmt.Sprintf(`{"endpoints": {"%s": %s, "%s": %s}}`, EndpointAuction, endpointStages("acme.auction"), EndpointAmp, endpointStages("acme.amp"))
+	if err := json.Unmarshal([]byte(planJSON), &cfg.HostExecutionPlan); err != nil {
+		t.Fatalf("Failed to unmarshal execution plan: %s", err)
+	}
+	repo, err := hooks.NewHookRepository(map[string]interface{}{
+		"acme.auction": mockEndpointHook{},
+		"acme.amp":     mockEndpointHook{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create hook repository: %s", err)
+	}
+	planBuilder := hooks.NewExecutionPlanBuilder(cfg, repo)
+
+	testCases := []struct {
+		description    string
+		givenEndpoint  string
+		expectedModule string
+	}{
+		{
+			description:    "Auction plan used for auction endpoint",
+			givenEndpoint:  EndpointAuction,
+			expectedModule: "acme.auction",
+		},
+		{
+			description:    "AMP plan used for AMP endpoint",
+			givenEndpoint:  EndpointAmp,
+			expectedModule: "acme.amp",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(planBuilder, test.givenEndpoint, &metricsConfig.NilMetricsEngine{})
+			exec.SetAccount(&config.Account{ID: "account-id"})
+
+			exec.ExecuteEntrypointStage(&http.Request{URL: &url.URL{}}, []byte(`{}`))
+			exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{}`))
+			exec.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{})
+			exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{}, "appnexus")
+			exec.ExecuteBidderHttpRequestStage([]*adapters.RequestData{{Headers: http.Header{}}}, "appnexus")
+			exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "appnexus")
+			exec.ExecuteAllProcessedBidResponsesStage(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{}, nil)
+			exec.ExecuteAuctionResponseStage(&openrtb2.BidResponse{})
+
+			stageOutcomes := exec.GetOutcomes()
+			if !assert.Len(t, stageOutcomes, len(stages)) {
+				return
+			}
+			for i, outcome := range stageOutcomes {
+				assert.Equal(t, stages[i].String(), outcome.Stage, "Incorrect stage order.")
+				for _, group := range outcome.Groups {
+					for _, result := range group.InvocationResults {
+						assert.Equal(t, test.expectedModule, result.HookID.ModuleCode, "Incorrect module invoked at %s stage.", outcome.Stage)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteBidderHttpRequestStageWithoutHooks(t *testing.T) {
+	requests := []*adapters.RequestData{{Uri: "https://bidder.com/bid"}}
+	exec := NewHookExecutor(hooks.EmptyPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	client, reject := exec.ExecuteBidderHttpRequestStage(requests, "appnexus")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Nil(t, client)
+	assert.Equal(t, "https://bidder.com/bid", requests[0].Uri)
+	assert.Empty(t, exec.GetOutcomes(), "No stage outcome expected without hooks.")
+}
+
+func TestExecuteBidderHttpRequestStageClientOverride(t *testing.T) {
+	client := &http.Client{}
+	exec := NewHookExecutor(TestClientOverridePlanBuilder{hook: mockClientOverrideHook{client: client}}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	overrideClient, reject := exec.ExecuteBidderHttpRequestStage([]*adapters.RequestData{{Uri: "https://bidder.com/bid"}}, "appnexus")
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Same(t, client, overrideClient, "Client provided by hook should be returned.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if assert.Len(t, stageOutcomes, 1) {
+		assert.Equal(t, []string{
+			"Hook mutation successfully applied, affected key: httprequest.client, mutation type: update",
+		}, stageOutcomes[0].Groups[0].InvocationResults[0].DebugMessages, "Client override should be recorded in debug.")
+	}
+}
+
+func TestExecuteAllProcessedBidResponsesStageDealTierOverride(t *testing.T) {
+	responses := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "bid1", ImpID: "imp1"}, DealPriority: 2, DealTierSatisfied: true},
+			{Bid: &openrtb2.Bid{ID: "bid2", ImpID: "imp1"}, DealPriority: 4},
+		}},
+	}
+	dealTiers := map[string]openrtb_ext.DealTierBidderMap{
+		"imp1": {"appnexus": {Prefix: "tier", MinDealTier: 5}},
+	}
+	exec := NewHookExecutor(TestDealTierOverridePlanBuilder{hook: mockDealTierOverrideHook{}}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	reject := exec.ExecuteAllProcessedBidResponsesStage(responses, dealTiers)
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	bids := responses["appnexus"].Bids
+	if assert.NotNil(t, bids[0].DealTierOverride) {
+		assert.False(t, *bids[0].DealTierOverride, "Hook should flip deal tier of bid below its threshold.")
+	}
+	if assert.NotNil(t, bids[1].DealTierOverride) {
+		assert.True(t, *bids[1].DealTierOverride, "Hook should flip deal tier of bid above its threshold.")
+	}
+}
+
+func TestHookContextValues(t *testing.T) {
+	hook := &mockContextValuesBidderRequestHook{}
+	exec := NewHookExecutor(TestContextValuesPlanBuilder{hook: hook}, EndpointAmp, &metricsConfig.NilMetricsEngine{})
+	exec.SetAccount(&config.Account{ID: "account-id"})
+
+	exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{}, "appnexus")
+
+	assert.Equal(t, "account-id", hook.accountID, "Incorrect account ID in hook context.")
+	assert.Equal(t, EndpointAmp, hook.endpoint, "Incorrect endpoint in hook context.")
+}
+
+func TestHookContextCorrelationID(t *testing.T) {
+	hook := &mockContextValuesBidderRequestHook{}
+	exec := NewHookExecutor(TestContextValuesPlanBuilder{hook: hook}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating http request: %s", err)
+	}
+	req.Header.Set("X-Request-Id", "request-id")
+
+	exec.ExecuteEntrypointStage(req, []byte(`{}`))
+	exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{}, "appnexus")
+
+	assert.Equal(t, "request-id", hook.correlationID, "Correlation ID should be taken from the request header.")
+}
+
+func TestHookContextValuesAbsent(t *testing.T) {
+	accountID, ok := AccountIDFromContext(executionContext{endpoint: EndpointAuction}.hookContext(context.Background()))
+
+	assert.False(t, ok, "Account ID should be absent if account not resolved.")
+	assert.Empty(t, accountID)
+}
+
+type TestContextValuesPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook *mockContextValuesBidderRequestHook
+}
+
+func (e TestContextValuesPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func TestAnalyticsTagsNamespacedByModule(t *testing.T) {
+	exec := NewHookExecutor(TestAnalyticsTagsPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	exec.ExecuteBidderRequestStage(&openrtb2.BidRequest{}, "appnexus")
+
+	outcomes := exec.GetOutcomes()
+	if !assert.Len(t, outcomes, 1) || !assert.Len(t, outcomes[0].Groups, 1) {
+		return
+	}
+	activityNames := make(map[string][]string)
+	for _, hookOutcome := range outcomes[0].Groups[0].InvocationResults {
+		for _, activity := range hookOutcome.AnalyticsTags.Activities {
+			activityNames[hookOutcome.HookID.ModuleCode] = append(activityNames[hookOutcome.HookID.ModuleCode], activity.Name)
+		}
+	}
+	assert.Equal(t, map[string][]string{"foobar": {"foobar.enrich"}, "foobaz": {"foobaz.enrich"}}, activityNames)
+}
+
+type TestAnalyticsTagsPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestAnalyticsTagsPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockAnalyticsTagsHook{}},
+				{Module: "foobaz", Code: "foo", Hook: mockAnalyticsTagsHook{}},
+			},
+		},
+	}
+}
+
+func TestExecuteEntrypointStageAmpParams(t *testing.T) {
+	const ampUrl string = "https://prebid.com/openrtb2/amp?tag_id=tag&curl=https%3A%2F%2Fexample.com&w=300"
+
+	testCases := []struct {
+		description       string
+		givenEndpoint     string
+		expectedIsAmp     bool
+		expectedAmpParams map[string]string
+		expectedQuery     url.Values
+	}{
+		{
+			description:       "AMP params provided to hooks and rewritten in request query for AMP endpoint",
+			givenEndpoint:     EndpointAmp,
+			expectedIsAmp:     true,
+			expectedAmpParams: map[string]string{"tag_id": "tag", "curl": "https://example.com", "w": "300"},
+			expectedQuery:     url.Values{"tag_id": []string{"rewritten-tag"}, "w": []string{"300"}},
+		},
+		{
+			description:       "AMP params not provided for auction endpoint",
+			givenEndpoint:     EndpointAuction,
+			expectedIsAmp:     false,
+			expectedAmpParams: map[string]string{},
+			expectedQuery:     url.Values{"tag_id": []string{"tag"}, "curl": []string{"https://example.com"}, "w": []string{"300"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ampUrl, nil)
+			assert.NoError(t, err)
+
+			hook := &mockAmpParamsEntrypointHook{}
+			exec := NewHookExecutor(TestAmpParamsPlanBuilder{hook: hook}, test.givenEndpoint, &metricsConfig.NilMetricsEngine{})
+			_, _, reject := exec.ExecuteEntrypointStage(req, nil)
+
+			assert.Nil(t, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedIsAmp, hook.isAmp, "Incorrect AMP flag.")
+			assert.Equal(t, test.expectedAmpParams, hook.ampParams, "Incorrect AMP params.")
+			assert.Equal(t, test.expectedQuery, req.URL.Query(), "Incorrect request query.")
+		})
+	}
+}
+
+func TestExecuteEntrypointAccountStageAmpParams(t *testing.T) {
+	const ampUrl string = "https://prebid.com/openrtb2/amp?tag_id=tag&curl=https%3A%2F%2Fexample.com&w=300"
+
+	testCases := []struct {
+		description       string
+		givenEndpoint     string
+		expectedIsAmp     bool
+		expectedAmpParams map[string]string
+		expectedQuery     url.Values
+	}{
+		{
+			description:       "AMP params provided to account hooks and rewritten in request query for AMP endpoint",
+			givenEndpoint:     EndpointAmp,
+			expectedIsAmp:     true,
+			expectedAmpParams: map[string]string{"tag_id": "tag", "curl": "https://example.com", "w": "300"},
+			expectedQuery:     url.Values{"tag_id": []string{"rewritten-tag"}, "w": []string{"300"}},
+		},
+		{
+			description:       "AMP params not provided to account hooks for auction endpoint",
+			givenEndpoint:     EndpointAuction,
+			expectedIsAmp:     false,
+			expectedAmpParams: map[string]string{},
+			expectedQuery:     url.Values{"tag_id": []string{"tag"}, "curl": []string{"https://example.com"}, "w": []string{"300"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ampUrl, nil)
+			assert.NoError(t, err)
+
+			hook := &mockAmpParamsEntrypointHook{}
+			exec := NewHookExecutor(TestAmpParamsPlanBuilder{hook: hook}, test.givenEndpoint, &metricsConfig.NilMetricsEngine{})
+			_, reject := exec.ExecuteEntrypointAccountStage(req, nil)
+
+			assert.Nil(t, reject, "Unexpected stage reject.")
+			assert.Equal(t, test.expectedIsAmp, hook.isAmp, "Incorrect AMP flag.")
+			assert.Equal(t, test.expectedAmpParams, hook.ampParams, "Incorrect AMP params.")
+			assert.Equal(t, test.expectedQuery, req.URL.Query(), "Incorrect request query.")
+		})
+	}
+}
+
+type TestAmpParamsPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook *mockAmpParamsEntrypointHook
+}
+
+func (e TestAmpParamsPlanBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
+	return hooks.Plan[hookstage.Entrypoint]{
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func TestExecuteRawAuctionStageHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("User-Agent", "some-agent")
+
+	exec := NewHookExecutor(TestRawAuctionHeaderPlanBuilder{hook: mockRawAuctionHeaderHook{header: "User-Agent"}}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	body, reject := exec.ExecuteRawAuctionStage(header, []byte(`{}`))
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.JSONEq(t, `{"header":"some-agent"}`, string(body), "Request header not passed to hook.")
+	assert.Equal(t, "some-agent", header.Get("User-Agent"), "Request header shouldn't be changed by hook.")
+}
+
+func TestExecuteRawAuctionStageBodyPatch(t *testing.T) {
+	exec := NewHookExecutor(TestRawAuctionBodyPatchPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	body, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{"id":"req","site":{}}`))
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.JSONEq(t, `{"id":"req","site":{"page":"https://prebid.org"}}`, string(body), "Incorrect request body.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if assert.Len(t, stageOutcomes, 1) {
+		assert.Equal(t, []string{
+			"Hook mutation successfully applied, affected key: body./site/page, mutation type: update",
+		}, stageOutcomes[0].Groups[0].InvocationResults[0].DebugMessages, "Patch path should be reported as affected key.")
+	}
+}
+
+func TestExecuteRawAuctionStageStopGroup(t *testing.T) {
+	exec := NewHookExecutor(TestStopGroupPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	body, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{"id":"req","site":{}}`))
+
+	assert.Nil(t, reject, "Stopping the group shouldn't reject the stage.")
+	assert.JSONEq(t, `{"id":"req","site":{"page":"https://prebid.org"},"stopped":true}`, string(body), "Incorrect request body.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if !assert.Len(t, stageOutcomes, 1) || !assert.Len(t, stageOutcomes[0].Groups, 2) {
+		return
+	}
+
+	stoppedGroup := stageOutcomes[0].Groups[0]
+	if assert.Len(t, stoppedGroup.InvocationResults, 1, "Remaining hooks of the stopped group shouldn't be recorded.") {
+		assert.Equal(t, ActionStopGroup, stoppedGroup.InvocationResults[0].Action)
+		assert.Equal(t, StatusSuccess, stoppedGroup.InvocationResults[0].Status)
+		assert.Equal(t, HookID{ModuleCode: "foobar", HookImplCode: "stop"}, stoppedGroup.InvocationResults[0].HookID)
+	}
+
+	nextGroup := stageOutcomes[0].Groups[1]
+	if assert.Len(t, nextGroup.InvocationResults, 1, "Next group should be executed.") {
+		assert.Equal(t, ActionUpdate, nextGroup.InvocationResults[0].Action)
+	}
+}
+
+func TestExecuteHookStoppedGroupNoGoroutineLeak(t *testing.T) {
+	handler := func(ctx context.Context, moduleCtx hookstage.ModuleInvocationContext, hook hookstage.RawAuctionRequest, payload hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+		return hook.HandleRawAuctionHook(ctx, moduleCtx, payload)
+	}
+	testCases := []struct {
+		description string
+		hook        hookstage.RawAuctionRequest
+		timeout     time.Duration
+	}{
+		{description: "Hook completed", hook: mockBodyPatchHook{}, timeout: time.Second},
+		{description: "Hook timed out", hook: mockDelayedHook{delay: 50 * time.Millisecond}, timeout: time.Millisecond},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			goroutinesBefore := runtime.NumGoroutine()
+
+			// nobody reads the responses, as the collector does once the group is stopped
+			resp := make(chan hookResponse[hookstage.RawAuctionRequestPayload])
+			stopped := make(chan struct{})
+			done := make(chan struct{})
+			hw := hooks.HookWrapper[hookstage.RawAuctionRequest]{Module: "foobar", Code: "foo", Hook: test.hook}
+			go func() {
+				defer close(done)
+				executeHook(context.Background(), hookstage.ModuleInvocationContext{}, hw, hookstage.RawAuctionRequestPayload{}, handler, test.timeout, clock.New(), resp, stopped)
+			}()
+
+			// let the hook complete or time out before the group is stopped
+			time.Sleep(10 * time.Millisecond)
+			close(stopped)
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				assert.Fail(t, "Hook execution blocked after the group was stopped.")
+				return
+			}
+
+			goroutinesAfter := runtime.NumGoroutine()
+			for deadline := time.Now().Add(time.Second); goroutinesAfter > goroutinesBefore && time.Now().Before(deadline); {
+				time.Sleep(10 * time.Millisecond)
+				goroutinesAfter = runtime.NumGoroutine()
+			}
+			assert.LessOrEqual(t, goroutinesAfter, goroutinesBefore, "Goroutines of the stopped group leaked.")
+		})
+	}
+}
+
+func TestExecuteRawAuctionStageStopGroupNoGoroutineLeak(t *testing.T) {
+	exec := NewHookExecutor(TestStopGroupPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	goroutinesBefore := runtime.NumGoroutine()
+
+	for i := 0; i < 10; i++ {
+		exec.Reset()
+		_, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{"id":"req","site":{}}`))
+		assert.Nil(t, reject, "Stopping the group shouldn't reject the stage.")
+	}
+
+	// the abandoned hooks of the stopped group exit once they complete or time out
+	goroutinesAfter := runtime.NumGoroutine()
+	for deadline := time.Now().Add(time.Second); goroutinesAfter > goroutinesBefore && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		goroutinesAfter = runtime.NumGoroutine()
+	}
+	assert.LessOrEqual(t, goroutinesAfter, goroutinesBefore, "Goroutines of the stopped group leaked.")
+}
+
+func TestExecuteRawAuctionStageUnresolvedHook(t *testing.T) {
+	exec := NewHookExecutor(TestUnresolvedHookPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	body, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{"id":"req","site":{}}`))
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.JSONEq(t, `{"id":"req","site":{"page":"https://prebid.org"}}`, string(body), "Registered hooks should be executed.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if !assert.Len(t, stageOutcomes, 1) || !assert.Len(t, stageOutcomes[0].Groups, 2) {
+		return
+	}
+
+	mixedGroup := stageOutcomes[0].Groups[0]
+	if assert.Len(t, mixedGroup.InvocationResults, 2) {
+		assert.Equal(t, StatusSuccess, mixedGroup.InvocationResults[0].Status)
+		assert.Equal(t, HookOutcome{
+			HookID:   HookID{ModuleCode: "missing", HookImplCode: "foo"},
+			Status:   StatusUnresolved,
+			Action:   ActionNone,
+			Warnings: []string{"Hook missing.foo referenced by the execution plan but not registered"},
+		}, mixedGroup.InvocationResults[1])
+	}
+
+	unresolvedGroup := stageOutcomes[0].Groups[1]
+	if assert.Len(t, unresolvedGroup.InvocationResults, 1) {
+		assert.Equal(t, StatusUnresolved, unresolvedGroup.InvocationResults[0].Status)
+		assert.Equal(t, HookID{ModuleCode: "missing", HookImplCode: "bar"}, unresolvedGroup.InvocationResults[0].HookID)
+	}
+}
+
+func TestExecuteRawAuctionStageStageTimeout(t *testing.T) {
+	exec := NewHookExecutor(TestStageTimeoutPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	start := time.Now()
+	_, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{}`))
+	elapsed := time.Since(start)
+
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	assert.Less(t, elapsed, 150*time.Millisecond, "Stage should be limited by the stage timeout.")
+
+	stageOutcomes := exec.GetOutcomes()
+	if !assert.Len(t, stageOutcomes, 1) || !assert.Len(t, stageOutcomes[0].Groups, 3) {
+		return
+	}
+
+	groups := stageOutcomes[0].Groups
+	assert.Equal(t, StatusSuccess, groups[0].InvocationResults[0].Status, "First group should complete.")
+	assert.Equal(t, StatusTimeout, groups[1].InvocationResults[0].Status, "Second group should be limited by the remaining stage time.")
+	assert.Equal(t, GroupOutcome{
+		InvocationResults: []HookOutcome{
+			{
+				HookID: HookID{ModuleCode: "foobar", HookImplCode: "third"},
+				Status: StatusTimeout,
+				Errors: []string{"Hook skipped, stage execution timeout exceeded"},
+			},
+		},
+	}, groups[2], "Third group should be skipped.")
+}
+
+type TestStageTimeoutPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestStageTimeoutPlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout:      200 * time.Millisecond,
+			StageTimeout: 50 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "first", Hook: mockDelayedHook{}},
+			},
+		},
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout:      200 * time.Millisecond,
+			StageTimeout: 50 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "second", Hook: mockDelayedHook{delay: 100 * time.Millisecond}},
+			},
+		},
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout:      200 * time.Millisecond,
+			StageTimeout: 50 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "third", Hook: mockDelayedHook{}},
+			},
+		},
+	}
+}
+
+type TestUnresolvedHookPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestUnresolvedHookPlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockBodyPatchHook{}},
+			},
+			Unresolved: []hooks.UnresolvedHook{{Module: "missing", Code: "foo"}},
+		},
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout:    10 * time.Millisecond,
+			Unresolved: []hooks.UnresolvedHook{{Module: "missing", Code: "bar"}},
+		},
+	}
+}
+
+type TestStopGroupPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestStopGroupPlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 100 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "stop", Hook: mockStopGroupHook{}},
+				{Module: "foobar", Code: "slow", Hook: mockTimeoutHook{}},
+			},
+		},
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockBodyPatchHook{}},
+			},
+		},
+	}
+}
+
+type TestRawAuctionBodyPatchPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestRawAuctionBodyPatchPlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockBodyPatchHook{}},
+			},
+		},
+	}
+}
+
+type TestRawAuctionHeaderPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockRawAuctionHeaderHook
+}
+
+func (e TestRawAuctionHeaderPlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+func TestGetHttpCalls(t *testing.T) {
+	fooCall := &openrtb_ext.ExtHttpCall{Uri: "https://foo.com", RequestBody: "foo", Status: 200}
+	barCall := &openrtb_ext.ExtHttpCall{Uri: "https://bar.com", RequestBody: "bar", Status: 204}
+	builder := TestHttpCallsPlanBuilder{
+		rawAuctionHook:       mockHttpCallsHook{httpCalls: []*openrtb_ext.ExtHttpCall{fooCall}},
+		processedAuctionHook: mockHttpCallsHook{httpCalls: []*openrtb_ext.ExtHttpCall{barCall}},
+	}
+
+	exec := NewHookExecutor(builder, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	assert.Empty(t, exec.GetHttpCalls(), "No http calls expected before stages executed.")
+
+	_, reject := exec.ExecuteRawAuctionStage(http.Header{}, []byte(`{}`))
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	reject = exec.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{})
+	assert.Nil(t, reject, "Unexpected stage reject.")
+
+	assert.Equal(t, map[string][]*openrtb_ext.ExtHttpCall{
+		"foobar": {fooCall, barCall},
+	}, exec.GetHttpCalls(), "Http calls of all stages expected grouped by module.")
+	assert.Nil(t, new(EmptyHookExecutor).GetHttpCalls())
+}
+
+func TestGetNonBids(t *testing.T) {
+	exec := NewHookExecutor(TestNonBidsPlanBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+	assert.Empty(t, exec.GetNonBids(), "No non-bids expected before stages executed.")
+
+	reject := exec.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{})
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	reject = exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "appnexus")
+	assert.Nil(t, reject, "Unexpected stage reject.")
+	reject = exec.ExecuteRawBidderResponseStage(&adapters.BidderResponse{}, "rubicon")
+	assert.Nil(t, reject, "Unexpected stage reject.")
+
+	assert.Equal(t, map[string][]openrtb_ext.NonBid{
+		"appnexus": {{ImpId: "imp1", StatusCode: 301}},
+		"rubicon":  {{ImpId: "imp1", StatusCode: 301}},
+	}, exec.GetNonBids(), "Non-bids of bidder-level stages expected grouped by bidder.")
+	assert.Nil(t, new(EmptyHookExecutor).GetNonBids())
+}
+
+type TestNonBidsPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestNonBidsPlanBuilder) PlanForProcessedAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.ProcessedAuctionRequest] {
+	return hooks.Plan[hookstage.ProcessedAuctionRequest]{
+		hooks.Group[hookstage.ProcessedAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.ProcessedAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockNonBidsHook{}},
+			},
+		},
+	}
+}
+
+func (e TestNonBidsPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponse] {
+	return hooks.Plan[hookstage.RawBidderResponse]{
+		hooks.Group[hookstage.RawBidderResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawBidderResponse]{
+				{Module: "foobar", Code: "foo", Hook: mockNonBidsHook{}},
+			},
+		},
+	}
+}
+
+type TestHttpCallsPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	rawAuctionHook       mockHttpCallsHook
+	processedAuctionHook mockHttpCallsHook
+}
+
+func (e TestHttpCallsPlanBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.rawAuctionHook},
+			},
+		},
+	}
+}
+
+func (e TestHttpCallsPlanBuilder) PlanForProcessedAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.ProcessedAuctionRequest] {
+	return hooks.Plan[hookstage.ProcessedAuctionRequest]{
+		hooks.Group[hookstage.ProcessedAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.ProcessedAuctionRequest]{
+				{Module: "foobar", Code: "bar", Hook: e.processedAuctionHook},
+			},
+		},
+	}
+}
+
+type TestUriRewritePlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestUriRewritePlanBuilder) PlanForBidderHttpRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderHttpRequest] {
+	return hooks.Plan[hookstage.BidderHttpRequest]{
+		hooks.Group[hookstage.BidderHttpRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderHttpRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockUriRewriteHook{}},
+			},
+		},
+	}
+}
+
+type TestClientOverridePlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockClientOverrideHook
+}
+
+func (e TestClientOverridePlanBuilder) PlanForBidderHttpRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderHttpRequest] {
+	return hooks.Plan[hookstage.BidderHttpRequest]{
+		hooks.Group[hookstage.BidderHttpRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderHttpRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+type TestDealTierOverridePlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockDealTierOverrideHook
+}
+
+func (e TestDealTierOverridePlanBuilder) PlanForAllProcessedBidResponsesStage(_ string, _ *config.Account) hooks.Plan[hookstage.AllProcessedBidResponses] {
+	return hooks.Plan[hookstage.AllProcessedBidResponses]{
+		hooks.Group[hookstage.AllProcessedBidResponses]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.AllProcessedBidResponses]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+type TestImpFloorPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook mockImpFloorHook
+}
+
+func (e TestImpFloorPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "floor", Hook: e.hook},
+			},
+		},
+	}
+}
+
+type TestMergedContextPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook *mockMergedContextHook
+}
+
+func (e TestMergedContextPlanBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
+	return hooks.Plan[hookstage.Entrypoint]{
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.Entrypoint]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
+func (e TestMergedContextPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.BidderRequest]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
+func (e TestMergedContextPlanBuilder) PlanForAuctionResponseStage(_ string, _ *config.Account) hooks.Plan[hookstage.AuctionResponse] {
+	return hooks.Plan[hookstage.AuctionResponse]{
+		hooks.Group[hookstage.AuctionResponse]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.AuctionResponse]{{Module: "foobar", Code: "foo", Hook: e.hook}},
+		},
+	}
+}
+
+type TestImpCountPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook *mockImpCountBidderRequestHook
+}
+
+func (e TestImpCountPlanBuilder) PlanForBidderRequestStage(_ string, _ *config.Account) hooks.Plan[hookstage.BidderRequest] {
+	return hooks.Plan[hookstage.BidderRequest]{
+		hooks.Group[hookstage.BidderRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.BidderRequest]{
+				{Module: "foobar", Code: "foo", Hook: e.hook},
+			},
+		},
+	}
+}
+
+type mockStageResultObserver struct {
+	sync.Mutex
+	outcomes []StageOutcome
+}
+
+func (o *mockStageResultObserver) OnStageResult(outcome StageOutcome) {
+	o.Lock()
+	defer o.Unlock()
+	o.outcomes = append(o.outcomes, outcome)
+}
+
+type TestEntrypointAccountPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestEntrypointAccountPlanBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
+	return hooks.Plan[hookstage.Entrypoint]{
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "acme.foobar", Code: "account", Hook: mockAccountEntrypointHook{}},
+				{Module: "foobar", Code: "foo", Hook: mockUpdateHeaderEntrypointHook{}},
+			},
+		},
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "foobar", Code: "bar", Hook: mockUpdateQueryEntrypointHook{}},
+			},
+		},
+	}
+}
+
+type TestApplyHookMutationsBuilder struct {
+	hooks.EmptyPlanBuilder
+}
+
+func (e TestApplyHookMutationsBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
+	return hooks.Plan[hookstage.Entrypoint]{
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateHeaderEntrypointHook{}},
+				{Module: "foobar", Code: "foobaz", Hook: mockFailedMutationHook{}},
+				{Module: "foobar", Code: "bar", Hook: mockUpdateQueryEntrypointHook{}},
+			},
+		},
+		hooks.Group[hookstage.Entrypoint]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
+				{Module: "foobar", Code: "baz", Hook: mockUpdateBodyHook{}},
+				{Module: "foobar", Code: "foo", Hook: mockFailureHook{}},
+			},
+		},
+	}
+}
+
+func (e TestApplyHookMutationsBuilder) PlanForRawAuctionStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawAuctionRequest] {
+	return hooks.Plan[hookstage.RawAuctionRequest]{
+		hooks.Group[hookstage.RawAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: mockUpdateBodyHook{}},
 				{Module: "foobar", Code: "bar", Hook: mockFailedMutationHook{}},
 			},
 		},
@@ -2317,15 +3806,22 @@ func (e TestRejectPlanBuilder) PlanForAuctionResponseStage(_ string, _ *config.A
 
 type TestWithTimeoutPlanBuilder struct {
 	hooks.EmptyPlanBuilder
+	// entrypointHook times out at the entrypoint stage, mockTimeoutHook is used if not set
+	entrypointHook hookstage.Entrypoint
 }
 
 func (e TestWithTimeoutPlanBuilder) PlanForEntrypointStage(_ string) hooks.Plan[hookstage.Entrypoint] {
+	timeoutHook := e.entrypointHook
+	if timeoutHook == nil {
+		timeoutHook = mockTimeoutHook{}
+	}
+
 	return hooks.Plan[hookstage.Entrypoint]{
 		hooks.Group[hookstage.Entrypoint]{
 			Timeout: 10 * time.Millisecond,
 			Hooks: []hooks.HookWrapper[hookstage.Entrypoint]{
 				{Module: "foobar", Code: "foo", Hook: mockUpdateHeaderEntrypointHook{}},
-				{Module: "foobar", Code: "bar", Hook: mockTimeoutHook{}},
+				{Module: "foobar", Code: "bar", Hook: timeoutHook},
 			},
 		},
 		hooks.Group[hookstage.Entrypoint]{
@@ -2594,3 +4090,42 @@ func (e TestAllHookResultsBuilder) PlanForEntrypointStage(_ string) hooks.Plan[h
 		},
 	}
 }
+
+func TestMutationLogSnapshot(t *testing.T) {
+	requestRecord := MutationRecord{HookID: HookID{ModuleCode: "foobar", HookImplCode: "foo"}, Stage: "raw_auction_request", Key: "body.site", Type: "update"}
+	bidderARecord := MutationRecord{HookID: HookID{ModuleCode: "foobar", HookImplCode: "bar"}, Stage: "bidder_request", Key: "bidRequest.imp", Type: "update"}
+	bidderBRecord := MutationRecord{HookID: HookID{ModuleCode: "foobar", HookImplCode: "bar"}, Stage: "bidder_request", Key: "bidRequest.user", Type: "delete"}
+
+	log := &mutationLog{}
+	log.add(entityAuctionRequest, requestRecord)
+	log.add(entity("bidderA"), bidderARecord)
+	log.add(entity("bidderB"), bidderBRecord)
+
+	testCases := []struct {
+		description     string
+		givenEntity     entity
+		expectedRecords []MutationRecord
+	}{
+		{
+			description:     "Bidder entity gets request-level and own mutations only",
+			givenEntity:     entity("bidderA"),
+			expectedRecords: []MutationRecord{requestRecord, bidderARecord},
+		},
+		{
+			description:     "Bidder entity without own mutations gets request-level mutations only",
+			givenEntity:     entity("bidderC"),
+			expectedRecords: []MutationRecord{requestRecord},
+		},
+		{
+			description:     "Request-level entity gets all mutations",
+			givenEntity:     entityAllProcessedBidResponses,
+			expectedRecords: []MutationRecord{requestRecord, bidderARecord, bidderBRecord},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedRecords, log.snapshot(test.givenEntity))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package hookexecution
+
+import (
+	"net/http"
+
+	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/util/uuidutil"
+)
+
+// correlationIDHeader is the request header the correlation ID is taken from if provided by the client
+const correlationIDHeader = "X-Request-Id"
+
+// hookLogger writes a line per hook invocation to the server log.
+// It is created only if enabled by the host config, as it produces a line for each hook of each request.
+//
+// All methods are safe to call on a nil hookLogger, in which case nothing is logged.
+type hookLogger struct {
+	logf func(format string, args ...interface{})
+}
+
+// newHookLogger returns a hookLogger writing at the configured glog verbosity, nil if logging disabled.
+func newHookLogger(cfg config.HookLogging) *hookLogger {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	verbose := glog.V(glog.Level(cfg.Verbosity))
+	return &hookLogger{logf: verbose.Infof}
+}
+
+// logStage writes a line for each hook of the executed stage, including hooks skipped or not resolved.
+func (l *hookLogger) logStage(ctx executionContext, outcome StageOutcome) {
+	if l == nil {
+		return
+	}
+
+	for _, group := range outcome.Groups {
+		for _, hook := range group.InvocationResults {
+			l.logf(
+				"Hook execution: correlation_id=%s endpoint=%s account=%s stage=%s module=%s hook=%s status=%s action=%s duration_ms=%d",
+				ctx.correlationID,
+				ctx.endpoint,
+				ctx.accountId,
+				ctx.stage,
+				hook.HookID.ModuleCode,
+				hook.HookID.HookImplCode,
+				hook.Status,
+				hook.Action,
+				hook.ExecutionTimeMillis.Milliseconds(),
+			)
+		}
+	}
+}
+
+// correlationID returns the ID the client provided for the request or generates a new one,
+// empty string is returned if the ID cannot be generated.
+func correlationID(req *http.Request, generator uuidutil.UUIDGenerator) string {
+	if req != nil {
+		if id := req.Header.Get(correlationIDHeader); id != "" {
+			return id
+		}
+	}
+
+	id, err := generator.Generate()
+	if err != nil {
+		glog.Errorf("Failed to generate hook execution correlation ID: %s", err)
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,112 @@
+package hookexecution
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHookLogger(t *testing.T) {
+	assert.Nil(t, newHookLogger(config.HookLogging{}), "Logger shouldn't be created if logging disabled.")
+	assert.NotNil(t, newHookLogger(config.HookLogging{Enabled: true, Verbosity: 2}), "Logger should be created if logging enabled.")
+}
+
+func TestHookLoggerLogStage(t *testing.T) {
+	var lines []string
+	logger := &hookLogger{logf: func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}}
+	ctx := executionContext{endpoint: EndpointAuction, accountId: "account-id", stage: "bidder_request", correlationID: "request-id"}
+	outcome := StageOutcome{Groups: []GroupOutcome{
+		{InvocationResults: []HookOutcome{
+			{
+				HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+				Status:        StatusSuccess,
+				Action:        ActionUpdate,
+				ExecutionTime: ExecutionTime{ExecutionTimeMillis: 15 * time.Millisecond},
+			},
+			{
+				HookID: HookID{ModuleCode: "foobar", HookImplCode: "bar"},
+				Status: StatusTimeout,
+			},
+		}},
+		{InvocationResults: []HookOutcome{
+			{
+				HookID: HookID{ModuleCode: "acme", HookImplCode: "baz"},
+				Status: StatusSuccess,
+				Action: ActionNone,
+			},
+		}},
+	}}
+
+	logger.logStage(ctx, outcome)
+
+	assert.Equal(t, []string{
+		"Hook execution: correlation_id=request-id endpoint=/openrtb2/auction account=account-id stage=bidder_request module=foobar hook=foo status=success action=update duration_ms=15",
+		"Hook execution: correlation_id=request-id endpoint=/openrtb2/auction account=account-id stage=bidder_request module=foobar hook=bar status=timeout action= duration_ms=0",
+		"Hook execution: correlation_id=request-id endpoint=/openrtb2/auction account=account-id stage=bidder_request module=acme hook=baz status=success action=no_action duration_ms=0",
+	}, lines)
+}
+
+func TestHookLoggerNil(t *testing.T) {
+	var logger *hookLogger
+
+	assert.NotPanics(t, func() {
+		logger.logStage(executionContext{}, StageOutcome{Groups: []GroupOutcome{{InvocationResults: []HookOutcome{{}}}}})
+	})
+}
+
+func TestCorrelationID(t *testing.T) {
+	reqWithHeader, _ := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+	reqWithHeader.Header.Set("X-Request-Id", "request-id")
+	reqWithoutHeader, _ := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", nil)
+
+	testCases := []struct {
+		description   string
+		givenRequest  *http.Request
+		givenGenerate func() (string, error)
+		expectedID    string
+	}{
+		{
+			description:   "ID taken from request header",
+			givenRequest:  reqWithHeader,
+			givenGenerate: func() (string, error) { return "generated-id", nil },
+			expectedID:    "request-id",
+		},
+		{
+			description:   "ID generated if header absent",
+			givenRequest:  reqWithoutHeader,
+			givenGenerate: func() (string, error) { return "generated-id", nil },
+			expectedID:    "generated-id",
+		},
+		{
+			description:   "ID generated if request absent",
+			givenRequest:  nil,
+			givenGenerate: func() (string, error) { return "generated-id", nil },
+			expectedID:    "generated-id",
+		},
+		{
+			description:   "Empty ID if generation fails",
+			givenRequest:  reqWithoutHeader,
+			givenGenerate: func() (string, error) { return "", errors.New("failed") },
+			expectedID:    "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedID, correlationID(test.givenRequest, fakeUUIDGenerator(test.givenGenerate)))
+		})
+	}
+}
+
+type fakeUUIDGenerator func() (string, error)
+
+func (f fakeUUIDGenerator) Generate() (string, error) {
+	return f()
+}
@@ -3,8 +3,14 @@ package hookexecution
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/openrtb_ext"
 )
@@ -58,12 +64,12 @@ func (e mockUpdateBodyHook) HandleRawAuctionHook(_ context.Context, _ hookstage.
 	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
 	c.AddMutation(
 		func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
-			payload = []byte(`{"name": "John", "last_name": "Doe", "foo": "bar"}`)
+			payload.Body = []byte(`{"name": "John", "last_name": "Doe", "foo": "bar"}`)
 			return payload, nil
 		}, hookstage.MutationUpdate, "body", "foo",
 	).AddMutation(
 		func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
-			payload = []byte(`{"last_name": "Doe", "foo": "bar"}`)
+			payload.Body = []byte(`{"last_name": "Doe", "foo": "bar"}`)
 			return payload, nil
 		}, hookstage.MutationDelete, "body", "name",
 	)
@@ -120,7 +126,7 @@ func (e mockTimeoutHook) HandleRawAuctionHook(_ context.Context, _ hookstage.Mod
 	time.Sleep(20 * time.Millisecond)
 	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
 	c.AddMutation(func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
-		payload = []byte(`{"last_name": "Doe", "foo": "bar", "address": "A st."}`)
+		payload.Body = []byte(`{"last_name": "Doe", "foo": "bar", "address": "A st."}`)
 		return payload, nil
 	}, hookstage.MutationUpdate, "param", "address")
 
@@ -182,6 +188,28 @@ func (e mockTimeoutHook) HandleAuctionResponseHook(_ context.Context, _ hookstag
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{ChangeSet: c}, nil
 }
 
+// mockFakeClockTimeoutHook simulates a hook running longer than its timeout by advancing the mock clock,
+// the result is returned only once released, so it can never be received before the timeout.
+type mockFakeClockTimeoutHook struct {
+	clock   *clock.Mock
+	delay   time.Duration
+	release <-chan struct{}
+}
+
+func (e mockFakeClockTimeoutHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	e.clock.Add(e.delay)
+	<-e.release
+	c := hookstage.ChangeSet[hookstage.EntrypointPayload]{}
+	c.AddMutation(func(payload hookstage.EntrypointPayload) (hookstage.EntrypointPayload, error) {
+		params := payload.Request.URL.Query()
+		params.Add("bar", "foo")
+		payload.Request.URL.RawQuery = params.Encode()
+		return payload, nil
+	}, hookstage.MutationUpdate, "param", "bar")
+
+	return hookstage.HookResult[hookstage.EntrypointPayload]{ChangeSet: c}, nil
+}
+
 type mockModuleContextHook struct {
 	key, val string
 }
@@ -375,3 +403,279 @@ func (e mockUpdateBidResponseHook) HandleAuctionResponseHook(_ context.Context,
 
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{ChangeSet: c}, nil
 }
+
+type mockImpCountBidderRequestHook struct {
+	impCount int
+}
+
+func (h *mockImpCountBidderRequestHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	h.impCount = payload.ImpCount
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{}, nil
+}
+
+// mockImpFloorHook sets the floor of the first impression to the value configured for the bidder of the payload.
+type mockImpFloorHook struct {
+	floors map[string]float64
+}
+
+func (h mockImpFloorHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.BidderRequestPayload]{}
+	c.BidderRequest().ImpFloor().Update(payload.BidRequest.Imp[0].ID, h.floors[payload.Bidder], "EUR")
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{ChangeSet: c}, nil
+}
+
+// mockMergedContextHook accumulates the stages and bidders it was invoked for in the module context,
+// the context received from the earlier stages is recorded at the auction-response stage.
+type mockMergedContextHook struct {
+	receivedCtx hookstage.ModuleContext
+}
+
+func (h *mockMergedContextHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{ModuleContext: hookstage.ModuleContext{
+		"stage":  "entrypoint",
+		"visits": hookstage.MergedContext{"entrypoint": true},
+	}}, nil
+}
+
+func (h *mockMergedContextHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{ModuleContext: hookstage.ModuleContext{
+		"stage":  "bidder_request",
+		"visits": hookstage.MergedContext{"bidders": hookstage.MergedContext{payload.Bidder: true}},
+	}}, nil
+}
+
+func (h *mockMergedContextHook) HandleAuctionResponseHook(_ context.Context, miCtx hookstage.ModuleInvocationContext, _ hookstage.AuctionResponsePayload) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
+	h.receivedCtx = miCtx.ModuleContext
+	return hookstage.HookResult[hookstage.AuctionResponsePayload]{ModuleContext: hookstage.ModuleContext{
+		"stage":  "auction_response",
+		"visits": hookstage.MergedContext{"auction_response": true},
+	}}, nil
+}
+
+// mockBodyPatchHook sets a single field of the request body without rebuilding it.
+type mockBodyPatchHook struct{}
+
+func (h mockBodyPatchHook) HandleRawAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
+	c.Body().Add("/site/page", []byte(`"https://prebid.org"`))
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{ChangeSet: c}, nil
+}
+
+// mockDelayedHook takes the given time to complete without any changes of the payload.
+type mockDelayedHook struct {
+	delay time.Duration
+}
+
+func (h mockDelayedHook) HandleRawAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	time.Sleep(h.delay)
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{}, nil
+}
+
+// mockStopGroupHook marks the request body and skips the remaining hooks of its group.
+type mockStopGroupHook struct{}
+
+func (h mockStopGroupHook) HandleRawAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
+	c.Body().Add("/stopped", []byte(`true`))
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{ChangeSet: c, StopGroup: true}, nil
+}
+
+// mockUriRewriteHook routes the first bidder HTTP request through a regional proxy.
+type mockUriRewriteHook struct{}
+
+func (h mockUriRewriteHook) HandleBidderHttpRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.BidderHttpRequestPayload) (hookstage.HookResult[hookstage.BidderHttpRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.BidderHttpRequestPayload]{}
+	c.BidderHttpRequest().Uri().Update(0, strings.Replace(payload.Requests[0].Uri, "bidder.com", "eu.proxy.com", 1))
+	c.BidderHttpRequest().Headers().Set(0, "X-Region", "eu")
+	return hookstage.HookResult[hookstage.BidderHttpRequestPayload]{ChangeSet: c}, nil
+}
+
+// mockClientOverrideHook provides the HTTP client the bidder requests are sent with.
+type mockClientOverrideHook struct {
+	client *http.Client
+}
+
+func (h mockClientOverrideHook) HandleBidderHttpRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderHttpRequestPayload) (hookstage.HookResult[hookstage.BidderHttpRequestPayload], error) {
+	c := hookstage.ChangeSet[hookstage.BidderHttpRequestPayload]{}
+	c.BidderHttpRequest().Client().Update(h.client)
+	return hookstage.HookResult[hookstage.BidderHttpRequestPayload]{ChangeSet: c}, nil
+}
+
+// mockDealTierOverrideHook satisfies the deal tier of bids with a deal priority
+// of at least half the configured minimum deal tier.
+type mockDealTierOverrideHook struct{}
+
+func (h mockDealTierOverrideHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	c := hookstage.ChangeSet[hookstage.AllProcessedBidResponsesPayload]{}
+	for bidder, seatBid := range payload.Responses {
+		for _, bid := range seatBid.Bids {
+			dealTier := payload.DealTiers[bid.Bid.ImpID][bidder]
+			c.AllProcessedBidResponses().DealTier().UpdateSatisfied(bidder, bid.Bid.ID, 2*bid.DealPriority >= dealTier.MinDealTier)
+		}
+	}
+	return hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{ChangeSet: c}, nil
+}
+
+// mockContextValuesBidderRequestHook captures the request metadata stored in the hook context.
+type mockContextValuesBidderRequestHook struct {
+	accountID     string
+	endpoint      string
+	correlationID string
+}
+
+func (h *mockContextValuesBidderRequestHook) HandleBidderRequestHook(ctx context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	h.accountID, _ = AccountIDFromContext(ctx)
+	h.endpoint, _ = EndpointFromContext(ctx)
+	h.correlationID, _ = CorrelationIDFromContext(ctx)
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{}, nil
+}
+
+// mockAnalyticsTagsHook reports an activity with the same name regardless of the module it's registered for.
+type mockAnalyticsTagsHook struct{}
+
+func (h mockAnalyticsTagsHook) HandleBidderRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderRequestPayload) (hookstage.HookResult[hookstage.BidderRequestPayload], error) {
+	return hookstage.HookResult[hookstage.BidderRequestPayload]{
+		AnalyticsTags: hookanalytics.Analytics{
+			Activities: []hookanalytics.Activity{{Name: "enrich", Status: hookanalytics.ActivityStatusSuccess}},
+		},
+	}, nil
+}
+
+type mockAmpParamsEntrypointHook struct {
+	isAmp     bool
+	ampParams map[string]string
+}
+
+func (h *mockAmpParamsEntrypointHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	h.isAmp = payload.IsAmp()
+	h.ampParams = make(map[string]string, len(payload.AmpParams))
+	for name, value := range payload.AmpParams {
+		h.ampParams[name] = value
+	}
+
+	c := hookstage.ChangeSet[hookstage.EntrypointPayload]{}
+	c.AddMutation(func(payload hookstage.EntrypointPayload) (hookstage.EntrypointPayload, error) {
+		if payload.IsAmp() {
+			payload.AmpParams["tag_id"] = "rewritten-tag"
+			delete(payload.AmpParams, "curl")
+		}
+		return payload, nil
+	}, hookstage.MutationUpdate, "amp_params")
+
+	return hookstage.HookResult[hookstage.EntrypointPayload]{ChangeSet: c}, nil
+}
+
+func (h *mockAmpParamsEntrypointHook) HandleEntrypointAccountHook(ctx context.Context, miCtx hookstage.ModuleInvocationContext, payload hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return h.HandleEntrypointHook(ctx, miCtx, payload)
+}
+
+// mockInjectBidsHook injects the given synthetic bids into the bidder response.
+type mockInjectBidsHook struct {
+	bids []*adapters.TypedBid
+}
+
+func (h mockInjectBidsHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+	for _, bid := range h.bids {
+		c.RawBidderResponse().Bids().Inject(bid)
+	}
+
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: c}, nil
+}
+
+// mockUpdateAdMHook wraps the markup of every returned bid, bids listed in missingBidIDs are updated as well.
+type mockUpdateAdMHook struct {
+	missingBidIDs []string
+}
+
+func (h mockUpdateAdMHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+	for _, bid := range payload.Bids {
+		c.RawBidderResponse().Bids().UpdateAdM(bid.Bid.ID, bid.Bid.AdM+"<script src=\"viewability.js\"></script>")
+	}
+	for _, bidID := range h.missingBidIDs {
+		c.RawBidderResponse().Bids().UpdateAdM(bidID, "")
+	}
+
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: c}, nil
+}
+
+// mockRawAuctionHeaderHook adds the value of the request header to the request body.
+type mockRawAuctionHeaderHook struct {
+	header string
+}
+
+func (h mockRawAuctionHeaderHook) HandleRawAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	value := payload.Header.Get(h.header)
+	payload.Header.Set(h.header, "changed")
+
+	c := hookstage.ChangeSet[hookstage.RawAuctionRequestPayload]{}
+	c.AddMutation(func(payload hookstage.RawAuctionRequestPayload) (hookstage.RawAuctionRequestPayload, error) {
+		payload.Body = []byte(fmt.Sprintf(`{"header":"%s"}`, value))
+		return payload, nil
+	}, hookstage.MutationUpdate, "body", "header")
+
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{ChangeSet: c}, nil
+}
+
+type mockHttpCallsHook struct {
+	httpCalls []*openrtb_ext.ExtHttpCall
+}
+
+func (h mockHttpCallsHook) HandleRawAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{HttpCalls: h.httpCalls}, nil
+}
+
+func (h mockHttpCallsHook) HandleProcessedAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.ProcessedAuctionRequestPayload) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{HttpCalls: h.httpCalls}, nil
+}
+
+// mockAccountEntrypointHook adds the account-level module config to the request body once the account is resolved.
+type mockAccountEntrypointHook struct{}
+
+func (e mockAccountEntrypointHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{}, nil
+}
+
+func (e mockAccountEntrypointHook) HandleEntrypointAccountHook(_ context.Context, miCtx hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	c := hookstage.ChangeSet[hookstage.EntrypointPayload]{}
+	c.AddMutation(func(payload hookstage.EntrypointPayload) (hookstage.EntrypointPayload, error) {
+		payload.Body = []byte(fmt.Sprintf(`{"name": "John", "module_config": %s}`, miCtx.AccountConfig))
+		return payload, nil
+	}, hookstage.MutationUpdate, "body")
+
+	return hookstage.HookResult[hookstage.EntrypointPayload]{ChangeSet: c}, nil
+}
+
+// mockCachedResponseHook serves the cached response at the entrypoint stage and returns the same result at raw-auction stage.
+type mockCachedResponseHook struct {
+	response hookstage.HTTPResponse
+}
+
+func (h mockCachedResponseHook) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
+	return hookstage.HookResult[hookstage.EntrypointPayload]{CachedResponse: &h.response}, nil
+}
+
+func (h mockCachedResponseHook) HandleRawAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawAuctionRequestPayload) (hookstage.HookResult[hookstage.RawAuctionRequestPayload], error) {
+	return hookstage.HookResult[hookstage.RawAuctionRequestPayload]{CachedResponse: &h.response}, nil
+}
+
+// mockNonBidsHook reports the non-bid for imp1, the non-bid is expected to be collected at bidder-level stages only.
+type mockNonBidsHook struct{}
+
+func (h mockNonBidsHook) HandleProcessedAuctionHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.ProcessedAuctionRequestPayload) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	return hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{NonBids: []openrtb_ext.NonBid{{ImpId: "imp1", StatusCode: 301}}}, nil
+}
+
+func (h mockNonBidsHook) HandleRawBidderResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawBidderResponsePayload) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{NonBids: []openrtb_ext.NonBid{{ImpId: "imp1", StatusCode: 301}}}, nil
+}
+
+// mockEndpointHook implements all stages without changing the payload.
+type mockEndpointHook struct {
+	mockModuleContextHook
+}
+
+func (e mockEndpointHook) HandleBidderHttpRequestHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.BidderHttpRequestPayload) (hookstage.HookResult[hookstage.BidderHttpRequestPayload], error) {
+	return hookstage.HookResult[hookstage.BidderHttpRequestPayload]{}, nil
+}
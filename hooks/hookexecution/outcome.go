@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/prebid/prebid-server/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 // Status indicates the result of hook execution.
@@ -14,15 +15,19 @@ const (
 	StatusTimeout          Status = "timeout"           // hook was not completed in the allotted time
 	StatusFailure          Status = "failure"           // expected module-side failure occurred during hook execution
 	StatusExecutionFailure Status = "execution_failure" // unexpected failure occurred during hook execution
+	StatusUnresolved       Status = "unresolved"        // hook referenced by the execution plan is not registered, so it was skipped
 )
 
 // Action indicates the type of taken behaviour after the successful hook execution.
 type Action string
 
 const (
-	ActionUpdate Action = "update"    // the hook returned mutations that were successfully applied
-	ActionReject Action = "reject"    // the hook decided to reject the stage
-	ActionNone   Action = "no_action" // the hook does not want to take any action
+	ActionUpdate     Action = "update"      // the hook returned mutations that were successfully applied
+	ActionReject     Action = "reject"      // the hook decided to reject the stage
+	ActionNone       Action = "no_action"   // the hook does not want to take any action
+	ActionInjectBids Action = "inject_bids" // the hook successfully injected synthetic bids, possibly along with other mutations
+	ActionStopGroup  Action = "stop_group"  // the hook skipped the remaining hooks of its group, possibly along with applied mutations
+	ActionCacheHit   Action = "cache_hit"   // the hook served a cached response, the auction is skipped
 )
 
 // Messages in format: {"module": {"hook": ["msg1", "msg2"]}}
@@ -83,8 +88,14 @@ type HookOutcome struct {
 	Action        Action                  `json:"action"`
 	Message       string                  `json:"message"` // arbitrary string value returned from hook execution
 	DebugMessages []string                `json:"debug_messages,omitempty"`
+	RejectChain   []MutationRecord        `json:"reject_chain,omitempty"` // mutations applied before the hook rejected the request
+	HTTPStatus    int                     `json:"http_status,omitempty"`  // status of the custom response provided by rejecting entrypoint hook
 	Errors        []string                `json:"-"`
 	Warnings      []string                `json:"-"`
+	// HttpCalls holds outbound HTTP calls reported by the hook, they are rendered as part of the response debug info
+	HttpCalls []*openrtb_ext.ExtHttpCall `json:"-"`
+	// NonBids holds the reasons of the bids removed by the hook, they are rendered as part of the response seatnonbid
+	NonBids []openrtb_ext.NonBid `json:"-"`
 }
 
 // HookID points to the specific hook defined by the hook execution plan.
@@ -93,6 +104,63 @@ type HookID struct {
 	HookImplCode string `json:"hook_impl_code"`
 }
 
+// MutationRecord describes a mutation successfully applied by a specific hook.
+type MutationRecord struct {
+	HookID HookID `json:"hook_id"`
+	Stage  string `json:"stage"`
+	Key    string `json:"key"`
+	Type   string `json:"mutation_type"`
+}
+
+// StageSummary is a compact representation of the StageOutcome
+// intended for delivering hook execution results to analytics modules.
+type StageSummary struct {
+	// ExecutionTime is the sum of ExecutionTime of all stage groups
+	ExecutionTime
+	Stage  string        `json:"stage"`
+	Entity entity        `json:"entity"`
+	Hooks  []HookSummary `json:"hooks"`
+}
+
+// HookSummary is a compact representation of the HookOutcome.
+type HookSummary struct {
+	// ExecutionTime is the execution time of a specific hook without applying its result.
+	ExecutionTime
+	HookID HookID `json:"hook_id"`
+	Status Status `json:"status"`
+	Action Action `json:"action"`
+}
+
+// SummarizeOutcomes converts stage outcomes to the list of stage summaries.
+// The summaries do not depend on the trace and debug settings of the request
+// and do not share memory with the stage outcomes.
+func SummarizeOutcomes(stageOutcomes []StageOutcome) []StageSummary {
+	summaries := make([]StageSummary, 0, len(stageOutcomes))
+	for _, stageOutcome := range stageOutcomes {
+		summary := StageSummary{
+			ExecutionTime: stageOutcome.ExecutionTime,
+			Stage:         stageOutcome.Stage,
+			Entity:        stageOutcome.Entity,
+			Hooks:         []HookSummary{},
+		}
+
+		for _, group := range stageOutcome.Groups {
+			for _, hookOutcome := range group.InvocationResults {
+				summary.Hooks = append(summary.Hooks, HookSummary{
+					ExecutionTime: hookOutcome.ExecutionTime,
+					HookID:        hookOutcome.HookID,
+					Status:        hookOutcome.Status,
+					Action:        hookOutcome.Action,
+				})
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
 type ExecutionTime struct {
 	ExecutionTimeMillis time.Duration `json:"execution_time_millis,omitempty"`
 }
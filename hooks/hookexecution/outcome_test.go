@@ -0,0 +1,78 @@
+package hookexecution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/hooks"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeOutcomes(t *testing.T) {
+	stageOutcomes := []StageOutcome{
+		{
+			ExecutionTime: ExecutionTime{ExecutionTimeMillis: 20 * time.Millisecond},
+			Entity:        entityHttpRequest,
+			Stage:         hooks.StageEntrypoint.String(),
+			Groups: []GroupOutcome{
+				{
+					InvocationResults: []HookOutcome{
+						{
+							ExecutionTime: ExecutionTime{ExecutionTimeMillis: 10 * time.Millisecond},
+							AnalyticsTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{Name: "foo"}}},
+							HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+							Status:        StatusSuccess,
+							Action:        ActionUpdate,
+							DebugMessages: []string{"debug"},
+						},
+					},
+				},
+				{
+					InvocationResults: []HookOutcome{
+						{
+							ExecutionTime: ExecutionTime{ExecutionTimeMillis: 10 * time.Millisecond},
+							HookID:        HookID{ModuleCode: "foobar", HookImplCode: "bar"},
+							Status:        StatusTimeout,
+							Errors:        []string{"Hook execution timeout"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Entity: entity("appnexus"),
+			Stage:  hooks.StageBidderRequest.String(),
+			Groups: []GroupOutcome{},
+		},
+	}
+
+	expectedSummary := []StageSummary{
+		{
+			ExecutionTime: ExecutionTime{ExecutionTimeMillis: 20 * time.Millisecond},
+			Stage:         hooks.StageEntrypoint.String(),
+			Entity:        entityHttpRequest,
+			Hooks: []HookSummary{
+				{
+					ExecutionTime: ExecutionTime{ExecutionTimeMillis: 10 * time.Millisecond},
+					HookID:        HookID{ModuleCode: "foobar", HookImplCode: "foo"},
+					Status:        StatusSuccess,
+					Action:        ActionUpdate,
+				},
+				{
+					ExecutionTime: ExecutionTime{ExecutionTimeMillis: 10 * time.Millisecond},
+					HookID:        HookID{ModuleCode: "foobar", HookImplCode: "bar"},
+					Status:        StatusTimeout,
+				},
+			},
+		},
+		{
+			Stage:  hooks.StageBidderRequest.String(),
+			Entity: entity("appnexus"),
+			Hooks:  []HookSummary{},
+		},
+	}
+
+	assert.Equal(t, expectedSummary, SummarizeOutcomes(stageOutcomes))
+	assert.Equal(t, []StageSummary{}, SummarizeOutcomes(nil))
+}
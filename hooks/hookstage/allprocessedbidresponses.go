@@ -14,7 +14,8 @@ import (
 // so it can be configured at the account-level execution plan,
 // the account-level module config is passed to hooks.
 //
-// Rejection has no effect and is completely ignored at this stage.
+// Rejection results in sending an empty BidResponse
+// with the NBR code indicating the rejection reason.
 type AllProcessedBidResponses interface {
 	HandleAllProcessedBidResponsesHook(
 		context.Context,
@@ -25,7 +26,11 @@ type AllProcessedBidResponses interface {
 
 // AllProcessedBidResponsesPayload consists of a list of all
 // processed responses received from bidders.
-// Hooks are allowed to modify payload object and discard bids using mutations.
+// Hooks are allowed to modify payload object and discard bids using mutations,
+// e.g. the deal priority, deal tier and meta of the bids, see ChangeSet.AllProcessedBidResponses.
 type AllProcessedBidResponsesPayload struct {
 	Responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+	// DealTiers holds the deal tier configuration of the bidders keyed by imp ID,
+	// it's provided only if the deals support is requested.
+	DealTiers map[string]openrtb_ext.DealTierBidderMap
 }
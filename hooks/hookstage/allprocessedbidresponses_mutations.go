@@ -0,0 +1,141 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+func (c *ChangeSet[T]) AllProcessedBidResponses() ChangeSetAllProcessedBidResponses[T] {
+	return ChangeSetAllProcessedBidResponses[T]{changeSet: c}
+}
+
+type ChangeSetAllProcessedBidResponses[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+func (c ChangeSetAllProcessedBidResponses[T]) DealPriority() ChangeSetDealPriority[T] {
+	return ChangeSetDealPriority[T]{changeSetAllProcessedBidResponses: c}
+}
+
+func (c ChangeSetAllProcessedBidResponses[T]) DealTier() ChangeSetDealTier[T] {
+	return ChangeSetDealTier[T]{changeSetAllProcessedBidResponses: c}
+}
+
+func (c ChangeSetAllProcessedBidResponses[T]) BidMeta() ChangeSetBidMeta[T] {
+	return ChangeSetBidMeta[T]{changeSetAllProcessedBidResponses: c}
+}
+
+func (c ChangeSetAllProcessedBidResponses[T]) castPayload(p T) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, error) {
+	if payload, ok := any(p).(AllProcessedBidResponsesPayload); ok {
+		if payload.Responses == nil {
+			return nil, errors.New("empty Responses provided")
+		}
+		return payload.Responses, nil
+	}
+	return nil, errors.New("failed to cast AllProcessedBidResponsesPayload")
+}
+
+// findBid returns the processed bid of the bidder by bid ID.
+func (c ChangeSetAllProcessedBidResponses[T]) findBid(p T, bidder openrtb_ext.BidderName, bidID string) (*entities.PbsOrtbBid, error) {
+	responses, err := c.castPayload(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if seatBid, ok := responses[bidder]; ok && seatBid != nil {
+		for _, bid := range seatBid.Bids {
+			if bid != nil && bid.Bid != nil && bid.Bid.ID == bidID {
+				return bid, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("bid %s of bidder %s not found", bidID, bidder)
+}
+
+type ChangeSetDealPriority[T any] struct {
+	changeSetAllProcessedBidResponses ChangeSetAllProcessedBidResponses[T]
+}
+
+// Update sets the deal priority of the bidder's bid used later to determine the bid's deal tier.
+func (c ChangeSetDealPriority[T]) Update(bidder openrtb_ext.BidderName, bidID string, dealPriority int) {
+	c.changeSetAllProcessedBidResponses.changeSet.AddMutation(func(p T) (T, error) {
+		bid, err := c.changeSetAllProcessedBidResponses.findBid(p, bidder, bidID)
+		if err == nil {
+			bid.DealPriority = dealPriority
+		}
+		return p, err
+	}, MutationUpdate, "processedbidresponses", bidder.String(), bidID, "dealpriority")
+}
+
+type ChangeSetDealTier[T any] struct {
+	changeSetAllProcessedBidResponses ChangeSetAllProcessedBidResponses[T]
+}
+
+// UpdateSatisfied decides whether the bidder's bid satisfies its deal tier, replacing the comparison
+// of the bid's deal priority with the minimum deal tier configured for the bidder. The decision applies
+// only if the deals support is requested, the category of a satisfied bid is prefixed with the tier
+// only if a valid deal tier is configured for the bidder.
+func (c ChangeSetDealTier[T]) UpdateSatisfied(bidder openrtb_ext.BidderName, bidID string, satisfied bool) {
+	c.changeSetAllProcessedBidResponses.changeSet.AddMutation(func(p T) (T, error) {
+		bid, err := c.changeSetAllProcessedBidResponses.findBid(p, bidder, bidID)
+		if err == nil {
+			bid.DealTierOverride = &satisfied
+		}
+		return p, err
+	}, MutationUpdate, "processedbidresponses", bidder.String(), bidID, "dealtiersatisfied")
+}
+
+type ChangeSetBidMeta[T any] struct {
+	changeSetAllProcessedBidResponses ChangeSetAllProcessedBidResponses[T]
+}
+
+// Update replaces the meta of the bidder's bid rendered as bid.ext.prebid.meta.
+func (c ChangeSetBidMeta[T]) Update(bidder openrtb_ext.BidderName, bidID string, meta *openrtb_ext.ExtBidPrebidMeta) {
+	c.changeSetAllProcessedBidResponses.changeSet.AddMutation(func(p T) (T, error) {
+		bid, err := c.changeSetAllProcessedBidResponses.findBid(p, bidder, bidID)
+		if err == nil {
+			bid.BidMeta = meta
+		}
+		return p, err
+	}, MutationUpdate, "processedbidresponses", bidder.String(), bidID, "meta")
+}
+
+// UpdateExperiment sets the experiment group of the bidder's bid rendered as bid.ext.prebid.meta.experiment.
+// Other meta fields are retained, if the bid has no meta yet it's initialized from the bid's ext.prebid.meta.
+func (c ChangeSetBidMeta[T]) UpdateExperiment(bidder openrtb_ext.BidderName, bidID string, experiment string) {
+	c.changeSetAllProcessedBidResponses.changeSet.AddMutation(func(p T) (T, error) {
+		bid, err := c.changeSetAllProcessedBidResponses.findBid(p, bidder, bidID)
+		if err != nil {
+			return p, err
+		}
+
+		if bid.BidMeta == nil {
+			meta, err := bidExtMeta(bid.Bid.Ext)
+			if err != nil {
+				return p, fmt.Errorf("failed to parse meta of bid %s of bidder %s: %s", bidID, bidder, err)
+			}
+			bid.BidMeta = meta
+		}
+		bid.BidMeta.Experiment = experiment
+		return p, nil
+	}, MutationUpdate, "processedbidresponses", bidder.String(), bidID, "meta", "experiment")
+}
+
+// bidExtMeta returns a copy of the meta provided in the bid's ext.prebid.meta, empty meta if absent.
+func bidExtMeta(ext json.RawMessage) (*openrtb_ext.ExtBidPrebidMeta, error) {
+	metaContainer := struct {
+		Prebid struct {
+			Meta openrtb_ext.ExtBidPrebidMeta `json:"meta"`
+		} `json:"prebid"`
+	}{}
+	if len(ext) > 0 {
+		if err := json.Unmarshal(ext, &metaContainer); err != nil {
+			return nil, err
+		}
+	}
+	return &metaContainer.Prebid.Meta, nil
+}
@@ -0,0 +1,127 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/exchange/entities"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllProcessedBidResponsesMutations(t *testing.T) {
+	newResponses := func() map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			"appnexus": {Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ID: "1"}, DealPriority: 1},
+				{Bid: &openrtb2.Bid{ID: "2"}, DealPriority: 2},
+			}},
+		}
+	}
+
+	t.Run("Deal priority updated", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().DealPriority().Update("appnexus", "2", 10)
+		responses := newResponses()
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{Responses: responses})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"processedbidresponses", "appnexus", "2", "dealpriority"}, changeSet.Mutations()[0].Key())
+		assert.Equal(t, 1, responses["appnexus"].Bids[0].DealPriority)
+		assert.Equal(t, 10, responses["appnexus"].Bids[1].DealPriority)
+	})
+
+	t.Run("Deal tier decision updated", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().DealTier().UpdateSatisfied("appnexus", "2", false)
+		responses := newResponses()
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{Responses: responses})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"processedbidresponses", "appnexus", "2", "dealtiersatisfied"}, changeSet.Mutations()[0].Key())
+		assert.Nil(t, responses["appnexus"].Bids[0].DealTierOverride)
+		if assert.NotNil(t, responses["appnexus"].Bids[1].DealTierOverride) {
+			assert.False(t, *responses["appnexus"].Bids[1].DealTierOverride)
+		}
+	})
+
+	t.Run("Bid meta updated", func(t *testing.T) {
+		meta := &openrtb_ext.ExtBidPrebidMeta{AdvertiserDomains: []string{"foo.com"}}
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().BidMeta().Update("appnexus", "1", meta)
+		responses := newResponses()
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{Responses: responses})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"processedbidresponses", "appnexus", "1", "meta"}, changeSet.Mutations()[0].Key())
+		assert.Equal(t, meta, responses["appnexus"].Bids[0].BidMeta)
+		assert.Nil(t, responses["appnexus"].Bids[1].BidMeta)
+	})
+
+	t.Run("Bid meta experiment updated", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().BidMeta().UpdateExperiment("appnexus", "1", "variant-b")
+		responses := newResponses()
+		responses["appnexus"].Bids[0].BidMeta = &openrtb_ext.ExtBidPrebidMeta{AdvertiserDomains: []string{"foo.com"}}
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{Responses: responses})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"processedbidresponses", "appnexus", "1", "meta", "experiment"}, changeSet.Mutations()[0].Key())
+		assert.Equal(t, &openrtb_ext.ExtBidPrebidMeta{AdvertiserDomains: []string{"foo.com"}, Experiment: "variant-b"}, responses["appnexus"].Bids[0].BidMeta)
+		assert.Nil(t, responses["appnexus"].Bids[1].BidMeta)
+	})
+
+	t.Run("Bid meta experiment initialized from bid ext", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().BidMeta().UpdateExperiment("appnexus", "1", "variant-b")
+		responses := newResponses()
+		responses["appnexus"].Bids[0].Bid.Ext = json.RawMessage(`{"prebid":{"meta":{"brandName":"foo"}}}`)
+		responses["appnexus"].Bids[1].Bid.Ext = json.RawMessage(`{"prebid":{"meta":{"brandName":"bar"}}}`)
+		changeSet.AllProcessedBidResponses().BidMeta().UpdateExperiment("appnexus", "2", "variant-b")
+
+		for _, mutation := range changeSet.Mutations() {
+			_, err := mutation.Apply(AllProcessedBidResponsesPayload{Responses: responses})
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, &openrtb_ext.ExtBidPrebidMeta{BrandName: "foo", Experiment: "variant-b"}, responses["appnexus"].Bids[0].BidMeta)
+		assert.Equal(t, &openrtb_ext.ExtBidPrebidMeta{BrandName: "bar", Experiment: "variant-b"}, responses["appnexus"].Bids[1].BidMeta)
+	})
+
+	t.Run("Error if bid ext malformed", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().BidMeta().UpdateExperiment("appnexus", "1", "variant-b")
+		responses := newResponses()
+		responses["appnexus"].Bids[0].Bid.Ext = json.RawMessage(`{"prebid":`)
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{Responses: responses})
+
+		assert.EqualError(t, err, "failed to parse meta of bid 1 of bidder appnexus: unexpected end of JSON input")
+		assert.Nil(t, responses["appnexus"].Bids[0].BidMeta)
+	})
+
+	t.Run("Error if bid not found", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().DealPriority().Update("rubicon", "1", 10)
+		responses := newResponses()
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{Responses: responses})
+
+		assert.EqualError(t, err, "bid 1 of bidder rubicon not found")
+		assert.Equal(t, newResponses(), responses)
+	})
+
+	t.Run("Error if Responses empty", func(t *testing.T) {
+		changeSet := &ChangeSet[AllProcessedBidResponsesPayload]{}
+		changeSet.AllProcessedBidResponses().BidMeta().Update("appnexus", "1", nil)
+
+		_, err := changeSet.Mutations()[0].Apply(AllProcessedBidResponsesPayload{})
+
+		assert.EqualError(t, err, "empty Responses provided")
+	})
+}
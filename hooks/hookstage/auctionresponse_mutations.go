@@ -0,0 +1,193 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+func (c *ChangeSet[T]) AuctionResponse() ChangeSetAuctionResponse[T] {
+	return ChangeSetAuctionResponse[T]{changeSet: c}
+}
+
+type ChangeSetAuctionResponse[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+// Ext provides mutations of the bidresponse.ext object addressed by JSON pointers (RFC 6901).
+func (c ChangeSetAuctionResponse[T]) Ext() ChangeSetResponseExt[T] {
+	return ChangeSetResponseExt[T]{changeSetAuctionResponse: c}
+}
+
+// BidExt provides mutations of the bidresponse.seatbid[].bid[].ext objects addressed by JSON pointers (RFC 6901).
+// Mutations are applied to the ext of every bid in the response.
+func (c ChangeSetAuctionResponse[T]) BidExt() ChangeSetBidExt[T] {
+	return ChangeSetBidExt[T]{changeSetAuctionResponse: c}
+}
+
+func (c ChangeSetAuctionResponse[T]) castPayload(p T) (*openrtb2.BidResponse, error) {
+	if payload, ok := any(p).(AuctionResponsePayload); ok {
+		if payload.BidResponse == nil {
+			return nil, errors.New("empty BidResponse provided")
+		}
+		return payload.BidResponse, nil
+	}
+	return nil, errors.New("failed to cast AuctionResponsePayload")
+}
+
+type ChangeSetResponseExt[T any] struct {
+	changeSetAuctionResponse ChangeSetAuctionResponse[T]
+}
+
+// Update sets the value at the pointer location of the bidresponse.ext,
+// the parent of the location must exist.
+func (c ChangeSetResponseExt[T]) Update(pointer string, value json.RawMessage) {
+	c.changeSetAuctionResponse.changeSet.AddMutation(func(p T) (T, error) {
+		bidResponse, err := c.changeSetAuctionResponse.castPayload(p)
+		if err == nil {
+			bidResponse.Ext, err = updateJSONPointer(bidResponse.Ext, pointer, value)
+		}
+		return p, err
+	}, MutationUpdate, "bidresponse", "ext", pointer)
+}
+
+// Delete removes the value at the pointer location of the bidresponse.ext if it exists.
+func (c ChangeSetResponseExt[T]) Delete(pointer string) {
+	c.changeSetAuctionResponse.changeSet.AddMutation(func(p T) (T, error) {
+		bidResponse, err := c.changeSetAuctionResponse.castPayload(p)
+		if err == nil {
+			bidResponse.Ext, err = deleteJSONPointer(bidResponse.Ext, pointer)
+		}
+		return p, err
+	}, MutationDelete, "bidresponse", "ext", pointer)
+}
+
+type ChangeSetBidExt[T any] struct {
+	changeSetAuctionResponse ChangeSetAuctionResponse[T]
+}
+
+// Update sets the value at the pointer location of the ext of every bid,
+// the parent of the location must exist.
+func (c ChangeSetBidExt[T]) Update(pointer string, value json.RawMessage) {
+	c.changeSetAuctionResponse.changeSet.AddMutation(func(p T) (T, error) {
+		bidResponse, err := c.changeSetAuctionResponse.castPayload(p)
+		if err == nil {
+			err = mutateBidExts(bidResponse, func(ext json.RawMessage) (json.RawMessage, error) {
+				return updateJSONPointer(ext, pointer, value)
+			})
+		}
+		return p, err
+	}, MutationUpdate, "bidresponse", "seatbid", "bid", "ext", pointer)
+}
+
+// Delete removes the value at the pointer location of the ext of every bid having it.
+func (c ChangeSetBidExt[T]) Delete(pointer string) {
+	c.changeSetAuctionResponse.changeSet.AddMutation(func(p T) (T, error) {
+		bidResponse, err := c.changeSetAuctionResponse.castPayload(p)
+		if err == nil {
+			err = mutateBidExts(bidResponse, func(ext json.RawMessage) (json.RawMessage, error) {
+				return deleteJSONPointer(ext, pointer)
+			})
+		}
+		return p, err
+	}, MutationDelete, "bidresponse", "seatbid", "bid", "ext", pointer)
+}
+
+// mutateBidExts applies fn to the ext of every bid, the bid response stays unchanged if fn fails for any bid.
+func mutateBidExts(bidResponse *openrtb2.BidResponse, fn func(json.RawMessage) (json.RawMessage, error)) error {
+	exts := make([][]json.RawMessage, len(bidResponse.SeatBid))
+	for i, seatBid := range bidResponse.SeatBid {
+		exts[i] = make([]json.RawMessage, len(seatBid.Bid))
+		for j, bid := range seatBid.Bid {
+			ext, err := fn(bid.Ext)
+			if err != nil {
+				return fmt.Errorf("failed to mutate ext of bid %s: %s", bid.ID, err)
+			}
+			exts[i][j] = ext
+		}
+	}
+
+	for i := range bidResponse.SeatBid {
+		for j := range bidResponse.SeatBid[i].Bid {
+			bidResponse.SeatBid[i].Bid[j].Ext = exts[i][j]
+		}
+	}
+
+	return nil
+}
+
+func updateJSONPointer(doc json.RawMessage, pointer string, value json.RawMessage) (json.RawMessage, error) {
+	if len(doc) == 0 {
+		doc = json.RawMessage(`{}`)
+	}
+	return applyJSONPatchOperation(doc, map[string]interface{}{"op": "add", "path": pointer, "value": value})
+}
+
+func deleteJSONPointer(doc json.RawMessage, pointer string) (json.RawMessage, error) {
+	exists, err := hasJSONPointer(doc, pointer)
+	if err != nil || !exists {
+		return doc, err
+	}
+	return applyJSONPatchOperation(doc, map[string]interface{}{"op": "remove", "path": pointer})
+}
+
+func applyJSONPatchOperation(doc json.RawMessage, operation map[string]interface{}) (json.RawMessage, error) {
+	patchJSON, err := json.Marshal([]interface{}{operation})
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := jsonpatch.DecodePatch(patchJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return patch.Apply(doc)
+}
+
+// hasJSONPointer reports whether the pointer location exists in the doc.
+func hasJSONPointer(doc json.RawMessage, pointer string) (bool, error) {
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return false, fmt.Errorf("invalid JSON pointer: %s", pointer)
+	}
+
+	if len(doc) == 0 {
+		return false, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(doc, &value); err != nil {
+		return false, err
+	}
+
+	if pointer == "" {
+		return true, nil
+	}
+
+	replacer := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = replacer.Replace(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return false, nil
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return false, nil
+			}
+			value = v[i]
+		default:
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,165 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionResponseExtMutations(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenExt         json.RawMessage
+		mutate           func(ChangeSetAuctionResponse[AuctionResponsePayload])
+		expectedExt      json.RawMessage
+		expectedKey      []string
+		expectedErrorMsg string
+	}{
+		{
+			description: "Nested field deleted",
+			givenExt:    json.RawMessage(`{"prebid":{"usersync":{"pixel":"url"},"foo":"bar"}}`),
+			mutate: func(c ChangeSetAuctionResponse[AuctionResponsePayload]) {
+				c.Ext().Delete("/prebid/usersync")
+			},
+			expectedExt: json.RawMessage(`{"prebid":{"foo":"bar"}}`),
+			expectedKey: []string{"bidresponse", "ext", "/prebid/usersync"},
+		},
+		{
+			description: "Delete of missing field leaves ext unchanged",
+			givenExt:    json.RawMessage(`{"prebid":{"foo":"bar"}}`),
+			mutate: func(c ChangeSetAuctionResponse[AuctionResponsePayload]) {
+				c.Ext().Delete("/prebid/usersync")
+			},
+			expectedExt: json.RawMessage(`{"prebid":{"foo":"bar"}}`),
+			expectedKey: []string{"bidresponse", "ext", "/prebid/usersync"},
+		},
+		{
+			description: "Escaped field deleted",
+			givenExt:    json.RawMessage(`{"a/b":{"c~d":1,"e":2}}`),
+			mutate: func(c ChangeSetAuctionResponse[AuctionResponsePayload]) {
+				c.Ext().Delete("/a~1b/c~0d")
+			},
+			expectedExt: json.RawMessage(`{"a/b":{"e":2}}`),
+			expectedKey: []string{"bidresponse", "ext", "/a~1b/c~0d"},
+		},
+		{
+			description: "Nested field rewritten",
+			givenExt:    json.RawMessage(`{"prebid":{"foo":"bar"}}`),
+			mutate: func(c ChangeSetAuctionResponse[AuctionResponsePayload]) {
+				c.Ext().Update("/prebid/foo", json.RawMessage(`"baz"`))
+			},
+			expectedExt: json.RawMessage(`{"prebid":{"foo":"baz"}}`),
+			expectedKey: []string{"bidresponse", "ext", "/prebid/foo"},
+		},
+		{
+			description: "Field added to empty ext",
+			givenExt:    nil,
+			mutate: func(c ChangeSetAuctionResponse[AuctionResponsePayload]) {
+				c.Ext().Update("/foo", json.RawMessage(`{"bar":1}`))
+			},
+			expectedExt: json.RawMessage(`{"foo":{"bar":1}}`),
+			expectedKey: []string{"bidresponse", "ext", "/foo"},
+		},
+		{
+			description: "Error if pointer invalid",
+			givenExt:    json.RawMessage(`{"foo":"bar"}`),
+			mutate: func(c ChangeSetAuctionResponse[AuctionResponsePayload]) {
+				c.Ext().Delete("foo")
+			},
+			expectedExt:      json.RawMessage(`{"foo":"bar"}`),
+			expectedKey:      []string{"bidresponse", "ext", "foo"},
+			expectedErrorMsg: "invalid JSON pointer: foo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[AuctionResponsePayload]{}
+			test.mutate(changeSet.AuctionResponse())
+			mutations := changeSet.Mutations()
+			if !assert.Len(t, mutations, 1) {
+				return
+			}
+			assert.Equal(t, test.expectedKey, mutations[0].Key())
+
+			payload := AuctionResponsePayload{BidResponse: &openrtb2.BidResponse{Ext: test.givenExt}}
+			_, err := mutations[0].Apply(payload)
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if test.expectedExt == nil {
+				assert.Nil(t, payload.BidResponse.Ext)
+			} else {
+				assert.JSONEq(t, string(test.expectedExt), string(payload.BidResponse.Ext))
+			}
+		})
+	}
+}
+
+func TestAuctionResponseBidExtMutations(t *testing.T) {
+	newResponse := func() *openrtb2.BidResponse {
+		return &openrtb2.BidResponse{
+			SeatBid: []openrtb2.SeatBid{
+				{Bid: []openrtb2.Bid{
+					{ID: "1", Ext: json.RawMessage(`{"prebid":{"meta":{"email":"foo@bar.com"}},"foo":1}`)},
+					{ID: "2", Ext: json.RawMessage(`{"foo":2}`)},
+				}},
+				{Bid: []openrtb2.Bid{
+					{ID: "3"},
+				}},
+			},
+		}
+	}
+
+	t.Run("Field deleted from every bid having it", func(t *testing.T) {
+		changeSet := &ChangeSet[AuctionResponsePayload]{}
+		changeSet.AuctionResponse().BidExt().Delete("/prebid/meta/email")
+		response := newResponse()
+
+		_, err := changeSet.Mutations()[0].Apply(AuctionResponsePayload{BidResponse: response})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bidresponse", "seatbid", "bid", "ext", "/prebid/meta/email"}, changeSet.Mutations()[0].Key())
+		assert.JSONEq(t, `{"prebid":{"meta":{}},"foo":1}`, string(response.SeatBid[0].Bid[0].Ext))
+		assert.JSONEq(t, `{"foo":2}`, string(response.SeatBid[0].Bid[1].Ext))
+		assert.Nil(t, response.SeatBid[1].Bid[0].Ext)
+	})
+
+	t.Run("Field rewritten in every bid", func(t *testing.T) {
+		changeSet := &ChangeSet[AuctionResponsePayload]{}
+		changeSet.AuctionResponse().BidExt().Update("/foo", json.RawMessage(`0`))
+		response := newResponse()
+
+		_, err := changeSet.Mutations()[0].Apply(AuctionResponsePayload{BidResponse: response})
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"prebid":{"meta":{"email":"foo@bar.com"}},"foo":0}`, string(response.SeatBid[0].Bid[0].Ext))
+		assert.JSONEq(t, `{"foo":0}`, string(response.SeatBid[0].Bid[1].Ext))
+		assert.JSONEq(t, `{"foo":0}`, string(response.SeatBid[1].Bid[0].Ext))
+	})
+
+	t.Run("Bids unchanged if mutation fails for any bid", func(t *testing.T) {
+		changeSet := &ChangeSet[AuctionResponsePayload]{}
+		changeSet.AuctionResponse().BidExt().Update("/prebid/meta/email", json.RawMessage(`""`))
+		response := newResponse()
+
+		_, err := changeSet.Mutations()[0].Apply(AuctionResponsePayload{BidResponse: response})
+
+		assert.Error(t, err)
+		assert.Equal(t, newResponse(), response)
+	})
+
+	t.Run("Error if BidResponse empty", func(t *testing.T) {
+		changeSet := &ChangeSet[AuctionResponsePayload]{}
+		changeSet.AuctionResponse().BidExt().Delete("/foo")
+
+		_, err := changeSet.Mutations()[0].Apply(AuctionResponsePayload{})
+
+		assert.EqualError(t, err, "empty BidResponse provided")
+	})
+}
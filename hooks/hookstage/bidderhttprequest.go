@@ -0,0 +1,36 @@
+package hookstage
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prebid/prebid-server/adapters"
+)
+
+// BidderHttpRequest hooks are invoked for each bidder participating in auction
+// after the bidder adapter built its HTTP requests and before they are sent.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in skipping the bidder's request.
+type BidderHttpRequest interface {
+	HandleBidderHttpRequestHook(
+		context.Context,
+		ModuleInvocationContext,
+		BidderHttpRequestPayload,
+	) (HookResult[BidderHttpRequestPayload], error)
+}
+
+// BidderHttpRequestPayload consists of the HTTP requests
+// built by the bidder adapter with the MakeRequests method.
+// Hooks are allowed to rewrite the URI and headers of the requests
+// and to provide the HTTP client the requests are sent with using mutations.
+type BidderHttpRequestPayload struct {
+	Requests []*adapters.RequestData
+	Bidder   string
+	// Client overrides the adapter HTTP client for the requests of the current auction if set,
+	// e.g. to send them with a client configured for mTLS with the bidder.
+	Client *http.Client
+}
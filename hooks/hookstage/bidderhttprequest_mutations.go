@@ -0,0 +1,109 @@
+package hookstage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prebid/prebid-server/adapters"
+)
+
+func (c *ChangeSet[T]) BidderHttpRequest() ChangeSetBidderHttpRequest[T] {
+	return ChangeSetBidderHttpRequest[T]{changeSet: c}
+}
+
+type ChangeSetBidderHttpRequest[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+// Uri provides mutations of the URI of the bidder HTTP requests.
+func (c ChangeSetBidderHttpRequest[T]) Uri() ChangeSetHttpRequestUri[T] {
+	return ChangeSetHttpRequestUri[T]{changeSetBidderHttpRequest: c}
+}
+
+// Headers provides mutations of the headers of the bidder HTTP requests.
+func (c ChangeSetBidderHttpRequest[T]) Headers() ChangeSetHttpRequestHeaders[T] {
+	return ChangeSetHttpRequestHeaders[T]{changeSetBidderHttpRequest: c}
+}
+
+// Client provides mutations of the HTTP client the bidder HTTP requests are sent with.
+func (c ChangeSetBidderHttpRequest[T]) Client() ChangeSetHttpRequestClient[T] {
+	return ChangeSetHttpRequestClient[T]{changeSetBidderHttpRequest: c}
+}
+
+func (c ChangeSetBidderHttpRequest[T]) castPayload(p T, index int) (*adapters.RequestData, error) {
+	if payload, ok := any(p).(BidderHttpRequestPayload); ok {
+		if index < 0 || index >= len(payload.Requests) || payload.Requests[index] == nil {
+			return nil, fmt.Errorf("no HTTP request at index %d", index)
+		}
+		return payload.Requests[index], nil
+	}
+	return nil, errors.New("failed to cast BidderHttpRequestPayload")
+}
+
+type ChangeSetHttpRequestUri[T any] struct {
+	changeSetBidderHttpRequest ChangeSetBidderHttpRequest[T]
+}
+
+// Update replaces the URI of the request at the given index of the payload requests.
+func (c ChangeSetHttpRequestUri[T]) Update(index int, uri string) {
+	c.changeSetBidderHttpRequest.changeSet.AddMutation(func(p T) (T, error) {
+		request, err := c.changeSetBidderHttpRequest.castPayload(p, index)
+		if err == nil {
+			request.Uri = uri
+		}
+		return p, err
+	}, MutationUpdate, "httprequest", strconv.Itoa(index), "uri")
+}
+
+type ChangeSetHttpRequestHeaders[T any] struct {
+	changeSetBidderHttpRequest ChangeSetBidderHttpRequest[T]
+}
+
+// Set sets the header of the request at the given index of the payload requests,
+// existing values of the header are replaced.
+func (c ChangeSetHttpRequestHeaders[T]) Set(index int, name, value string) {
+	c.changeSetBidderHttpRequest.changeSet.AddMutation(func(p T) (T, error) {
+		request, err := c.changeSetBidderHttpRequest.castPayload(p, index)
+		if err == nil {
+			if request.Headers == nil {
+				request.Headers = http.Header{}
+			}
+			request.Headers.Set(name, value)
+		}
+		return p, err
+	}, MutationUpdate, "httprequest", strconv.Itoa(index), "header", name)
+}
+
+// Delete removes the header of the request at the given index of the payload requests.
+func (c ChangeSetHttpRequestHeaders[T]) Delete(index int, name string) {
+	c.changeSetBidderHttpRequest.changeSet.AddMutation(func(p T) (T, error) {
+		request, err := c.changeSetBidderHttpRequest.castPayload(p, index)
+		if err == nil {
+			request.Headers.Del(name)
+		}
+		return p, err
+	}, MutationDelete, "httprequest", strconv.Itoa(index), "header", name)
+}
+
+type ChangeSetHttpRequestClient[T any] struct {
+	changeSetBidderHttpRequest ChangeSetBidderHttpRequest[T]
+}
+
+// Update replaces the HTTP client all requests of the payload are sent with in the current auction,
+// the adapter HTTP client is used by default.
+func (c ChangeSetHttpRequestClient[T]) Update(client *http.Client) {
+	c.changeSetBidderHttpRequest.changeSet.AddMutation(func(p T) (T, error) {
+		payload, ok := any(p).(BidderHttpRequestPayload)
+		if !ok {
+			return p, errors.New("failed to cast BidderHttpRequestPayload")
+		}
+		if client == nil {
+			return p, errors.New("HTTP client is nil")
+		}
+
+		payload.Client = client
+		return any(payload).(T), nil
+	}, MutationUpdate, "httprequest", "client")
+}
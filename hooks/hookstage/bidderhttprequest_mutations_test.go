@@ -0,0 +1,148 @@
+package hookstage
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidderHttpRequestMutations(t *testing.T) {
+	newPayload := func() BidderHttpRequestPayload {
+		return BidderHttpRequestPayload{
+			Requests: []*adapters.RequestData{
+				{Uri: "https://bidder.com/bid", Headers: http.Header{"X-Foo": {"foo"}}},
+				{Uri: "https://bidder.com/video"},
+			},
+			Bidder: "appnexus",
+		}
+	}
+
+	testCases := []struct {
+		description      string
+		mutate           func(ChangeSetBidderHttpRequest[BidderHttpRequestPayload])
+		expectedRequests []*adapters.RequestData
+		expectedKey      []string
+		expectedErrorMsg string
+	}{
+		{
+			description: "URI rewritten",
+			mutate: func(c ChangeSetBidderHttpRequest[BidderHttpRequestPayload]) {
+				c.Uri().Update(1, "https://eu.bidder.com/video")
+			},
+			expectedRequests: []*adapters.RequestData{
+				{Uri: "https://bidder.com/bid", Headers: http.Header{"X-Foo": {"foo"}}},
+				{Uri: "https://eu.bidder.com/video"},
+			},
+			expectedKey: []string{"httprequest", "1", "uri"},
+		},
+		{
+			description: "Header set",
+			mutate: func(c ChangeSetBidderHttpRequest[BidderHttpRequestPayload]) {
+				c.Headers().Set(0, "X-Foo", "bar")
+			},
+			expectedRequests: []*adapters.RequestData{
+				{Uri: "https://bidder.com/bid", Headers: http.Header{"X-Foo": {"bar"}}},
+				{Uri: "https://bidder.com/video"},
+			},
+			expectedKey: []string{"httprequest", "0", "header", "X-Foo"},
+		},
+		{
+			description: "Header set if request has no headers",
+			mutate: func(c ChangeSetBidderHttpRequest[BidderHttpRequestPayload]) {
+				c.Headers().Set(1, "X-Region", "eu")
+			},
+			expectedRequests: []*adapters.RequestData{
+				{Uri: "https://bidder.com/bid", Headers: http.Header{"X-Foo": {"foo"}}},
+				{Uri: "https://bidder.com/video", Headers: http.Header{"X-Region": {"eu"}}},
+			},
+			expectedKey: []string{"httprequest", "1", "header", "X-Region"},
+		},
+		{
+			description: "Header deleted",
+			mutate: func(c ChangeSetBidderHttpRequest[BidderHttpRequestPayload]) {
+				c.Headers().Delete(0, "X-Foo")
+			},
+			expectedRequests: []*adapters.RequestData{
+				{Uri: "https://bidder.com/bid", Headers: http.Header{}},
+				{Uri: "https://bidder.com/video"},
+			},
+			expectedKey: []string{"httprequest", "0", "header", "X-Foo"},
+		},
+		{
+			description: "Error if request index out of range",
+			mutate: func(c ChangeSetBidderHttpRequest[BidderHttpRequestPayload]) {
+				c.Uri().Update(2, "https://eu.bidder.com/bid")
+			},
+			expectedRequests: newPayload().Requests,
+			expectedKey:      []string{"httprequest", "2", "uri"},
+			expectedErrorMsg: "no HTTP request at index 2",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[BidderHttpRequestPayload]{}
+			test.mutate(changeSet.BidderHttpRequest())
+			mutations := changeSet.Mutations()
+			if !assert.Len(t, mutations, 1) {
+				return
+			}
+			assert.Equal(t, test.expectedKey, mutations[0].Key())
+
+			payload, err := mutations[0].Apply(newPayload())
+
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedRequests, payload.Requests)
+		})
+	}
+}
+
+func TestBidderHttpRequestClientMutation(t *testing.T) {
+	client := &http.Client{}
+
+	testCases := []struct {
+		description      string
+		givenClient      *http.Client
+		expectedClient   *http.Client
+		expectedErrorMsg string
+	}{
+		{
+			description:    "Client set",
+			givenClient:    client,
+			expectedClient: client,
+		},
+		{
+			description:      "Error if client nil",
+			givenClient:      nil,
+			expectedClient:   nil,
+			expectedErrorMsg: "HTTP client is nil",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[BidderHttpRequestPayload]{}
+			changeSet.BidderHttpRequest().Client().Update(test.givenClient)
+			mutations := changeSet.Mutations()
+			if !assert.Len(t, mutations, 1) {
+				return
+			}
+			assert.Equal(t, []string{"httprequest", "client"}, mutations[0].Key())
+
+			payload, err := mutations[0].Apply(BidderHttpRequestPayload{Requests: []*adapters.RequestData{{Uri: "https://bidder.com/bid"}}})
+
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Same(t, test.expectedClient, payload.Client)
+		})
+	}
+}
@@ -27,4 +27,9 @@ type BidderRequest interface {
 type BidderRequestPayload struct {
 	BidRequest *openrtb2.BidRequest
 	Bidder     string
+	// ImpCount holds the number of impressions in the BidRequest at the beginning of the stage,
+	// it is not updated when hooks mutate the list of impressions.
+	ImpCount int
+	// impExtCache holds the bidder params parsed from the impression exts, see BidderImpExt.
+	impExtCache *bidderImpExtCache
 }
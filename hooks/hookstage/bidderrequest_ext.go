@@ -0,0 +1,135 @@
+package hookstage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+)
+
+// NewBidderRequestPayload returns the payload of the bidder request hooks
+// sharing the cache of the parsed impression exts between the hooks.
+func NewBidderRequestPayload(bidRequest *openrtb2.BidRequest, bidder string) BidderRequestPayload {
+	payload := BidderRequestPayload{
+		BidRequest:  bidRequest,
+		Bidder:      bidder,
+		impExtCache: &bidderImpExtCache{entries: make(map[int]bidderImpExtEntry)},
+	}
+	if bidRequest != nil {
+		payload.ImpCount = len(bidRequest.Imp)
+	}
+	return payload
+}
+
+// BidderImpExtError indicates that the ext of the impression cannot be parsed.
+type BidderImpExtError struct {
+	ImpIndex int
+	Message  string
+}
+
+func (e BidderImpExtError) Error() string {
+	return fmt.Sprintf("failed to parse ext of imp %d: %s", e.ImpIndex, e.Message)
+}
+
+// BidderImpExt returns the params of the payload bidder from the ext of the impression with the given index.
+// The params are read from imp[].ext.bidder or, if absent, from imp[].ext.prebid.bidder.<bidder>,
+// nil is returned if the impression has no params for the bidder. The request is not modified.
+//
+// The parsed ext is cached until the impression ext is changed, so the hooks of the stage
+// reading the params of the same impression don't parse the ext repeatedly.
+func (p BidderRequestPayload) BidderImpExt(impIndex int) (json.RawMessage, error) {
+	if p.BidRequest == nil || impIndex < 0 || impIndex >= len(p.BidRequest.Imp) {
+		return nil, BidderImpExtError{ImpIndex: impIndex, Message: "impression not found"}
+	}
+
+	ext := p.BidRequest.Imp[impIndex].Ext
+	if params, ok := p.impExtCache.get(impIndex, ext); ok {
+		return params, nil
+	}
+
+	params, err := parseBidderImpExt(ext, p.Bidder)
+	if err != nil {
+		return nil, BidderImpExtError{ImpIndex: impIndex, Message: err.Error()}
+	}
+	p.impExtCache.set(impIndex, ext, params)
+
+	return params, nil
+}
+
+// UnmarshalBidderImpExt unmarshals the params of the payload bidder from the ext
+// of the impression with the given index into the value of the requested type.
+func UnmarshalBidderImpExt[T any](p BidderRequestPayload, impIndex int) (T, error) {
+	var value T
+	params, err := p.BidderImpExt(impIndex)
+	if err != nil || params == nil {
+		return value, err
+	}
+
+	if err := json.Unmarshal(params, &value); err != nil {
+		return value, BidderImpExtError{ImpIndex: impIndex, Message: err.Error()}
+	}
+	return value, nil
+}
+
+func parseBidderImpExt(ext json.RawMessage, bidder string) (json.RawMessage, error) {
+	if len(ext) == 0 {
+		return nil, nil
+	}
+
+	var impExt struct {
+		Bidder json.RawMessage `json:"bidder"`
+		Prebid struct {
+			Bidder map[string]json.RawMessage `json:"bidder"`
+		} `json:"prebid"`
+	}
+	if err := json.Unmarshal(ext, &impExt); err != nil {
+		return nil, err
+	}
+
+	if impExt.Bidder != nil {
+		return impExt.Bidder, nil
+	}
+	return impExt.Prebid.Bidder[bidder], nil
+}
+
+// bidderImpExtCache holds the bidder params parsed from the impression exts by impression index,
+// it is shared by the hooks running concurrently, so access to the entries is synchronized.
+type bidderImpExtCache struct {
+	mu      sync.Mutex
+	entries map[int]bidderImpExtEntry
+}
+
+type bidderImpExtEntry struct {
+	ext    json.RawMessage
+	params json.RawMessage
+}
+
+// get returns the cached params if the impression ext has not changed since it was parsed.
+func (c *bidderImpExtCache) get(impIndex int, ext json.RawMessage) (json.RawMessage, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[impIndex]
+	if !ok || !bytes.Equal(entry.ext, ext) {
+		return nil, false
+	}
+	return entry.params, true
+}
+
+func (c *bidderImpExtCache) set(impIndex int, ext json.RawMessage, params json.RawMessage) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// ext is copied so that in-place changes of the impression ext invalidate the entry
+	c.entries[impIndex] = bidderImpExtEntry{ext: append(json.RawMessage(nil), ext...), params: params}
+}
@@ -0,0 +1,119 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidderImpExt(t *testing.T) {
+	testCases := []struct {
+		description    string
+		givenImpExt    json.RawMessage
+		givenImpIndex  int
+		expectedParams json.RawMessage
+		expectedError  error
+	}{
+		{
+			description:    "Params read from imp.ext.bidder",
+			givenImpExt:    json.RawMessage(`{"bidder":{"placementId":1},"prebid":{"is_rewarded_inventory":1}}`),
+			expectedParams: json.RawMessage(`{"placementId":1}`),
+		},
+		{
+			description:    "Params read from imp.ext.prebid.bidder",
+			givenImpExt:    json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":2},"rubicon":{"zoneId":3}}}}`),
+			expectedParams: json.RawMessage(`{"placementId":2}`),
+		},
+		{
+			description:    "Nil params if absent",
+			givenImpExt:    json.RawMessage(`{"prebid":{"bidder":{"rubicon":{"zoneId":3}}}}`),
+			expectedParams: nil,
+		},
+		{
+			description:    "Nil params if imp ext empty",
+			givenImpExt:    nil,
+			expectedParams: nil,
+		},
+		{
+			description:   "Error if imp ext malformed",
+			givenImpExt:   json.RawMessage(`{"bidder":`),
+			expectedError: BidderImpExtError{ImpIndex: 0, Message: "unexpected end of JSON input"},
+		},
+		{
+			description:   "Error if imp not found",
+			givenImpExt:   json.RawMessage(`{}`),
+			givenImpIndex: 1,
+			expectedError: BidderImpExtError{ImpIndex: 1, Message: "impression not found"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", Ext: test.givenImpExt}}}
+			payload := NewBidderRequestPayload(request, "appnexus")
+
+			params, err := payload.BidderImpExt(test.givenImpIndex)
+
+			assert.Equal(t, test.expectedError, err)
+			assert.Equal(t, test.expectedParams, params)
+			assert.Equal(t, test.givenImpExt, request.Imp[0].Ext, "Request shouldn't be modified.")
+		})
+	}
+}
+
+func TestBidderImpExtCache(t *testing.T) {
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", Ext: json.RawMessage(`{"bidder":{"placementId":1}}`)}}}
+	payload := NewBidderRequestPayload(request, "appnexus")
+
+	params, err := payload.BidderImpExt(0)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"placementId":1}`, string(params))
+
+	copied := payload
+	cached, ok := copied.impExtCache.get(0, request.Imp[0].Ext)
+	assert.True(t, ok, "Parsed ext should be cached for payload copies.")
+	assert.Equal(t, params, cached)
+
+	request.Imp[0].Ext = json.RawMessage(`{"bidder":{"placementId":2}}`)
+	params, err = copied.BidderImpExt(0)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"placementId":2}`, string(params), "Changed ext should be parsed again.")
+}
+
+func TestBidderImpExtWithoutCache(t *testing.T) {
+	payload := BidderRequestPayload{
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1", Ext: json.RawMessage(`{"bidder":{"placementId":1}}`)}}},
+		Bidder:     "appnexus",
+	}
+
+	params, err := payload.BidderImpExt(0)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"placementId":1}`, string(params))
+}
+
+func TestUnmarshalBidderImpExt(t *testing.T) {
+	type params struct {
+		PlacementID int `json:"placementId"`
+	}
+
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+		{ID: "imp1", Ext: json.RawMessage(`{"bidder":{"placementId":1}}`)},
+		{ID: "imp2", Ext: json.RawMessage(`{"bidder":{"placementId":"1"}}`)},
+		{ID: "imp3", Ext: json.RawMessage(`{"prebid":{}}`)},
+	}}
+	payload := NewBidderRequestPayload(request, "appnexus")
+
+	value, err := UnmarshalBidderImpExt[params](payload, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, params{PlacementID: 1}, value)
+
+	_, err = UnmarshalBidderImpExt[params](payload, 1)
+	assert.IsType(t, BidderImpExtError{}, err, "Typed error expected for params of invalid type.")
+
+	value, err = UnmarshalBidderImpExt[params](payload, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, params{}, value, "Zero value expected for absent params.")
+}
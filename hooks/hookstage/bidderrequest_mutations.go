@@ -2,6 +2,8 @@ package hookstage
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/prebid/openrtb/v17/adcom1"
 	"github.com/prebid/openrtb/v17/openrtb2"
@@ -31,6 +33,11 @@ func (c ChangeSetBidderRequest[T]) BApp() ChangeSetBApp[T] {
 	return ChangeSetBApp[T]{changeSetBidderRequest: c}
 }
 
+// ImpFloor provides mutations of the floor of the impressions of the bidder request.
+func (c ChangeSetBidderRequest[T]) ImpFloor() ChangeSetImpFloor[T] {
+	return ChangeSetImpFloor[T]{changeSetBidderRequest: c}
+}
+
 func (c ChangeSetBidderRequest[T]) castPayload(p T) (*openrtb2.BidRequest, error) {
 	if payload, ok := any(p).(BidderRequestPayload); ok {
 		if payload.BidRequest == nil {
@@ -96,3 +103,35 @@ func (c ChangeSetBApp[T]) Update(bapp []string) {
 		return p, err
 	}, MutationUpdate, "bidrequest", "bapp")
 }
+
+type ChangeSetImpFloor[T any] struct {
+	changeSetBidderRequest ChangeSetBidderRequest[T]
+}
+
+// Update sets the imp.bidfloor and imp.bidfloorcur of the impression with the given ID together,
+// so that the floor is never left in the currency of the previous value.
+// Each bidder gets its own copy of the impressions, so the change affects only the bidder of the payload.
+func (c ChangeSetImpFloor[T]) Update(impID string, floor float64, currency string) {
+	c.changeSetBidderRequest.changeSet.AddMutation(func(p T) (T, error) {
+		bidRequest, err := c.changeSetBidderRequest.castPayload(p)
+		if err != nil {
+			return p, err
+		}
+
+		if floor < 0 {
+			return p, fmt.Errorf("invalid floor %g of imp %s, floor must not be negative", floor, impID)
+		}
+		if len(currency) != 3 {
+			return p, fmt.Errorf("invalid floor currency %q of imp %s, ISO 4217 code expected", currency, impID)
+		}
+
+		for i := range bidRequest.Imp {
+			if bidRequest.Imp[i].ID == impID {
+				bidRequest.Imp[i].BidFloor = floor
+				bidRequest.Imp[i].BidFloorCur = strings.ToUpper(currency)
+				return p, nil
+			}
+		}
+		return p, fmt.Errorf("imp %s not found", impID)
+	}, MutationUpdate, "bidrequest", "imp", impID, "bidfloor")
+}
@@ -0,0 +1,107 @@
+package hookstage
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBidderRequestImpFloorMutation(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenImpID       string
+		givenFloor       float64
+		givenCurrency    string
+		expectedImps     []openrtb2.Imp
+		expectedErrorMsg string
+	}{
+		{
+			description:   "Floor and currency of imp updated",
+			givenImpID:    "imp2",
+			givenFloor:    1.5,
+			givenCurrency: "eur",
+			expectedImps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"},
+				{ID: "imp2", BidFloor: 1.5, BidFloorCur: "EUR"},
+			},
+		},
+		{
+			description:   "Floor can be reset",
+			givenImpID:    "imp1",
+			givenFloor:    0,
+			givenCurrency: "USD",
+			expectedImps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0, BidFloorCur: "USD"},
+				{ID: "imp2"},
+			},
+		},
+		{
+			description:   "Error if floor negative",
+			givenImpID:    "imp1",
+			givenFloor:    -1,
+			givenCurrency: "USD",
+			expectedImps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"},
+				{ID: "imp2"},
+			},
+			expectedErrorMsg: "invalid floor -1 of imp imp1, floor must not be negative",
+		},
+		{
+			description:   "Error if currency invalid",
+			givenImpID:    "imp1",
+			givenFloor:    1,
+			givenCurrency: "US",
+			expectedImps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"},
+				{ID: "imp2"},
+			},
+			expectedErrorMsg: `invalid floor currency "US" of imp imp1, ISO 4217 code expected`,
+		},
+		{
+			description:   "Error if imp not found",
+			givenImpID:    "imp3",
+			givenFloor:    1,
+			givenCurrency: "USD",
+			expectedImps: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"},
+				{ID: "imp2"},
+			},
+			expectedErrorMsg: "imp imp3 not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[BidderRequestPayload]{}
+			changeSet.BidderRequest().ImpFloor().Update(test.givenImpID, test.givenFloor, test.givenCurrency)
+			mutations := changeSet.Mutations()
+			if !assert.Len(t, mutations, 1) {
+				return
+			}
+			assert.Equal(t, MutationUpdate, mutations[0].Type())
+			assert.Equal(t, []string{"bidrequest", "imp", test.givenImpID, "bidfloor"}, mutations[0].Key())
+
+			request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+				{ID: "imp1", BidFloor: 0.5, BidFloorCur: "USD"},
+				{ID: "imp2"},
+			}}
+			_, err := mutations[0].Apply(BidderRequestPayload{BidRequest: request})
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedImps, request.Imp)
+		})
+	}
+}
+
+func TestBidderRequestImpFloorMutationEmptyRequest(t *testing.T) {
+	changeSet := &ChangeSet[BidderRequestPayload]{}
+	changeSet.BidderRequest().ImpFloor().Update("imp1", 1, "USD")
+
+	_, err := changeSet.Mutations()[0].Apply(BidderRequestPayload{})
+
+	assert.EqualError(t, err, "empty BidRequest provided")
+}
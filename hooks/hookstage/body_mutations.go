@@ -0,0 +1,80 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Body provides targeted JSON Patch (RFC 6902) mutations of the raw request body
+// of the EntrypointPayload and RawAuctionRequestPayload, locations are addressed by JSON pointers (RFC 6901).
+// Mutations replacing the whole body can still be added with the AddMutation method.
+func (c *ChangeSet[T]) Body() ChangeSetBody[T] {
+	return ChangeSetBody[T]{changeSet: c}
+}
+
+type ChangeSetBody[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+// Add sets the value at the pointer location, the value is replaced if it exists,
+// the parent of the location must exist.
+func (c ChangeSetBody[T]) Add(pointer string, value json.RawMessage) {
+	c.changeSet.AddMutation(func(p T) (T, error) {
+		return patchBody(p, map[string]interface{}{"op": "add", "path": pointer, "value": value})
+	}, MutationUpdate, "body", pointer)
+}
+
+// Replace sets the value at the pointer location, the location must exist.
+func (c ChangeSetBody[T]) Replace(pointer string, value json.RawMessage) {
+	c.changeSet.AddMutation(func(p T) (T, error) {
+		return patchBody(p, map[string]interface{}{"op": "replace", "path": pointer, "value": value})
+	}, MutationUpdate, "body", pointer)
+}
+
+// Remove deletes the value at the pointer location, the location must exist.
+func (c ChangeSetBody[T]) Remove(pointer string) {
+	c.changeSet.AddMutation(func(p T) (T, error) {
+		return patchBody(p, map[string]interface{}{"op": "remove", "path": pointer})
+	}, MutationDelete, "body", pointer)
+}
+
+func patchBody[T any](p T, operation map[string]interface{}) (T, error) {
+	switch payload := any(p).(type) {
+	case EntrypointPayload:
+		body, err := patchRequestBody(payload.Body, operation)
+		if err != nil {
+			return p, err
+		}
+		payload.Body = body
+		return any(payload).(T), nil
+	case RawAuctionRequestPayload:
+		body, err := patchRequestBody(payload.Body, operation)
+		if err != nil {
+			return p, err
+		}
+		payload.Body = body
+		return any(payload).(T), nil
+	}
+	return p, errors.New("failed to cast payload with request body")
+}
+
+func patchRequestBody(body []byte, operation map[string]interface{}) ([]byte, error) {
+	if len(body) == 0 {
+		return nil, errors.New("empty request body provided")
+	}
+
+	// the jsonpatch package adds missing values on replace, so the location is checked beforehand
+	if operation["op"] != "add" {
+		pointer, _ := operation["path"].(string)
+		exists, err := hasJSONPointer(body, pointer)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("no value at the pointer location: %s", pointer)
+		}
+	}
+
+	return applyJSONPatchOperation(body, operation)
+}
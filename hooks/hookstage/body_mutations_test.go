@@ -0,0 +1,149 @@
+package hookstage
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyMutations(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenBody        []byte
+		mutate           func(ChangeSetBody[RawAuctionRequestPayload])
+		expectedBody     []byte
+		expectedKey      []string
+		expectedType     MutationType
+		expectedErrorMsg string
+	}{
+		{
+			description: "Field added",
+			givenBody:   []byte(`{"site":{"domain":"prebid.org"}}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Add("/site/page", json.RawMessage(`"https://prebid.org/page"`))
+			},
+			expectedBody: []byte(`{"site":{"domain":"prebid.org","page":"https://prebid.org/page"}}`),
+			expectedKey:  []string{"body", "/site/page"},
+			expectedType: MutationUpdate,
+		},
+		{
+			description: "Existing field overwritten by add",
+			givenBody:   []byte(`{"site":{"page":"old"}}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Add("/site/page", json.RawMessage(`"new"`))
+			},
+			expectedBody: []byte(`{"site":{"page":"new"}}`),
+			expectedKey:  []string{"body", "/site/page"},
+			expectedType: MutationUpdate,
+		},
+		{
+			description: "Field replaced",
+			givenBody:   []byte(`{"tmax":500}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Replace("/tmax", json.RawMessage(`50`))
+			},
+			expectedBody: []byte(`{"tmax":50}`),
+			expectedKey:  []string{"body", "/tmax"},
+			expectedType: MutationUpdate,
+		},
+		{
+			description: "Error if replaced field missing",
+			givenBody:   []byte(`{"id":"req"}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Replace("/tmax", json.RawMessage(`50`))
+			},
+			expectedBody:     []byte(`{"id":"req"}`),
+			expectedKey:      []string{"body", "/tmax"},
+			expectedType:     MutationUpdate,
+			expectedErrorMsg: "no value at the pointer location: /tmax",
+		},
+		{
+			description: "Array element removed",
+			givenBody:   []byte(`{"imp":[{"id":"1"},{"id":"2"}]}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Remove("/imp/0")
+			},
+			expectedBody: []byte(`{"imp":[{"id":"2"}]}`),
+			expectedKey:  []string{"body", "/imp/0"},
+			expectedType: MutationDelete,
+		},
+		{
+			description: "Error if removed field missing",
+			givenBody:   []byte(`{"id":"req"}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Remove("/tmax")
+			},
+			expectedBody:     []byte(`{"id":"req"}`),
+			expectedKey:      []string{"body", "/tmax"},
+			expectedType:     MutationDelete,
+			expectedErrorMsg: "no value at the pointer location: /tmax",
+		},
+		{
+			description: "Error if pointer invalid",
+			givenBody:   []byte(`{"tmax":500}`),
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Replace("tmax", json.RawMessage(`50`))
+			},
+			expectedBody:     []byte(`{"tmax":500}`),
+			expectedKey:      []string{"body", "tmax"},
+			expectedType:     MutationUpdate,
+			expectedErrorMsg: "invalid JSON pointer: tmax",
+		},
+		{
+			description: "Error if body empty",
+			givenBody:   nil,
+			mutate: func(c ChangeSetBody[RawAuctionRequestPayload]) {
+				c.Remove("/tmax")
+			},
+			expectedKey:      []string{"body", "/tmax"},
+			expectedType:     MutationDelete,
+			expectedErrorMsg: "empty request body provided",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[RawAuctionRequestPayload]{}
+			test.mutate(changeSet.Body())
+			mutations := changeSet.Mutations()
+			if !assert.Len(t, mutations, 1) {
+				return
+			}
+			assert.Equal(t, test.expectedKey, mutations[0].Key())
+			assert.Equal(t, test.expectedType, mutations[0].Type())
+
+			payload, err := mutations[0].Apply(RawAuctionRequestPayload{Body: test.givenBody})
+
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+				assert.Equal(t, test.givenBody, payload.Body, "Body shouldn't change on error.")
+			} else {
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(test.expectedBody), string(payload.Body))
+			}
+		})
+	}
+}
+
+func TestEntrypointBodyMutation(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://prebid.org/openrtb2/auction", nil)
+	changeSet := &ChangeSet[EntrypointPayload]{}
+	changeSet.Body().Add("/site", json.RawMessage(`{"page":"https://prebid.org"}`))
+
+	payload, err := changeSet.Mutations()[0].Apply(EntrypointPayload{Request: request, Body: []byte(`{"id":"req"}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, request, payload.Request, "Request should be kept.")
+	assert.JSONEq(t, `{"id":"req","site":{"page":"https://prebid.org"}}`, string(payload.Body))
+}
+
+func TestBodyMutationUnsupportedPayload(t *testing.T) {
+	changeSet := &ChangeSet[BidderRequestPayload]{}
+	changeSet.Body().Remove("/tmax")
+
+	_, err := changeSet.Mutations()[0].Apply(BidderRequestPayload{})
+
+	assert.EqualError(t, err, "failed to cast payload with request body")
+}
@@ -10,6 +10,7 @@ import (
 // At this stage, account config is not yet available,
 // so it can only be defined as part of the host-level execution plan,
 // the account-level module config is not available.
+// Hooks depending on the account can implement EntrypointAccount.
 //
 // Rejection results in sending an empty BidResponse
 // with the NBR code indicating the rejection reason.
@@ -21,10 +22,36 @@ type Entrypoint interface {
 	) (HookResult[EntrypointPayload], error)
 }
 
+// EntrypointAccount is an optional interface of the Entrypoint hooks depending on the account config.
+//
+// As the account is resolved from the request processed by the entrypoint stage,
+// hooks implementing the interface are invoked once more, when the account is known,
+// before the RawAuctionRequest stage. The account-level module config is passed to hooks
+// and the payload holds the request body as processed by the entrypoint stage.
+// The hook is invoked for the "/openrtb2/auction" and "/openrtb2/amp" endpoints, for the latter
+// the payload holds the AMP params and the body is nil.
+//
+// Entrypoint hooks not implementing the interface are invoked only once.
+type EntrypointAccount interface {
+	HandleEntrypointAccountHook(
+		context.Context,
+		ModuleInvocationContext,
+		EntrypointPayload,
+	) (HookResult[EntrypointPayload], error)
+}
+
 // EntrypointPayload consists of an HTTP request and a raw body of the openrtb2.BidRequest.
 // For "/openrtb2/amp" endpoint the body is nil.
 // Hooks are allowed to modify this data using mutations.
 type EntrypointPayload struct {
 	Request *http.Request
 	Body    []byte
+	// AmpParams holds the query parameters of the "/openrtb2/amp" request keyed by name, e.g. "tag_id" and "curl",
+	// it is nil for other endpoints. Parameters updated or deleted by mutations are written back to the Request URL.
+	AmpParams map[string]string
+}
+
+// IsAmp tells whether the request is received by the "/openrtb2/amp" endpoint.
+func (p EntrypointPayload) IsAmp() bool {
+	return p.AmpParams != nil
 }
@@ -2,14 +2,17 @@ package hookstage
 
 import (
 	"encoding/json"
+	"net/http"
 
 	"github.com/prebid/prebid-server/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/openrtb_ext"
 )
 
 // HookResult represents the result of execution the concrete hook instance.
 type HookResult[T any] struct {
 	Reject        bool         // true value indicates rejection of the program execution at the specific stage
 	NbrCode       int          // hook must provide NbrCode if the field Reject set to true
+	StopGroup     bool         // true value skips the remaining hooks of the current group, the next groups are executed as usual
 	Message       string       // holds arbitrary message added by hook
 	ChangeSet     ChangeSet[T] // set of changes the module wants to apply to hook payload in case of successful execution
 	Errors        []string
@@ -17,6 +20,25 @@ type HookResult[T any] struct {
 	DebugMessages []string
 	AnalyticsTags hookanalytics.Analytics
 	ModuleContext ModuleContext // holds values that the module wants to pass to itself at later stages
+	HTTPResponse  *HTTPResponse // optional response returned to client if request rejected at the entrypoint stage
+	// CachedResponse is an optional complete response, e.g. cached earlier by the module, returned to client
+	// at the entrypoint stage instead of running the auction. Unlike rejection, it is treated as a successful response.
+	// The remaining entrypoint hooks and later stages are not executed. The field is ignored at other stages.
+	CachedResponse *HTTPResponse
+	// HttpCalls holds outbound HTTP calls made by the module,
+	// added to the response.ext.debug.httpcalls under the module code if debug is allowed
+	HttpCalls []*openrtb_ext.ExtHttpCall
+	// NonBids holds the reason codes of the bids removed by the hook, e.g. with the ChangeSet,
+	// reported in the response.ext.seatnonbid of the bidder. The field is used at the bidder-level stages only.
+	NonBids []openrtb_ext.NonBid
+}
+
+// HTTPResponse represents a custom HTTP response the hook wants to return to client instead of the default one.
+type HTTPResponse struct {
+	StatusCode int
+	Body       []byte
+	// Header holds the optional response headers, the Content-Type defaults to "application/json" if not set
+	Header http.Header
 }
 
 // ModuleInvocationContext holds data passed to the module hook during invocation.
@@ -31,4 +53,14 @@ type ModuleInvocationContext struct {
 
 // ModuleContext holds arbitrary data passed between module hooks at different stages.
 // We use interface as we do not know exactly how the modules will use their inner context.
+//
+// The context returned by a hook doesn't replace the context saved earlier, it is merged with it key by key:
+// the returned keys overwrite the saved values of the same keys, the saved keys absent in the returned context are preserved.
+// To contribute to the value of a key instead of overwriting it, the value should be of the MergedContext type.
 type ModuleContext map[string]interface{}
+
+// MergedContext is a value of the ModuleContext merged with the MergedContext saved under the same key
+// following the rules of the ModuleContext merge, nested MergedContext values are merged as well.
+// It allows hooks executed concurrently (e.g. bidder-request hooks of different bidders)
+// or at different stages to accumulate data under a single key without clobbering each other.
+type MergedContext map[string]interface{}
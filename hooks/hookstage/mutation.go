@@ -1,18 +1,29 @@
 package hookstage
 
+import "sort"
+
 type MutationType int
 
 const (
 	MutationAdd MutationType = iota
 	MutationUpdate
 	MutationDelete
+	// MutationInjectBid adds a synthetic bid which wasn't returned by the bidder.
+	MutationInjectBid
+	// MutationUpdateAdM rewrites the markup of a bid returned by the bidder.
+	MutationUpdateAdM
+	// MutationUpdateBidType corrects the type of a bid returned by the bidder.
+	MutationUpdateBidType
 )
 
 func (mt MutationType) String() string {
 	if v, ok := map[MutationType]string{
-		MutationAdd:    "add",
-		MutationUpdate: "update",
-		MutationDelete: "delete",
+		MutationAdd:           "add",
+		MutationUpdate:        "update",
+		MutationDelete:        "delete",
+		MutationInjectBid:     "inject_bid",
+		MutationUpdateAdM:     "update_adm",
+		MutationUpdateBidType: "update_bid_type",
 	}[mt]; ok {
 		return v
 	}
@@ -23,9 +34,10 @@ func (mt MutationType) String() string {
 type MutationFunc[T any] func(T) (T, error)
 
 type Mutation[T any] struct {
-	mutType MutationType
-	key     []string        // key indicates path to the modified field
-	fn      MutationFunc[T] // fn actual function that makes changes to payload
+	mutType  MutationType
+	key      []string        // key indicates path to the modified field
+	fn       MutationFunc[T] // fn actual function that makes changes to payload
+	priority int             // priority defines the order of the mutation within the ChangeSet
 }
 
 func (m Mutation[T]) Type() MutationType {
@@ -36,6 +48,10 @@ func (m Mutation[T]) Key() []string {
 	return m.key
 }
 
+func (m Mutation[T]) Priority() int {
+	return m.priority
+}
+
 func (m Mutation[T]) Apply(p T) (T, error) {
 	return m.fn(p)
 }
@@ -44,11 +60,38 @@ type ChangeSet[T any] struct {
 	muts []Mutation[T]
 }
 
+// Mutations returns the mutations in the order they are applied:
+// mutations with higher priority come first, equal priorities keep the insertion order.
 func (c *ChangeSet[T]) Mutations() []Mutation[T] {
-	return c.muts
+	prioritized := false
+	for _, mut := range c.muts {
+		if mut.priority != 0 {
+			prioritized = true
+			break
+		}
+	}
+	if !prioritized {
+		return c.muts
+	}
+
+	muts := make([]Mutation[T], len(c.muts))
+	copy(muts, c.muts)
+	sort.SliceStable(muts, func(i, j int) bool {
+		return muts[i].priority > muts[j].priority
+	})
+	return muts
 }
 
 func (c *ChangeSet[T]) AddMutation(fn MutationFunc[T], t MutationType, k ...string) *ChangeSet[T] {
-	c.muts = append(c.muts, Mutation[T]{fn: fn, mutType: t, key: k})
+	return c.AddMutationWithPriority(fn, 0, t, k...)
+}
+
+// AddMutationWithPriority adds a mutation applied before the mutations of the ChangeSet having lower priority,
+// mutations added with AddMutation have zero priority.
+//
+// Priorities order only the mutations returned by a single hook. Across hooks, mutations are applied
+// following the group and hook sequence of the execution plan first, and priority second.
+func (c *ChangeSet[T]) AddMutationWithPriority(fn MutationFunc[T], priority int, t MutationType, k ...string) *ChangeSet[T] {
+	c.muts = append(c.muts, Mutation[T]{fn: fn, mutType: t, key: k, priority: priority})
 	return c
 }
@@ -0,0 +1,57 @@
+package hookstage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeSetMutationsOrder(t *testing.T) {
+	testCases := []struct {
+		description  string
+		addMutations func(c *ChangeSet[[]string])
+		expectedKeys []string
+	}{
+		{
+			description: "Insertion order kept without priorities",
+			addMutations: func(c *ChangeSet[[]string]) {
+				c.AddMutation(nil, MutationUpdate, "a")
+				c.AddMutation(nil, MutationUpdate, "b")
+				c.AddMutation(nil, MutationUpdate, "c")
+			},
+			expectedKeys: []string{"a", "b", "c"},
+		},
+		{
+			description: "Higher priority applied first",
+			addMutations: func(c *ChangeSet[[]string]) {
+				c.AddMutation(nil, MutationUpdate, "a")
+				c.AddMutationWithPriority(nil, -1, MutationUpdate, "b")
+				c.AddMutationWithPriority(nil, 10, MutationUpdate, "c")
+			},
+			expectedKeys: []string{"c", "a", "b"},
+		},
+		{
+			description: "Equal priorities keep insertion order",
+			addMutations: func(c *ChangeSet[[]string]) {
+				c.AddMutationWithPriority(nil, 1, MutationUpdate, "a")
+				c.AddMutation(nil, MutationUpdate, "b")
+				c.AddMutationWithPriority(nil, 1, MutationUpdate, "c")
+				c.AddMutation(nil, MutationUpdate, "d")
+			},
+			expectedKeys: []string{"a", "c", "b", "d"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[[]string]{}
+			test.addMutations(changeSet)
+
+			var keys []string
+			for _, mut := range changeSet.Mutations() {
+				keys = append(keys, mut.Key()...)
+			}
+			assert.Equal(t, test.expectedKeys, keys)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package hookstage
 
 import (
 	"context"
+	"net/http"
 )
 
 // RawAuctionRequest hooks are invoked only for "/openrtb2/auction"
@@ -22,6 +23,11 @@ type RawAuctionRequest interface {
 	) (HookResult[RawAuctionRequestPayload], error)
 }
 
-// RawAuctionRequestPayload represents a raw body of the openrtb2.BidRequest.
-// Hooks are allowed to modify body using mutations.
-type RawAuctionRequestPayload []byte
+// RawAuctionRequestPayload consists of a raw body of the openrtb2.BidRequest
+// and the headers of the HTTP request, including changes made by the entrypoint hooks.
+// Hooks are allowed to modify body using mutations,
+// changes of the headers are not propagated to the request.
+type RawAuctionRequestPayload struct {
+	Body   []byte
+	Header http.Header
+}
@@ -23,7 +23,7 @@ type RawBidderResponse interface {
 
 // RawBidderResponsePayload consists of a list of adapters.TypedBid
 // objects representing bids returned by a particular bidder.
-// Hooks are allowed to modify bids using mutations.
+// Hooks are allowed to modify bids and inject synthetic bids using mutations.
 type RawBidderResponsePayload struct {
 	Bids   []*adapters.TypedBid
 	Bidder string
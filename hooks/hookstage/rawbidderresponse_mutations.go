@@ -0,0 +1,102 @@
+package hookstage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/openrtb_ext"
+)
+
+func (c *ChangeSet[T]) RawBidderResponse() ChangeSetRawBidderResponse[T] {
+	return ChangeSetRawBidderResponse[T]{changeSet: c}
+}
+
+type ChangeSetRawBidderResponse[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+func (c ChangeSetRawBidderResponse[T]) Bids() ChangeSetBids[T] {
+	return ChangeSetBids[T]{changeSetRawBidderResponse: c}
+}
+
+type ChangeSetBids[T any] struct {
+	changeSetRawBidderResponse ChangeSetRawBidderResponse[T]
+}
+
+// Inject appends a synthetic bid, not returned by the bidder, to the bidder's response.
+// The bid must have a positive price and refer to an impression.
+func (c ChangeSetBids[T]) Inject(bid *adapters.TypedBid) {
+	c.changeSetRawBidderResponse.changeSet.AddMutation(func(p T) (T, error) {
+		payload, ok := any(p).(RawBidderResponsePayload)
+		if !ok {
+			return p, errors.New("failed to cast RawBidderResponsePayload")
+		}
+
+		if err := validateSyntheticBid(bid); err != nil {
+			return p, err
+		}
+
+		payload.Bids = append(payload.Bids, bid)
+		return any(payload).(T), nil
+	}, MutationInjectBid, "bidderresponse", "bids")
+}
+
+// UpdateAdM replaces the markup of the bid with the given ID, the bid is updated in place.
+// The markup is treated as an opaque string, so HTML, VAST and native JSON markup are all supported.
+// The mutation key holds the bid ID, e.g. "bidderresponse.bid.<ID>.adm", to identify the bid in debug output.
+func (c ChangeSetBids[T]) UpdateAdM(bidID string, adm string) {
+	c.changeSetRawBidderResponse.changeSet.AddMutation(func(p T) (T, error) {
+		payload, ok := any(p).(RawBidderResponsePayload)
+		if !ok {
+			return p, errors.New("failed to cast RawBidderResponsePayload")
+		}
+
+		for _, bid := range payload.Bids {
+			if bid != nil && bid.Bid != nil && bid.Bid.ID == bidID {
+				bid.Bid.AdM = adm
+				return p, nil
+			}
+		}
+		return p, fmt.Errorf("bid %s not found", bidID)
+	}, MutationUpdateAdM, "bidderresponse", "bid", bidID, "adm")
+}
+
+// UpdateBidType replaces the type of the bid with the given ID, the bid is updated in place.
+// It allows to correct the type misreported by the bidder, e.g. a native bid labeled as banner,
+// as the stage is executed before the bids are processed according to their type, native assets
+// of the bid corrected to native are enriched the same way as of bids reported as native by the bidder.
+// The mutation key holds the bid ID, e.g. "bidderresponse.bid.<ID>.type", to identify the bid in debug output.
+func (c ChangeSetBids[T]) UpdateBidType(bidID string, bidType openrtb_ext.BidType) {
+	c.changeSetRawBidderResponse.changeSet.AddMutation(func(p T) (T, error) {
+		payload, ok := any(p).(RawBidderResponsePayload)
+		if !ok {
+			return p, errors.New("failed to cast RawBidderResponsePayload")
+		}
+
+		if _, err := openrtb_ext.ParseBidType(string(bidType)); err != nil {
+			return p, err
+		}
+
+		for _, bid := range payload.Bids {
+			if bid != nil && bid.Bid != nil && bid.Bid.ID == bidID {
+				bid.BidType = bidType
+				return p, nil
+			}
+		}
+		return p, fmt.Errorf("bid %s not found", bidID)
+	}, MutationUpdateBidType, "bidderresponse", "bid", bidID, "type")
+}
+
+func validateSyntheticBid(bid *adapters.TypedBid) error {
+	if bid == nil || bid.Bid == nil {
+		return errors.New("empty synthetic bid provided")
+	}
+	if bid.Bid.Price <= 0 {
+		return errors.New("synthetic bid must have a positive price")
+	}
+	if bid.Bid.ImpID == "" {
+		return errors.New("synthetic bid must have an imp ID")
+	}
+	return nil
+}
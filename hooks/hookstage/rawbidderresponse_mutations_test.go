@@ -0,0 +1,182 @@
+package hookstage
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawBidderResponseInjectBid(t *testing.T) {
+	bidderBid := &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "1", ImpID: "imp", Price: 1}}
+
+	testCases := []struct {
+		description      string
+		givenBid         *adapters.TypedBid
+		expectedBids     []*adapters.TypedBid
+		expectedErrorMsg string
+	}{
+		{
+			description:  "Synthetic bid appended",
+			givenBid:     &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "2", ImpID: "imp", Price: 0.5}},
+			expectedBids: []*adapters.TypedBid{bidderBid, {Bid: &openrtb2.Bid{ID: "2", ImpID: "imp", Price: 0.5}}},
+		},
+		{
+			description:      "Error if bid empty",
+			givenBid:         &adapters.TypedBid{},
+			expectedBids:     []*adapters.TypedBid{bidderBid},
+			expectedErrorMsg: "empty synthetic bid provided",
+		},
+		{
+			description:      "Error if bid price not set",
+			givenBid:         &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "2", ImpID: "imp"}},
+			expectedBids:     []*adapters.TypedBid{bidderBid},
+			expectedErrorMsg: "synthetic bid must have a positive price",
+		},
+		{
+			description:      "Error if bid imp ID not set",
+			givenBid:         &adapters.TypedBid{Bid: &openrtb2.Bid{ID: "2", Price: 0.5}},
+			expectedBids:     []*adapters.TypedBid{bidderBid},
+			expectedErrorMsg: "synthetic bid must have an imp ID",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			changeSet := &ChangeSet[RawBidderResponsePayload]{}
+			changeSet.RawBidderResponse().Bids().Inject(test.givenBid)
+			mutation := changeSet.Mutations()[0]
+
+			payload, err := mutation.Apply(RawBidderResponsePayload{Bids: []*adapters.TypedBid{bidderBid}, Bidder: "appnexus"})
+
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, MutationInjectBid, mutation.Type())
+			assert.Equal(t, []string{"bidderresponse", "bids"}, mutation.Key())
+			assert.Equal(t, test.expectedBids, payload.Bids)
+		})
+	}
+}
+
+func TestRawBidderResponseUpdateAdM(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenBidID       string
+		givenAdM         string
+		expectedAdMs     []string
+		expectedErrorMsg string
+	}{
+		{
+			description:  "HTML markup updated",
+			givenBidID:   "banner",
+			givenAdM:     `<div>ad</div><script src="viewability.js"></script>`,
+			expectedAdMs: []string{`<div>ad</div><script src="viewability.js"></script>`, "<VAST/>", `{"assets":[]}`},
+		},
+		{
+			description:  "VAST markup updated",
+			givenBidID:   "video",
+			givenAdM:     `<VAST version="4.0"><Ad/></VAST>`,
+			expectedAdMs: []string{"<div>ad</div>", `<VAST version="4.0"><Ad/></VAST>`, `{"assets":[]}`},
+		},
+		{
+			description:  "Native markup updated",
+			givenBidID:   "native",
+			givenAdM:     `{"assets":[{"id":1}],"eventtrackers":[{"event":1,"method":1,"url":"https://tracker.com"}]}`,
+			expectedAdMs: []string{"<div>ad</div>", "<VAST/>", `{"assets":[{"id":1}],"eventtrackers":[{"event":1,"method":1,"url":"https://tracker.com"}]}`},
+		},
+		{
+			description:      "Error if bid not found",
+			givenBidID:       "other",
+			givenAdM:         "<div>other</div>",
+			expectedAdMs:     []string{"<div>ad</div>", "<VAST/>", `{"assets":[]}`},
+			expectedErrorMsg: "bid other not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bids := []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "banner", ImpID: "imp", AdM: "<div>ad</div>"}, BidType: "banner"},
+				nil,
+				{Bid: &openrtb2.Bid{ID: "video", ImpID: "imp", AdM: "<VAST/>"}, BidType: "video"},
+				{Bid: &openrtb2.Bid{ID: "native", ImpID: "imp", AdM: `{"assets":[]}`}, BidType: "native"},
+			}
+
+			changeSet := &ChangeSet[RawBidderResponsePayload]{}
+			changeSet.RawBidderResponse().Bids().UpdateAdM(test.givenBidID, test.givenAdM)
+			mutation := changeSet.Mutations()[0]
+
+			_, err := mutation.Apply(RawBidderResponsePayload{Bids: bids, Bidder: "appnexus"})
+
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, MutationUpdateAdM, mutation.Type())
+			assert.Equal(t, []string{"bidderresponse", "bid", test.givenBidID, "adm"}, mutation.Key())
+			assert.Equal(t, test.expectedAdMs, []string{bids[0].Bid.AdM, bids[2].Bid.AdM, bids[3].Bid.AdM})
+		})
+	}
+}
+
+func TestRawBidderResponseUpdateBidType(t *testing.T) {
+	testCases := []struct {
+		description      string
+		givenBidID       string
+		givenBidType     openrtb_ext.BidType
+		expectedBidTypes []openrtb_ext.BidType
+		expectedErrorMsg string
+	}{
+		{
+			description:      "Misreported banner corrected to native",
+			givenBidID:       "native",
+			givenBidType:     openrtb_ext.BidTypeNative,
+			expectedBidTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeNative},
+		},
+		{
+			description:      "Error if bid type invalid",
+			givenBidID:       "native",
+			givenBidType:     "unknown",
+			expectedBidTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeBanner},
+			expectedErrorMsg: "invalid BidType: unknown",
+		},
+		{
+			description:      "Error if bid not found",
+			givenBidID:       "other",
+			givenBidType:     openrtb_ext.BidTypeVideo,
+			expectedBidTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeBanner},
+			expectedErrorMsg: "bid other not found",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bids := []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "banner", ImpID: "imp", AdM: "<div>ad</div>"}, BidType: openrtb_ext.BidTypeBanner},
+				nil,
+				{Bid: &openrtb2.Bid{ID: "native", ImpID: "imp", AdM: `{"assets":[]}`}, BidType: openrtb_ext.BidTypeBanner},
+			}
+
+			changeSet := &ChangeSet[RawBidderResponsePayload]{}
+			changeSet.RawBidderResponse().Bids().UpdateBidType(test.givenBidID, test.givenBidType)
+			mutation := changeSet.Mutations()[0]
+
+			_, err := mutation.Apply(RawBidderResponsePayload{Bids: bids, Bidder: "appnexus"})
+
+			if test.expectedErrorMsg != "" {
+				assert.EqualError(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, MutationUpdateBidType, mutation.Type())
+			assert.Equal(t, []string{"bidderresponse", "bid", test.givenBidID, "type"}, mutation.Key())
+			assert.Equal(t, test.expectedBidTypes, []openrtb_ext.BidType{bids[0].BidType, bids[2].BidType})
+		})
+	}
+}
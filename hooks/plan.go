@@ -16,18 +16,26 @@ const (
 	StageRawAuctionRequest        Stage = "raw_auction_request"
 	StageProcessedAuctionRequest  Stage = "processed_auction_request"
 	StageBidderRequest            Stage = "bidder_request"
+	StageBidderHttpRequest        Stage = "bidder_http_request"
 	StageRawBidderResponse        Stage = "raw_bidder_response"
 	StageAllProcessedBidResponses Stage = "all_processed_bid_responses"
 	StageAuctionResponse          Stage = "auction_response"
 )
 
+// StageEntrypointAccount invokes once more the entrypoint hooks implementing hookstage.EntrypointAccount
+// when the account is resolved. It has no execution plan of its own, the entrypoint plan is used.
+const StageEntrypointAccount Stage = "entrypoint_account"
+
+// HookImplCodeWildcard used as hook_impl_code of the hook sequence entry includes
+// the hook the module registers for the stage, if any. Such hooks are identified by the stage name.
+const HookImplCodeWildcard = "*"
+
 func (s Stage) String() string {
 	return string(s)
 }
 
 func (s Stage) IsRejectable() bool {
-	return s != StageAllProcessedBidResponses &&
-		s != StageAuctionResponse
+	return s != StageAuctionResponse
 }
 
 // ExecutionPlanBuilder is the interface that provides methods
@@ -38,6 +46,7 @@ type ExecutionPlanBuilder interface {
 	PlanForRawAuctionStage(endpoint string, account *config.Account) Plan[hookstage.RawAuctionRequest]
 	PlanForProcessedAuctionStage(endpoint string, account *config.Account) Plan[hookstage.ProcessedAuctionRequest]
 	PlanForBidderRequestStage(endpoint string, account *config.Account) Plan[hookstage.BidderRequest]
+	PlanForBidderHttpRequestStage(endpoint string, account *config.Account) Plan[hookstage.BidderHttpRequest]
 	PlanForRawBidderResponseStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponse]
 	PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses]
 	PlanForAuctionResponseStage(endpoint string, account *config.Account) Plan[hookstage.AuctionResponse]
@@ -46,12 +55,38 @@ type ExecutionPlanBuilder interface {
 // Plan represents a slice of groups of hooks of a specific type grouped in the established order.
 type Plan[T any] []Group[T]
 
+// StageTimeout returns the max duration of the whole stage, which is the smallest
+// stage timeout of the plan groups, zero means the stage duration is not limited.
+func (p Plan[T]) StageTimeout() time.Duration {
+	var timeout time.Duration
+	for _, group := range p {
+		if group.StageTimeout > 0 && (timeout == 0 || group.StageTimeout < timeout) {
+			timeout = group.StageTimeout
+		}
+	}
+	return timeout
+}
+
 // Group represents a slice of hooks sorted in the established order.
 type Group[T any] struct {
 	// Timeout specifies the max duration in milliseconds that a group of hooks is allowed to run.
 	Timeout time.Duration
 	// Hooks holds a slice of HookWrapper of a specific type.
 	Hooks []HookWrapper[T]
+	// Unresolved holds hooks referenced by the execution plan but not registered in the hook repository.
+	Unresolved []UnresolvedHook
+	// StageTimeout is the max duration of the stage set by the execution plan the group comes from.
+	// Zero value means no limit.
+	StageTimeout time.Duration
+}
+
+// UnresolvedHook identifies a hook referenced by the execution plan that cannot be executed,
+// because the module providing it is not registered for the stage.
+type UnresolvedHook struct {
+	// Module holds a name of the module expected to provide the hook.
+	Module string
+	// Code is the hook code assigned via the hook execution plan.
+	Code string
 }
 
 // HookWrapper wraps Hook representing specific hook interface
@@ -126,6 +161,16 @@ func (p PlanBuilder) PlanForBidderRequestStage(endpoint string, account *config.
 	)
 }
 
+func (p PlanBuilder) PlanForBidderHttpRequestStage(endpoint string, account *config.Account) Plan[hookstage.BidderHttpRequest] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageBidderHttpRequest,
+		p.repo.GetBidderHttpRequestHook,
+	)
+}
+
 func (p PlanBuilder) PlanForRawBidderResponseStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponse] {
 	return getMergedPlan(
 		p.hooks,
@@ -165,22 +210,46 @@ func getMergedPlan[T any](
 	stage Stage,
 	getHookFn hookFn[T],
 ) Plan[T] {
+	// stages disabled for the account skip both host and account level hooks
+	if account != nil && account.Hooks.IsStageDisabled(stage.String()) {
+		return Plan[T]{}
+	}
+
 	accountPlan := cfg.DefaultAccountExecutionPlan
 	if account != nil && account.Hooks.ExecutionPlan.Endpoints != nil {
 		accountPlan = account.Hooks.ExecutionPlan
 	}
 
-	plan := getPlan(getHookFn, cfg.HostExecutionPlan, endpoint, stage)
-	plan = append(plan, getPlan(getHookFn, accountPlan, endpoint, stage)...)
+	plan := getPlan(getHookFn, cfg.Modules, cfg.HostExecutionPlan, endpoint, stage)
+	plan = append(plan, getPlan(getHookFn, cfg.Modules, accountPlan, endpoint, stage)...)
+
+	if account != nil {
+		applyTimeoutMultiplier(plan, account.Hooks.TimeoutMultiplier)
+	}
 
 	return plan
 }
 
-func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint string, stage Stage) Plan[T] {
-	plan := make(Plan[T], 0, len(cfg.Endpoints[endpoint].Stages[stage.String()].Groups))
-	for _, groupCfg := range cfg.Endpoints[endpoint].Stages[stage.String()].Groups {
-		group := getGroup(getHookFn, groupCfg)
-		if len(group.Hooks) > 0 {
+// applyTimeoutMultiplier scales timeouts of all plan groups by the provided multiplier.
+// Non-positive multiplier leaves the plan unchanged.
+func applyTimeoutMultiplier[T any](plan Plan[T], multiplier float64) {
+	if multiplier <= 0 || multiplier == 1 {
+		return
+	}
+
+	for i := range plan {
+		plan[i].Timeout = time.Duration(float64(plan[i].Timeout) * multiplier)
+		plan[i].StageTimeout = time.Duration(float64(plan[i].StageTimeout) * multiplier)
+	}
+}
+
+func getPlan[T any](getHookFn hookFn[T], modules config.Modules, cfg config.HookExecutionPlan, endpoint string, stage Stage) Plan[T] {
+	stageCfg := cfg.Endpoints[endpoint].Stages[stage.String()]
+	plan := make(Plan[T], 0, len(stageCfg.Groups))
+	for _, groupCfg := range stageCfg.Groups {
+		group := getGroup(getHookFn, modules, groupCfg, stage)
+		group.StageTimeout = time.Duration(stageCfg.Timeout) * time.Millisecond
+		if len(group.Hooks) > 0 || len(group.Unresolved) > 0 {
 			plan = append(plan, group)
 		}
 	}
@@ -188,17 +257,46 @@ func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint
 	return plan
 }
 
-func getGroup[T any](getHookFn hookFn[T], cfg config.HookExecutionGroup) Group[T] {
+func getGroup[T any](getHookFn hookFn[T], modules config.Modules, cfg config.HookExecutionGroup, stage Stage) Group[T] {
 	group := Group[T]{
 		Timeout: time.Duration(cfg.Timeout) * time.Millisecond,
 		Hooks:   make([]HookWrapper[T], 0, len(cfg.HookSequence)),
 	}
 
+	// a module included by wildcard and explicitly in the same group is executed once, at its first occurrence
+	explicitModules := make(map[string]struct{})
+	wildcardModules := make(map[string]struct{})
+
 	for _, hookCfg := range cfg.HookSequence {
+		// modules disabled by config are skipped even if referenced by the execution plan
+		if modules.IsModuleDisabled(hookCfg.ModuleCode) {
+			continue
+		}
+
+		if hookCfg.HookImplCode == HookImplCodeWildcard {
+			_, isExplicit := explicitModules[hookCfg.ModuleCode]
+			_, isWildcard := wildcardModules[hookCfg.ModuleCode]
+			if isExplicit || isWildcard {
+				continue
+			}
+			// modules without hook for the stage are skipped, not reported as unresolved
+			if h, ok := getHookFn(hookCfg.ModuleCode); ok {
+				wildcardModules[hookCfg.ModuleCode] = struct{}{}
+				group.Hooks = append(group.Hooks, HookWrapper[T]{Module: hookCfg.ModuleCode, Code: stage.String(), Hook: h})
+			}
+			continue
+		}
+
+		if _, isWildcard := wildcardModules[hookCfg.ModuleCode]; isWildcard {
+			continue
+		}
+
 		if h, ok := getHookFn(hookCfg.ModuleCode); ok {
+			explicitModules[hookCfg.ModuleCode] = struct{}{}
 			group.Hooks = append(group.Hooks, HookWrapper[T]{Module: hookCfg.ModuleCode, Code: hookCfg.HookImplCode, Hook: h})
 		} else {
 			glog.Warningf("Not found hook while building hook execution plan: %s %s", hookCfg.ModuleCode, hookCfg.HookImplCode)
+			group.Unresolved = append(group.Unresolved, UnresolvedHook{Module: hookCfg.ModuleCode, Code: hookCfg.HookImplCode})
 		}
 	}
 
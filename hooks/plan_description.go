@@ -0,0 +1,72 @@
+package hooks
+
+import (
+	"github.com/prebid/prebid-server/config"
+)
+
+// PlanDescription is a serializable representation of the hook execution plans
+// of all stages resolved for a specific endpoint and account.
+// It is intended for read-only inspection of the plan, e.g. by the debug tooling.
+type PlanDescription struct {
+	Endpoint string             `json:"endpoint"`
+	Stages   []StageDescription `json:"stages"`
+}
+
+// StageDescription describes the groups of hooks executed at the stage in the established order.
+type StageDescription struct {
+	Stage  Stage              `json:"stage"`
+	Groups []GroupDescription `json:"groups"`
+}
+
+// GroupDescription describes the group of hooks executed in parallel.
+type GroupDescription struct {
+	TimeoutMillis int64             `json:"timeout"`
+	Hooks         []HookDescription `json:"hook_sequence"`
+}
+
+// HookDescription identifies the hook by the module and hook codes.
+type HookDescription struct {
+	ModuleCode   string `json:"module_code"`
+	HookImplCode string `json:"hook_impl_code"`
+}
+
+// DescribePlan returns the description of the execution plans of all stages for the endpoint and account.
+// Plans are retrieved from the builder, so the description reflects the same merge precedence
+// as the actual hook execution, the entrypoint stage plan never depends on the account.
+func DescribePlan(builder ExecutionPlanBuilder, endpoint string, account *config.Account) PlanDescription {
+	return PlanDescription{
+		Endpoint: endpoint,
+		Stages: []StageDescription{
+			describeStage(StageEntrypoint, builder.PlanForEntrypointStage(endpoint)),
+			describeStage(StageRawAuctionRequest, builder.PlanForRawAuctionStage(endpoint, account)),
+			describeStage(StageProcessedAuctionRequest, builder.PlanForProcessedAuctionStage(endpoint, account)),
+			describeStage(StageBidderRequest, builder.PlanForBidderRequestStage(endpoint, account)),
+			describeStage(StageBidderHttpRequest, builder.PlanForBidderHttpRequestStage(endpoint, account)),
+			describeStage(StageRawBidderResponse, builder.PlanForRawBidderResponseStage(endpoint, account)),
+			describeStage(StageAllProcessedBidResponses, builder.PlanForAllProcessedBidResponsesStage(endpoint, account)),
+			describeStage(StageAuctionResponse, builder.PlanForAuctionResponseStage(endpoint, account)),
+		},
+	}
+}
+
+func describeStage[T any](stage Stage, plan Plan[T]) StageDescription {
+	description := StageDescription{
+		Stage:  stage,
+		Groups: make([]GroupDescription, 0, len(plan)),
+	}
+
+	for _, group := range plan {
+		groupDescription := GroupDescription{
+			TimeoutMillis: group.Timeout.Milliseconds(),
+			Hooks:         make([]HookDescription, 0, len(group.Hooks)),
+		}
+
+		for _, hook := range group.Hooks {
+			groupDescription.Hooks = append(groupDescription.Hooks, HookDescription{ModuleCode: hook.Module, HookImplCode: hook.Code})
+		}
+
+		description.Groups = append(description.Groups, groupDescription)
+	}
+
+	return description
+}
@@ -0,0 +1,115 @@
+package hooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribePlan(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "bar"}, {"module_code": "ortb2blocking", "hook_impl_code": "block_request"}]}`
+	const group3 string = `{"timeout": 15, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "baz"}]}`
+	const hostPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"entrypoint": {"groups": [` + group1 + `]}, "raw_auction_request": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"entrypoint": {"groups": [` + group2 + `]}, "raw_auction_request": {"groups": [` + group2 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"groups": [` + group3 + `]}}}}}, "timeout_multiplier": 2}`
+
+	hooks := map[string]interface{}{
+		"foobar":        fakeAllStagesHook{},
+		"ortb2blocking": fakeAllStagesHook{},
+		"prebid":        fakeAllStagesHook{},
+	}
+
+	entrypointStage := StageDescription{
+		Stage: StageEntrypoint,
+		Groups: []GroupDescription{
+			{TimeoutMillis: 5, Hooks: []HookDescription{{ModuleCode: "foobar", HookImplCode: "foo"}}},
+			{TimeoutMillis: 10, Hooks: []HookDescription{{ModuleCode: "foobar", HookImplCode: "bar"}, {ModuleCode: "ortb2blocking", HookImplCode: "block_request"}}},
+		},
+	}
+	emptyStages := []StageDescription{
+		{Stage: StageProcessedAuctionRequest, Groups: []GroupDescription{}},
+		{Stage: StageBidderRequest, Groups: []GroupDescription{}},
+		{Stage: StageBidderHttpRequest, Groups: []GroupDescription{}},
+		{Stage: StageRawBidderResponse, Groups: []GroupDescription{}},
+		{Stage: StageAllProcessedBidResponses, Groups: []GroupDescription{}},
+		{Stage: StageAuctionResponse, Groups: []GroupDescription{}},
+	}
+
+	testCases := []struct {
+		description          string
+		givenAccountPlanData []byte
+		expectedDescription  PlanDescription
+	}{
+		{
+			description:          "Account-specific execution plan rewrites default-account plan and host plan comes first",
+			givenAccountPlanData: []byte(accountPlanData),
+			expectedDescription: PlanDescription{
+				Endpoint: "/openrtb2/auction",
+				Stages: append([]StageDescription{
+					// entrypoint stage plan does not depend on account
+					entrypointStage,
+					{
+						Stage: StageRawAuctionRequest,
+						Groups: []GroupDescription{
+							{TimeoutMillis: 10, Hooks: []HookDescription{{ModuleCode: "foobar", HookImplCode: "foo"}}},
+							{TimeoutMillis: 30, Hooks: []HookDescription{{ModuleCode: "prebid", HookImplCode: "baz"}}},
+						},
+					},
+				}, emptyStages...),
+			},
+		},
+		{
+			description:          "Default-account execution plan used if account has no plan",
+			givenAccountPlanData: []byte(`{}`),
+			expectedDescription: PlanDescription{
+				Endpoint: "/openrtb2/auction",
+				Stages: append([]StageDescription{
+					entrypointStage,
+					{
+						Stage: StageRawAuctionRequest,
+						Groups: []GroupDescription{
+							{TimeoutMillis: 5, Hooks: []HookDescription{{ModuleCode: "foobar", HookImplCode: "foo"}}},
+							{TimeoutMillis: 10, Hooks: []HookDescription{{ModuleCode: "foobar", HookImplCode: "bar"}, {ModuleCode: "ortb2blocking", HookImplCode: "block_request"}}},
+						},
+					},
+				}, emptyStages...),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			account := new(config.Account)
+			if err := json.Unmarshal(test.givenAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(hooks, []byte(hostPlanData), []byte(defaultAccountPlanData))
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				description := DescribePlan(planBuilder, "/openrtb2/auction", account)
+				assert.Equal(t, test.expectedDescription, description)
+			}
+		})
+	}
+}
+
+func TestDescribePlanSerialization(t *testing.T) {
+	description := DescribePlan(EmptyPlanBuilder{}, "/openrtb2/amp", nil)
+
+	data, err := json.Marshal(description)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"endpoint": "/openrtb2/amp", "stages": [
+		{"stage": "entrypoint", "groups": []},
+		{"stage": "raw_auction_request", "groups": []},
+		{"stage": "processed_auction_request", "groups": []},
+		{"stage": "bidder_request", "groups": []},
+		{"stage": "bidder_http_request", "groups": []},
+		{"stage": "raw_bidder_response", "groups": []},
+		{"stage": "all_processed_bid_responses", "groups": []},
+		{"stage": "auction_response", "groups": []}
+	]}`, string(data))
+}
@@ -3,6 +3,7 @@ package hooks
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -115,12 +116,39 @@ func TestPlanForEntrypointStage(t *testing.T) {
 			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
 			expectedPlan:                Plan[hookstage.Entrypoint]{},
 		},
-		"Empty plan if hook repository empty": {
+		"Hooks reported as unresolved if hook repository empty": {
 			givenEndpoint:               "/openrtb2/auction",
 			givenHostPlanData:           []byte(planData1),
 			givenDefaultAccountPlanData: []byte(`{}`),
 			givenHooks:                  nil,
-			expectedPlan:                Plan[hookstage.Entrypoint]{},
+			expectedPlan: Plan[hookstage.Entrypoint]{
+				Group[hookstage.Entrypoint]{
+					Timeout:    5 * time.Millisecond,
+					Hooks:      []HookWrapper[hookstage.Entrypoint]{},
+					Unresolved: []UnresolvedHook{{Module: "foobar", Code: "foo"}},
+				},
+			},
+		},
+		"Unresolved hooks collected along with registered ones": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(`{}`),
+			givenDefaultAccountPlanData: []byte(planData2),
+			givenHooks:                  map[string]interface{}{"foobar": fakeEntrypointHook{}},
+			expectedPlan: Plan[hookstage.Entrypoint]{
+				Group[hookstage.Entrypoint]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Entrypoint]{
+						{Module: "foobar", Code: "bar", Hook: fakeEntrypointHook{}},
+					},
+					Unresolved: []UnresolvedHook{{Module: "ortb2blocking", Code: "block_request"}},
+				},
+				Group[hookstage.Entrypoint]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.Entrypoint]{
+						{Module: "foobar", Code: "foo", Hook: fakeEntrypointHook{}},
+					},
+				},
+			},
 		},
 	}
 
@@ -770,9 +798,386 @@ func TestPlanForAuctionResponseStage(t *testing.T) {
 	}
 }
 
+func TestPlanWithAccountTimeoutMultiplier(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 15, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "baz"}]}`
+	const hostPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"groups": [` + group1 + `]}}}}}`
+	const accountPlan string = `"execution_plan": {"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"groups": [` + group2 + `]}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar": fakeRawAuctionHook{},
+		"prebid": fakeRawAuctionHook{},
+	}
+
+	testCases := map[string]struct {
+		giveAccountData []byte
+		expectedPlan    Plan[hookstage.RawAuctionRequest]
+	}{
+		"Group timeouts doubled with 2x multiplier": {
+			giveAccountData: []byte(`{"timeout_multiplier": 2, ` + accountPlan + `}`),
+			expectedPlan: Plan[hookstage.RawAuctionRequest]{
+				Group[hookstage.RawAuctionRequest]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawAuctionRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}},
+					},
+				},
+				Group[hookstage.RawAuctionRequest]{
+					Timeout: 30 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawAuctionRequest]{
+						{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+					},
+				},
+			},
+		},
+		"Group timeouts unchanged without multiplier": {
+			giveAccountData: []byte(`{` + accountPlan + `}`),
+			expectedPlan: Plan[hookstage.RawAuctionRequest]{
+				Group[hookstage.RawAuctionRequest]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawAuctionRequest]{
+						{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}},
+					},
+				},
+				Group[hookstage.RawAuctionRequest]{
+					Timeout: 15 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawAuctionRequest]{
+						{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := json.Unmarshal(test.giveAccountData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(hooks, []byte(hostPlanData), []byte(`{}`))
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForRawAuctionStage("/openrtb2/auction", account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
+func TestPlanStageTimeout(t *testing.T) {
+	const group string = `{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	hooks := map[string]interface{}{"foobar": fakeRawAuctionHook{}}
+
+	testCases := map[string]struct {
+		givenHostPlanData    string
+		givenAccountPlanData string
+		givenAccountData     string
+		expectedStageTimeout time.Duration
+	}{
+		"No stage timeout by default": {
+			givenHostPlanData:    `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"groups": [` + group + `]}}}}}`,
+			givenAccountPlanData: `{}`,
+			givenAccountData:     `{}`,
+			expectedStageTimeout: 0,
+		},
+		"Stage timeout taken from plan": {
+			givenHostPlanData:    `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"timeout": 50, "groups": [` + group + `]}}}}}`,
+			givenAccountPlanData: `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"groups": [` + group + `]}}}}}`,
+			givenAccountData:     `{}`,
+			expectedStageTimeout: 50 * time.Millisecond,
+		},
+		"Smallest stage timeout of host and account plans used": {
+			givenHostPlanData:    `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"timeout": 50, "groups": [` + group + `]}}}}}`,
+			givenAccountPlanData: `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"timeout": 30, "groups": [` + group + `]}}}}}`,
+			givenAccountData:     `{}`,
+			expectedStageTimeout: 30 * time.Millisecond,
+		},
+		"Stage timeout scaled by account multiplier": {
+			givenHostPlanData:    `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"timeout": 50, "groups": [` + group + `]}}}}}`,
+			givenAccountPlanData: `{}`,
+			givenAccountData:     `{"timeout_multiplier": 2}`,
+			expectedStageTimeout: 100 * time.Millisecond,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := json.Unmarshal([]byte(test.givenAccountData), &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(hooks, []byte(test.givenHostPlanData), []byte(test.givenAccountPlanData))
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForRawAuctionStage("/openrtb2/auction", account)
+				assert.Equal(t, test.expectedStageTimeout, plan.StageTimeout())
+			}
+		})
+	}
+}
+
+func TestPlanWithDisabledModule(t *testing.T) {
+	const group string = `{"timeout": 5, "hook_sequence": [{"module_code": "acme.foo", "hook_impl_code": "foo"}, {"module_code": "acme.bar", "hook_impl_code": "bar"}]}`
+	stages := []Stage{
+		StageEntrypoint,
+		StageRawAuctionRequest,
+		StageProcessedAuctionRequest,
+		StageBidderRequest,
+		StageRawBidderResponse,
+		StageAllProcessedBidResponses,
+		StageAuctionResponse,
+	}
+
+	stagesData := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		stagesData = append(stagesData, `"`+stage.String()+`": {"groups": [`+group+`]}`)
+	}
+	planData := []byte(`{"endpoints": {"/openrtb2/auction": {"stages": {` + strings.Join(stagesData, ",") + `}}}}`)
+
+	hooks := map[string]interface{}{
+		"acme.foo": fakeAllStagesHook{},
+		"acme.bar": fakeAllStagesHook{},
+	}
+	modules := config.Modules{
+		"acme": {
+			"foo": map[string]interface{}{"enabled": true},
+			"bar": map[string]interface{}{"enabled": false},
+		},
+	}
+
+	planBuilder, err := getPlanBuilderWithModules(hooks, modules, planData, planData)
+	if !assert.NoError(t, err, "Failed to init hook execution plan builder") {
+		return
+	}
+
+	endpoint := "/openrtb2/auction"
+	account := &config.Account{}
+	plans := map[Stage][]string{
+		StageEntrypoint:               getPlanModules(planBuilder.PlanForEntrypointStage(endpoint)),
+		StageRawAuctionRequest:        getPlanModules(planBuilder.PlanForRawAuctionStage(endpoint, account)),
+		StageProcessedAuctionRequest:  getPlanModules(planBuilder.PlanForProcessedAuctionStage(endpoint, account)),
+		StageBidderRequest:            getPlanModules(planBuilder.PlanForBidderRequestStage(endpoint, account)),
+		StageRawBidderResponse:        getPlanModules(planBuilder.PlanForRawBidderResponseStage(endpoint, account)),
+		StageAllProcessedBidResponses: getPlanModules(planBuilder.PlanForAllProcessedBidResponsesStage(endpoint, account)),
+		StageAuctionResponse:          getPlanModules(planBuilder.PlanForAuctionResponseStage(endpoint, account)),
+	}
+
+	for _, stage := range stages {
+		assert.Equal(t, []string{"acme.foo", "acme.foo"}, plans[stage], "Disabled module included in %s stage plan.", stage)
+	}
+}
+
+func TestPlanWithAccountDisabledStages(t *testing.T) {
+	const group string = `{"timeout": 5, "hook_sequence": [{"module_code": "acme.foo", "hook_impl_code": "foo"}]}`
+	stages := []Stage{
+		StageRawAuctionRequest,
+		StageProcessedAuctionRequest,
+		StageBidderRequest,
+		StageRawBidderResponse,
+		StageAllProcessedBidResponses,
+		StageAuctionResponse,
+	}
+
+	stagesData := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		stagesData = append(stagesData, `"`+stage.String()+`": {"groups": [`+group+`]}`)
+	}
+	planData := `{"endpoints": {"/openrtb2/auction": {"stages": {` + strings.Join(stagesData, ",") + `}}}}`
+
+	hooks := map[string]interface{}{"acme.foo": fakeAllStagesHook{}}
+	planBuilder, err := getPlanBuilder(hooks, []byte(planData), []byte(`{}`))
+	if !assert.NoError(t, err, "Failed to init hook execution plan builder") {
+		return
+	}
+
+	account := new(config.Account)
+	accountData := []byte(`{"disabled_stages": ["all_processed_bid_responses", "auction_response"], "execution_plan": ` + planData + `}`)
+	if err := json.Unmarshal(accountData, &account.Hooks); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := "/openrtb2/auction"
+	plans := map[Stage][]string{
+		StageRawAuctionRequest:        getPlanModules(planBuilder.PlanForRawAuctionStage(endpoint, account)),
+		StageProcessedAuctionRequest:  getPlanModules(planBuilder.PlanForProcessedAuctionStage(endpoint, account)),
+		StageBidderRequest:            getPlanModules(planBuilder.PlanForBidderRequestStage(endpoint, account)),
+		StageRawBidderResponse:        getPlanModules(planBuilder.PlanForRawBidderResponseStage(endpoint, account)),
+		StageAllProcessedBidResponses: getPlanModules(planBuilder.PlanForAllProcessedBidResponsesStage(endpoint, account)),
+		StageAuctionResponse:          getPlanModules(planBuilder.PlanForAuctionResponseStage(endpoint, account)),
+	}
+
+	for _, stage := range stages {
+		if account.Hooks.IsStageDisabled(stage.String()) {
+			assert.Empty(t, plans[stage], "Hooks included in disabled %s stage plan.", stage)
+		} else {
+			assert.Equal(t, []string{"acme.foo", "acme.foo"}, plans[stage], "Host and account hooks expected in %s stage plan.", stage)
+		}
+	}
+
+	assert.Equal(t, []string{"acme.foo"}, getPlanModules(planBuilder.PlanForAuctionResponseStage(endpoint, nil)), "Stage should not be disabled without account.")
+}
+
+func TestPlanWithHooksDisabled(t *testing.T) {
+	const group string = `{"timeout": 5, "hook_sequence": [{"module_code": "acme.foo", "hook_impl_code": "foo"}]}`
+	stages := []Stage{
+		StageEntrypoint,
+		StageRawAuctionRequest,
+		StageProcessedAuctionRequest,
+		StageBidderRequest,
+		StageRawBidderResponse,
+		StageAllProcessedBidResponses,
+		StageAuctionResponse,
+	}
+
+	stagesData := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		stagesData = append(stagesData, `"`+stage.String()+`": {"groups": [`+group+`]}`)
+	}
+	planData := `{"endpoints": {"/openrtb2/auction": {"stages": {` + strings.Join(stagesData, ",") + `}}}}`
+
+	var hooksCfg config.Hooks
+	if err := json.Unmarshal([]byte(planData), &hooksCfg.HostExecutionPlan); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := NewHookRepository(map[string]interface{}{"acme.foo": fakeAllStagesHook{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := "/openrtb2/auction"
+	account := &config.Account{}
+	getPlans := func(builder ExecutionPlanBuilder) map[Stage][]string {
+		return map[Stage][]string{
+			StageEntrypoint:               getPlanModules(builder.PlanForEntrypointStage(endpoint)),
+			StageRawAuctionRequest:        getPlanModules(builder.PlanForRawAuctionStage(endpoint, account)),
+			StageProcessedAuctionRequest:  getPlanModules(builder.PlanForProcessedAuctionStage(endpoint, account)),
+			StageBidderRequest:            getPlanModules(builder.PlanForBidderRequestStage(endpoint, account)),
+			StageRawBidderResponse:        getPlanModules(builder.PlanForRawBidderResponseStage(endpoint, account)),
+			StageAllProcessedBidResponses: getPlanModules(builder.PlanForAllProcessedBidResponsesStage(endpoint, account)),
+			StageAuctionResponse:          getPlanModules(builder.PlanForAuctionResponseStage(endpoint, account)),
+		}
+	}
+
+	hooksCfg.Enabled = true
+	enabledPlans := getPlans(NewExecutionPlanBuilder(hooksCfg, repo))
+	for _, stage := range stages {
+		assert.Equal(t, []string{"acme.foo"}, enabledPlans[stage], "Host hooks expected in %s stage plan.", stage)
+	}
+
+	hooksCfg.Enabled = false
+	disabledPlans := getPlans(NewExecutionPlanBuilder(hooksCfg, repo))
+	assert.Equal(t, getPlans(EmptyPlanBuilder{}), disabledPlans, "Plans should match empty plan builder when hooks disabled.")
+	for _, stage := range stages {
+		assert.Empty(t, disabledPlans[stage], "Hooks included in %s stage plan when hooks disabled.", stage)
+	}
+}
+
+func TestPlanWithWildcardHookImplCode(t *testing.T) {
+	const group string = `{"timeout": 5, "hook_sequence": [{"module_code": "acme.foo", "hook_impl_code": "*"}]}`
+	stages := []Stage{
+		StageEntrypoint,
+		StageRawAuctionRequest,
+		StageProcessedAuctionRequest,
+		StageBidderRequest,
+		StageRawBidderResponse,
+		StageAllProcessedBidResponses,
+		StageAuctionResponse,
+	}
+
+	stagesData := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		stagesData = append(stagesData, `"`+stage.String()+`": {"groups": [`+group+`]}`)
+	}
+	planData := []byte(`{"endpoints": {"/openrtb2/auction": {"stages": {` + strings.Join(stagesData, ",") + `}}}}`)
+
+	hooks := map[string]interface{}{"acme.foo": fakeThreeStagesHook{}}
+	planBuilder, err := getPlanBuilder(hooks, planData, []byte(`{}`))
+	if !assert.NoError(t, err, "Failed to init hook execution plan builder") {
+		return
+	}
+
+	endpoint := "/openrtb2/auction"
+	account := &config.Account{}
+	entrypointPlan := planBuilder.PlanForEntrypointStage(endpoint)
+	rawAuctionPlan := planBuilder.PlanForRawAuctionStage(endpoint, account)
+	processedAuctionPlan := planBuilder.PlanForProcessedAuctionStage(endpoint, account)
+
+	assert.Equal(t, []HookDescription{{"acme.foo", "entrypoint"}}, getPlanHooks(entrypointPlan))
+	assert.Equal(t, []HookDescription{{"acme.foo", "raw_auction_request"}}, getPlanHooks(rawAuctionPlan))
+	assert.Equal(t, []HookDescription{{"acme.foo", "processed_auction_request"}}, getPlanHooks(processedAuctionPlan))
+
+	assert.Empty(t, planBuilder.PlanForBidderRequestStage(endpoint, account), "Wildcard without module hook for the stage should be skipped.")
+	assert.Empty(t, planBuilder.PlanForRawBidderResponseStage(endpoint, account), "Wildcard without module hook for the stage should be skipped.")
+	assert.Empty(t, planBuilder.PlanForAllProcessedBidResponsesStage(endpoint, account), "Wildcard without module hook for the stage should be skipped.")
+	assert.Empty(t, planBuilder.PlanForAuctionResponseStage(endpoint, account), "Wildcard without module hook for the stage should be skipped.")
+}
+
+func TestPlanWithWildcardAndExplicitHookImplCodes(t *testing.T) {
+	planData := []byte(`{"endpoints": {"/openrtb2/auction": {"stages": {"entrypoint": {"groups": [
+		{"timeout": 5, "hook_sequence": [
+			{"module_code": "acme.bar", "hook_impl_code": "*"},
+			{"module_code": "acme.foo", "hook_impl_code": "foo"},
+			{"module_code": "acme.bar", "hook_impl_code": "bar"},
+			{"module_code": "acme.foo", "hook_impl_code": "*"},
+			{"module_code": "acme.foo", "hook_impl_code": "foo-2"},
+			{"module_code": "acme.baz", "hook_impl_code": "*"},
+			{"module_code": "acme.bar", "hook_impl_code": "*"}
+		]}
+	]}}}}}`)
+
+	hooks := map[string]interface{}{
+		"acme.foo": fakeEntrypointHook{},
+		"acme.bar": fakeEntrypointHook{},
+	}
+	planBuilder, err := getPlanBuilder(hooks, planData, []byte(`{}`))
+	if !assert.NoError(t, err, "Failed to init hook execution plan builder") {
+		return
+	}
+
+	plan := planBuilder.PlanForEntrypointStage("/openrtb2/auction")
+
+	assert.Equal(t, []HookDescription{
+		{"acme.bar", "entrypoint"},
+		{"acme.foo", "foo"},
+		{"acme.foo", "foo-2"},
+	}, getPlanHooks(plan), "Modules included by wildcard should be deduplicated preserving first occurrence.")
+	if assert.Len(t, plan, 1) {
+		assert.Empty(t, plan[0].Unresolved, "Unknown module included by wildcard shouldn't be reported as unresolved.")
+	}
+}
+
+func getPlanHooks[T any](plan Plan[T]) []HookDescription {
+	var hooks []HookDescription
+	for _, group := range plan {
+		for _, hook := range group.Hooks {
+			hooks = append(hooks, HookDescription{ModuleCode: hook.Module, HookImplCode: hook.Code})
+		}
+	}
+	return hooks
+}
+
+func getPlanModules[T any](plan Plan[T]) []string {
+	var modules []string
+	for _, group := range plan {
+		for _, hook := range group.Hooks {
+			modules = append(modules, hook.Module)
+		}
+	}
+	return modules
+}
+
 func getPlanBuilder(
 	moduleHooks map[string]interface{},
 	hostPlanData, accountPlanData []byte,
+) (ExecutionPlanBuilder, error) {
+	return getPlanBuilderWithModules(moduleHooks, nil, hostPlanData, accountPlanData)
+}
+
+func getPlanBuilderWithModules(
+	moduleHooks map[string]interface{},
+	modules config.Modules,
+	hostPlanData, accountPlanData []byte,
 ) (ExecutionPlanBuilder, error) {
 	var err error
 	var hooks config.Hooks
@@ -790,6 +1195,7 @@ func getPlanBuilder(
 	}
 
 	hooks.Enabled = true
+	hooks.Modules = modules
 	hooks.HostExecutionPlan = hostPlan
 	hooks.DefaultAccountExecutionPlan = defaultAccountPlan
 
@@ -870,3 +1276,19 @@ func (f fakeAuctionResponseHook) HandleAuctionResponseHook(
 ) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{}, nil
 }
+
+type fakeThreeStagesHook struct {
+	fakeEntrypointHook
+	fakeRawAuctionHook
+	fakeProcessedAuctionHook
+}
+
+type fakeAllStagesHook struct {
+	fakeEntrypointHook
+	fakeRawAuctionHook
+	fakeProcessedAuctionHook
+	fakeBidderRequestHook
+	fakeRawBidderResponseHook
+	fakeAllProcessedBidResponsesHook
+	fakeAuctionResponseHook
+}
@@ -17,6 +17,7 @@ type HookRepository interface {
 	GetRawAuctionHook(id string) (hookstage.RawAuctionRequest, bool)
 	GetProcessedAuctionHook(id string) (hookstage.ProcessedAuctionRequest, bool)
 	GetBidderRequestHook(id string) (hookstage.BidderRequest, bool)
+	GetBidderHttpRequestHook(id string) (hookstage.BidderHttpRequest, bool)
 	GetRawBidderResponseHook(id string) (hookstage.RawBidderResponse, bool)
 	GetAllProcessedBidResponsesHook(id string) (hookstage.AllProcessedBidResponses, bool)
 	GetAuctionResponseHook(id string) (hookstage.AuctionResponse, bool)
@@ -45,6 +46,7 @@ type hookRepository struct {
 	rawAuctionHooks              map[string]hookstage.RawAuctionRequest
 	processedAuctionHooks        map[string]hookstage.ProcessedAuctionRequest
 	bidderRequestHooks           map[string]hookstage.BidderRequest
+	bidderHttpRequestHooks       map[string]hookstage.BidderHttpRequest
 	rawBidderResponseHooks       map[string]hookstage.RawBidderResponse
 	allProcessedBidResponseHooks map[string]hookstage.AllProcessedBidResponses
 	auctionResponseHooks         map[string]hookstage.AuctionResponse
@@ -66,6 +68,10 @@ func (r *hookRepository) GetBidderRequestHook(id string) (hookstage.BidderReques
 	return getHook(r.bidderRequestHooks, id)
 }
 
+func (r *hookRepository) GetBidderHttpRequestHook(id string) (hookstage.BidderHttpRequest, bool) {
+	return getHook(r.bidderHttpRequestHooks, id)
+}
+
 func (r *hookRepository) GetRawBidderResponseHook(id string) (hookstage.RawBidderResponse, bool) {
 	return getHook(r.rawBidderResponseHooks, id)
 }
@@ -110,6 +116,13 @@ func (r *hookRepository) add(id string, hook interface{}) error {
 		}
 	}
 
+	if h, ok := hook.(hookstage.BidderHttpRequest); ok {
+		hasAnyHooks = true
+		if r.bidderHttpRequestHooks, err = addHook(r.bidderHttpRequestHooks, h, id); err != nil {
+			return err
+		}
+	}
+
 	if h, ok := hook.(hookstage.RawBidderResponse); ok {
 		hasAnyHooks = true
 		if r.rawBidderResponseHooks, err = addHook(r.rawBidderResponseHooks, h, id); err != nil {
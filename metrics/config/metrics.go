@@ -254,6 +254,13 @@ func (me *MultiMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ex
 	}
 }
 
+// RecordAdapterAccountRequestBlocked across all engines
+func (me *MultiMetricsEngine) RecordAdapterAccountRequestBlocked(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterAccountRequestBlocked(adapter)
+	}
+}
+
 // RecordDebugRequest across all engines
 func (me *MultiMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 	for _, thisME := range *me {
@@ -315,9 +322,9 @@ func (me *MultiMetricsEngine) RecordModuleFailed(labels metrics.ModuleLabels) {
 	}
 }
 
-func (me *MultiMetricsEngine) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+func (me *MultiMetricsEngine) RecordModuleSuccessNooped(labels metrics.ModuleLabels, hookCode string) {
 	for _, thisME := range *me {
-		thisME.RecordModuleSuccessNooped(labels)
+		thisME.RecordModuleSuccessNooped(labels, hookCode)
 	}
 }
 
@@ -333,6 +340,12 @@ func (me *MultiMetricsEngine) RecordModuleSuccessRejected(labels metrics.ModuleL
 	}
 }
 
+func (me *MultiMetricsEngine) RecordModuleSuccessCacheHit(labels metrics.ModuleLabels) {
+	for _, thisME := range *me {
+		thisME.RecordModuleSuccessCacheHit(labels)
+	}
+}
+
 func (me *MultiMetricsEngine) RecordModuleExecutionError(labels metrics.ModuleLabels) {
 	for _, thisME := range *me {
 		thisME.RecordModuleExecutionError(labels)
@@ -345,6 +358,78 @@ func (me *MultiMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 	}
 }
 
+func (me *MultiMetricsEngine) RecordModuleMutationError(labels metrics.ModuleLabels, hookCode string) {
+	for _, thisME := range *me {
+		thisME.RecordModuleMutationError(labels, hookCode)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordRequestBodySizeExceeded() {
+	for _, thisME := range *me {
+		thisME.RecordRequestBodySizeExceeded()
+	}
+}
+
+func (me *MultiMetricsEngine) RecordRejectedRequest(stage, module string, code int) {
+	for _, thisME := range *me {
+		thisME.RecordRejectedRequest(stage, module, code)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidderResponseError(adapterName openrtb_ext.BidderName, errorType metrics.AdapterError) {
+	for _, thisME := range *me {
+		thisME.RecordBidderResponseError(adapterName, errorType)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidValidationImpIDError(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationImpIDError(adapter)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidValidationMaxCPMError(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationMaxCPMError(adapter)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordBidValidationFloorError(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidValidationFloorError(adapter)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordRequestCompression(adapter openrtb_ext.BidderName, originalBytes, compressedBytes int) {
+	for _, thisME := range *me {
+		thisME.RecordRequestCompression(adapter, originalBytes, compressedBytes)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordDuplicateBids(adapter openrtb_ext.BidderName, count int) {
+	for _, thisME := range *me {
+		thisME.RecordDuplicateBids(adapter, count)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordNoBidResponse(adapter openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordNoBidResponse(adapter)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordGzipProbe(adapter openrtb_ext.BidderName, success bool) {
+	for _, thisME := range *me {
+		thisME.RecordGzipProbe(adapter, success)
+	}
+}
+
+func (me *MultiMetricsEngine) RecordAdapterRequestFanout(adapter openrtb_ext.BidderName, count int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterRequestFanout(adapter, count)
+	}
+}
+
 // NilMetricsEngine implements the MetricsEngine interface where no metrics are actually captured. This is
 // used if no metric backend is configured and also for tests.
 type NilMetricsEngine struct{}
@@ -457,6 +542,10 @@ func (me *NilMetricsEngine) RecordRequestPrivacy(privacy metrics.PrivacyLabels)
 func (me *NilMetricsEngine) RecordAdapterGDPRRequestBlocked(adapter openrtb_ext.BidderName) {
 }
 
+// RecordAdapterAccountRequestBlocked as a noop
+func (me *NilMetricsEngine) RecordAdapterAccountRequestBlocked(adapter openrtb_ext.BidderName) {
+}
+
 // RecordDebugRequest as a noop
 func (me *NilMetricsEngine) RecordDebugRequest(debugEnabled bool, pubId string) {
 }
@@ -490,7 +579,7 @@ func (me *NilMetricsEngine) RecordModuleCalled(labels metrics.ModuleLabels, dura
 func (me *NilMetricsEngine) RecordModuleFailed(labels metrics.ModuleLabels) {
 }
 
-func (me *NilMetricsEngine) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+func (me *NilMetricsEngine) RecordModuleSuccessNooped(labels metrics.ModuleLabels, hookCode string) {
 }
 
 func (me *NilMetricsEngine) RecordModuleSuccessUpdated(labels metrics.ModuleLabels) {
@@ -499,8 +588,47 @@ func (me *NilMetricsEngine) RecordModuleSuccessUpdated(labels metrics.ModuleLabe
 func (me *NilMetricsEngine) RecordModuleSuccessRejected(labels metrics.ModuleLabels) {
 }
 
+func (me *NilMetricsEngine) RecordModuleSuccessCacheHit(labels metrics.ModuleLabels) {
+}
+
 func (me *NilMetricsEngine) RecordModuleExecutionError(labels metrics.ModuleLabels) {
 }
 
 func (me *NilMetricsEngine) RecordModuleTimeout(labels metrics.ModuleLabels) {
 }
+
+func (me *NilMetricsEngine) RecordModuleMutationError(labels metrics.ModuleLabels, hookCode string) {
+}
+
+func (me *NilMetricsEngine) RecordRequestBodySizeExceeded() {
+}
+
+func (me *NilMetricsEngine) RecordRejectedRequest(stage, module string, code int) {
+}
+
+func (me *NilMetricsEngine) RecordBidderResponseError(adapterName openrtb_ext.BidderName, errorType metrics.AdapterError) {
+}
+
+func (me *NilMetricsEngine) RecordBidValidationImpIDError(adapter openrtb_ext.BidderName) {
+}
+
+func (me *NilMetricsEngine) RecordBidValidationMaxCPMError(adapter openrtb_ext.BidderName) {
+}
+
+func (me *NilMetricsEngine) RecordBidValidationFloorError(adapter openrtb_ext.BidderName) {
+}
+
+func (me *NilMetricsEngine) RecordRequestCompression(adapter openrtb_ext.BidderName, originalBytes, compressedBytes int) {
+}
+
+func (me *NilMetricsEngine) RecordDuplicateBids(adapter openrtb_ext.BidderName, count int) {
+}
+
+func (me *NilMetricsEngine) RecordNoBidResponse(adapter openrtb_ext.BidderName) {
+}
+
+func (me *NilMetricsEngine) RecordGzipProbe(adapter openrtb_ext.BidderName, success bool) {
+}
+
+func (me *NilMetricsEngine) RecordAdapterRequestFanout(adapter openrtb_ext.BidderName, count int) {
+}
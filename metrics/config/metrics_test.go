@@ -102,9 +102,10 @@ func TestMultiMetricsEngine(t *testing.T) {
 	for _, module := range moduleLabels {
 		metricsEngine.RecordModuleCalled(module, time.Millisecond*1)
 		metricsEngine.RecordModuleFailed(module)
-		metricsEngine.RecordModuleSuccessNooped(module)
+		metricsEngine.RecordModuleSuccessNooped(module, "hook-code")
 		metricsEngine.RecordModuleSuccessUpdated(module)
 		metricsEngine.RecordModuleSuccessRejected(module)
+		metricsEngine.RecordModuleSuccessCacheHit(module)
 		metricsEngine.RecordModuleExecutionError(module)
 		metricsEngine.RecordModuleTimeout(module)
 	}
@@ -198,6 +199,7 @@ func TestMultiMetricsEngine(t *testing.T) {
 			VerifyMetrics(t, fmt.Sprintf("ModuleMetrics.%s.%s.SuccessNoop", module, stage), goEngine.ModuleMetrics[module][stage].SuccessNoopCounter.Count(), 1)
 			VerifyMetrics(t, fmt.Sprintf("ModuleMetrics.%s.%s.SuccessUpdate", module, stage), goEngine.ModuleMetrics[module][stage].SuccessUpdateCounter.Count(), 1)
 			VerifyMetrics(t, fmt.Sprintf("ModuleMetrics.%s.%s.SuccessReject", module, stage), goEngine.ModuleMetrics[module][stage].SuccessRejectCounter.Count(), 1)
+			VerifyMetrics(t, fmt.Sprintf("ModuleMetrics.%s.%s.SuccessCacheHit", module, stage), goEngine.ModuleMetrics[module][stage].SuccessCacheHitCounter.Count(), 1)
 			VerifyMetrics(t, fmt.Sprintf("ModuleMetrics.%s.%s.ExecutionError", module, stage), goEngine.ModuleMetrics[module][stage].ExecutionErrorCounter.Count(), 1)
 			VerifyMetrics(t, fmt.Sprintf("ModuleMetrics.%s.%s.Timeout", module, stage), goEngine.ModuleMetrics[module][stage].TimeoutCounter.Count(), 1)
 		}
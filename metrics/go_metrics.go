@@ -33,6 +33,7 @@ type Metrics struct {
 	DNSLookupTimer                 metrics.Timer
 	TLSHandshakeTimer              metrics.Timer
 	StoredResponsesMeter           metrics.Meter
+	RequestBodySizeExceededMeter   metrics.Meter
 
 	// Metrics for OpenRTB requests specifically. So we can track what % of RequestsMeter are OpenRTB
 	// and know when legacy requests have been abandoned.
@@ -83,25 +84,45 @@ type Metrics struct {
 
 // AdapterMetrics houses the metrics for a particular adapter
 type AdapterMetrics struct {
-	NoCookieMeter      metrics.Meter
-	ErrorMeters        map[AdapterError]metrics.Meter
-	NoBidMeter         metrics.Meter
-	GotBidsMeter       metrics.Meter
-	RequestTimer       metrics.Timer
-	PriceHistogram     metrics.Histogram
-	BidsReceivedMeter  metrics.Meter
-	PanicMeter         metrics.Meter
-	MarkupMetrics      map[openrtb_ext.BidType]*MarkupDeliveryMetrics
-	ConnCreated        metrics.Counter
-	ConnReused         metrics.Counter
-	ConnWaitTime       metrics.Timer
-	GDPRRequestBlocked metrics.Meter
+	NoCookieMeter metrics.Meter
+	ErrorMeters   map[AdapterError]metrics.Meter
+	// ResponseErrorMeters count errors returned by the adapter while processing the bidder responses
+	ResponseErrorMeters map[AdapterError]metrics.Meter
+	NoBidMeter          metrics.Meter
+	GotBidsMeter        metrics.Meter
+	RequestTimer        metrics.Timer
+	PriceHistogram      metrics.Histogram
+	BidsReceivedMeter   metrics.Meter
+	PanicMeter          metrics.Meter
+	MarkupMetrics       map[openrtb_ext.BidType]*MarkupDeliveryMetrics
+	ConnCreated         metrics.Counter
+	ConnReused          metrics.Counter
+	ConnWaitTime        metrics.Timer
+	GDPRRequestBlocked  metrics.Meter
 
 	BidValidationCreativeSizeErrorMeter metrics.Meter
 	BidValidationCreativeSizeWarnMeter  metrics.Meter
 
 	BidValidationSecureMarkupErrorMeter metrics.Meter
 	BidValidationSecureMarkupWarnMeter  metrics.Meter
+
+	BidValidationImpIDErrorMeter  metrics.Meter
+	BidValidationMaxCPMErrorMeter metrics.Meter
+	BidValidationFloorErrorMeter  metrics.Meter
+
+	// RequestCompressionRatioHistogram holds the compressed to original size ratio of the request bodies in percent
+	RequestCompressionRatioHistogram metrics.Histogram
+	// DuplicateBidsMeter counts the bids removed as duplicates of bids returned under another seat
+	DuplicateBidsMeter metrics.Meter
+	// NoBidResponseMeter counts the 204 No Content responses treated as no-bids
+	NoBidResponseMeter metrics.Meter
+	// GzipProbeSuccessMeter and GzipProbeFailureMeter count the gzip compressed probe requests accepted and rejected by the bidder
+	GzipProbeSuccessMeter metrics.Meter
+	GzipProbeFailureMeter metrics.Meter
+	// RequestFanoutHistogram holds the number of HTTP requests made out of a single bid request
+	RequestFanoutHistogram metrics.Histogram
+	// AccountRequestBlocked counts the requests skipped as the adapter is not allowed for the account
+	AccountRequestBlocked metrics.Meter
 }
 
 type MarkupDeliveryMetrics struct {
@@ -127,14 +148,17 @@ type accountMetrics struct {
 }
 
 type ModuleMetrics struct {
-	DurationTimer         metrics.Timer
-	CallCounter           metrics.Counter
-	FailureCounter        metrics.Counter
-	SuccessNoopCounter    metrics.Counter
-	SuccessUpdateCounter  metrics.Counter
-	SuccessRejectCounter  metrics.Counter
-	ExecutionErrorCounter metrics.Counter
-	TimeoutCounter        metrics.Counter
+	DurationTimer        metrics.Timer
+	CallCounter          metrics.Counter
+	FailureCounter       metrics.Counter
+	SuccessNoopCounter   metrics.Counter
+	SuccessUpdateCounter metrics.Counter
+	SuccessRejectCounter metrics.Counter
+	// SuccessCacheHitCounter counts the cached responses served by the module instead of running the auction
+	SuccessCacheHitCounter metrics.Counter
+	ExecutionErrorCounter  metrics.Counter
+	TimeoutCounter         metrics.Counter
+	MutationErrorCounter   metrics.Counter
 }
 
 // NewBlankMetrics creates a new Metrics object with all blank metrics object. This may also be useful for
@@ -177,6 +201,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderNa
 		SetUidStatusMeter:              make(map[SetUidStatus]metrics.Meter),
 		SyncerSetsMeter:                make(map[string]map[SyncerSetUidStatus]metrics.Meter),
 		StoredResponsesMeter:           blankMeter,
+		RequestBodySizeExceededMeter:   blankMeter,
 
 		ImpsTypeBanner: blankMeter,
 		ImpsTypeVideo:  blankMeter,
@@ -288,6 +313,7 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.PrebidCacheRequestTimerSuccess = metrics.GetOrRegisterTimer("prebid_cache_request_time.ok", registry)
 	newMetrics.PrebidCacheRequestTimerError = metrics.GetOrRegisterTimer("prebid_cache_request_time.err", registry)
 	newMetrics.StoredResponsesMeter = metrics.GetOrRegisterMeter("stored_responses", registry)
+	newMetrics.RequestBodySizeExceededMeter = metrics.GetOrRegisterMeter("request_body_size_exceeded", registry)
 
 	for _, dt := range StoredDataTypes() {
 		for _, ft := range StoredDataFetchTypes() {
@@ -369,15 +395,16 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMetrics {
 	blankMeter := &metrics.NilMeter{}
 	newAdapter := &AdapterMetrics{
-		NoCookieMeter:     blankMeter,
-		ErrorMeters:       make(map[AdapterError]metrics.Meter),
-		NoBidMeter:        blankMeter,
-		GotBidsMeter:      blankMeter,
-		RequestTimer:      &metrics.NilTimer{},
-		PriceHistogram:    &metrics.NilHistogram{},
-		BidsReceivedMeter: blankMeter,
-		PanicMeter:        blankMeter,
-		MarkupMetrics:     makeBlankBidMarkupMetrics(),
+		NoCookieMeter:       blankMeter,
+		ErrorMeters:         make(map[AdapterError]metrics.Meter),
+		ResponseErrorMeters: make(map[AdapterError]metrics.Meter),
+		NoBidMeter:          blankMeter,
+		GotBidsMeter:        blankMeter,
+		RequestTimer:        &metrics.NilTimer{},
+		PriceHistogram:      &metrics.NilHistogram{},
+		BidsReceivedMeter:   blankMeter,
+		PanicMeter:          blankMeter,
+		MarkupMetrics:       makeBlankBidMarkupMetrics(),
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
@@ -389,6 +416,7 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 	}
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
+		newAdapter.ResponseErrorMeters[err] = blankMeter
 	}
 	return newAdapter
 }
@@ -404,14 +432,16 @@ func makeBlankModuleStageMetrics(stages []string) map[string]*ModuleMetrics {
 
 func makeBlankModuleMetrics() *ModuleMetrics {
 	return &ModuleMetrics{
-		DurationTimer:         &metrics.NilTimer{},
-		CallCounter:           metrics.NilCounter{},
-		FailureCounter:        metrics.NilCounter{},
-		SuccessNoopCounter:    metrics.NilCounter{},
-		SuccessUpdateCounter:  metrics.NilCounter{},
-		SuccessRejectCounter:  metrics.NilCounter{},
-		ExecutionErrorCounter: metrics.NilCounter{},
-		TimeoutCounter:        metrics.NilCounter{},
+		DurationTimer:          &metrics.NilTimer{},
+		CallCounter:            metrics.NilCounter{},
+		FailureCounter:         metrics.NilCounter{},
+		SuccessNoopCounter:     metrics.NilCounter{},
+		SuccessUpdateCounter:   metrics.NilCounter{},
+		SuccessRejectCounter:   metrics.NilCounter{},
+		SuccessCacheHitCounter: metrics.NilCounter{},
+		ExecutionErrorCounter:  metrics.NilCounter{},
+		TimeoutCounter:         metrics.NilCounter{},
+		MutationErrorCounter:   metrics.NilCounter{},
 	}
 }
 
@@ -449,6 +479,9 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	for err := range am.ErrorMeters {
 		am.ErrorMeters[err] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.requests.%s", adapterOrAccount, exchange, err), registry)
 	}
+	for err := range am.ResponseErrorMeters {
+		am.ResponseErrorMeters[err] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.response.errors.%s", adapterOrAccount, exchange, err), registry)
+	}
 	if adapterOrAccount != "adapter" {
 		am.BidsReceivedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.bids_received", adapterOrAccount, exchange), registry)
 	}
@@ -460,6 +493,18 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 
 	am.BidValidationSecureMarkupErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.err", adapterOrAccount, exchange), registry)
 	am.BidValidationSecureMarkupWarnMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.secure.warn", adapterOrAccount, exchange), registry)
+
+	am.BidValidationImpIDErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.impid.err", adapterOrAccount, exchange), registry)
+	am.BidValidationMaxCPMErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.maxcpm.err", adapterOrAccount, exchange), registry)
+	am.BidValidationFloorErrorMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.validation.floor.err", adapterOrAccount, exchange), registry)
+
+	am.RequestCompressionRatioHistogram = metrics.GetOrRegisterHistogram(fmt.Sprintf("%[1]s.%[2]s.request.compression_ratio", adapterOrAccount, exchange), registry, metrics.NewExpDecaySample(1028, 0.015))
+	am.DuplicateBidsMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.duplicate_bids", adapterOrAccount, exchange), registry)
+	am.NoBidResponseMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.response.nobid", adapterOrAccount, exchange), registry)
+	am.GzipProbeSuccessMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.request.gzip_probe.success", adapterOrAccount, exchange), registry)
+	am.GzipProbeFailureMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.request.gzip_probe.failure", adapterOrAccount, exchange), registry)
+	am.RequestFanoutHistogram = metrics.GetOrRegisterHistogram(fmt.Sprintf("%[1]s.%[2]s.request.fanout", adapterOrAccount, exchange), registry, metrics.NewExpDecaySample(1028, 0.015))
+	am.AccountRequestBlocked = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.account_request_blocked", adapterOrAccount, exchange), registry)
 }
 
 func registerModuleMetrics(registry metrics.Registry, module string, stages []string, mm map[string]*ModuleMetrics) {
@@ -470,8 +515,10 @@ func registerModuleMetrics(registry metrics.Registry, module string, stages []st
 		mm[stage].SuccessNoopCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.success.noop", module, stage), registry)
 		mm[stage].SuccessUpdateCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.success.update", module, stage), registry)
 		mm[stage].SuccessRejectCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.success.reject", module, stage), registry)
+		mm[stage].SuccessCacheHitCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.success.cache_hit", module, stage), registry)
 		mm[stage].ExecutionErrorCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.execution_error", module, stage), registry)
 		mm[stage].TimeoutCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.timeout", module, stage), registry)
+		mm[stage].MutationErrorCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("modules.module.%s.stage.%s.mutation_error", module, stage), registry)
 	}
 }
 
@@ -482,8 +529,10 @@ func registerAccountModuleMetrics(registry metrics.Registry, id string, module s
 	mm.SuccessNoopCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.success.noop", id, module), registry)
 	mm.SuccessUpdateCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.success.update", id, module), registry)
 	mm.SuccessRejectCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.success.reject", id, module), registry)
+	mm.SuccessCacheHitCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.success.cache_hit", id, module), registry)
 	mm.ExecutionErrorCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.execution_error", id, module), registry)
 	mm.TimeoutCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.timeout", id, module), registry)
+	mm.MutationErrorCounter = metrics.GetOrRegisterCounter(fmt.Sprintf("account.%s.modules.module.%s.mutation_error", id, module), registry)
 }
 
 func makeDeliveryMetrics(registry metrics.Registry, prefix string, bidType openrtb_ext.BidType) *MarkupDeliveryMetrics {
@@ -886,6 +935,16 @@ func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidde
 	am.GDPRRequestBlocked.Mark(1)
 }
 
+func (me *Metrics) RecordAdapterAccountRequestBlocked(adapterName openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to log adapter account request blocked metric for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	am.AccountRequestBlocked.Mark(1)
+}
+
 func (me *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		me.AdsCertRequestsSuccess.Mark(1)
@@ -990,7 +1049,9 @@ func (me *Metrics) RecordModuleFailed(labels ModuleLabels) {
 	}
 }
 
-func (me *Metrics) RecordModuleSuccessNooped(labels ModuleLabels) {
+// RecordModuleSuccessNooped counts the no-op executions per module and stage, the hook code is not part of the metric name,
+// as it is assigned by the execution plans and would grow the registry without limit.
+func (me *Metrics) RecordModuleSuccessNooped(labels ModuleLabels, hookCode string) {
 	mm, err := me.getModuleMetric(labels)
 	if err != nil {
 		return
@@ -1041,6 +1102,23 @@ func (me *Metrics) RecordModuleSuccessRejected(labels ModuleLabels) {
 	}
 }
 
+func (me *Metrics) RecordModuleSuccessCacheHit(labels ModuleLabels) {
+	mm, err := me.getModuleMetric(labels)
+	if err != nil {
+		return
+	}
+
+	// Module metrics
+	mm.SuccessCacheHitCounter.Inc(1)
+
+	// Account-Module metrics
+	if labels.AccountID != "" && labels.AccountID != PublisherUnknown {
+		if aam, ok := me.getAccountMetrics(labels.AccountID).moduleMetrics[labels.Module]; ok {
+			aam.SuccessCacheHitCounter.Inc(1)
+		}
+	}
+}
+
 func (me *Metrics) RecordModuleExecutionError(labels ModuleLabels) {
 	mm, err := me.getModuleMetric(labels)
 	if err != nil {
@@ -1058,6 +1136,24 @@ func (me *Metrics) RecordModuleExecutionError(labels ModuleLabels) {
 	}
 }
 
+// RecordModuleMutationError counts the mutation failures per module and stage, the hook code is not part of the metric name.
+func (me *Metrics) RecordModuleMutationError(labels ModuleLabels, hookCode string) {
+	mm, err := me.getModuleMetric(labels)
+	if err != nil {
+		return
+	}
+
+	// Module metrics
+	mm.MutationErrorCounter.Inc(1)
+
+	// Account-Module metrics
+	if labels.AccountID != "" && labels.AccountID != PublisherUnknown {
+		if aam, ok := me.getAccountMetrics(labels.AccountID).moduleMetrics[labels.Module]; ok {
+			aam.MutationErrorCounter.Inc(1)
+		}
+	}
+}
+
 func (me *Metrics) RecordModuleTimeout(labels ModuleLabels) {
 	mm, err := me.getModuleMetric(labels)
 	if err != nil {
@@ -1075,6 +1171,98 @@ func (me *Metrics) RecordModuleTimeout(labels ModuleLabels) {
 	}
 }
 
+func (me *Metrics) RecordRequestBodySizeExceeded() {
+	me.RequestBodySizeExceededMeter.Mark(1)
+}
+
+// RecordRejectedRequest marks a meter per stage, module and reject code, the meter is registered on first use.
+func (me *Metrics) RecordRejectedRequest(stage, module string, code int) {
+	name := fmt.Sprintf("requests.rejected.stage.%s.module.%s.code.%d", stage, module, code)
+	metrics.GetOrRegisterMeter(name, me.MetricsRegistry).Mark(1)
+}
+
+func (me *Metrics) RecordBidderResponseError(adapterName openrtb_ext.BidderName, errorType AdapterError) {
+	am, ok := me.AdapterMetrics[adapterName]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapterName))
+		return
+	}
+
+	if meter, ok := am.ResponseErrorMeters[errorType]; ok {
+		meter.Mark(1)
+	}
+}
+
+func (me *Metrics) RecordBidValidationImpIDError(adapter openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.BidValidationImpIDErrorMeter.Mark(1)
+}
+
+func (me *Metrics) RecordBidValidationMaxCPMError(adapter openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.BidValidationMaxCPMErrorMeter.Mark(1)
+}
+
+func (me *Metrics) RecordBidValidationFloorError(adapter openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.BidValidationFloorErrorMeter.Mark(1)
+}
+
+func (me *Metrics) RecordRequestCompression(adapter openrtb_ext.BidderName, originalBytes, compressedBytes int) {
+	if originalBytes <= 0 {
+		return
+	}
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.RequestCompressionRatioHistogram.Update(int64(compressedBytes * 100 / originalBytes))
+}
+
+func (me *Metrics) RecordDuplicateBids(adapter openrtb_ext.BidderName, count int) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.DuplicateBidsMeter.Mark(int64(count))
+}
+
+func (me *Metrics) RecordNoBidResponse(adapter openrtb_ext.BidderName) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.NoBidResponseMeter.Mark(1)
+}
+
+func (me *Metrics) RecordGzipProbe(adapter openrtb_ext.BidderName, success bool) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	if success {
+		am.GzipProbeSuccessMeter.Mark(1)
+	} else {
+		am.GzipProbeFailureMeter.Mark(1)
+	}
+}
+
 func (me *Metrics) getModuleMetric(labels ModuleLabels) (*ModuleMetrics, error) {
 	mm, ok := me.ModuleMetrics[labels.Module][labels.Stage]
 	if !ok {
@@ -1085,3 +1273,12 @@ func (me *Metrics) getModuleMetric(labels ModuleLabels) (*ModuleMetrics, error)
 
 	return mm, nil
 }
+
+func (me *Metrics) RecordAdapterRequestFanout(adapter openrtb_ext.BidderName, count int) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	am.RequestFanoutHistogram.Update(int64(count))
+}
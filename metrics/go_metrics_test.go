@@ -122,6 +122,7 @@ func ensureContainsAdapterMetrics(t *testing.T, registry metrics.Registry, name
 	ensureContains(t, registry, name+".requests.badserverresponse", adapterMetrics.ErrorMeters[AdapterErrorBadServerResponse])
 	ensureContains(t, registry, name+".requests.timeout", adapterMetrics.ErrorMeters[AdapterErrorTimeout])
 	ensureContains(t, registry, name+".requests.unknown_error", adapterMetrics.ErrorMeters[AdapterErrorUnknown])
+	ensureContains(t, registry, name+".response.errors.badserverresponse", adapterMetrics.ResponseErrorMeters[AdapterErrorBadServerResponse])
 
 	ensureContains(t, registry, name+".request_time", adapterMetrics.RequestTimer)
 	ensureContains(t, registry, name+".prices", adapterMetrics.PriceHistogram)
@@ -146,8 +147,10 @@ func ensureContainsModuleMetrics(t *testing.T, registry metrics.Registry, name s
 	ensureContains(t, registry, name+".success.noop", moduleMetrics.SuccessNoopCounter)
 	ensureContains(t, registry, name+".success.update", moduleMetrics.SuccessUpdateCounter)
 	ensureContains(t, registry, name+".success.reject", moduleMetrics.SuccessRejectCounter)
+	ensureContains(t, registry, name+".success.cache_hit", moduleMetrics.SuccessCacheHitCounter)
 	ensureContains(t, registry, name+".execution_error", moduleMetrics.ExecutionErrorCounter)
 	ensureContains(t, registry, name+".timeout", moduleMetrics.TimeoutCounter)
+	ensureContains(t, registry, name+".mutation_error", moduleMetrics.MutationErrorCounter)
 }
 
 func TestRecordBidTypeDisabledConfig(t *testing.T) {
@@ -362,6 +365,112 @@ func TestRecordBidValidationSecureMarkup(t *testing.T) {
 	}
 }
 
+func TestRecordBidderResponseError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordBidderResponseError(openrtb_ext.BidderAppnexus, AdapterErrorBadServerResponse)
+	m.RecordBidderResponseError(openrtb_ext.BidderAppnexus, AdapterErrorBadServerResponse)
+	m.RecordBidderResponseError(openrtb_ext.BidderAppnexus, AdapterErrorBadInput)
+	m.RecordBidderResponseError("unknown-bidder", AdapterErrorBadInput)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderAppnexus]
+	assert.Equal(t, int64(2), am.ResponseErrorMeters[AdapterErrorBadServerResponse].Count())
+	assert.Equal(t, int64(1), am.ResponseErrorMeters[AdapterErrorBadInput].Count())
+	assert.Equal(t, int64(0), am.ResponseErrorMeters[AdapterErrorUnknown].Count())
+	assert.Equal(t, int64(0), am.ErrorMeters[AdapterErrorBadInput].Count())
+}
+
+func TestRecordBidValidationImpIDError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordBidValidationImpIDError(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationImpIDError("unknown-bidder")
+
+	assert.Equal(t, int64(1), m.AdapterMetrics[openrtb_ext.BidderAppnexus].BidValidationImpIDErrorMeter.Count())
+}
+
+func TestRecordBidValidationMaxCPMError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordBidValidationMaxCPMError(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationMaxCPMError("unknown-bidder")
+
+	assert.Equal(t, int64(1), m.AdapterMetrics[openrtb_ext.BidderAppnexus].BidValidationMaxCPMErrorMeter.Count())
+}
+
+func TestRecordRequestCompression(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordRequestCompression(openrtb_ext.BidderAppnexus, 1000, 250)
+	m.RecordRequestCompression(openrtb_ext.BidderAppnexus, 0, 20)
+	m.RecordRequestCompression("unknown-bidder", 1000, 250)
+
+	histogram := m.AdapterMetrics[openrtb_ext.BidderAppnexus].RequestCompressionRatioHistogram
+	assert.Equal(t, int64(1), histogram.Count())
+	assert.Equal(t, int64(25), histogram.Max())
+}
+
+func TestRecordDuplicateBids(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordDuplicateBids(openrtb_ext.BidderAppnexus, 2)
+	m.RecordDuplicateBids(openrtb_ext.BidderAppnexus, 1)
+	m.RecordDuplicateBids("unknown-bidder", 1)
+
+	assert.Equal(t, int64(3), m.AdapterMetrics[openrtb_ext.BidderAppnexus].DuplicateBidsMeter.Count())
+}
+
+func TestRecordNoBidResponse(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordNoBidResponse(openrtb_ext.BidderAppnexus)
+	m.RecordNoBidResponse("unknown-bidder")
+
+	assert.Equal(t, int64(1), m.AdapterMetrics[openrtb_ext.BidderAppnexus].NoBidResponseMeter.Count())
+}
+
+func TestRecordGzipProbe(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordGzipProbe(openrtb_ext.BidderAppnexus, true)
+	m.RecordGzipProbe(openrtb_ext.BidderAppnexus, true)
+	m.RecordGzipProbe(openrtb_ext.BidderAppnexus, false)
+	m.RecordGzipProbe("unknown-bidder", true)
+
+	assert.Equal(t, int64(2), m.AdapterMetrics[openrtb_ext.BidderAppnexus].GzipProbeSuccessMeter.Count())
+	assert.Equal(t, int64(1), m.AdapterMetrics[openrtb_ext.BidderAppnexus].GzipProbeFailureMeter.Count())
+}
+
+func TestRecordAdapterRequestFanout(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterRequestFanout(openrtb_ext.BidderAppnexus, 1)
+	m.RecordAdapterRequestFanout(openrtb_ext.BidderAppnexus, 5)
+	m.RecordAdapterRequestFanout("unknown-bidder", 10)
+
+	histogram := m.AdapterMetrics[openrtb_ext.BidderAppnexus].RequestFanoutHistogram
+	assert.Equal(t, int64(2), histogram.Count())
+	assert.Equal(t, int64(5), histogram.Max())
+}
+
+func TestRecordBidValidationFloorError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordBidValidationFloorError(openrtb_ext.BidderAppnexus)
+	m.RecordBidValidationFloorError("unknown-bidder")
+
+	assert.Equal(t, int64(1), m.AdapterMetrics[openrtb_ext.BidderAppnexus].BidValidationFloorErrorMeter.Count())
+}
+
 func TestRecordDNSTime(t *testing.T) {
 	testCases := []struct {
 		description         string
@@ -742,6 +851,16 @@ func TestRecordRequestPrivacy(t *testing.T) {
 	assert.Equal(t, m.PrivacyTCFRequestVersion[TCFVersionV2].Count(), int64(1), "TCF V2")
 }
 
+func TestRecordAdapterAccountRequestBlocked(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordAdapterAccountRequestBlocked(openrtb_ext.BidderAppnexus)
+	m.RecordAdapterAccountRequestBlocked("unknown-bidder")
+
+	assert.Equal(t, int64(1), m.AdapterMetrics[openrtb_ext.BidderAppnexus].AccountRequestBlocked.Count())
+}
+
 func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	var fakeBidder openrtb_ext.BidderName = "fooAdvertising"
 
@@ -1030,6 +1149,43 @@ func TestRecordModuleAccountMetrics(t *testing.T) {
 	}
 }
 
+func TestRecordRejectedRequest(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, nil)
+
+	m.RecordRejectedRequest("raw_auction_request", "foobar", 2)
+	m.RecordRejectedRequest("raw_auction_request", "foobar", 2)
+
+	meter, ok := registry.Get("requests.rejected.stage.raw_auction_request.module.foobar.code.2").(metrics.Meter)
+	if assert.True(t, ok, "Rejected request meter should be registered.") {
+		assert.Equal(t, int64(2), meter.Count())
+	}
+}
+
+func TestRecordModuleMutationError(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, map[string][]string{"foobar": {"raw_auction"}})
+
+	m.RecordModuleMutationError(ModuleLabels{Module: "foobar", Stage: "raw_auction", AccountID: "acc-1"}, "foo")
+	m.RecordModuleMutationError(ModuleLabels{Module: "unknown", Stage: "raw_auction"}, "foo")
+
+	assert.Equal(t, int64(1), m.ModuleMetrics["foobar"]["raw_auction"].MutationErrorCounter.Count())
+	assert.Equal(t, int64(1), m.getAccountMetrics("acc-1").moduleMetrics["foobar"].MutationErrorCounter.Count())
+}
+
+func TestRecordModuleSuccessNooped(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, nil, config.DisabledMetrics{}, nil, map[string][]string{"foobar": {"raw_auction"}})
+
+	m.RecordModuleSuccessNooped(ModuleLabels{Module: "foobar", Stage: "raw_auction", AccountID: "acc-1"}, "foo")
+	m.RecordModuleSuccessNooped(ModuleLabels{Module: "foobar", Stage: "raw_auction"}, "bar")
+	m.RecordModuleSuccessNooped(ModuleLabels{Module: "unknown", Stage: "raw_auction"}, "foo")
+
+	assert.Equal(t, int64(2), m.ModuleMetrics["foobar"]["raw_auction"].SuccessNoopCounter.Count())
+	assert.Equal(t, int64(1), m.getAccountMetrics("acc-1").moduleMetrics["foobar"].SuccessNoopCounter.Count())
+	assert.Nil(t, registry.Get("modules.module.foobar.stage.raw_auction.hook.foo.success.noop"), "Per hook counter shouldn't be registered.")
+}
+
 func ensureContainsBidTypeMetrics(t *testing.T, registry metrics.Registry, prefix string, mdm map[openrtb_ext.BidType]*MarkupDeliveryMetrics) {
 	ensureContains(t, registry, prefix+".banner.adm_bids_received", mdm[openrtb_ext.BidTypeBanner].AdmMeter)
 	ensureContains(t, registry, prefix+".banner.nurl_bids_received", mdm[openrtb_ext.BidTypeBanner].NurlMeter)
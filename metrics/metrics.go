@@ -431,6 +431,8 @@ type MetricsEngine interface {
 	RecordTimeoutNotice(success bool)
 	RecordRequestPrivacy(privacy PrivacyLabels)
 	RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.BidderName)
+	// RecordAdapterAccountRequestBlocked records a request to the adapter skipped as the adapter is not allowed for the account.
+	RecordAdapterAccountRequestBlocked(adapterName openrtb_ext.BidderName)
 	RecordDebugRequest(debugEnabled bool, pubId string)
 	RecordStoredResponse(pubId string)
 	RecordAdsCertReq(success bool)
@@ -441,9 +443,32 @@ type MetricsEngine interface {
 	RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.BidderName, account string)
 	RecordModuleCalled(labels ModuleLabels, duration time.Duration)
 	RecordModuleFailed(labels ModuleLabels)
-	RecordModuleSuccessNooped(labels ModuleLabels)
+	// RecordModuleSuccessNooped records the successful hook execution without mutations and rejection,
+	// the hook code helps to find hooks having no effect on the request.
+	RecordModuleSuccessNooped(labels ModuleLabels, hookCode string)
 	RecordModuleSuccessUpdated(labels ModuleLabels)
 	RecordModuleSuccessRejected(labels ModuleLabels)
+	// RecordModuleSuccessCacheHit records the module serving a cached response instead of running the auction.
+	RecordModuleSuccessCacheHit(labels ModuleLabels)
 	RecordModuleExecutionError(labels ModuleLabels)
 	RecordModuleTimeout(labels ModuleLabels)
+	// RecordModuleMutationError records a failure to apply a mutation returned by the module hook.
+	RecordModuleMutationError(labels ModuleLabels, hookCode string)
+	RecordRequestBodySizeExceeded()
+	// RecordRejectedRequest records the rejection of the request by the module at the given stage with the no-bid reason code.
+	RecordRejectedRequest(stage, module string, code int)
+	RecordBidderResponseError(adapterName openrtb_ext.BidderName, errorType AdapterError)
+	RecordBidValidationImpIDError(adapter openrtb_ext.BidderName)
+	RecordBidValidationMaxCPMError(adapter openrtb_ext.BidderName)
+	RecordBidValidationFloorError(adapter openrtb_ext.BidderName)
+	// RecordRequestCompression records the size of the bidder request body before and after the endpoint compression.
+	RecordRequestCompression(adapter openrtb_ext.BidderName, originalBytes, compressedBytes int)
+	// RecordDuplicateBids records the number of bids removed as duplicates of bids returned under another seat of the adapter.
+	RecordDuplicateBids(adapter openrtb_ext.BidderName, count int)
+	// RecordNoBidResponse records a 204 No Content response of the adapter treated as a no-bid.
+	RecordNoBidResponse(adapter openrtb_ext.BidderName)
+	// RecordGzipProbe records whether the adapter accepted a bid request sent gzip compressed to probe the compression support.
+	RecordGzipProbe(adapter openrtb_ext.BidderName, success bool)
+	// RecordAdapterRequestFanout records the number of HTTP requests the adapter made out of a single bid request.
+	RecordAdapterRequestFanout(adapter openrtb_ext.BidderName, count int)
 }
@@ -146,6 +146,11 @@ func (me *MetricsEngineMock) RecordAdapterGDPRRequestBlocked(adapterName openrtb
 	me.Called(adapterName)
 }
 
+// RecordAdapterAccountRequestBlocked mock
+func (me *MetricsEngineMock) RecordAdapterAccountRequestBlocked(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
 // RecordDebugRequest mock
 func (me *MetricsEngineMock) RecordDebugRequest(debugEnabled bool, pubId string) {
 	me.Called(debugEnabled, pubId)
@@ -187,8 +192,8 @@ func (me *MetricsEngineMock) RecordModuleFailed(labels ModuleLabels) {
 	me.Called(labels)
 }
 
-func (me *MetricsEngineMock) RecordModuleSuccessNooped(labels ModuleLabels) {
-	me.Called(labels)
+func (me *MetricsEngineMock) RecordModuleSuccessNooped(labels ModuleLabels, hookCode string) {
+	me.Called(labels, hookCode)
 }
 
 func (me *MetricsEngineMock) RecordModuleSuccessUpdated(labels ModuleLabels) {
@@ -199,6 +204,10 @@ func (me *MetricsEngineMock) RecordModuleSuccessRejected(labels ModuleLabels) {
 	me.Called(labels)
 }
 
+func (me *MetricsEngineMock) RecordModuleSuccessCacheHit(labels ModuleLabels) {
+	me.Called(labels)
+}
+
 func (me *MetricsEngineMock) RecordModuleExecutionError(labels ModuleLabels) {
 	me.Called(labels)
 }
@@ -206,3 +215,51 @@ func (me *MetricsEngineMock) RecordModuleExecutionError(labels ModuleLabels) {
 func (me *MetricsEngineMock) RecordModuleTimeout(labels ModuleLabels) {
 	me.Called(labels)
 }
+
+func (me *MetricsEngineMock) RecordModuleMutationError(labels ModuleLabels, hookCode string) {
+	me.Called(labels, hookCode)
+}
+
+func (me *MetricsEngineMock) RecordRequestBodySizeExceeded() {
+	me.Called()
+}
+
+func (me *MetricsEngineMock) RecordRejectedRequest(stage, module string, code int) {
+	me.Called(stage, module, code)
+}
+
+func (me *MetricsEngineMock) RecordBidderResponseError(adapterName openrtb_ext.BidderName, errorType AdapterError) {
+	me.Called(adapterName, errorType)
+}
+
+func (me *MetricsEngineMock) RecordBidValidationImpIDError(adapter openrtb_ext.BidderName) {
+	me.Called(adapter)
+}
+
+func (me *MetricsEngineMock) RecordBidValidationMaxCPMError(adapter openrtb_ext.BidderName) {
+	me.Called(adapter)
+}
+
+func (me *MetricsEngineMock) RecordBidValidationFloorError(adapter openrtb_ext.BidderName) {
+	me.Called(adapter)
+}
+
+func (me *MetricsEngineMock) RecordRequestCompression(adapter openrtb_ext.BidderName, originalBytes, compressedBytes int) {
+	me.Called(adapter, originalBytes, compressedBytes)
+}
+
+func (me *MetricsEngineMock) RecordDuplicateBids(adapter openrtb_ext.BidderName, count int) {
+	me.Called(adapter, count)
+}
+
+func (me *MetricsEngineMock) RecordNoBidResponse(adapter openrtb_ext.BidderName) {
+	me.Called(adapter)
+}
+
+func (me *MetricsEngineMock) RecordGzipProbe(adapter openrtb_ext.BidderName, success bool) {
+	me.Called(adapter, success)
+}
+
+func (me *MetricsEngineMock) RecordAdapterRequestFanout(adapter openrtb_ext.BidderName, count int) {
+	me.Called(adapter, count)
+}
@@ -241,15 +241,15 @@ func preloadLabelValues(m *Metrics, syncerKeys []string, moduleStageNames map[st
 			stageLabel: stageValues,
 		})
 
-		preloadLabelValuesForCounter(m.moduleSuccessNoops[module], map[string][]string{
+		preloadLabelValuesForCounter(m.moduleSuccessUpdates[module], map[string][]string{
 			stageLabel: stageValues,
 		})
 
-		preloadLabelValuesForCounter(m.moduleSuccessUpdates[module], map[string][]string{
+		preloadLabelValuesForCounter(m.moduleSuccessRejects[module], map[string][]string{
 			stageLabel: stageValues,
 		})
 
-		preloadLabelValuesForCounter(m.moduleSuccessRejects[module], map[string][]string{
+		preloadLabelValuesForCounter(m.moduleSuccessCacheHits[module], map[string][]string{
 			stageLabel: stageValues,
 		})
 
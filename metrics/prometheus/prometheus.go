@@ -52,6 +52,8 @@ type Metrics struct {
 	privacyLMT                   *prometheus.CounterVec
 	privacyTCF                   *prometheus.CounterVec
 	storedResponses              prometheus.Counter
+	requestBodySizeExceeded      prometheus.Counter
+	rejectedRequests             *prometheus.CounterVec
 	storedResponsesFetchTimer    *prometheus.HistogramVec
 	storedResponsesErrors        *prometheus.CounterVec
 	adsCertRequests              *prometheus.CounterVec
@@ -60,6 +62,7 @@ type Metrics struct {
 	// Adapter Metrics
 	adapterBids                           *prometheus.CounterVec
 	adapterErrors                         *prometheus.CounterVec
+	adapterResponseErrors                 *prometheus.CounterVec
 	adapterPanics                         *prometheus.CounterVec
 	adapterPrices                         *prometheus.HistogramVec
 	adapterRequests                       *prometheus.CounterVec
@@ -72,6 +75,15 @@ type Metrics struct {
 	adapterBidResponseValidationSizeWarn  *prometheus.CounterVec
 	adapterBidResponseSecureMarkupError   *prometheus.CounterVec
 	adapterBidResponseSecureMarkupWarn    *prometheus.CounterVec
+	adapterBidResponseValidationImpID     *prometheus.CounterVec
+	adapterBidResponseValidationMaxCPM    *prometheus.CounterVec
+	adapterBidResponseValidationFloor     *prometheus.CounterVec
+	adapterRequestCompressionRatio        *prometheus.HistogramVec
+	adapterDuplicateBids                  *prometheus.CounterVec
+	adapterNoBidResponses                 *prometheus.CounterVec
+	adapterGzipProbes                     *prometheus.CounterVec
+	adapterRequestFanout                  *prometheus.HistogramVec
+	adapterAccountBlockedRequests         *prometheus.CounterVec
 
 	// Syncer Metrics
 	syncerRequests *prometheus.CounterVec
@@ -88,14 +100,16 @@ type Metrics struct {
 	accountBidResponseSecureMarkupWarn  *prometheus.CounterVec
 
 	// Module Metrics as a map where the key is the module name
-	moduleDuration        map[string]*prometheus.HistogramVec
-	moduleCalls           map[string]*prometheus.CounterVec
-	moduleFailures        map[string]*prometheus.CounterVec
-	moduleSuccessNoops    map[string]*prometheus.CounterVec
-	moduleSuccessUpdates  map[string]*prometheus.CounterVec
-	moduleSuccessRejects  map[string]*prometheus.CounterVec
-	moduleExecutionErrors map[string]*prometheus.CounterVec
-	moduleTimeouts        map[string]*prometheus.CounterVec
+	moduleDuration         map[string]*prometheus.HistogramVec
+	moduleCalls            map[string]*prometheus.CounterVec
+	moduleFailures         map[string]*prometheus.CounterVec
+	moduleSuccessNoops     map[string]*prometheus.CounterVec
+	moduleSuccessUpdates   map[string]*prometheus.CounterVec
+	moduleSuccessRejects   map[string]*prometheus.CounterVec
+	moduleSuccessCacheHits map[string]*prometheus.CounterVec
+	moduleExecutionErrors  map[string]*prometheus.CounterVec
+	moduleMutationErrors   map[string]*prometheus.CounterVec
+	moduleTimeouts         map[string]*prometheus.CounterVec
 
 	metricsDisabled config.DisabledMetrics
 }
@@ -107,14 +121,17 @@ const (
 	adapterLabel         = "adapter"
 	bidTypeLabel         = "bid_type"
 	cacheResultLabel     = "cache_result"
+	codeLabel            = "code"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
 	hasBidsLabel         = "has_bids"
+	hookLabel            = "hook"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
 	isNativeLabel        = "native"
 	isVideoLabel         = "video"
 	markupDeliveryLabel  = "delivery"
+	moduleLabel          = "module"
 	optOutLabel          = "opt_out"
 	privacyBlockedLabel  = "privacy_blocked"
 	requestStatusLabel   = "request_status"
@@ -162,6 +179,8 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 	cacheWriteTimeBuckets := []float64{0.001, 0.002, 0.005, 0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 1}
 	priceBuckets := []float64{250, 500, 750, 1000, 1500, 2000, 2500, 3000, 3500, 4000}
 	queuedRequestTimeBuckets := []float64{0, 1, 5, 30, 60, 120, 180, 240, 300}
+	compressionRatioBuckets := []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
+	requestFanoutBuckets := []float64{1, 2, 3, 5, 10, 20, 50, 100}
 
 	metrics := Metrics{}
 	reg := prometheus.NewRegistry()
@@ -352,6 +371,15 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"stored_responses",
 		"Count of total requests to Prebid Server that have stored responses")
 
+	metrics.requestBodySizeExceeded = newCounterWithoutLabels(cfg, reg,
+		"request_body_size_exceeded",
+		"Count of requests rejected before hook execution because their body exceeds the max size")
+
+	metrics.rejectedRequests = newCounter(cfg, reg,
+		"requests_rejected_by_modules",
+		"Count of requests rejected by module hooks labeled by stage, module and reject code.",
+		[]string{stageLabel, moduleLabel, codeLabel})
+
 	metrics.adapterBids = newCounter(cfg, reg,
 		"adapter_bids",
 		"Count of bids labeled by adapter and markup delivery type (adm or nurl).",
@@ -362,6 +390,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of errors labeled by adapter and error type.",
 		[]string{adapterLabel, adapterErrorLabel})
 
+	metrics.adapterResponseErrors = newCounter(cfg, reg,
+		"adapter_response_errors",
+		"Count of errors returned while processing bidder responses labeled by adapter and error type.",
+		[]string{adapterLabel, adapterErrorLabel})
+
 	metrics.adapterPanics = newCounter(cfg, reg,
 		"adapter_panics",
 		"Count of panics labeled by adapter.",
@@ -416,6 +449,53 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count that tracks number of bids removed from bid response that had a invalid bidAdm (warn)",
 		[]string{adapterLabel, successLabel})
 
+	metrics.adapterBidResponseValidationImpID = newCounter(cfg, reg,
+		"adapter_response_validation_impid_err",
+		"Count that tracks number of bids removed from bid response that had an imp ID not present in the bid request",
+		[]string{adapterLabel})
+
+	metrics.adapterBidResponseValidationMaxCPM = newCounter(cfg, reg,
+		"adapter_response_validation_maxcpm_err",
+		"Count that tracks number of bids removed from bid response that had a price above the account max bid CPM",
+		[]string{adapterLabel})
+
+	metrics.adapterBidResponseValidationFloor = newCounter(cfg, reg,
+		"adapter_response_validation_floor_err",
+		"Count that tracks number of bids removed from bid response that had a price below the imp bid floor",
+		[]string{adapterLabel})
+
+	metrics.adapterRequestCompressionRatio = newHistogramVec(cfg, reg,
+		"adapter_request_compression_ratio",
+		"Ratio of the compressed to the original size of the request bodies labeled by adapter.",
+		[]string{adapterLabel},
+		compressionRatioBuckets)
+
+	metrics.adapterDuplicateBids = newCounter(cfg, reg,
+		"adapter_duplicate_bids",
+		"Count that tracks number of bids removed from bid response as duplicates of bids returned under another seat of the adapter",
+		[]string{adapterLabel})
+
+	metrics.adapterNoBidResponses = newCounter(cfg, reg,
+		"adapter_no_bid_responses",
+		"Count that tracks number of 204 No Content responses treated as no-bids labeled by adapter",
+		[]string{adapterLabel})
+
+	metrics.adapterGzipProbes = newCounter(cfg, reg,
+		"adapter_gzip_probes",
+		"Count of gzip compressed requests sent to probe the compression support labeled by adapter and success.",
+		[]string{adapterLabel, successLabel})
+
+	metrics.adapterRequestFanout = newHistogramVec(cfg, reg,
+		"adapter_request_fanout",
+		"Number of HTTP requests made out of a single bid request labeled by adapter.",
+		[]string{adapterLabel},
+		requestFanoutBuckets)
+
+	metrics.adapterAccountBlockedRequests = newCounter(cfg, reg,
+		"adapter_account_requests_blocked",
+		"Count of total bidder requests skipped as the bidder is not allowed for the account",
+		[]string{adapterLabel})
+
 	metrics.adapterRequestsTimer = newHistogramVec(cfg, reg,
 		"adapter_request_time_seconds",
 		"Seconds to resolve each successful request labeled by adapter.",
@@ -511,7 +591,9 @@ func createModulesMetrics(cfg config.PrometheusMetrics, registry *prometheus.Reg
 	m.moduleSuccessNoops = make(map[string]*prometheus.CounterVec, l)
 	m.moduleSuccessUpdates = make(map[string]*prometheus.CounterVec, l)
 	m.moduleSuccessRejects = make(map[string]*prometheus.CounterVec, l)
+	m.moduleSuccessCacheHits = make(map[string]*prometheus.CounterVec, l)
 	m.moduleExecutionErrors = make(map[string]*prometheus.CounterVec, l)
+	m.moduleMutationErrors = make(map[string]*prometheus.CounterVec, l)
 	m.moduleTimeouts = make(map[string]*prometheus.CounterVec, l)
 
 	// create for each registered module its own metric
@@ -534,8 +616,8 @@ func createModulesMetrics(cfg config.PrometheusMetrics, registry *prometheus.Reg
 
 		m.moduleSuccessNoops[module] = newCounter(cfg, registry,
 			fmt.Sprintf("modules_%s_success_noops", module),
-			"Count of module successful noops labeled by stage name.",
-			[]string{stageLabel})
+			"Count of module successful noops labeled by stage name and hook code.",
+			[]string{stageLabel, hookLabel})
 
 		m.moduleSuccessUpdates[module] = newCounter(cfg, registry,
 			fmt.Sprintf("modules_%s_success_updates", module),
@@ -547,6 +629,11 @@ func createModulesMetrics(cfg config.PrometheusMetrics, registry *prometheus.Reg
 			"Count of module successful rejects labeled by stage name.",
 			[]string{stageLabel})
 
+		m.moduleSuccessCacheHits[module] = newCounter(cfg, registry,
+			fmt.Sprintf("modules_%s_success_cache_hits", module),
+			"Count of cached responses served by module instead of running auction labeled by stage name.",
+			[]string{stageLabel})
+
 		m.moduleExecutionErrors[module] = newCounter(cfg, registry,
 			fmt.Sprintf("modules_%s_execution_errors", module),
 			"Count of module execution errors labeled by stage name.",
@@ -556,6 +643,11 @@ func createModulesMetrics(cfg config.PrometheusMetrics, registry *prometheus.Reg
 			fmt.Sprintf("modules_%s_timeouts", module),
 			"Count of module timeouts labeled by stage name.",
 			[]string{stageLabel})
+
+		m.moduleMutationErrors[module] = newCounter(cfg, registry,
+			fmt.Sprintf("modules_%s_mutation_errors", module),
+			"Count of module hook mutations failed to apply labeled by stage name and hook code.",
+			[]string{stageLabel, hookLabel})
 	}
 }
 
@@ -931,6 +1023,12 @@ func (m *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidder
 	}).Inc()
 }
 
+func (m *Metrics) RecordAdapterAccountRequestBlocked(adapterName openrtb_ext.BidderName) {
+	m.adapterAccountBlockedRequests.With(prometheus.Labels{
+		adapterLabel: string(adapterName),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdsCertReq(success bool) {
 	if success {
 		m.adsCertRequests.With(prometheus.Labels{
@@ -1010,9 +1108,10 @@ func (m *Metrics) RecordModuleFailed(labels metrics.ModuleLabels) {
 	}).Inc()
 }
 
-func (m *Metrics) RecordModuleSuccessNooped(labels metrics.ModuleLabels) {
+func (m *Metrics) RecordModuleSuccessNooped(labels metrics.ModuleLabels, hookCode string) {
 	m.moduleSuccessNoops[labels.Module].With(prometheus.Labels{
 		stageLabel: labels.Stage,
+		hookLabel:  hookCode,
 	}).Inc()
 }
 
@@ -1028,6 +1127,12 @@ func (m *Metrics) RecordModuleSuccessRejected(labels metrics.ModuleLabels) {
 	}).Inc()
 }
 
+func (m *Metrics) RecordModuleSuccessCacheHit(labels metrics.ModuleLabels) {
+	m.moduleSuccessCacheHits[labels.Module].With(prometheus.Labels{
+		stageLabel: labels.Stage,
+	}).Inc()
+}
+
 func (m *Metrics) RecordModuleExecutionError(labels metrics.ModuleLabels) {
 	m.moduleExecutionErrors[labels.Module].With(prometheus.Labels{
 		stageLabel: labels.Stage,
@@ -1039,3 +1144,81 @@ func (m *Metrics) RecordModuleTimeout(labels metrics.ModuleLabels) {
 		stageLabel: labels.Stage,
 	}).Inc()
 }
+
+func (m *Metrics) RecordModuleMutationError(labels metrics.ModuleLabels, hookCode string) {
+	m.moduleMutationErrors[labels.Module].With(prometheus.Labels{
+		stageLabel: labels.Stage,
+		hookLabel:  hookCode,
+	}).Inc()
+}
+
+func (m *Metrics) RecordRequestBodySizeExceeded() {
+	m.requestBodySizeExceeded.Inc()
+}
+
+func (m *Metrics) RecordRejectedRequest(stage, module string, code int) {
+	m.rejectedRequests.With(prometheus.Labels{
+		stageLabel:  stage,
+		moduleLabel: module,
+		codeLabel:   strconv.Itoa(code),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidderResponseError(adapterName openrtb_ext.BidderName, errorType metrics.AdapterError) {
+	m.adapterResponseErrors.With(prometheus.Labels{
+		adapterLabel:      string(adapterName),
+		adapterErrorLabel: string(errorType),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationImpIDError(adapter openrtb_ext.BidderName) {
+	m.adapterBidResponseValidationImpID.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationMaxCPMError(adapter openrtb_ext.BidderName) {
+	m.adapterBidResponseValidationMaxCPM.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Inc()
+}
+
+func (m *Metrics) RecordBidValidationFloorError(adapter openrtb_ext.BidderName) {
+	m.adapterBidResponseValidationFloor.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Inc()
+}
+
+func (m *Metrics) RecordRequestCompression(adapter openrtb_ext.BidderName, originalBytes, compressedBytes int) {
+	if originalBytes <= 0 {
+		return
+	}
+	m.adapterRequestCompressionRatio.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Observe(float64(compressedBytes) / float64(originalBytes))
+}
+
+func (m *Metrics) RecordDuplicateBids(adapter openrtb_ext.BidderName, count int) {
+	m.adapterDuplicateBids.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Add(float64(count))
+}
+
+func (m *Metrics) RecordNoBidResponse(adapter openrtb_ext.BidderName) {
+	m.adapterNoBidResponses.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Inc()
+}
+
+func (m *Metrics) RecordGzipProbe(adapter openrtb_ext.BidderName, success bool) {
+	m.adapterGzipProbes.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+		successLabel: strconv.FormatBool(success),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterRequestFanout(adapter openrtb_ext.BidderName, count int) {
+	m.adapterRequestFanout.With(prometheus.Labels{
+		adapterLabel: string(adapter),
+	}).Observe(float64(count))
+}
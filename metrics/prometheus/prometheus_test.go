@@ -1066,6 +1066,128 @@ func TestAdapterPanicMetric(t *testing.T) {
 		})
 }
 
+func TestBidderResponseErrorMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordBidderResponseError(openrtb_ext.BidderAppnexus, metrics.AdapterErrorBadServerResponse)
+	m.RecordBidderResponseError(openrtb_ext.BidderAppnexus, metrics.AdapterErrorBadServerResponse)
+	m.RecordBidderResponseError(openrtb_ext.BidderAppnexus, metrics.AdapterErrorBadInput)
+
+	assertCounterVecValue(t, "", "adapterResponseErrors:badserverresponse", m.adapterResponseErrors,
+		float64(2),
+		prometheus.Labels{
+			adapterLabel:      string(openrtb_ext.BidderAppnexus),
+			adapterErrorLabel: string(metrics.AdapterErrorBadServerResponse),
+		})
+	assertCounterVecValue(t, "", "adapterResponseErrors:badinput", m.adapterResponseErrors,
+		float64(1),
+		prometheus.Labels{
+			adapterLabel:      string(openrtb_ext.BidderAppnexus),
+			adapterErrorLabel: string(metrics.AdapterErrorBadInput),
+		})
+}
+
+func TestBidValidationImpIDErrorMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordBidValidationImpIDError(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t, "", "adapterBidResponseValidationImpID", m.adapterBidResponseValidationImpID,
+		float64(1),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
+func TestRecordRequestCompression(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordRequestCompression(openrtb_ext.BidderAppnexus, 1000, 250)
+	m.RecordRequestCompression(openrtb_ext.BidderAppnexus, 0, 20)
+
+	result := getHistogramFromHistogramVec(m.adapterRequestCompressionRatio, adapterLabel, string(openrtb_ext.BidderAppnexus))
+	assertHistogram(t, "adapterRequestCompressionRatio", result, 1, 0.25)
+}
+
+func TestRecordDuplicateBids(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordDuplicateBids(openrtb_ext.BidderAppnexus, 2)
+
+	assertCounterVecValue(t, "", "adapterDuplicateBids", m.adapterDuplicateBids,
+		float64(2),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
+func TestRecordNoBidResponse(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordNoBidResponse(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t, "", "adapterNoBidResponses", m.adapterNoBidResponses,
+		float64(1),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
+func TestRecordGzipProbe(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordGzipProbe(openrtb_ext.BidderAppnexus, true)
+	m.RecordGzipProbe(openrtb_ext.BidderAppnexus, false)
+	m.RecordGzipProbe(openrtb_ext.BidderAppnexus, false)
+
+	assertCounterVecValue(t, "", "adapterGzipProbes", m.adapterGzipProbes,
+		float64(1),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+			successLabel: "true",
+		})
+	assertCounterVecValue(t, "", "adapterGzipProbes", m.adapterGzipProbes,
+		float64(2),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+			successLabel: "false",
+		})
+}
+
+func TestRecordAdapterRequestFanout(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterRequestFanout(openrtb_ext.BidderAppnexus, 1)
+	m.RecordAdapterRequestFanout(openrtb_ext.BidderAppnexus, 4)
+
+	result := getHistogramFromHistogramVec(m.adapterRequestFanout, adapterLabel, string(openrtb_ext.BidderAppnexus))
+	assertHistogram(t, "adapterRequestFanout", result, 2, 5)
+}
+
+func TestBidValidationMaxCPMErrorMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordBidValidationMaxCPMError(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t, "", "adapterBidResponseValidationMaxCPM", m.adapterBidResponseValidationMaxCPM,
+		float64(1),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
+func TestBidValidationFloorErrorMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordBidValidationFloorError(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t, "", "adapterBidResponseValidationFloor", m.adapterBidResponseValidationFloor,
+		float64(1),
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
 func TestStoredReqCacheResultMetric(t *testing.T) {
 	m := createMetricsForTesting()
 
@@ -1701,6 +1823,21 @@ func assertHistogram(t *testing.T, name string, histogram dto.Histogram, expecte
 	assert.Equal(t, expectedSum, histogram.GetSampleSum(), name+":sum")
 }
 
+func TestRecordAdapterAccountRequestBlocked(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordAdapterAccountRequestBlocked(openrtb_ext.BidderAppnexus)
+
+	assertCounterVecValue(t,
+		"Increment adapter account request blocked counter",
+		"adapter_account_requests_blocked",
+		m.adapterAccountBlockedRequests,
+		1,
+		prometheus.Labels{
+			adapterLabel: string(openrtb_ext.BidderAppnexus),
+		})
+}
+
 func TestRecordAdapterGDPRRequestBlocked(t *testing.T) {
 	m := createMetricsForTesting()
 
@@ -1849,6 +1986,17 @@ func TestRecordAdsCertSignTime(t *testing.T) {
 	}
 }
 
+func TestRecordRejectedRequest(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordRejectedRequest("raw_auction_request", "foobar", 2)
+	m.RecordRejectedRequest("raw_auction_request", "foobar", 2)
+	m.RecordRejectedRequest("bidder_request", "foobar", 3)
+
+	assertCounterVecValue(t, "", "rejected at raw auction stage", m.rejectedRequests, 2, prometheus.Labels{stageLabel: "raw_auction_request", moduleLabel: "foobar", codeLabel: "2"})
+	assertCounterVecValue(t, "", "rejected at bidder request stage", m.rejectedRequests, 1, prometheus.Labels{stageLabel: "bidder_request", moduleLabel: "foobar", codeLabel: "3"})
+}
+
 func TestRecordModuleMetrics(t *testing.T) {
 	m := createMetricsForTesting()
 
@@ -1867,7 +2015,7 @@ func TestRecordModuleMetrics(t *testing.T) {
 			m.RecordModuleSuccessNooped(metrics.ModuleLabels{
 				Module: module,
 				Stage:  stage,
-			})
+			}, "hook-code")
 			m.RecordModuleSuccessUpdated(metrics.ModuleLabels{
 				Module: module,
 				Stage:  stage,
@@ -1876,6 +2024,10 @@ func TestRecordModuleMetrics(t *testing.T) {
 				Module: module,
 				Stage:  stage,
 			})
+			m.RecordModuleSuccessCacheHit(metrics.ModuleLabels{
+				Module: module,
+				Stage:  stage,
+			})
 			m.RecordModuleExecutionError(metrics.ModuleLabels{
 				Module: module,
 				Stage:  stage,
@@ -1884,17 +2036,23 @@ func TestRecordModuleMetrics(t *testing.T) {
 				Module: module,
 				Stage:  stage,
 			})
+			m.RecordModuleMutationError(metrics.ModuleLabels{
+				Module: module,
+				Stage:  stage,
+			}, "hook-code")
 
 			// now check that the values are correct
 			result := getHistogramFromHistogramVec(m.moduleDuration[module], stageLabel, stage)
 			assertHistogram(t, fmt.Sprintf("module_%s_duration", module), result, 1, 0.001)
 			assertCounterVecValue(t, "Module calls performed", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleCalls[module], 1, prometheus.Labels{stageLabel: stage})
 			assertCounterVecValue(t, "Module calls failed", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleFailures[module], 1, prometheus.Labels{stageLabel: stage})
-			assertCounterVecValue(t, "Module success noop action", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleSuccessNoops[module], 1, prometheus.Labels{stageLabel: stage})
+			assertCounterVecValue(t, "Module success noop action", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleSuccessNoops[module], 1, prometheus.Labels{stageLabel: stage, hookLabel: "hook-code"})
 			assertCounterVecValue(t, "Module success update action", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleSuccessUpdates[module], 1, prometheus.Labels{stageLabel: stage})
 			assertCounterVecValue(t, "Module success reject action", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleSuccessRejects[module], 1, prometheus.Labels{stageLabel: stage})
+			assertCounterVecValue(t, "Module success cache hit action", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleSuccessCacheHits[module], 1, prometheus.Labels{stageLabel: stage})
 			assertCounterVecValue(t, "Module execution error", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleExecutionErrors[module], 1, prometheus.Labels{stageLabel: stage})
 			assertCounterVecValue(t, "Module timeout", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleTimeouts[module], 1, prometheus.Labels{stageLabel: stage})
+			assertCounterVecValue(t, "Module mutation error", fmt.Sprintf("%s metric recorded during %s stage", module, stage), m.moduleMutationErrors[module], 1, prometheus.Labels{stageLabel: stage, hookLabel: "hook-code"})
 		}
 	}
 }
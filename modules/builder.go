@@ -1,15 +1,13 @@
 package modules
 
+// modules register their builders on init, see moduleregistry.Register
 import (
-	prebidOrtb2blocking "github.com/prebid/prebid-server/modules/prebid/ortb2blocking"
+	_ "github.com/prebid/prebid-server/modules/prebid/maximps"
+	_ "github.com/prebid/prebid-server/modules/prebid/ortb2blocking"
 )
 
-// builders returns mapping between module name and its builder
-// vendor and module names are chosen based on the module directory name
-func builders() ModuleBuilders {
-	return ModuleBuilders{
-		"prebid": {
-			"ortb2blocking": prebidOrtb2blocking.Builder,
-		},
-	}
+// schemas returns mapping between module name and its config schema
+// only modules providing a schema.go file are listed
+func schemas() ModuleSchemas {
+	return ModuleSchemas{}
 }
@@ -18,11 +18,14 @@ var (
 	r        = regexp.MustCompile("^([^/]+)/([^/]+)/module.go$")
 	tmplName = "builder.tmpl"
 	outName  = "builder.go"
+	// schemaName is a file in the module directory expected to define the module's Schema function
+	schemaName = "schema.go"
 )
 
 type Module struct {
-	Vendor string
-	Module string
+	Vendor    string
+	Module    string
+	HasSchema bool
 }
 
 func main() {
@@ -33,9 +36,11 @@ func main() {
 			return nil
 		}
 		match := r.FindStringSubmatch(path)
+		_, statErr := os.Stat(filepath.Join(match[1], match[2], schemaName))
 		modules = append(modules, Module{
-			Vendor: match[1],
-			Module: match[2],
+			Vendor:    match[1],
+			Module:    match[2],
+			HasSchema: statErr == nil,
 		})
 		return nil
 	})
@@ -39,6 +39,12 @@ func createModuleStageNamesCollection(modules map[string]interface{}) (map[strin
 			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
 		}
 
+		if _, ok := hook.(hookstage.BidderHttpRequest); ok {
+			added = true
+			stageName := hooks.StageBidderHttpRequest.String()
+			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
+		}
+
 		if _, ok := hook.(hookstage.RawBidderResponse); ok {
 			added = true
 			stageName := hooks.StageRawBidderResponse.String()
@@ -0,0 +1,63 @@
+package moduleregistry
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/modules/moduledeps"
+)
+
+// BuilderFn returns an interface{} type that implements certain hook interfaces.
+type BuilderFn func(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error)
+
+var defaultRegistry = newRegistry()
+
+// Register adds the module builder to the registry of modules available to the server.
+// It is intended to be called from the init function of the module package,
+// registering the same vendor and module pair more than once causes a panic.
+func Register(vendor, module string, fn BuilderFn) {
+	defaultRegistry.register(vendor, module, fn)
+}
+
+// Builders returns the builders of all registered modules: map[vendor]map[module]BuilderFn.
+func Builders() map[string]map[string]BuilderFn {
+	return defaultRegistry.copy()
+}
+
+type registry struct {
+	builders map[string]map[string]BuilderFn
+}
+
+func newRegistry() *registry {
+	return &registry{builders: make(map[string]map[string]BuilderFn)}
+}
+
+func (r *registry) register(vendor, module string, fn BuilderFn) {
+	if vendor == "" || module == "" {
+		panic(fmt.Sprintf("module registration requires vendor and module names, got vendor: %q, module: %q", vendor, module))
+	}
+
+	if fn == nil {
+		panic(fmt.Sprintf("module %s.%s registered with nil builder", vendor, module))
+	}
+
+	if _, ok := r.builders[vendor][module]; ok {
+		panic(fmt.Sprintf("module %s.%s registered more than once", vendor, module))
+	}
+
+	if r.builders[vendor] == nil {
+		r.builders[vendor] = make(map[string]BuilderFn)
+	}
+	r.builders[vendor][module] = fn
+}
+
+func (r *registry) copy() map[string]map[string]BuilderFn {
+	builders := make(map[string]map[string]BuilderFn, len(r.builders))
+	for vendor, modules := range r.builders {
+		builders[vendor] = make(map[string]BuilderFn, len(modules))
+		for module, fn := range modules {
+			builders[vendor][module] = fn
+		}
+	}
+	return builders
+}
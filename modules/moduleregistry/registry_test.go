@@ -0,0 +1,73 @@
+package moduleregistry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/modules/moduledeps"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	r := newRegistry()
+	r.register("acme", "foo", fakeBuilder)
+	r.register("acme", "bar", fakeBuilder)
+	r.register("prebid", "foo", fakeBuilder)
+
+	builders := r.copy()
+
+	assert.Len(t, builders, 2)
+	assert.Len(t, builders["acme"], 2)
+	assert.Len(t, builders["prebid"], 1)
+	assert.NotNil(t, builders["acme"]["bar"])
+
+	delete(builders, "acme")
+	assert.Len(t, r.copy(), 2, "Registry should not be changed through the returned builders.")
+}
+
+func TestRegisterPanics(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenVendor   string
+		givenModule   string
+		givenBuilder  BuilderFn
+		expectedPanic string
+	}{
+		{
+			description:   "Duplicate registration",
+			givenVendor:   "acme",
+			givenModule:   "foo",
+			givenBuilder:  fakeBuilder,
+			expectedPanic: "module acme.foo registered more than once",
+		},
+		{
+			description:   "Empty module name",
+			givenVendor:   "acme",
+			givenModule:   "",
+			givenBuilder:  fakeBuilder,
+			expectedPanic: `module registration requires vendor and module names, got vendor: "acme", module: ""`,
+		},
+		{
+			description:   "Nil builder",
+			givenVendor:   "acme",
+			givenModule:   "bar",
+			givenBuilder:  nil,
+			expectedPanic: "module acme.bar registered with nil builder",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			r := newRegistry()
+			r.register("acme", "foo", fakeBuilder)
+
+			assert.PanicsWithValue(t, test.expectedPanic, func() {
+				r.register(test.givenVendor, test.givenModule, test.givenBuilder)
+			})
+		})
+	}
+}
+
+func fakeBuilder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	return nil, nil
+}
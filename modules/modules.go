@@ -2,19 +2,25 @@ package modules
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/config"
 	"github.com/prebid/prebid-server/hooks"
 	"github.com/prebid/prebid-server/modules/moduledeps"
+	"github.com/prebid/prebid-server/modules/moduleregistry"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 //go:generate go run ./generator/buildergen.go
 
 // NewBuilder returns a new module builder.
 func NewBuilder() Builder {
-	return &builder{builders()}
+	return &builder{builders: builders(), schemas: schemas()}
 }
 
 // Builder is the interfaces intended for building modules
@@ -24,7 +30,9 @@ type Builder interface {
 	// It returns hook repository created based on the implemented hook interfaces by modules
 	// and a map of modules to a list of stage names for which module provides hooks
 	// or an error encountered during module initialization.
-	Build(cfg config.Modules, client moduledeps.ModuleDeps) (hooks.HookRepository, map[string][]string, error)
+	// The returned shutdown function closes the built modules implementing io.Closer,
+	// it is intended to be called by the host once the server stops.
+	Build(cfg config.Modules, client moduledeps.ModuleDeps) (hooks.HookRepository, map[string][]string, func(), error)
 }
 
 type (
@@ -32,10 +40,28 @@ type (
 	ModuleBuilders map[string]map[string]ModuleBuilderFn
 	// ModuleBuilderFn returns an interface{} type that implements certain hook interfaces.
 	ModuleBuilderFn func(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error)
+	// ModuleSchemas mapping between module name and its optional config schema: map[vendor]map[module]ModuleSchemaFn
+	ModuleSchemas map[string]map[string]ModuleSchemaFn
+	// ModuleSchemaFn returns a JSON schema used to validate module config before passing it to the ModuleBuilderFn.
+	ModuleSchemaFn func() json.RawMessage
 )
 
+// builders returns mapping between module name and its builder
+// composed of the modules registered via moduleregistry.Register
+func builders() ModuleBuilders {
+	moduleBuilders := make(ModuleBuilders)
+	for vendor, modules := range moduleregistry.Builders() {
+		moduleBuilders[vendor] = make(map[string]ModuleBuilderFn, len(modules))
+		for moduleName, fn := range modules {
+			moduleBuilders[vendor][moduleName] = ModuleBuilderFn(fn)
+		}
+	}
+	return moduleBuilders
+}
+
 type builder struct {
 	builders ModuleBuilders
+	schemas  ModuleSchemas
 }
 
 // Build walks over the list of registered modules and initializes them.
@@ -43,12 +69,17 @@ type builder struct {
 // The ID chosen for the module's hooks represents a fully qualified module path in the format
 // "vendor.module_name" and should be used to retrieve module hooks from the hooks.HookRepository.
 //
-// Method returns a hooks.HookRepository and a map of modules to a list of stage names
-// for which module provides hooks or an error occurred during modules initialization.
+// Method returns a hooks.HookRepository, a map of modules to a list of stage names
+// for which module provides hooks and a function closing the modules implementing io.Closer
+// or an error occurred during modules initialization.
 func (m *builder) Build(
 	cfg config.Modules,
 	deps moduledeps.ModuleDeps,
-) (hooks.HookRepository, map[string][]string, error) {
+) (hooks.HookRepository, map[string][]string, func(), error) {
+	if err := checkModuleIDs(m.builders); err != nil {
+		return nil, nil, nil, err
+	}
+
 	modules := make(map[string]interface{})
 	for vendor, moduleBuilders := range m.builders {
 		for moduleName, builder := range moduleBuilders {
@@ -59,7 +90,7 @@ func (m *builder) Build(
 			id := fmt.Sprintf("%s.%s", vendor, moduleName)
 			if data, ok := cfg[vendor][moduleName]; ok {
 				if conf, err = json.Marshal(data); err != nil {
-					return nil, nil, fmt.Errorf(`failed to marshal "%s" module config: %s`, id, err)
+					return nil, nil, nil, fmt.Errorf(`failed to marshal "%s" module config: %s`, id, err)
 				}
 
 				if values, ok := data.(map[string]interface{}); ok {
@@ -74,9 +105,15 @@ func (m *builder) Build(
 				continue
 			}
 
+			if schema, ok := m.schemas[vendor][moduleName]; ok {
+				if err = validateConfig(schema(), conf); err != nil {
+					return nil, nil, nil, fmt.Errorf(`invalid config for module "%s": %s`, id, err)
+				}
+			}
+
 			module, err := builder(conf, deps)
 			if err != nil {
-				return nil, nil, fmt.Errorf(`failed to init "%s" module: %s`, id, err)
+				return nil, nil, nil, fmt.Errorf(`failed to init "%s" module: %s`, id, err)
 			}
 
 			modules[id] = module
@@ -85,10 +122,75 @@ func (m *builder) Build(
 
 	collection, err := createModuleStageNamesCollection(modules)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	repo, err := hooks.NewHookRepository(modules)
 
-	return repo, collection, err
+	return repo, collection, closeModulesFn(modules), err
+}
+
+// closeModulesFn returns a function closing the modules implementing io.Closer in the order of their IDs.
+// Errors are logged, so a module failing to close doesn't prevent closing the others.
+func closeModulesFn(modules map[string]interface{}) func() {
+	ids := make([]string, 0, len(modules))
+	for id, module := range modules {
+		if _, ok := module.(io.Closer); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return func() {
+		for _, id := range ids {
+			if err := modules[id].(io.Closer).Close(); err != nil {
+				glog.Errorf("Failed to close %s module: %s", id, err)
+			}
+		}
+	}
+}
+
+// checkModuleIDs ensures no two registered modules share the same "vendor.module_name" ID,
+// which is possible if the vendor or module names contain dots. Otherwise one of the modules
+// would silently replace the other in the hook repository.
+func checkModuleIDs(builders ModuleBuilders) error {
+	modulesByID := make(map[string][]string)
+	for vendor, moduleBuilders := range builders {
+		for moduleName := range moduleBuilders {
+			id := fmt.Sprintf("%s.%s", vendor, moduleName)
+			modulesByID[id] = append(modulesByID[id], fmt.Sprintf("vendor %q module %q", vendor, moduleName))
+		}
+	}
+
+	var conflicts []string
+	for id, modules := range modulesByID {
+		if len(modules) > 1 {
+			sort.Strings(modules)
+			conflicts = append(conflicts, fmt.Sprintf(`"%s" used by %s`, id, strings.Join(modules, ", ")))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("duplicate module IDs: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// validateConfig checks module config against the JSON schema provided by module.
+func validateConfig(schema, conf json.RawMessage) error {
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(conf))
+	if err != nil {
+		return fmt.Errorf("failed to validate config against schema: %s", err)
+	}
+
+	if !result.Valid() {
+		errs := make([]string, 0, len(result.Errors()))
+		for _, resultErr := range result.Errors() {
+			errs = append(errs, resultErr.String())
+		}
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
 }
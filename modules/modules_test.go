@@ -33,6 +33,7 @@ func TestModuleBuilderBuild(t *testing.T) {
 		givenModule           interface{}
 		givenConfig           config.Modules
 		givenHookBuilderErr   error
+		givenSchema           json.RawMessage
 		expectedHookRepo      hooks.HookRepository
 		expectedModulesStages map[string][]string
 		expectedErr           error
@@ -87,6 +88,30 @@ func TestModuleBuilderBuild(t *testing.T) {
 			expectedModulesStages: nil,
 			expectedErr:           fmt.Errorf(`failed to marshal "%s.%s" module config: json: unsupported value: +Inf`, vendor, moduleName),
 		},
+		"Can build module with config matching schema": {
+			givenModule:           module{},
+			givenConfig:           defaultModulesConfig,
+			givenSchema:           json.RawMessage(`{"type": "object", "properties": {"enabled": {"type": "boolean"}}}`),
+			expectedModulesStages: map[string][]string{vendor + "_" + moduleName: {hooks.StageEntrypoint.String(), hooks.StageAuctionResponse.String()}},
+			expectedHookRepo:      defaultHookRepository,
+			expectedErr:           nil,
+		},
+		"Fails if config does not match module schema": {
+			givenModule:           module{},
+			givenConfig:           map[string]map[string]interface{}{vendor: {moduleName: map[string]interface{}{"enabled": true, "attr": 1}}},
+			givenSchema:           json.RawMessage(`{"type": "object", "properties": {"attr": {"type": "string"}}}`),
+			expectedHookRepo:      nil,
+			expectedModulesStages: nil,
+			expectedErr:           fmt.Errorf(`invalid config for module "%s.%s": attr: Invalid type. Expected: string, given: integer`, vendor, moduleName),
+		},
+		"Fails if module schema is malformed": {
+			givenModule:           module{},
+			givenConfig:           defaultModulesConfig,
+			givenSchema:           json.RawMessage(`{"type": 1}`),
+			expectedHookRepo:      nil,
+			expectedModulesStages: nil,
+			expectedErr:           fmt.Errorf(`invalid config for module "%s.%s": failed to validate config against schema: Invalid type. Expected: string/array of strings, given: type`, vendor, moduleName),
+		},
 	}
 
 	for name, test := range testCases {
@@ -100,8 +125,15 @@ func TestModuleBuilderBuild(t *testing.T) {
 					},
 				},
 			}
+			if test.givenSchema != nil {
+				builder.schemas = ModuleSchemas{
+					vendor: {
+						moduleName: func() json.RawMessage { return test.givenSchema },
+					},
+				}
+			}
 
-			repo, modulesStages, err := builder.Build(test.givenConfig, moduledeps.ModuleDeps{HTTPClient: http.DefaultClient})
+			repo, modulesStages, _, err := builder.Build(test.givenConfig, moduledeps.ModuleDeps{HTTPClient: http.DefaultClient})
 			assert.Equal(t, test.expectedErr, err)
 			assert.Equal(t, test.expectedModulesStages, modulesStages)
 			assert.Equal(t, test.expectedHookRepo, repo)
@@ -109,6 +141,73 @@ func TestModuleBuilderBuild(t *testing.T) {
 	}
 }
 
+func TestModuleBuilderBuildDuplicateIDs(t *testing.T) {
+	builderFn := func(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) { return module{}, nil }
+	builder := &builder{
+		builders: ModuleBuilders{
+			"acme":     {"foo.bar": builderFn, "baz": builderFn},
+			"acme.foo": {"bar": builderFn},
+		},
+		schemas: ModuleSchemas{},
+	}
+
+	repo, modulesStages, shutdown, err := builder.Build(config.Modules{"acme": {"baz": map[string]interface{}{"enabled": true}}}, moduledeps.ModuleDeps{})
+
+	assert.EqualError(t, err, `duplicate module IDs: "acme.foo.bar" used by vendor "acme" module "foo.bar", vendor "acme.foo" module "bar"`)
+	assert.Nil(t, repo)
+	assert.Nil(t, modulesStages)
+	assert.Nil(t, shutdown)
+}
+
+func TestModuleBuilderBuildShutdown(t *testing.T) {
+	closed := make([]string, 0, 2)
+	closingBuilderFn := func(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+		var name string
+		if err := json.Unmarshal(cfg, &struct{ Name *string }{&name}); err != nil {
+			return nil, err
+		}
+		return closingModule{close: func() error {
+			closed = append(closed, name)
+			if name == "foo" {
+				return errors.New("failed to stop")
+			}
+			return nil
+		}}, nil
+	}
+	builder := &builder{
+		builders: ModuleBuilders{
+			"acme": {
+				"foo":      closingBuilderFn,
+				"bar":      closingBuilderFn,
+				"disabled": closingBuilderFn,
+				"baz":      func(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) { return module{}, nil },
+			},
+		},
+		schemas: ModuleSchemas{},
+	}
+
+	_, _, shutdown, err := builder.Build(config.Modules{"acme": {
+		"foo":      map[string]interface{}{"enabled": true, "name": "foo"},
+		"bar":      map[string]interface{}{"enabled": true, "name": "bar"},
+		"disabled": map[string]interface{}{"enabled": false, "name": "disabled"},
+		"baz":      map[string]interface{}{"enabled": true},
+	}}, moduledeps.ModuleDeps{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, closed, "Modules shouldn't be closed before shutdown.")
+
+	shutdown()
+
+	assert.Equal(t, []string{"bar", "foo"}, closed, "Enabled modules implementing io.Closer should be closed, regardless of errors.")
+}
+
+func TestBuildersComposedFromRegistry(t *testing.T) {
+	moduleBuilders := builders()
+
+	assert.NotNil(t, moduleBuilders["prebid"]["ortb2blocking"], "Registered module builder expected.")
+}
+
 type module struct{}
 
 func (h module) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.EntrypointPayload) (hookstage.HookResult[hookstage.EntrypointPayload], error) {
@@ -118,3 +217,12 @@ func (h module) HandleEntrypointHook(_ context.Context, _ hookstage.ModuleInvoca
 func (h module) HandleAuctionResponseHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AuctionResponsePayload) (hookstage.HookResult[hookstage.AuctionResponsePayload], error) {
 	return hookstage.HookResult[hookstage.AuctionResponsePayload]{}, nil
 }
+
+type closingModule struct {
+	module
+	close func() error
+}
+
+func (m closingModule) Close() error {
+	return m.close()
+}
@@ -0,0 +1,99 @@
+package maximps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/openrtb/v17/openrtb3"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/hooks/hookstage"
+	"github.com/prebid/prebid-server/modules/moduledeps"
+	"github.com/prebid/prebid-server/modules/moduleregistry"
+)
+
+func init() {
+	moduleregistry.Register("prebid", "maximps", Builder)
+}
+
+// Builder returns the module with the host-level config applied to the accounts not configuring the module.
+func Builder(data json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	cfg, err := newConfig(data, config{})
+	if err != nil {
+		return nil, err
+	}
+	return Module{hostConfig: cfg}, nil
+}
+
+type Module struct {
+	hostConfig config
+}
+
+// HandleProcessedAuctionHook rejects the request if the number of its impressions,
+// counted after the stored requests are merged, exceeds the limit configured for the account.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+	if payload.BidRequest == nil {
+		return result, nil
+	}
+
+	cfg, err := newConfig(miCtx.AccountConfig, m.hostConfig)
+	if err != nil {
+		return result, err
+	}
+	if cfg.MaxImps <= 0 {
+		return result, nil
+	}
+
+	impCount := len(payload.BidRequest.Imp)
+	if impCount <= cfg.MaxImps {
+		return result, nil
+	}
+
+	result.Reject = true
+	result.NbrCode = cfg.nbrCode()
+	result.Message = fmt.Sprintf("request has %d imps, exceeding the limit of %d", impCount, cfg.MaxImps)
+	result.AnalyticsTags = hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:   "enforce-max-imps",
+			Status: hookanalytics.ActivityStatusSuccess,
+			Results: []hookanalytics.Result{{
+				Status:    hookanalytics.ResultStatusBlock,
+				Values:    map[string]interface{}{"imp_count": impCount, "max_imps": cfg.MaxImps},
+				AppliedTo: hookanalytics.AppliedTo{Request: true},
+			}},
+		}},
+	}
+
+	return result, nil
+}
+
+func newConfig(data json.RawMessage, defaultConfig config) (config, error) {
+	cfg := defaultConfig
+	if len(data) == 0 {
+		return cfg, nil
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config: %s", err)
+	}
+	return cfg, nil
+}
+
+type config struct {
+	// MaxImps is the max number of impressions allowed per request, not limited if not positive
+	MaxImps int `json:"max_imps"`
+	// Nbr is the no-bid reason code of the rejection, openrtb3.NoBidInvalidRequest is used if not set
+	Nbr int `json:"nbr"`
+}
+
+func (c config) nbrCode() int {
+	if c.Nbr != 0 {
+		return c.Nbr
+	}
+	return int(openrtb3.NoBidInvalidRequest)
+}
@@ -0,0 +1,192 @@
+package maximps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/openrtb/v17/openrtb3"
+	pbsconfig "github.com/prebid/prebid-server/config"
+	"github.com/prebid/prebid-server/hooks"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/hooks/hookexecution"
+	"github.com/prebid/prebid-server/hooks/hookstage"
+	metricsConfig "github.com/prebid/prebid-server/metrics/config"
+	"github.com/prebid/prebid-server/modules/moduledeps"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	request := &openrtb2.BidRequest{ID: "some-id", Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}, {ID: "imp3"}}}
+
+	testCases := []struct {
+		description      string
+		givenHostConfig  json.RawMessage
+		givenConfig      json.RawMessage
+		givenBidRequest  *openrtb2.BidRequest
+		expectedResult   hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]
+		expectedErrorMsg string
+	}{
+		{
+			description:     "Request rejected if imp count exceeds limit",
+			givenConfig:     json.RawMessage(`{"max_imps":2}`),
+			givenBidRequest: request,
+			expectedResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				Reject:  true,
+				NbrCode: int(openrtb3.NoBidInvalidRequest),
+				Message: "request has 3 imps, exceeding the limit of 2",
+				AnalyticsTags: hookanalytics.Analytics{
+					Activities: []hookanalytics.Activity{{
+						Name:   "enforce-max-imps",
+						Status: hookanalytics.ActivityStatusSuccess,
+						Results: []hookanalytics.Result{{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"imp_count": 3, "max_imps": 2},
+							AppliedTo: hookanalytics.AppliedTo{Request: true},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			description:     "Request rejected with configured NBR code",
+			givenConfig:     json.RawMessage(`{"max_imps":1,"nbr":123}`),
+			givenBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}},
+			expectedResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				Reject:  true,
+				NbrCode: 123,
+				Message: "request has 2 imps, exceeding the limit of 1",
+				AnalyticsTags: hookanalytics.Analytics{
+					Activities: []hookanalytics.Activity{{
+						Name:   "enforce-max-imps",
+						Status: hookanalytics.ActivityStatusSuccess,
+						Results: []hookanalytics.Result{{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"imp_count": 2, "max_imps": 1},
+							AppliedTo: hookanalytics.AppliedTo{Request: true},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			description:     "Request allowed if imp count within limit",
+			givenConfig:     json.RawMessage(`{"max_imps":3}`),
+			givenBidRequest: request,
+			expectedResult:  hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:     "Request allowed if limit not configured",
+			givenBidRequest: request,
+			expectedResult:  hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:     "Host config used if account config absent",
+			givenHostConfig: json.RawMessage(`{"enabled":true,"max_imps":2}`),
+			givenBidRequest: request,
+			expectedResult: hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{
+				Reject:  true,
+				NbrCode: int(openrtb3.NoBidInvalidRequest),
+				Message: "request has 3 imps, exceeding the limit of 2",
+				AnalyticsTags: hookanalytics.Analytics{
+					Activities: []hookanalytics.Activity{{
+						Name:   "enforce-max-imps",
+						Status: hookanalytics.ActivityStatusSuccess,
+						Results: []hookanalytics.Result{{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"imp_count": 3, "max_imps": 2},
+							AppliedTo: hookanalytics.AppliedTo{Request: true},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			description:     "Account config overrides host config",
+			givenHostConfig: json.RawMessage(`{"enabled":true,"max_imps":2}`),
+			givenConfig:     json.RawMessage(`{"max_imps":5}`),
+			givenBidRequest: request,
+			expectedResult:  hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:     "Nothing done if request empty",
+			givenConfig:     json.RawMessage(`{"max_imps":2}`),
+			givenBidRequest: nil,
+			expectedResult:  hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+		},
+		{
+			description:      "Error if account config invalid",
+			givenConfig:      json.RawMessage(`{"max_imps":"2"}`),
+			givenBidRequest:  request,
+			expectedResult:   hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{},
+			expectedErrorMsg: "failed to parse config",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			module, err := Builder(test.givenHostConfig, moduledeps.ModuleDeps{})
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			result, err := module.(Module).HandleProcessedAuctionHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.givenConfig},
+				hookstage.ProcessedAuctionRequestPayload{BidRequest: test.givenBidRequest},
+			)
+
+			if test.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, test.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedResult, result)
+		})
+	}
+}
+
+func TestBuilderInvalidConfig(t *testing.T) {
+	_, err := Builder(json.RawMessage(`{"max_imps":`), moduledeps.ModuleDeps{})
+
+	assert.Error(t, err)
+}
+
+func TestRejectionRecordedInStageOutcome(t *testing.T) {
+	exec := hookexecution.NewHookExecutor(testPlanBuilder{hook: Module{hostConfig: config{MaxImps: 1}}}, hookexecution.EndpointAuction, &metricsConfig.NilMetricsEngine{})
+
+	reject := exec.ExecuteProcessedAuctionStage(&openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}, {ID: "imp2"}}})
+
+	if !assert.NotNil(t, reject, "Request should be rejected.") {
+		return
+	}
+	assert.Equal(t, int(openrtb3.NoBidInvalidRequest), reject.NBR)
+	assert.Equal(t, "Module prebid.maximps (hook: processed-auction) rejected request with code 2 at processed_auction_request stage: request has 2 imps, exceeding the limit of 1", reject.Error())
+
+	stageOutcomes := exec.GetOutcomes()
+	if !assert.Len(t, stageOutcomes, 1) {
+		return
+	}
+	hookOutcome := stageOutcomes[0].Groups[0].InvocationResults[0]
+	assert.Equal(t, hookexecution.ActionReject, hookOutcome.Action)
+	assert.Equal(t, "request has 2 imps, exceeding the limit of 1", hookOutcome.Message)
+	assert.Equal(t, map[string]interface{}{"imp_count": 2, "max_imps": 1}, hookOutcome.AnalyticsTags.Activities[0].Results[0].Values)
+}
+
+type testPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hook Module
+}
+
+func (b testPlanBuilder) PlanForProcessedAuctionStage(_ string, _ *pbsconfig.Account) hooks.Plan[hookstage.ProcessedAuctionRequest] {
+	return hooks.Plan[hookstage.ProcessedAuctionRequest]{
+		hooks.Group[hookstage.ProcessedAuctionRequest]{
+			Timeout: 10 * time.Millisecond,
+			Hooks: []hooks.HookWrapper[hookstage.ProcessedAuctionRequest]{
+				{Module: "prebid.maximps", Code: "processed-auction", Hook: b.hook},
+			},
+		},
+	}
+}
@@ -1,22 +1,123 @@
 package ortb2blocking
 
 import (
+	"bytes"
+	"container/list"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v17/adcom1"
 )
 
+// maxLoggedExpiredOverrides limits the number of expired overrides remembered as already logged,
+// the least recently seen ones are forgotten and may be logged again.
+const maxLoggedExpiredOverrides = 1000
+
+// loggedExpiredOverrides holds the expired overrides already reported to the log
+var loggedExpiredOverrides = newOverridesLRU(maxLoggedExpiredOverrides)
+
+// overridesLRU is a set of override keys bounded by its size, dropping the least recently used keys.
+type overridesLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newOverridesLRU(size int) *overridesLRU {
+	return &overridesLRU{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// add stores the key and reports whether it was already present.
+func (l *overridesLRU) add(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, ok := l.entries[key]; ok {
+		l.order.MoveToFront(entry)
+		return true
+	}
+
+	l.entries[key] = l.order.PushFront(key)
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(string))
+	}
+	return false
+}
+
 func newConfig(data json.RawMessage) (config, error) {
 	var cfg config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return cfg, fmt.Errorf("failed to parse config: %s", err)
 	}
+	cfg.hasExpiringOverrides = bytes.Contains(data, []byte(`"expires_at"`))
 	return cfg, nil
 }
 
 type config struct {
 	Attributes Attributes `json:"attributes"`
+
+	// hasExpiringOverrides is set if any override may have the expires_at field,
+	// otherwise there is nothing to remove by removeExpiredOverrides.
+	hasExpiringOverrides bool
+}
+
+// removeExpiredOverrides drops the action overrides expired by the given time,
+// each expired override is logged only once.
+func (c *config) removeExpiredOverrides(now time.Time) {
+	if !c.hasExpiringOverrides {
+		return
+	}
+
+	attributes := &c.Attributes
+	overridesByField := map[string]*[]ActionOverride{
+		"badv.allowed_adomain_for_deals":      &attributes.Badv.ActionOverrides.AllowedAdomainForDeals,
+		"badv.blocked_adomain":                &attributes.Badv.ActionOverrides.BlockedAdomain,
+		"badv.block_unknown_adomain":          &attributes.Badv.ActionOverrides.BlockUnknownAdomain,
+		"badv.enforce_blocks":                 &attributes.Badv.ActionOverrides.EnforceBlocks,
+		"bcat.allowed_adv_cat_for_deals":      &attributes.Bcat.ActionOverrides.AllowedAdvCatForDeals,
+		"bcat.blocked_adv_cat":                &attributes.Bcat.ActionOverrides.BlockedAdvCat,
+		"bcat.block_unknown_adv_cat":          &attributes.Bcat.ActionOverrides.BlockUnknownAdvCat,
+		"bcat.category_taxonomy":              &attributes.Bcat.ActionOverrides.CategoryTaxonomy,
+		"bcat.enforce_blocks":                 &attributes.Bcat.ActionOverrides.EnforceBlocks,
+		"bapp.allowed_app_for_deals":          &attributes.Bapp.ActionOverrides.AllowedAppForDeals,
+		"bapp.blocked_app":                    &attributes.Bapp.ActionOverrides.BlockedApp,
+		"bapp.enforce_blocks":                 &attributes.Bapp.ActionOverrides.EnforceBlocks,
+		"btype.blocked_banner_type":           &attributes.Btype.ActionOverrides.BlockedBannerType,
+		"battr.allowed_banner_attr_for_deals": &attributes.Battr.ActionOverrides.AllowedBannerAttrForDeals,
+		"battr.blocked_banner_attr":           &attributes.Battr.ActionOverrides.BlockedBannerAttr,
+		"battr.enforce_blocks":                &attributes.Battr.ActionOverrides.EnforceBlocks,
+	}
+
+	for field, overrides := range overridesByField {
+		if len(*overrides) == 0 {
+			continue
+		}
+
+		active := make([]ActionOverride, 0, len(*overrides))
+		for _, override := range *overrides {
+			if !override.isExpired(now) {
+				active = append(active, override)
+				continue
+			}
+
+			expiresAt := override.ExpiresAt.Format(time.RFC3339)
+			key := fmt.Sprintf("%s %s %+v %+v", field, expiresAt, override.Conditions, override.Override)
+			if logged := loggedExpiredOverrides.add(key); !logged {
+				glog.Warningf("ortb2blocking: ignoring %s override expired at %s", field, expiresAt)
+			}
+		}
+		*overrides = active
+	}
 }
 
 type Attributes struct {
@@ -32,7 +133,7 @@ type Badv struct {
 	AllowedAdomainForDeals []string           `json:"allowed_adomain_for_deals"`
 	BlockedAdomain         []string           `json:"blocked_adomain"`
 	BlockUnknownAdomain    bool               `json:"block_unknown_adomain"`
-	EnforceBlocks          bool               `json:"enforce_blocks"`
+	EnforceBlocks          EnforceBlocksMode  `json:"enforce_blocks"`
 }
 
 type BadvActionOverride struct {
@@ -48,13 +149,14 @@ type Bcat struct {
 	BlockedAdvCat         []string                `json:"blocked_adv_cat"`
 	BlockUnknownAdvCat    bool                    `json:"block_unknown_adv_cat"`
 	CategoryTaxonomy      adcom1.CategoryTaxonomy `json:"category_taxonomy"`
-	EnforceBlocks         bool                    `json:"enforce_blocks"`
+	EnforceBlocks         EnforceBlocksMode       `json:"enforce_blocks"`
 }
 
 type BcatActionOverride struct {
 	AllowedAdvCatForDeals []ActionOverride `json:"allowed_adv_cat_for_deals"`
 	BlockedAdvCat         []ActionOverride `json:"blocked_adv_cat"`
 	BlockUnknownAdvCat    []ActionOverride `json:"block_unknown_adv_cat"`
+	CategoryTaxonomy      []ActionOverride `json:"category_taxonomy"`
 	EnforceBlocks         []ActionOverride `json:"enforce_blocks"`
 }
 
@@ -62,7 +164,7 @@ type Bapp struct {
 	ActionOverrides    BappActionOverride `json:"action_overrides"`
 	AllowedAppForDeals []string           `json:"allowed_app_for_deals"`
 	BlockedApp         []string           `json:"blocked_app"`
-	EnforceBlocks      bool               `json:"enforce_blocks"`
+	EnforceBlocks      EnforceBlocksMode  `json:"enforce_blocks"`
 }
 
 type BappActionOverride struct {
@@ -84,7 +186,34 @@ type Battr struct {
 	ActionOverrides           BattrActionOverride `json:"action_overrides"`
 	AllowedBannerAttrForDeals []int               `json:"allowed_banner_attr_for_deals"`
 	BlockedBannerAttr         []int               `json:"blocked_banner_attr"`
-	EnforceBlocks             bool                `json:"enforce_blocks"`
+	// BlockedBannerAttrGroups blocks combinations of creative attributes, a bid matches the group
+	// only if it has all attributes of the group, e.g. [[1, 6]] blocks bids with both attributes 1 and 6.
+	// Single attribute groups are equal to the blocked_banner_attr entries.
+	BlockedBannerAttrGroups [][]int           `json:"blocked_banner_attr_groups"`
+	EnforceBlocks           EnforceBlocksMode `json:"enforce_blocks"`
+}
+
+// blockedBannerAttr returns the attributes blocked individually, which can be sent to the bidders in imp.banner.battr.
+// Groups of several attributes are excluded, as the bidders treat imp.banner.battr entries independently.
+func (b Battr) blockedBannerAttr() []int {
+	battr := b.BlockedBannerAttr
+	for _, group := range b.BlockedBannerAttrGroups {
+		if len(group) == 1 && !containsInt(battr, group[0]) {
+			battr = append(battr[:len(battr):len(battr)], group[0])
+		}
+	}
+	return battr
+}
+
+// combinedBannerAttrGroups returns the groups of several attributes, which are checked in bidder responses only.
+func (b Battr) combinedBannerAttrGroups() [][]int {
+	var groups [][]int
+	for _, group := range b.BlockedBannerAttrGroups {
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
 }
 
 type BattrActionOverride struct {
@@ -93,21 +222,80 @@ type BattrActionOverride struct {
 	EnforceBlocks             []ActionOverride `json:"enforce_blocks"`
 }
 
+// EnforceBlocksMode defines how bids having blocked attributes are handled:
+// "off" skips the check, "warn" reports the matches through the hook analytics
+// keeping the bids, "enforce" drops the bids.
+type EnforceBlocksMode string
+
+const (
+	EnforceBlocksOff     EnforceBlocksMode = "off"
+	EnforceBlocksWarn    EnforceBlocksMode = "warn"
+	EnforceBlocksEnforce EnforceBlocksMode = "enforce"
+)
+
+// UnmarshalJSON accepts the mode name as well as the legacy boolean,
+// where false stands for "off" and true for "enforce".
+func (m *EnforceBlocksMode) UnmarshalJSON(bytes []byte) error {
+	var modeData interface{}
+	if err := json.Unmarshal(bytes, &modeData); err != nil {
+		return err
+	}
+
+	switch mode := modeData.(type) {
+	case bool:
+		if mode {
+			*m = EnforceBlocksEnforce
+		} else {
+			*m = EnforceBlocksOff
+		}
+	case string:
+		switch EnforceBlocksMode(mode) {
+		case EnforceBlocksOff, EnforceBlocksWarn, EnforceBlocksEnforce:
+			*m = EnforceBlocksMode(mode)
+		default:
+			return fmt.Errorf("invalid enforce_blocks mode: %s", mode)
+		}
+	case nil:
+		*m = EnforceBlocksOff
+	default:
+		return fmt.Errorf("invalid enforce_blocks value: %s", bytes)
+	}
+
+	return nil
+}
+
 type ActionOverride struct {
 	Conditions Conditions `json:"conditions"`
 	Override   Override   `json:"override"`
+	// ExpiresAt is an optional RFC3339 timestamp after which the override is ignored,
+	// overrides without expiry are permanent.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (a ActionOverride) isExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
 }
 
+// Conditions define when the action override is applied. Entries of bidders, media_types and deal_ids
+// prefixed with "!" exclude the value, e.g. ["!video"] matches all media types except video.
+// Negated entries take precedence over the positive ones, and if the list has positive entries
+// the value must match one of them as well. Empty list matches all values.
+// Deal IDs are known only for the bids, so overrides having deal_ids apply to the response side checks only.
 type Conditions struct {
 	Bidders    []string `json:"bidders"`
 	MediaTypes []string `json:"media_types"`
 	DealIds    []string `json:"deal_ids"`
+	// Sizes in the WxH format matched against imp banner sizes,
+	// applies only to the impression level attributes (btype, battr).
+	Sizes []string `json:"sizes"`
 }
 
 type Override struct {
 	IsActive bool
 	Ids      []int
 	Names    []string
+	// Name holds the override given as a single string, e.g. the enforce_blocks mode
+	Name string
 }
 
 func (o *Override) UnmarshalJSON(bytes []byte) error {
@@ -119,6 +307,10 @@ func (o *Override) UnmarshalJSON(bytes []byte) error {
 	switch overrideValue := overrideData.(type) {
 	case bool:
 		o.IsActive = overrideValue
+	case string:
+		o.Name = overrideValue
+	case float64:
+		o.Ids = []int{int(overrideValue)}
 	case []interface{}:
 		for _, value := range overrideValue {
 			switch override := value.(type) {
@@ -2,6 +2,7 @@ package ortb2blocking
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prebid/openrtb/v17/adcom1"
 	"github.com/stretchr/testify/assert"
@@ -194,6 +195,10 @@ var fullConfig = []byte(`
         9,
         10
       ],
+      "blocked_banner_attr_groups": [
+        [2],
+        [4, 6]
+      ],
       "action_overrides": {
         "enforce_blocks": [
           {
@@ -216,7 +221,7 @@ func TestNewConfig(t *testing.T) {
 	require.NoError(t, err)
 
 	// badv
-	assert.True(t, c.Attributes.Badv.EnforceBlocks, "attributes.badv.enforce_blocks")
+	assert.Equal(t, EnforceBlocksEnforce, c.Attributes.Badv.EnforceBlocks, "attributes.badv.enforce_blocks")
 	assert.True(t, c.Attributes.Badv.BlockUnknownAdomain, "attributes.badv.block_unknown_adomain")
 	assert.Equal(t, []string{"a.com", "b.com", "c.com"}, c.Attributes.Badv.BlockedAdomain, "attributes.badv.blocked_adomain")
 	assert.Equal(t, []string{"z.com", "x.com"}, c.Attributes.Badv.AllowedAdomainForDeals, "attributes.badv.allowed_adomain_for_deals")
@@ -249,7 +254,7 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, []string{"a.com"}, c.Attributes.Badv.ActionOverrides.AllowedAdomainForDeals[0].Override.Names, "attributes.badv.action_overrides[0].allowed_adomain_for_deals[0].override")
 
 	// bcat
-	assert.False(t, c.Attributes.Bcat.EnforceBlocks, "attributes.bcat.enforce_blocks")
+	assert.Equal(t, EnforceBlocksOff, c.Attributes.Bcat.EnforceBlocks, "attributes.bcat.enforce_blocks")
 	assert.False(t, c.Attributes.Bcat.BlockUnknownAdvCat, "attributes.bcat.block_unknown_adv_cat")
 	assert.Equal(t, adcom1.CategoryTaxonomy(6), c.Attributes.Bcat.CategoryTaxonomy, "attributes.bcat.category_taxonomy")
 	assert.Equal(t, []string{"IAB-1", "IAB-2"}, c.Attributes.Bcat.BlockedAdvCat, "attributes.bcat.blocked_adv_cat")
@@ -278,7 +283,7 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, []string{"IAB-1"}, c.Attributes.Bcat.ActionOverrides.AllowedAdvCatForDeals[0].Override.Names, "attributes.bcat.action_overrides[0].allowed_adv_cat_for_deals[0].override")
 
 	// bapp
-	assert.False(t, c.Attributes.Bapp.EnforceBlocks, "attributes.bapp.enforce_blocks")
+	assert.Equal(t, EnforceBlocksOff, c.Attributes.Bapp.EnforceBlocks, "attributes.bapp.enforce_blocks")
 	assert.Equal(t, []string{"app1", "app2"}, c.Attributes.Bapp.BlockedApp, "attributes.bapp.blocked_app")
 	assert.Empty(t, c.Attributes.Bapp.AllowedAppForDeals, "attributes.bapp.allowed_app_for_deals")
 
@@ -300,8 +305,9 @@ func TestNewConfig(t *testing.T) {
 
 	// battr
 	assert.Empty(t, c.Attributes.Battr.AllowedBannerAttrForDeals, "attributes.battr.allowed_banner_attr_for_deals")
-	assert.False(t, c.Attributes.Battr.EnforceBlocks, "attributes.battr.enforce_blocks")
+	assert.Equal(t, EnforceBlocksOff, c.Attributes.Battr.EnforceBlocks, "attributes.battr.enforce_blocks")
 	assert.Equal(t, []int{1, 8, 9, 10}, c.Attributes.Battr.BlockedBannerAttr, "attributes.battr.blocked_banner_attr")
+	assert.Equal(t, [][]int{{2}, {4, 6}}, c.Attributes.Battr.BlockedBannerAttrGroups, "attributes.battr.blocked_banner_attr_groups")
 
 	assert.Empty(t, c.Attributes.Battr.ActionOverrides.AllowedBannerAttrForDeals, "attributes.battr.action_overrides[0].allowed_banner_attr_for_deals")
 	assert.Empty(t, c.Attributes.Battr.ActionOverrides.BlockedBannerAttr, "attributes.battr.action_overrides[0].blocked_banner_attr")
@@ -332,8 +338,102 @@ func TestOverride_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, override.UnmarshalJSON([]byte(`["one", "two"]`)), "Failed to unmarshal override with Names.")
 	assert.Equal(t, Override{Names: []string{"one", "two"}}, override, "Invalid override.Names.")
 
+	// expect single ID to be initialized from number
+	override = Override{}
+	assert.NoError(t, override.UnmarshalJSON([]byte("7")), "Failed to unmarshal numeric override.")
+	assert.Equal(t, Override{Ids: []int{7}}, override, "Invalid override.IDs.")
+
+	// expect Name to be initialized from string
+	override = Override{}
+	assert.NoError(t, override.UnmarshalJSON([]byte(`"warn"`)), "Failed to unmarshal string override.")
+	assert.Equal(t, Override{Name: "warn"}, override, "Invalid override.Name.")
+
 	// expect empty override on ignored JSON
 	override = Override{}
-	assert.NoError(t, override.UnmarshalJSON([]byte(`"string"`)), "Failed to unmarshal override with ignored value.")
+	assert.NoError(t, override.UnmarshalJSON([]byte(`{"key": "value"}`)), "Failed to unmarshal override with ignored value.")
 	assert.Equal(t, Override{}, override, "Empty override expected.")
 }
+
+func TestEnforceBlocksMode_UnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		description   string
+		givenJSON     string
+		expectedMode  EnforceBlocksMode
+		expectedError string
+	}{
+		{description: "Legacy false is off", givenJSON: `false`, expectedMode: EnforceBlocksOff},
+		{description: "Legacy true is enforce", givenJSON: `true`, expectedMode: EnforceBlocksEnforce},
+		{description: "Null is off", givenJSON: `null`, expectedMode: EnforceBlocksOff},
+		{description: "Off mode", givenJSON: `"off"`, expectedMode: EnforceBlocksOff},
+		{description: "Warn mode", givenJSON: `"warn"`, expectedMode: EnforceBlocksWarn},
+		{description: "Enforce mode", givenJSON: `"enforce"`, expectedMode: EnforceBlocksEnforce},
+		{description: "Unknown mode", givenJSON: `"drop"`, expectedError: "invalid enforce_blocks mode: drop"},
+		{description: "Invalid type", givenJSON: `1`, expectedError: "invalid enforce_blocks value: 1"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var mode EnforceBlocksMode
+			err := mode.UnmarshalJSON([]byte(test.givenJSON))
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedMode, mode)
+		})
+	}
+}
+
+func TestRemoveExpiredOverrides(t *testing.T) {
+	c, err := newConfig([]byte(`{"attributes": {
+		"badv": {"action_overrides": {"blocked_adomain": [
+			{"conditions": {"bidders": ["bidderA"]}, "override": ["a.com"], "expires_at": "2023-01-01T00:00:00Z"},
+			{"conditions": {"bidders": ["bidderB"]}, "override": ["b.com"], "expires_at": "2023-01-02T00:00:00Z"},
+			{"conditions": {"bidders": ["bidderC"]}, "override": ["c.com"]}
+		]}},
+		"battr": {"action_overrides": {"blocked_banner_attr": [
+			{"conditions": {"bidders": ["bidderA"]}, "override": [1], "expires_at": "2023-01-01T00:00:00+02:00"}
+		]}}
+	}}`))
+	require.NoError(t, err)
+
+	expiresAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NotNil(t, c.Attributes.Badv.ActionOverrides.BlockedAdomain[0].ExpiresAt, "Override expiry expected.")
+	assert.True(t, expiresAt.Equal(*c.Attributes.Badv.ActionOverrides.BlockedAdomain[0].ExpiresAt), "Invalid override expiry.")
+
+	c.removeExpiredOverrides(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	blockedAdomain := c.Attributes.Badv.ActionOverrides.BlockedAdomain
+	if assert.Len(t, blockedAdomain, 2, "Expired badv override expected to be removed.") {
+		assert.Equal(t, []string{"b.com"}, blockedAdomain[0].Override.Names, "Not expired override expected to be kept.")
+		assert.Equal(t, []string{"c.com"}, blockedAdomain[1].Override.Names, "Override without expiry expected to be kept.")
+	}
+	assert.Empty(t, c.Attributes.Battr.ActionOverrides.BlockedBannerAttr, "Expired battr override expected to be removed.")
+}
+
+func TestRemoveExpiredOverridesWithoutExpiry(t *testing.T) {
+	c, err := newConfig([]byte(`{"attributes": {"badv": {"action_overrides": {"blocked_adomain": [
+		{"conditions": {"bidders": ["bidderA"]}, "override": ["a.com"]}
+	]}}}}`))
+	require.NoError(t, err)
+	assert.False(t, c.hasExpiringOverrides, "Config without expires_at expected to have no expiring overrides.")
+
+	c.removeExpiredOverrides(time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Len(t, c.Attributes.Badv.ActionOverrides.BlockedAdomain, 1, "Override without expiry expected to be kept.")
+}
+
+func TestOverridesLRU(t *testing.T) {
+	lru := newOverridesLRU(2)
+
+	assert.False(t, lru.add("a"), "New key a expected to be added.")
+	assert.False(t, lru.add("b"), "New key b expected to be added.")
+	assert.True(t, lru.add("a"), "Key a expected to be present.")
+	assert.False(t, lru.add("c"), "New key c expected to be added.")
+
+	assert.Equal(t, 2, lru.order.Len(), "Size of the set expected to be bounded.")
+	assert.True(t, lru.add("a"), "Recently used key a expected to be kept.")
+	assert.False(t, lru.add("b"), "Least recently used key b expected to be dropped.")
+}
@@ -44,7 +44,9 @@ func handleBidderRequestHook(
 		return result, hookexecution.NewFailure("failed to update battr field: %s", err)
 	}
 
-	updateCatTax(cfg, payload, &blockingAttributes, &changeSet)
+	if err = updateCatTax(cfg, payload, mediaTypes, &blockingAttributes, &result, &changeSet); err != nil {
+		return result, hookexecution.NewFailure("failed to update cattax field: %s", err)
+	}
 
 	result.ChangeSet = changeSet
 	result.ModuleContext = hookstage.ModuleContext{payload.Bidder: blockingAttributes}
@@ -68,7 +70,7 @@ func updateBAdv(
 	badv := cfg.Attributes.Badv.BlockedAdomain
 	actionOverrides := cfg.Attributes.Badv.ActionOverrides.BlockedAdomain
 
-	attributes.bAdv, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, getNames, actionOverrides, badv)
+	attributes.bAdv, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, "", getNames, actionOverrides, badv)
 	result.Warnings = mergeStrings(result.Warnings, message)
 	if err != nil {
 		return fmt.Errorf("failed to get override for badv.blocked_adomain: %s", err)
@@ -95,7 +97,7 @@ func updateBApp(
 	bapp := cfg.Attributes.Bapp.BlockedApp
 	actionOverrides := cfg.Attributes.Bapp.ActionOverrides.BlockedApp
 
-	attributes.bApp, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, getNames, actionOverrides, bapp)
+	attributes.bApp, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, "", getNames, actionOverrides, bapp)
 	result.Warnings = mergeStrings(result.Warnings, message)
 	if err != nil {
 		return fmt.Errorf("failed to get override for bapp.blocked_app: %s", err)
@@ -114,20 +116,40 @@ func updateBCat(
 	result *hookstage.HookResult[hookstage.BidderRequestPayload],
 	changeSet *hookstage.ChangeSet[hookstage.BidderRequestPayload],
 ) (err error) {
-	if len(payload.BidRequest.BCat) > 0 {
-		return nil
-	}
-
 	var message string
 	bcat := cfg.Attributes.Bcat.BlockedAdvCat
 	actionOverrides := cfg.Attributes.Bcat.ActionOverrides.BlockedAdvCat
 
-	attributes.bCat, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, getNames, actionOverrides, bcat)
+	attributes.bCat, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, "", getNames, actionOverrides, bcat)
 	result.Warnings = mergeStrings(result.Warnings, message)
 	if err != nil {
 		return fmt.Errorf("failed to get override for bcat.blocked_adv_cat: %s", err)
-	} else if len(attributes.bCat) > 0 {
-		changeSet.BidderRequest().BCat().Update(attributes.bCat)
+	}
+
+	if len(attributes.bCat) == 0 {
+		return nil
+	}
+
+	// unlike the other attributes, categories already present in the request are kept and extended
+	// with the blocked ones, provided both lists refer to the same category taxonomy
+	if len(payload.BidRequest.BCat) > 0 {
+		// the warning about several matching conditions is reported on the cattax update
+		catTax, _, err := firstOrDefaultOverride(payload.Bidder, mediaTypes, "", getCategoryTaxonomy, cfg.Attributes.Bcat.ActionOverrides.CategoryTaxonomy, cfg.Attributes.Bcat.CategoryTaxonomy)
+		if err != nil {
+			return fmt.Errorf("failed to get override for bcat.category_taxonomy: %s", err)
+		} else if effectiveCatTax(catTax) != effectiveCatTax(payload.BidRequest.CatTax) {
+			result.Warnings = mergeStrings(result.Warnings, fmt.Sprintf(
+				"Blocked categories not added to bcat. Bidder: %s, request category taxonomy %d differs from configured %d",
+				payload.Bidder,
+				effectiveCatTax(payload.BidRequest.CatTax),
+				effectiveCatTax(catTax),
+			))
+			return nil
+		}
+	}
+
+	if bCat := mergeUnique(payload.BidRequest.BCat, attributes.bCat); len(bCat) > len(payload.BidRequest.BCat) {
+		changeSet.BidderRequest().BCat().Update(bCat)
 	}
 
 	return nil
@@ -167,7 +189,7 @@ func updateBAttr(
 	changeSet *hookstage.ChangeSet[hookstage.BidderRequestPayload],
 ) (err error) {
 	var messages []string
-	battr := cfg.Attributes.Battr.BlockedBannerAttr
+	battr := cfg.Attributes.Battr.blockedBannerAttr()
 	actionOverrides := cfg.Attributes.Battr.ActionOverrides.BlockedBannerAttr
 	checkAttrExistence := func(imp openrtb2.Imp) bool {
 		return imp.Banner != nil && len(imp.Banner.BAttr) > 0
@@ -188,15 +210,36 @@ func updateBAttr(
 func updateCatTax(
 	cfg config,
 	payload hookstage.BidderRequestPayload,
+	mediaTypes mediaTypes,
 	attributes *blockingAttributes,
+	result *hookstage.HookResult[hookstage.BidderRequestPayload],
 	changeSet *hookstage.ChangeSet[hookstage.BidderRequestPayload],
-) {
+) (err error) {
 	if payload.BidRequest.CatTax > 0 {
-		return
+		return nil
+	}
+
+	var message string
+	catTax := cfg.Attributes.Bcat.CategoryTaxonomy
+	actionOverrides := cfg.Attributes.Bcat.ActionOverrides.CategoryTaxonomy
+
+	attributes.catTax, message, err = firstOrDefaultOverride(payload.Bidder, mediaTypes, "", getCategoryTaxonomy, actionOverrides, catTax)
+	result.Warnings = mergeStrings(result.Warnings, message)
+	if err != nil {
+		return fmt.Errorf("failed to get override for bcat.category_taxonomy: %s", err)
 	}
 
-	attributes.catTax = cfg.Attributes.Bcat.CategoryTaxonomy
 	changeSet.BidderRequest().CatTax().Update(attributes.catTax)
+
+	return nil
+}
+
+// effectiveCatTax returns the category taxonomy applying the OpenRTB default of IAB Content Category Taxonomy 1.0 if absent.
+func effectiveCatTax(catTax adcom1.CategoryTaxonomy) adcom1.CategoryTaxonomy {
+	if catTax == 0 {
+		return adcom1.CatTaxIABContent10
+	}
+	return catTax
 }
 
 func bTypeMutation(bTypeByImp map[string][]int) hookstage.MutationFunc[hookstage.BidderRequestPayload] {
@@ -246,9 +289,12 @@ func mutationForImp(
 // firstOrDefaultOverride searches for matching override based on conditions.
 // Returns only first found override. Override for specific bidder has higher priority
 // than override matching all bidders. If no override found, the defaultOverride returned.
+// Overrides with the deal_ids condition match only bids of the matching deals,
+// so they're never applied if the deal ID is empty, e.g. for the bidder request.
 func firstOrDefaultOverride[T any](
 	bidder string,
 	requestMediaTypes mediaTypes,
+	dealID string,
 	overrideGetter overrideGetterFn[T],
 	actionOverrides []ActionOverride,
 	defaultOverride T,
@@ -261,11 +307,13 @@ func firstOrDefaultOverride[T any](
 			return override, message, err
 		}
 
-		matchAllBidders := action.Conditions.Bidders == nil
-		matchesBidder := matchAllBidders || hasMatches(action.Conditions.Bidders, bidder)
-		matchesMedia := action.Conditions.MediaTypes == nil || requestMediaTypes.intersects(action.Conditions.MediaTypes)
+		// conditions excluding bidders only, e.g. ["!appnexus"], have the priority of the overrides matching all bidders
+		matchAllBidders := !hasPositiveEntries(action.Conditions.Bidders)
+		matchesBidder := matchesCondition(action.Conditions.Bidders, bidder)
+		matchesMedia := len(action.Conditions.MediaTypes) == 0 || requestMediaTypes.matches(action.Conditions.MediaTypes)
+		matchesDeal := len(action.Conditions.DealIds) == 0 || (dealID != "" && matchesCondition(action.Conditions.DealIds, dealID))
 
-		if matchesBidder && matchesMedia {
+		if matchesBidder && matchesMedia && matchesDeal {
 			actionOverride, err := overrideGetter(action.Override)
 			if err != nil {
 				return override, message, err
@@ -317,7 +365,8 @@ func findImpressionOverrides(
 		}
 
 		mediaTypes := mediaTypesFromImp(imp)
-		override, message, err := firstOrDefaultOverride(bidder, mediaTypes, getIds, actionOverrides, defaultOverride)
+		impActionOverrides := actionOverridesMatchingSizes(actionOverrides, bannerSizesFromImp(imp))
+		override, message, err := firstOrDefaultOverride(bidder, mediaTypes, "", getIds, impActionOverrides, defaultOverride)
 		messages = mergeStrings(messages, message)
 		if err != nil {
 			return nil, messages, err
@@ -329,6 +378,18 @@ func findImpressionOverrides(
 	return overrides, messages, nil
 }
 
+// actionOverridesMatchingSizes filters out action overrides with sizes condition
+// not matching any of the impression banner sizes. Overrides without sizes condition match all sizes.
+func actionOverridesMatchingSizes(actionOverrides []ActionOverride, sizes bannerSizes) []ActionOverride {
+	matchingOverrides := make([]ActionOverride, 0, len(actionOverrides))
+	for _, action := range actionOverrides {
+		if len(action.Conditions.Sizes) == 0 || sizes.intersects(action.Conditions.Sizes) {
+			matchingOverrides = append(matchingOverrides, action)
+		}
+	}
+	return matchingOverrides
+}
+
 type overrideGetterFn[T any] func(override Override) (T, error)
 
 func getNames(override Override) ([]string, error) {
@@ -345,6 +406,13 @@ func getIds(override Override) ([]int, error) {
 	return override.Ids, nil
 }
 
+func getCategoryTaxonomy(override Override) (adcom1.CategoryTaxonomy, error) {
+	if len(override.Ids) != 1 {
+		return 0, errors.New("override field must hold a single category taxonomy")
+	}
+	return adcom1.CategoryTaxonomy(override.Ids[0]), nil
+}
+
 type mediaTypes map[string]struct{}
 
 func (m mediaTypes) String() string {
@@ -367,9 +435,10 @@ func (m mediaTypes) String() string {
 	return builder.String()
 }
 
-func (m mediaTypes) intersects(mediaTypes []string) bool {
-	for _, mediaType := range mediaTypes {
-		if _, ok := m[strings.ToLower(mediaType)]; ok {
+// matches checks whether any of the media types satisfies the media_types condition.
+func (m mediaTypes) matches(condition []string) bool {
+	for mediaType := range m {
+		if matchesCondition(condition, mediaType) {
 			return true
 		}
 	}
@@ -412,9 +481,39 @@ func mediaTypesFromImp(imp openrtb2.Imp) mediaTypes {
 	return mediaTypes
 }
 
+type bannerSizes map[string]struct{}
+
+func (b bannerSizes) intersects(sizes []string) bool {
+	for _, size := range sizes {
+		if _, ok := b[strings.ToLower(strings.TrimSpace(size))]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bannerSizesFromImp returns impression banner sizes in the WxH format
+// collected from the banner format list and the banner w and h fields.
+func bannerSizesFromImp(imp openrtb2.Imp) bannerSizes {
+	sizes := bannerSizes{}
+	if imp.Banner == nil {
+		return sizes
+	}
+
+	for _, format := range imp.Banner.Format {
+		sizes[fmt.Sprintf("%dx%d", format.W, format.H)] = struct{}{}
+	}
+
+	if imp.Banner.W != nil && imp.Banner.H != nil {
+		sizes[fmt.Sprintf("%dx%d", *imp.Banner.W, *imp.Banner.H)] = struct{}{}
+	}
+
+	return sizes
+}
+
 func validateCondition(conditions Conditions) error {
-	if conditions.Bidders == nil && conditions.MediaTypes == nil {
-		return errors.New("bidders and media_types absent from conditions, at least one of the fields must be present")
+	if conditions.Bidders == nil && conditions.MediaTypes == nil && conditions.DealIds == nil {
+		return errors.New("bidders, media_types and deal_ids absent from conditions, at least one of the fields must be present")
 	}
 	return nil
 }
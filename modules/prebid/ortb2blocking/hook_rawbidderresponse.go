@@ -0,0 +1,313 @@
+package ortb2blocking
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prebid/openrtb/v17/adcom1"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/hooks/hookexecution"
+	"github.com/prebid/prebid-server/hooks/hookstage"
+)
+
+// enforceBlockingActivity is the name of the analytics activity reporting bids with blocked attributes
+const enforceBlockingActivity = "enforce_blocking"
+
+func handleRawBidderResponseHook(
+	cfg config,
+	payload hookstage.RawBidderResponsePayload,
+	moduleCtx hookstage.ModuleContext,
+) (result hookstage.HookResult[hookstage.RawBidderResponsePayload], err error) {
+	attributes, _ := moduleCtx[payload.Bidder].(blockingAttributes)
+	checks := bidChecks(cfg, payload.Bidder, attributes)
+
+	var analyticsResults []hookanalytics.Result
+	blockedBids := map[*adapters.TypedBid]struct{}{}
+	for _, bid := range payload.Bids {
+		if bid == nil || bid.Bid == nil {
+			continue
+		}
+
+		bidMediaTypes := mediaTypes{string(bid.BidType): struct{}{}}
+		for _, check := range checks {
+			mode, message, err := firstOrDefaultOverride(payload.Bidder, bidMediaTypes, bid.Bid.DealID, getEnforceBlocksMode, check.enforceBlocksOverrides, check.enforceBlocks)
+			result.Warnings = mergeStrings(result.Warnings, message)
+			if err != nil {
+				return result, hookexecution.NewFailure("failed to get override for %s.enforce_blocks: %s", check.field, err)
+			} else if mode != EnforceBlocksWarn && mode != EnforceBlocksEnforce {
+				continue
+			}
+
+			values, messages, err := check.match(bid, bidMediaTypes)
+			result.Warnings = mergeStrings(result.Warnings, messages...)
+			if err != nil {
+				return result, hookexecution.NewFailure("%s", err)
+			} else if len(values) == 0 {
+				continue
+			}
+
+			status := hookanalytics.ResultStatusAllow
+			if mode == EnforceBlocksEnforce {
+				status = hookanalytics.ResultStatusBlock
+				blockedBids[bid] = struct{}{}
+			}
+			analyticsResults = append(analyticsResults, hookanalytics.Result{
+				Status: status,
+				Values: values,
+				AppliedTo: hookanalytics.AppliedTo{
+					Bidders: []string{payload.Bidder},
+					BidIds:  []string{bid.Bid.ID},
+					ImpIds:  []string{bid.Bid.ImpID},
+				},
+			})
+		}
+	}
+
+	if len(analyticsResults) > 0 {
+		result.AnalyticsTags = hookanalytics.Analytics{
+			Activities: []hookanalytics.Activity{{
+				Name:    enforceBlockingActivity,
+				Status:  hookanalytics.ActivityStatusSuccess,
+				Results: analyticsResults,
+			}},
+		}
+	}
+
+	if len(blockedBids) > 0 {
+		changeSet := hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+		changeSet.AddMutation(func(p hookstage.RawBidderResponsePayload) (hookstage.RawBidderResponsePayload, error) {
+			bids := make([]*adapters.TypedBid, 0, len(p.Bids))
+			for _, bid := range p.Bids {
+				if _, blocked := blockedBids[bid]; !blocked {
+					bids = append(bids, bid)
+				}
+			}
+			p.Bids = bids
+			return p, nil
+		}, hookstage.MutationDelete, "bidderresponse", "bids")
+		result.ChangeSet = changeSet
+	}
+
+	return result, nil
+}
+
+// bidCheck checks the bids against the blocks of a single attribute according to its enforce_blocks mode.
+type bidCheck struct {
+	field                  string
+	enforceBlocks          EnforceBlocksMode
+	enforceBlocksOverrides []ActionOverride
+	// match returns the analytics values describing the blocks matched by the bid, nil if the bid is not blocked
+	match func(bid *adapters.TypedBid, bidMediaTypes mediaTypes) (map[string]interface{}, []string, error)
+}
+
+// bidChecks returns the checks of the attributes blocked by the module. The advertiser domains, categories and apps
+// are checked against the values sent to the bidder, the creative attributes against the ones sent for the bid's imp
+// and the groups of several attributes, which can't be sent to the bidder.
+func bidChecks(cfg config, bidder string, attributes blockingAttributes) []bidCheck {
+	badv, bcat, bapp, battr := cfg.Attributes.Badv, cfg.Attributes.Bcat, cfg.Attributes.Bapp, cfg.Attributes.Battr
+	attrGroups := battr.combinedBannerAttrGroups()
+
+	return []bidCheck{
+		{
+			field:                  "badv",
+			enforceBlocks:          badv.EnforceBlocks,
+			enforceBlocksOverrides: badv.ActionOverrides.EnforceBlocks,
+			match: func(bid *adapters.TypedBid, bidMediaTypes mediaTypes) (map[string]interface{}, []string, error) {
+				return matchBlockedNames(bidder, bid, bidMediaTypes, blockedNamesCheck{
+					field:                    "badv",
+					valuesKey:                "adomain",
+					bidValues:                bid.Bid.ADomain,
+					blocked:                  attributes.bAdv,
+					allowedForDeals:          badv.AllowedAdomainForDeals,
+					allowedForDealsOverrides: badv.ActionOverrides.AllowedAdomainForDeals,
+					allowedForDealsField:     "allowed_adomain_for_deals",
+					blockUnknown:             badv.BlockUnknownAdomain,
+					blockUnknownOverrides:    badv.ActionOverrides.BlockUnknownAdomain,
+					blockUnknownField:        "block_unknown_adomain",
+				})
+			},
+		},
+		{
+			field:                  "bcat",
+			enforceBlocks:          bcat.EnforceBlocks,
+			enforceBlocksOverrides: bcat.ActionOverrides.EnforceBlocks,
+			match: func(bid *adapters.TypedBid, bidMediaTypes mediaTypes) (map[string]interface{}, []string, error) {
+				return matchBlockedNames(bidder, bid, bidMediaTypes, blockedNamesCheck{
+					field:                    "bcat",
+					valuesKey:                "cat",
+					bidValues:                bid.Bid.Cat,
+					blocked:                  attributes.bCat,
+					allowedForDeals:          bcat.AllowedAdvCatForDeals,
+					allowedForDealsOverrides: bcat.ActionOverrides.AllowedAdvCatForDeals,
+					allowedForDealsField:     "allowed_adv_cat_for_deals",
+					blockUnknown:             bcat.BlockUnknownAdvCat,
+					blockUnknownOverrides:    bcat.ActionOverrides.BlockUnknownAdvCat,
+					blockUnknownField:        "block_unknown_adv_cat",
+				})
+			},
+		},
+		{
+			field:                  "bapp",
+			enforceBlocks:          bapp.EnforceBlocks,
+			enforceBlocksOverrides: bapp.ActionOverrides.EnforceBlocks,
+			match: func(bid *adapters.TypedBid, bidMediaTypes mediaTypes) (map[string]interface{}, []string, error) {
+				var bundle []string
+				if bid.Bid.Bundle != "" {
+					bundle = []string{bid.Bid.Bundle}
+				}
+				return matchBlockedNames(bidder, bid, bidMediaTypes, blockedNamesCheck{
+					field:                    "bapp",
+					valuesKey:                "bundle",
+					bidValues:                bundle,
+					blocked:                  attributes.bApp,
+					allowedForDeals:          bapp.AllowedAppForDeals,
+					allowedForDealsOverrides: bapp.ActionOverrides.AllowedAppForDeals,
+					allowedForDealsField:     "allowed_app_for_deals",
+				})
+			},
+		},
+		{
+			field:                  "battr",
+			enforceBlocks:          battr.EnforceBlocks,
+			enforceBlocksOverrides: battr.ActionOverrides.EnforceBlocks,
+			match: func(bid *adapters.TypedBid, bidMediaTypes mediaTypes) (map[string]interface{}, []string, error) {
+				var allowedAttr []int
+				var messages []string
+				if bid.Bid.DealID != "" {
+					var message string
+					var err error
+					allowedAttr, message, err = firstOrDefaultOverride(bidder, bidMediaTypes, bid.Bid.DealID, getIds, battr.ActionOverrides.AllowedBannerAttrForDeals, battr.AllowedBannerAttrForDeals)
+					messages = mergeStrings(messages, message)
+					if err != nil {
+						return nil, messages, fmt.Errorf("failed to get override for battr.allowed_banner_attr_for_deals: %s", err)
+					}
+				}
+
+				matchedAttr := blockedBidAttr(bid.Bid.Attr, attributes.bAttr[bid.Bid.ImpID], attrGroups, allowedAttr)
+				if len(matchedAttr) == 0 {
+					return nil, messages, nil
+				}
+				return map[string]interface{}{"attributes": matchedAttr}, messages, nil
+			},
+		},
+	}
+}
+
+// blockedNamesCheck describes the blocks of an attribute holding names, e.g. the advertiser domains.
+// The unknown values are blocked only if the blockUnknownField is set.
+type blockedNamesCheck struct {
+	field                    string
+	valuesKey                string
+	bidValues                []string
+	blocked                  []string
+	allowedForDeals          []string
+	allowedForDealsOverrides []ActionOverride
+	allowedForDealsField     string
+	blockUnknown             bool
+	blockUnknownOverrides    []ActionOverride
+	blockUnknownField        string
+}
+
+// matchBlockedNames returns the analytics values describing the blocked names of the bid, nil if the bid is not blocked.
+// Bids without any value match the blocks if the unknown values are blocked, names allowed for deals are ignored for deal bids.
+func matchBlockedNames(bidder string, bid *adapters.TypedBid, bidMediaTypes mediaTypes, check blockedNamesCheck) (map[string]interface{}, []string, error) {
+	var messages []string
+	if len(check.bidValues) == 0 {
+		if check.blockUnknownField == "" {
+			return nil, messages, nil
+		}
+
+		blockUnknown, message, err := firstOrDefaultOverride(bidder, bidMediaTypes, bid.Bid.DealID, getIsActive, check.blockUnknownOverrides, check.blockUnknown)
+		messages = mergeStrings(messages, message)
+		if err != nil {
+			return nil, messages, fmt.Errorf("failed to get override for %s.%s: %s", check.field, check.blockUnknownField, err)
+		} else if blockUnknown {
+			return map[string]interface{}{check.blockUnknownField: true}, messages, nil
+		}
+		return nil, messages, nil
+	}
+
+	var allowed []string
+	if bid.Bid.DealID != "" {
+		var message string
+		var err error
+		allowed, message, err = firstOrDefaultOverride(bidder, bidMediaTypes, bid.Bid.DealID, getNames, check.allowedForDealsOverrides, check.allowedForDeals)
+		messages = mergeStrings(messages, message)
+		if err != nil {
+			return nil, messages, fmt.Errorf("failed to get override for %s.%s: %s", check.field, check.allowedForDealsField, err)
+		}
+	}
+
+	var matched []string
+	for _, value := range check.bidValues {
+		if containsFold(check.blocked, value) && !containsFold(allowed, value) && !containsFold(matched, value) {
+			matched = append(matched, value)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, messages, nil
+	}
+	return map[string]interface{}{check.valuesKey: matched}, messages, nil
+}
+
+// blockedBidAttr returns the bid attributes matching the blocks, nil if the bid is not blocked.
+// The bid matches if it has any of the blocked attributes or all attributes of any of the groups,
+// the attributes allowed for deals are not taken into account.
+func blockedBidAttr(bidAttr []adcom1.CreativeAttribute, blockedAttr []int, blockedGroups [][]int, allowedAttr []int) []int {
+	attrs := make([]int, 0, len(bidAttr))
+	for _, attr := range bidAttr {
+		if !containsInt(allowedAttr, int(attr)) {
+			attrs = append(attrs, int(attr))
+		}
+	}
+
+	var matched []int
+	for _, attr := range attrs {
+		if containsInt(blockedAttr, attr) && !containsInt(matched, attr) {
+			matched = append(matched, attr)
+		}
+	}
+
+	for _, group := range blockedGroups {
+		if !containsAllInts(attrs, group) {
+			continue
+		}
+		for _, attr := range group {
+			if !containsInt(matched, attr) {
+				matched = append(matched, attr)
+			}
+		}
+	}
+
+	return matched
+}
+
+// getIsActive returns the boolean override, e.g. of the block_unknown_adomain field.
+func getIsActive(override Override) (bool, error) {
+	if len(override.Ids) > 0 || len(override.Names) > 0 {
+		return false, errors.New("override field must hold a boolean")
+	}
+	return override.IsActive, nil
+}
+
+// getEnforceBlocksMode returns the mode set by the enforce_blocks override, the mode name
+// as well as the legacy boolean is accepted, true stands for "enforce" and false for "off".
+func getEnforceBlocksMode(override Override) (EnforceBlocksMode, error) {
+	if len(override.Ids) > 0 || len(override.Names) > 0 {
+		return "", errors.New("override field must hold a mode or a boolean")
+	}
+	if override.Name != "" {
+		switch mode := EnforceBlocksMode(override.Name); mode {
+		case EnforceBlocksOff, EnforceBlocksWarn, EnforceBlocksEnforce:
+			return mode, nil
+		default:
+			return "", fmt.Errorf("invalid enforce_blocks mode: %s", override.Name)
+		}
+	}
+	if override.IsActive {
+		return EnforceBlocksEnforce, nil
+	}
+	return EnforceBlocksOff, nil
+}
@@ -3,12 +3,18 @@ package ortb2blocking
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/prebid/openrtb/v17/adcom1"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/modules/moduledeps"
+	"github.com/prebid/prebid-server/modules/moduleregistry"
 )
 
+func init() {
+	moduleregistry.Register("prebid", "ortb2blocking", Builder)
+}
+
 func Builder(_ json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
 	return Module{}, nil
 }
@@ -31,10 +37,33 @@ func (m Module) HandleBidderRequestHook(
 	if err != nil {
 		return result, err
 	}
+	cfg.removeExpiredOverrides(time.Now())
 
 	return handleBidderRequestHook(cfg, payload)
 }
 
+// HandleRawBidderResponseHook checks the bids returned by the bidder against the blocked advertiser domains,
+// categories, apps and creative attributes. Bids matching the blocks are reported or dropped depending on
+// the enforce_blocks mode of the matched attribute.
+func (m Module) HandleRawBidderResponseHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.RawBidderResponsePayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePayload], error) {
+	result := hookstage.HookResult[hookstage.RawBidderResponsePayload]{}
+	if len(miCtx.AccountConfig) == 0 {
+		return result, nil
+	}
+
+	cfg, err := newConfig(miCtx.AccountConfig)
+	if err != nil {
+		return result, err
+	}
+	cfg.removeExpiredOverrides(time.Now())
+
+	return handleRawBidderResponseHook(cfg, payload, miCtx.ModuleContext)
+}
+
 type blockingAttributes struct {
 	bAdv   []string
 	bApp   []string
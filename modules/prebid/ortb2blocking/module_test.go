@@ -8,6 +8,8 @@ import (
 
 	"github.com/prebid/openrtb/v17/adcom1"
 	"github.com/prebid/openrtb/v17/openrtb2"
+	"github.com/prebid/prebid-server/adapters"
+	"github.com/prebid/prebid-server/hooks/hookanalytics"
 	"github.com/prebid/prebid-server/hooks/hookexecution"
 	"github.com/prebid/prebid-server/hooks/hookstage"
 	"github.com/prebid/prebid-server/modules/moduledeps"
@@ -393,7 +395,7 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			expectedError: nil,
 		},
 		{
-			description: "BidderRequest attributes not updated if they already present in BidderRequest",
+			description: "BidderRequest attributes not updated if they already present in BidderRequest, bcat kept if category taxonomy differs",
 			bidder:      bidder,
 			config:      testConfig,
 			bidRequest: &openrtb2.BidRequest{
@@ -434,6 +436,52 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			},
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{
 				ModuleContext: map[string]interface{}{bidder: blockingAttributes{
+					bCat:  []string{bCat1, bCat2, bCat3, bCat4},
+					bType: map[string][]int{},
+					bAttr: map[string][]int{},
+				}},
+				Warnings: []string{"Blocked categories not added to bcat. Bidder: appnexus, request category taxonomy 1 differs from configured 6"},
+			},
+			expectedError: nil,
+		},
+		{
+			description: "Blocked categories merged with bcat present in BidderRequest",
+			bidder:      bidder,
+			config:      json.RawMessage(`{"attributes": {"bcat": {"blocked_adv_cat": ["IAB-1", "IAB-2"]}}}`),
+			bidRequest: &openrtb2.BidRequest{
+				BCat: []string{"Existing-IAB-1", bCat2},
+				Imp:  []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}},
+			},
+			expectedBidRequest: &openrtb2.BidRequest{
+				BCat: []string{"Existing-IAB-1", bCat2, bCat1},
+				Imp:  []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}},
+			},
+			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{
+				ModuleContext: map[string]interface{}{bidder: blockingAttributes{
+					bCat:  []string{bCat1, bCat2},
+					bType: map[string][]int{},
+					bAttr: map[string][]int{},
+				}},
+			},
+			expectedError: nil,
+		},
+		{
+			description: "Blocked categories of bidder override merged with bcat present in BidderRequest",
+			bidder:      bidder,
+			config:      json.RawMessage(`{"attributes": {"bcat": {"category_taxonomy": 6, "blocked_adv_cat": ["IAB-1"], "action_overrides": {"blocked_adv_cat": [{"conditions": {"bidders": ["appnexus"]}, "override": ["IAB-3", "IAB-4"]}, {"conditions": {"bidders": ["rubicon"]}, "override": ["IAB-2"]}]}}}}`),
+			bidRequest: &openrtb2.BidRequest{
+				BCat:   []string{"Existing-IAB-1"},
+				CatTax: adcom1.CatTaxIABContent22,
+				Imp:    []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}},
+			},
+			expectedBidRequest: &openrtb2.BidRequest{
+				BCat:   []string{"Existing-IAB-1", bCat3, bCat4},
+				CatTax: adcom1.CatTaxIABContent22,
+				Imp:    []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}},
+			},
+			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{
+				ModuleContext: map[string]interface{}{bidder: blockingAttributes{
+					bCat:  []string{bCat3, bCat4},
 					bType: map[string][]int{},
 					bAttr: map[string][]int{},
 				}},
@@ -501,7 +549,7 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			bidRequest:         &openrtb2.BidRequest{},
 			expectedBidRequest: &openrtb2.BidRequest{},
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{},
-			expectedError:      hookexecution.NewFailure("failed to update badv field: failed to get override for badv.blocked_adomain: bidders and media_types absent from conditions, at least one of the fields must be present"),
+			expectedError:      hookexecution.NewFailure("failed to update badv field: failed to get override for badv.blocked_adomain: bidders, media_types and deal_ids absent from conditions, at least one of the fields must be present"),
 		},
 		{
 			description:        "Expect bapp error if bidders and media_types not defined in config conditions",
@@ -510,7 +558,7 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			bidRequest:         &openrtb2.BidRequest{},
 			expectedBidRequest: &openrtb2.BidRequest{},
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{},
-			expectedError:      hookexecution.NewFailure("failed to update bapp field: failed to get override for bapp.blocked_app: bidders and media_types absent from conditions, at least one of the fields must be present"),
+			expectedError:      hookexecution.NewFailure("failed to update bapp field: failed to get override for bapp.blocked_app: bidders, media_types and deal_ids absent from conditions, at least one of the fields must be present"),
 		},
 		{
 			description:        "Expect bcat error if bidders and media_types not defined in config conditions",
@@ -519,7 +567,7 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			bidRequest:         &openrtb2.BidRequest{},
 			expectedBidRequest: &openrtb2.BidRequest{},
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{},
-			expectedError:      hookexecution.NewFailure("failed to update bcat field: failed to get override for bcat.blocked_adv_cat: bidders and media_types absent from conditions, at least one of the fields must be present"),
+			expectedError:      hookexecution.NewFailure("failed to update bcat field: failed to get override for bcat.blocked_adv_cat: bidders, media_types and deal_ids absent from conditions, at least one of the fields must be present"),
 		},
 		{
 			description:        "Expect btype error if bidders and media_types not defined in config conditions",
@@ -528,7 +576,7 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			bidRequest:         &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}}},
 			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}}},
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{},
-			expectedError:      hookexecution.NewFailure("failed to update btype field: failed to get override for imp.*.banner.btype: bidders and media_types absent from conditions, at least one of the fields must be present"),
+			expectedError:      hookexecution.NewFailure("failed to update btype field: failed to get override for imp.*.banner.btype: bidders, media_types and deal_ids absent from conditions, at least one of the fields must be present"),
 		},
 		{
 			description:        "Expect battr error if bidders and media_types not defined in config conditions",
@@ -537,7 +585,7 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			bidRequest:         &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}}},
 			expectedBidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Video: &openrtb2.Video{}}}},
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{},
-			expectedError:      hookexecution.NewFailure("failed to update battr field: failed to get override for imp.*.banner.battr: bidders and media_types absent from conditions, at least one of the fields must be present"),
+			expectedError:      hookexecution.NewFailure("failed to update battr field: failed to get override for imp.*.banner.battr: bidders, media_types and deal_ids absent from conditions, at least one of the fields must be present"),
 		},
 		{
 			description:        "Expect error if override.names empty in config conditions",
@@ -557,6 +605,53 @@ func TestHandleBidderRequestHook(t *testing.T) {
 			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{},
 			expectedError:      hookexecution.NewFailure("failed to update battr field: failed to get override for imp.*.banner.battr: empty override field"),
 		},
+		{
+			description: "Battr override applied only to impressions with matching banner sizes",
+			bidder:      bidder,
+			config:      json.RawMessage(`{"attributes": {"battr": {"blocked_banner_attr": [1, 8], "action_overrides": {"blocked_banner_attr": [{"conditions": {"media_types": ["banner"], "sizes": ["970x250"]}, "override": [1]}]}}}}`),
+			bidRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{
+					{
+						ID:     "ImpID1",
+						Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 970, H: 250}}},
+					},
+					{
+						ID:     "ImpID2",
+						Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}},
+					},
+				},
+			},
+			expectedBidRequest: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{
+					{
+						ID: "ImpID1",
+						Banner: &openrtb2.Banner{
+							Format: []openrtb2.Format{{W: 300, H: 250}, {W: 970, H: 250}},
+							BAttr:  []adcom1.CreativeAttribute{bAttr1},
+						},
+					},
+					{
+						ID: "ImpID2",
+						Banner: &openrtb2.Banner{
+							Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}},
+							BAttr:  []adcom1.CreativeAttribute{bAttr1, bAttr8},
+						},
+					},
+				},
+			},
+			expectedHookResult: hookstage.HookResult[hookstage.BidderRequestPayload]{
+				ModuleContext: map[string]interface{}{
+					bidder: blockingAttributes{
+						bType: map[string][]int{},
+						bAttr: map[string][]int{
+							"ImpID1": toInt([]adcom1.CreativeAttribute{bAttr1}),
+							"ImpID2": toInt([]adcom1.CreativeAttribute{bAttr1, bAttr8}),
+						},
+					},
+				},
+			},
+			expectedError: nil,
+		},
 	}
 
 	for _, test := range testCases {
@@ -594,6 +689,574 @@ func TestHandleBidderRequestHook(t *testing.T) {
 	}
 }
 
+func TestHandleBidderRequestHookCatTaxOverride(t *testing.T) {
+	config := json.RawMessage(`{"attributes": {"bcat": {"category_taxonomy": 6, "action_overrides": {"category_taxonomy": [{"conditions": {"bidders": ["rubicon"]}, "override": 1}]}}}}`)
+	bidRequest := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Banner: &openrtb2.Banner{}}}}
+
+	testCases := []struct {
+		description    string
+		bidder         string
+		expectedCatTax adcom1.CategoryTaxonomy
+	}{
+		{
+			description:    "Account-level category taxonomy used when no override matches bidder",
+			bidder:         "appnexus",
+			expectedCatTax: adcom1.CatTaxIABContent22,
+		},
+		{
+			description:    "Category taxonomy overridden for bidder",
+			bidder:         "rubicon",
+			expectedCatTax: adcom1.CatTaxIABContent10,
+		},
+	}
+
+	module := Module{}
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := *bidRequest
+			payload := hookstage.BidderRequestPayload{Bidder: test.bidder, BidRequest: &request}
+
+			hookResult, err := module.HandleBidderRequestHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{
+					AccountConfig: config,
+					Endpoint:      hookexecution.EndpointAuction,
+					ModuleContext: map[string]interface{}{},
+				},
+				payload,
+			)
+			assert.NoError(t, err, "Unexpected hook execution error.")
+
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedCatTax, payload.BidRequest.CatTax, "Invalid BidRequest.CatTax.")
+
+			attributes, ok := hookResult.ModuleContext[test.bidder].(blockingAttributes)
+			if assert.True(t, ok, "Blocking attributes expected in module context.") {
+				assert.Equal(t, test.expectedCatTax, attributes.catTax, "Invalid catTax in module context.")
+			}
+		})
+	}
+}
+
+func TestHandleBidderRequestHookExpiredOverride(t *testing.T) {
+	testCases := []struct {
+		description  string
+		expiresAt    string
+		expectedBAdv []string
+	}{
+		{
+			description:  "Expired override ignored",
+			expiresAt:    "2000-01-01T00:00:00Z",
+			expectedBAdv: []string{"default.com"},
+		},
+		{
+			description:  "Not expired override applied",
+			expiresAt:    "2999-01-01T00:00:00Z",
+			expectedBAdv: []string{"incident.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			config := json.RawMessage(`{"attributes": {"badv": {"blocked_adomain": ["default.com"], "action_overrides": {"blocked_adomain": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": ["incident.com"], "expires_at": "` + test.expiresAt + `"}
+			]}}}}`)
+			payload := hookstage.BidderRequestPayload{Bidder: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Banner: &openrtb2.Banner{}}}}}
+
+			hookResult, err := Module{}.HandleBidderRequestHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{
+					AccountConfig: config,
+					Endpoint:      hookexecution.EndpointAuction,
+					ModuleContext: map[string]interface{}{},
+				},
+				payload,
+			)
+			assert.NoError(t, err, "Unexpected hook execution error.")
+
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBAdv, payload.BidRequest.BAdv, "Invalid BidRequest.BAdv.")
+		})
+	}
+}
+
+func TestHandleBidderRequestHookNegatedConditions(t *testing.T) {
+	testCases := []struct {
+		description  string
+		conditions   string
+		bidder       string
+		imp          openrtb2.Imp
+		expectedBAdv []string
+	}{
+		{
+			description:  "Excluded bidder not matched",
+			conditions:   `{"bidders": ["!appnexus"]}`,
+			bidder:       "appnexus",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"default.com"},
+		},
+		{
+			description:  "Bidder not excluded matched",
+			conditions:   `{"bidders": ["!appnexus"]}`,
+			bidder:       "rubicon",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"override.com"},
+		},
+		{
+			description:  "Negation takes precedence over inclusion of the same bidder",
+			conditions:   `{"bidders": ["appnexus", "rubicon", "!APPNEXUS"]}`,
+			bidder:       "appnexus",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"default.com"},
+		},
+		{
+			description:  "Bidder not included by mixed list not matched",
+			conditions:   `{"bidders": ["rubicon", "!appnexus"]}`,
+			bidder:       "openx",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"default.com"},
+		},
+		{
+			description:  "Bidder included by mixed list matched",
+			conditions:   `{"bidders": ["rubicon", "!appnexus"]}`,
+			bidder:       "rubicon",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"override.com"},
+		},
+		{
+			description:  "Excluded media type not matched",
+			conditions:   `{"media_types": ["!video"]}`,
+			bidder:       "appnexus",
+			imp:          openrtb2.Imp{ID: "ImpID1", Video: &openrtb2.Video{}},
+			expectedBAdv: []string{"default.com"},
+		},
+		{
+			description:  "Media type not excluded matched",
+			conditions:   `{"media_types": ["!video"]}`,
+			bidder:       "appnexus",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"override.com"},
+		},
+		{
+			description:  "Empty bidders list matches all bidders",
+			conditions:   `{"bidders": []}`,
+			bidder:       "appnexus",
+			imp:          openrtb2.Imp{ID: "ImpID1", Banner: &openrtb2.Banner{}},
+			expectedBAdv: []string{"override.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			config := json.RawMessage(`{"attributes": {"badv": {"blocked_adomain": ["default.com"], "action_overrides": {"blocked_adomain": [
+				{"conditions": ` + test.conditions + `, "override": ["override.com"]}
+			]}}}}`)
+			payload := hookstage.BidderRequestPayload{Bidder: test.bidder, BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{test.imp}}}
+
+			hookResult, err := Module{}.HandleBidderRequestHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{
+					AccountConfig: config,
+					Endpoint:      hookexecution.EndpointAuction,
+					ModuleContext: map[string]interface{}{},
+				},
+				payload,
+			)
+			assert.NoError(t, err, "Unexpected hook execution error.")
+
+			for _, mut := range hookResult.ChangeSet.Mutations() {
+				_, err := mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBAdv, payload.BidRequest.BAdv, "Invalid BidRequest.BAdv.")
+		})
+	}
+}
+
+func TestHandleBidderRequestHookBannerAttrGroups(t *testing.T) {
+	config := json.RawMessage(`{"attributes": {"battr": {"blocked_banner_attr": [1, 2], "blocked_banner_attr_groups": [[3], [2], [4, 6]]}}}`)
+	payload := hookstage.BidderRequestPayload{Bidder: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "ImpID1", Banner: &openrtb2.Banner{}}}}}
+
+	hookResult, err := Module{}.HandleBidderRequestHook(
+		context.Background(),
+		hookstage.ModuleInvocationContext{AccountConfig: config, Endpoint: hookexecution.EndpointAuction},
+		payload,
+	)
+	assert.NoError(t, err, "Unexpected hook execution error.")
+
+	for _, mut := range hookResult.ChangeSet.Mutations() {
+		_, err := mut.Apply(payload)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []adcom1.CreativeAttribute{1, 2, 3}, payload.BidRequest.Imp[0].Banner.BAttr, "Only single attribute groups should be sent to bidder.")
+	assert.Equal(t, map[string][]int{"ImpID1": {1, 2, 3}}, hookResult.ModuleContext["appnexus"].(blockingAttributes).bAttr)
+}
+
+func TestHandleRawBidderResponseHook(t *testing.T) {
+	bidder := "appnexus"
+	moduleCtx := hookstage.ModuleContext{bidder: blockingAttributes{bAttr: map[string][]int{"ImpID1": {1, 2}}}}
+
+	testCases := []struct {
+		description    string
+		config         json.RawMessage
+		bids           []*adapters.TypedBid
+		expectedBids   []*adapters.TypedBid
+		expectedResult hookstage.HookResult[hookstage.RawBidderResponsePayload]
+		expectedError  error
+	}{
+		{
+			description: "Bids matching flat attributes or all attributes of group dropped",
+			config:      json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "enforce", "blocked_banner_attr": [1, 2], "blocked_banner_attr_groups": [[4, 6]]}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{2, 3}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "2", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 5, 6}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "3", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 5}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "3", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 5}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+					Name:   enforceBlockingActivity,
+					Status: hookanalytics.ActivityStatusSuccess,
+					Results: []hookanalytics.Result{
+						{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"attributes": []int{2}},
+							AppliedTo: hookanalytics.AppliedTo{Bidders: []string{bidder}, BidIds: []string{"1"}, ImpIds: []string{"ImpID1"}},
+						},
+						{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"attributes": []int{4, 6}},
+							AppliedTo: hookanalytics.AppliedTo{Bidders: []string{bidder}, BidIds: []string{"2"}, ImpIds: []string{"ImpID1"}},
+						},
+					},
+				}}},
+			},
+		},
+		{
+			description: "Bids matching blocks reported but kept in warn mode",
+			config:      json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "warn", "blocked_banner_attr_groups": [[4, 6]]}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID2", Attr: []adcom1.CreativeAttribute{6, 4}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID2", Attr: []adcom1.CreativeAttribute{6, 4}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+					Name:   enforceBlockingActivity,
+					Status: hookanalytics.ActivityStatusSuccess,
+					Results: []hookanalytics.Result{{
+						Status:    hookanalytics.ResultStatusAllow,
+						Values:    map[string]interface{}{"attributes": []int{4, 6}},
+						AppliedTo: hookanalytics.AppliedTo{Bidders: []string{bidder}, BidIds: []string{"1"}, ImpIds: []string{"ImpID2"}},
+					}},
+				}}},
+			},
+		},
+		{
+			description: "Attributes allowed for deals ignored for deal bids",
+			config:      json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": true, "allowed_banner_attr_for_deals": [2, 6], "blocked_banner_attr_groups": [[4, 6]]}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", DealID: "deal", Attr: []adcom1.CreativeAttribute{2, 4, 6}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", DealID: "deal", Attr: []adcom1.CreativeAttribute{2, 4, 6}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{},
+		},
+		{
+			description: "Attributes allowed for deals override applied to bids of matching deals",
+			config: json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "enforce", "blocked_banner_attr": [1, 2], "action_overrides": {"allowed_banner_attr_for_deals": [
+				{"conditions": {"deal_ids": ["deal1"]}, "override": [1]},
+				{"conditions": {"deal_ids": ["!deal1"]}, "override": [2]}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", DealID: "deal1", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "2", ImpID: "ImpID1", DealID: "deal2", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "3", ImpID: "ImpID1", DealID: "deal2", Attr: []adcom1.CreativeAttribute{2}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "4", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{2}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", DealID: "deal1", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "3", ImpID: "ImpID1", DealID: "deal2", Attr: []adcom1.CreativeAttribute{2}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+					Name:   enforceBlockingActivity,
+					Status: hookanalytics.ActivityStatusSuccess,
+					Results: []hookanalytics.Result{
+						{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"attributes": []int{1}},
+							AppliedTo: hookanalytics.AppliedTo{Bidders: []string{bidder}, BidIds: []string{"2"}, ImpIds: []string{"ImpID1"}},
+						},
+						{
+							Status:    hookanalytics.ResultStatusBlock,
+							Values:    map[string]interface{}{"attributes": []int{2}},
+							AppliedTo: hookanalytics.AppliedTo{Bidders: []string{bidder}, BidIds: []string{"4"}, ImpIds: []string{"ImpID1"}},
+						},
+					},
+				}}},
+			},
+		},
+		{
+			description: "Bids kept if enforcement disabled by override",
+			config: json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "enforce", "blocked_banner_attr_groups": [[4, 6]], "action_overrides": {"enforce_blocks": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": false}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 6}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 6}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{},
+		},
+		{
+			description: "Bids matching blocks reported but kept if warn mode set by override",
+			config: json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "enforce", "blocked_banner_attr_groups": [[4, 6]], "action_overrides": {"enforce_blocks": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": "warn"}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 6}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{4, 6}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{
+				AnalyticsTags: hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+					Name:   enforceBlockingActivity,
+					Status: hookanalytics.ActivityStatusSuccess,
+					Results: []hookanalytics.Result{{
+						Status:    hookanalytics.ResultStatusAllow,
+						Values:    map[string]interface{}{"attributes": []int{4, 6}},
+						AppliedTo: hookanalytics.AppliedTo{Bidders: []string{bidder}, BidIds: []string{"1"}, ImpIds: []string{"ImpID1"}},
+					}},
+				}}},
+			},
+		},
+		{
+			description: "Bids kept if enforce_blocks absent",
+			config:      json.RawMessage(`{"attributes": {"battr": {"blocked_banner_attr_groups": [[4, 6]]}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{1, 4, 6}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{1, 4, 6}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{},
+		},
+		{
+			description: "Error if enforce_blocks override invalid",
+			config: json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "enforce", "action_overrides": {"enforce_blocks": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": [1]}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{},
+			expectedError:  hookexecution.NewFailure("failed to get override for battr.enforce_blocks: override field must hold a mode or a boolean"),
+		},
+		{
+			description: "Error if enforce_blocks override mode unknown",
+			config: json.RawMessage(`{"attributes": {"battr": {"enforce_blocks": "enforce", "action_overrides": {"enforce_blocks": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": "drop"}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+			},
+			expectedBids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ImpID: "ImpID1", Attr: []adcom1.CreativeAttribute{1}}, BidType: "banner"},
+			},
+			expectedResult: hookstage.HookResult[hookstage.RawBidderResponsePayload]{},
+			expectedError:  hookexecution.NewFailure("failed to get override for battr.enforce_blocks: invalid enforce_blocks mode: drop"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.RawBidderResponsePayload{Bidder: bidder, Bids: test.bids}
+
+			result, err := Module{}.HandleRawBidderResponseHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.config, Endpoint: hookexecution.EndpointAuction, ModuleContext: moduleCtx},
+				payload,
+			)
+			assert.Equal(t, test.expectedError, err, "Invalid hook execution error.")
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedBids, payload.Bids, "Invalid bids.")
+
+			result.ChangeSet = hookstage.ChangeSet[hookstage.RawBidderResponsePayload]{}
+			assert.Equal(t, test.expectedResult, result, "Invalid hook execution result.")
+		})
+	}
+}
+
+func TestHandleRawBidderResponseHookAdvertiserBlocks(t *testing.T) {
+	bidder := "appnexus"
+	moduleCtx := hookstage.ModuleContext{bidder: blockingAttributes{
+		bAdv: []string{"a.com", "b.com"},
+		bCat: []string{"IAB-1"},
+		bApp: []string{"com.blocked.app"},
+	}}
+
+	testCases := []struct {
+		description    string
+		config         json.RawMessage
+		bids           []*adapters.TypedBid
+		expectedBidIDs []string
+		expectedValues []map[string]interface{}
+		expectedStatus hookanalytics.ResultStatus
+		expectedError  error
+	}{
+		{
+			description: "Bids with blocked advertiser domain dropped",
+			config:      json.RawMessage(`{"attributes": {"badv": {"enforce_blocks": "enforce"}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ADomain: []string{"c.com", "B.com"}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "2", ADomain: []string{"c.com"}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "3"}, BidType: "banner"},
+			},
+			expectedBidIDs: []string{"2", "3"},
+			expectedValues: []map[string]interface{}{{"adomain": []string{"B.com"}}},
+			expectedStatus: hookanalytics.ResultStatusBlock,
+		},
+		{
+			description: "Bids without advertiser domain dropped if unknown blocked",
+			config:      json.RawMessage(`{"attributes": {"badv": {"enforce_blocks": "enforce", "block_unknown_adomain": true}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ADomain: []string{"c.com"}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "2"}, BidType: "banner"},
+			},
+			expectedBidIDs: []string{"1"},
+			expectedValues: []map[string]interface{}{{"block_unknown_adomain": true}},
+			expectedStatus: hookanalytics.ResultStatusBlock,
+		},
+		{
+			description: "Advertiser domains allowed for deals ignored for deal bids",
+			config:      json.RawMessage(`{"attributes": {"badv": {"enforce_blocks": "enforce", "allowed_adomain_for_deals": ["a.com"]}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", DealID: "deal", ADomain: []string{"a.com"}}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "2", ADomain: []string{"a.com"}}, BidType: "banner"},
+			},
+			expectedBidIDs: []string{"1"},
+			expectedValues: []map[string]interface{}{{"adomain": []string{"a.com"}}},
+			expectedStatus: hookanalytics.ResultStatusBlock,
+		},
+		{
+			description: "Bids with blocked category reported but kept in warn mode",
+			config:      json.RawMessage(`{"attributes": {"bcat": {"enforce_blocks": "warn", "block_unknown_adv_cat": true}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", Cat: []string{"IAB-1", "IAB-2"}}, BidType: "video"},
+				{Bid: &openrtb2.Bid{ID: "2"}, BidType: "video"},
+			},
+			expectedBidIDs: []string{"1", "2"},
+			expectedValues: []map[string]interface{}{{"cat": []string{"IAB-1"}}, {"block_unknown_adv_cat": true}},
+			expectedStatus: hookanalytics.ResultStatusAllow,
+		},
+		{
+			description: "Bids with blocked app dropped for bidder enforcing blocks by override",
+			config: json.RawMessage(`{"attributes": {"bapp": {"enforce_blocks": "off", "action_overrides": {"enforce_blocks": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": true}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", Bundle: "com.blocked.app"}, BidType: "banner"},
+				{Bid: &openrtb2.Bid{ID: "2", Bundle: "com.other.app"}, BidType: "banner"},
+			},
+			expectedBidIDs: []string{"2"},
+			expectedValues: []map[string]interface{}{{"bundle": []string{"com.blocked.app"}}},
+			expectedStatus: hookanalytics.ResultStatusBlock,
+		},
+		{
+			description: "Bids kept if enforce_blocks off",
+			config:      json.RawMessage(`{"attributes": {"badv": {"enforce_blocks": "off"}, "bcat": {"enforce_blocks": false}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1", ADomain: []string{"a.com"}, Cat: []string{"IAB-1"}}, BidType: "banner"},
+			},
+			expectedBidIDs: []string{"1"},
+		},
+		{
+			description: "Error if block_unknown_adv_cat override invalid",
+			config: json.RawMessage(`{"attributes": {"bcat": {"enforce_blocks": "enforce", "action_overrides": {"block_unknown_adv_cat": [
+				{"conditions": {"bidders": ["appnexus"]}, "override": ["IAB-1"]}
+			]}}}}`),
+			bids: []*adapters.TypedBid{
+				{Bid: &openrtb2.Bid{ID: "1"}, BidType: "banner"},
+			},
+			expectedBidIDs: []string{"1"},
+			expectedError:  hookexecution.NewFailure("failed to get override for bcat.block_unknown_adv_cat: override field must hold a boolean"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload := hookstage.RawBidderResponsePayload{Bidder: bidder, Bids: test.bids}
+
+			result, err := Module{}.HandleRawBidderResponseHook(
+				context.Background(),
+				hookstage.ModuleInvocationContext{AccountConfig: test.config, Endpoint: hookexecution.EndpointAuction, ModuleContext: moduleCtx},
+				payload,
+			)
+			assert.Equal(t, test.expectedError, err, "Invalid hook execution error.")
+
+			for _, mut := range result.ChangeSet.Mutations() {
+				payload, err = mut.Apply(payload)
+				assert.NoError(t, err)
+			}
+			bidIDs := make([]string, 0, len(payload.Bids))
+			for _, bid := range payload.Bids {
+				bidIDs = append(bidIDs, bid.Bid.ID)
+			}
+			assert.Equal(t, test.expectedBidIDs, bidIDs, "Invalid bids.")
+
+			var values []map[string]interface{}
+			for _, activity := range result.AnalyticsTags.Activities {
+				for _, analyticsResult := range activity.Results {
+					assert.Equal(t, test.expectedStatus, analyticsResult.Status, "Invalid analytics result status.")
+					values = append(values, analyticsResult.Values)
+				}
+			}
+			assert.Equal(t, test.expectedValues, values, "Invalid analytics values.")
+		})
+	}
+}
+
+func TestMatchesCondition(t *testing.T) {
+	testCases := []struct {
+		description string
+		condition   []string
+		value       string
+		expected    bool
+	}{
+		{description: "Empty list matches all", condition: nil, value: "video", expected: true},
+		{description: "Included value", condition: []string{"banner", "video"}, value: "VIDEO", expected: true},
+		{description: "Not included value", condition: []string{"banner"}, value: "video", expected: false},
+		{description: "Excluded value", condition: []string{"!video"}, value: "video", expected: false},
+		{description: "Not excluded value", condition: []string{"!video"}, value: "banner", expected: true},
+		{description: "Negation takes precedence", condition: []string{"video", "!video"}, value: "video", expected: false},
+		{description: "Mixed list requires inclusion", condition: []string{"banner", "!video"}, value: "native", expected: false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, matchesCondition(test.condition, test.value), test.description)
+	}
+}
+
 type numeric interface {
 	openrtb2.BannerAdType | adcom1.CreativeAttribute
 }
@@ -12,11 +12,87 @@ func mergeStrings(messages []string, newMessages ...string) []string {
 	return messages
 }
 
-func hasMatches(list []string, s string) bool {
-	for _, val := range list {
-		if strings.EqualFold(val, s) {
+// mergeUnique returns the values followed by the new values not present among them.
+// The values slice is never modified.
+func mergeUnique(values []string, newValues []string) []string {
+	merged := make([]string, len(values), len(values)+len(newValues))
+	copy(merged, values)
+	for _, value := range newValues {
+		if !containsString(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// containsFold checks whether the value is present in the list, values are compared case-insensitively.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// negationPrefix marks condition entries excluding the value instead of including it.
+const negationPrefix = "!"
+
+// matchesCondition checks whether the value satisfies the condition list, values are compared case-insensitively.
+// Negated entries (e.g. "!video") take precedence and exclude the value. If the list has any positive entries,
+// the value must also match one of them, otherwise all values not excluded match. Empty list matches all values.
+func matchesCondition(condition []string, value string) bool {
+	hasPositives := false
+	included := false
+	for _, entry := range condition {
+		if strings.HasPrefix(entry, negationPrefix) {
+			if strings.EqualFold(strings.TrimPrefix(entry, negationPrefix), value) {
+				return false
+			}
+			continue
+		}
+
+		hasPositives = true
+		if strings.EqualFold(entry, value) {
+			included = true
+		}
+	}
+	return included || !hasPositives
+}
+
+// hasPositiveEntries checks whether the condition list includes some values explicitly.
+func hasPositiveEntries(condition []string) bool {
+	for _, entry := range condition {
+		if !strings.HasPrefix(entry, negationPrefix) {
 			return true
 		}
 	}
 	return false
 }
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAllInts(values []int, subset []int) bool {
+	for _, v := range subset {
+		if !containsInt(values, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -69,6 +69,22 @@ type ExtRequestPrebid struct {
 	// - basic: excludes debugmessages and analytic_tags from output
 	// any other value or an empty string disables trace output at all.
 	Trace string `json:"trace,omitempty"`
+
+	// BidAdjustmentFactorsByCur defines bid adjustment factors keyed by bidder and then by bid response currency.
+	// A currency-specific factor takes precedence over the BidAdjustmentFactors entry of the same bidder.
+	BidAdjustmentFactorsByCur map[string]map[string]float64 `json:"bidadjustmentfactorsbycur,omitempty"`
+
+	// EndpointCompression overrides the compression of the requests sent to the bidders, keyed by bidder.
+	// Supported values are "gzip" and "none", the bidder config is used for any other value.
+	EndpointCompression map[string]string `json:"endpointcompression,omitempty"`
+
+	// DebugBidders limits the debug httpcalls output to the listed bidders, the bidders are still subject to
+	// the debug restrictions of the account and the bidder config. Empty list includes all bidders allowed.
+	DebugBidders []string `json:"debugbidders,omitempty"`
+
+	// BidderTimeouts limits the time of the requests sent to the bidders in milliseconds, keyed by bidder.
+	// The auction deadline and the bidder config timeout still apply if shorter, non-positive values are ignored.
+	BidderTimeouts map[string]int `json:"biddertimeouts,omitempty"`
 }
 
 // Experiment defines if experimental features are available for the request
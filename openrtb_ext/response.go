@@ -20,6 +20,20 @@ type ExtBidResponse struct {
 	Usersync map[BidderName]*ExtResponseSyncData `json:"usersync,omitempty"`
 	// Prebid defines the contract for bidresponse.ext.prebid
 	Prebid *ExtResponsePrebid `json:"prebid,omitempty"`
+	// SeatNonBid defines the contract for bidresponse.ext.seatnonbid
+	SeatNonBid []SeatNonBid `json:"seatnonbid,omitempty"`
+}
+
+// SeatNonBid defines the contract for bidresponse.ext.seatnonbid[i], the reasons the seat didn't bid on impressions
+type SeatNonBid struct {
+	Seat   string   `json:"seat"`
+	NonBid []NonBid `json:"nonbid"`
+}
+
+// NonBid defines the contract for bidresponse.ext.seatnonbid[i].nonbid[j]
+type NonBid struct {
+	ImpId      string `json:"impid"`
+	StatusCode int    `json:"statuscode"`
 }
 
 // ExtResponseDebug defines the contract for bidresponse.ext.debug
@@ -0,0 +1,11 @@
+package version
+
+const userAgentProduct = "prebid-server"
+
+// BuildUserAgent returns the User-Agent header value identifying the Prebid Server version.
+func BuildUserAgent(version string) string {
+	if version == "" {
+		version = VerUnknown
+	}
+	return userAgentProduct + "/" + version
+}
@@ -0,0 +1,12 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildUserAgent(t *testing.T) {
+	assert.Equal(t, "prebid-server/1.2.3", BuildUserAgent("1.2.3"))
+	assert.Equal(t, "prebid-server/unknown", BuildUserAgent(""))
+}